@@ -0,0 +1,121 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PayloadType is the DSSE payload type of in-toto Statements.
+const PayloadType = "application/vnd.in-toto+json"
+
+var (
+	errInvalidSubject     = errors.New("invalid subject")
+	errInvalidEnvelope    = errors.New("invalid envelope")
+	errInvalidStatement   = errors.New("invalid statement")
+	errSignatureMismatch  = errors.New("no signature verified")
+	errUnsupportedKeyType = errors.New("unsupported key type")
+)
+
+// Envelope is a DSSE envelope, see https://github.com/secure-systems-lab/dsse.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a signature of a DSSE envelope.
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// Signer signs the pre-authentication encoding of DSSE envelopes.
+type Signer interface {
+	// Sign returns the signature of data, and the ID of the key which signed it.
+	Sign(data []byte) (sig []byte, keyID string, err error)
+}
+
+// Verifier verifies the signatures of DSSE envelopes.
+type Verifier interface {
+	Verify(data, sig []byte) error
+}
+
+// PAE is the DSSE pre-authentication encoding of a payload, which is what gets signed.
+func PAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// Sign serializes the Statement and signs it as a DSSE envelope.
+func Sign(statement *Statement, signer Signer) (*Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	sig, keyID, err := signer.Sign(PAE(PayloadType, payload))
+	if err != nil {
+		return nil, fmt.Errorf("error signing the statement: %w", err)
+	}
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []Signature{{
+			KeyID: keyID,
+			Sig:   base64.StdEncoding.EncodeToString(sig),
+		}},
+	}, nil
+}
+
+// Verify checks that a signature of the envelope verifies, and returns its Statement.
+func Verify(envelope *Envelope, verifier Verifier) (*Statement, error) {
+	if envelope.PayloadType != PayloadType {
+		return nil, fmt.Errorf("%w: unexpected payload type %s", errInvalidEnvelope, envelope.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: payload: %v", errInvalidEnvelope, err)
+	}
+
+	pae := PAE(envelope.PayloadType, payload)
+	verified := false
+	for _, signature := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+		if verifier.Verify(pae, sig) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errSignatureMismatch
+	}
+
+	statement := &Statement{}
+	if err := json.Unmarshal(payload, statement); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidStatement, err)
+	}
+	if statement.Type != StatementType {
+		return nil, fmt.Errorf("%w: unexpected type %s", errInvalidStatement, statement.Type)
+	}
+	if statement.PredicateType != PredicateType {
+		return nil, fmt.Errorf("%w: unexpected predicate type %s", errInvalidStatement, statement.PredicateType)
+	}
+	return statement, nil
+}
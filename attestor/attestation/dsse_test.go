@@ -0,0 +1,221 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck
+	"golang.org/x/crypto/openpgp/armor"
+
+	"github.com/ossf/scorecard-attestor/policy"
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+func pkixKeys(t *testing.T) (privateKey, publicKey []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKCS8PrivateKey: %v", err)
+	}
+	pubDer, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKIXPublicKey: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer})
+}
+
+func pgpKeys(t *testing.T) (privateKey, publicKey []byte) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("scorecard", "", "scorecard@example.com", nil)
+	if err != nil {
+		t.Fatalf("openpgp.NewEntity: %v", err)
+	}
+	var priv, pub bytes.Buffer
+	w, err := armor.Encode(&priv, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatalf("armor.Encode: %v", err)
+	}
+	if err := entity.SerializePrivate(w, nil); err != nil {
+		t.Fatalf("SerializePrivate: %v", err)
+	}
+	w.Close()
+	w, err = armor.Encode(&pub, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("armor.Encode: %v", err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatalf("Serialize: %v", err)
+	}
+	w.Close()
+	return priv.Bytes(), pub.Bytes()
+}
+
+func testStatement() *Statement {
+	result := &pkg.ScorecardResult{
+		Repo:      pkg.RepoInfo{Name: "github.com/foo/bar", CommitSHA: "0123456789abcdef"},
+		Date:      time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+		Scorecard: pkg.ScorecardInfo{Version: "v4.8.0", CommitSHA: "fedcba"},
+		Checks:    []checker.CheckResult{{Name: "Binary-Artifacts", Score: 10, Reason: "no binaries found in the repo"}},
+	}
+	report := &policy.Report{
+		Predicates: []policy.PredicateResult{{Name: policy.PredicateBinaryArtifacts, Result: policy.Pass}},
+	}
+	return NewStatement([]Subject{RepoSubject(result)}, result, PolicyDigest([]byte("preventBinaryArtifacts: true")), report)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	t.Parallel()
+
+	pkixPrivateKey, pkixPublicKey := pkixKeys(t)
+	pgpPrivateKey, pgpPublicKey := pgpKeys(t)
+	otherPkixPrivateKey, _ := pkixKeys(t)
+
+	tests := []struct {
+		newSigner   func() (Signer, error)
+		newVerifier func() (Verifier, error)
+		tamper      func(e *Envelope)
+		err         error
+		name        string
+	}{
+		{
+			name:        "pkix",
+			newSigner:   func() (Signer, error) { return NewPKIXSigner(pkixPrivateKey, "ecdsa-p256-sha256") },
+			newVerifier: func() (Verifier, error) { return NewPKIXVerifier(pkixPublicKey, "ecdsa-p256-sha256") },
+		},
+		{
+			name:        "pgp",
+			newSigner:   func() (Signer, error) { return NewPGPSigner(pgpPrivateKey, "") },
+			newVerifier: func() (Verifier, error) { return NewPGPVerifier(pgpPublicKey) },
+		},
+		{
+			name:        "signed with another key",
+			newSigner:   func() (Signer, error) { return NewPKIXSigner(otherPkixPrivateKey, "ecdsa-p256-sha256") },
+			newVerifier: func() (Verifier, error) { return NewPKIXVerifier(pkixPublicKey, "ecdsa-p256-sha256") },
+			err:         errSignatureMismatch,
+		},
+		{
+			name:        "tampered payload",
+			newSigner:   func() (Signer, error) { return NewPGPSigner(pgpPrivateKey, "") },
+			newVerifier: func() (Verifier, error) { return NewPGPVerifier(pgpPublicKey) },
+			tamper: func(e *Envelope) {
+				e.Payload = base64.StdEncoding.EncodeToString([]byte(`{"_type": "tampered"}`))
+			},
+			err: errSignatureMismatch,
+		},
+		{
+			name:        "unexpected payload type",
+			newSigner:   func() (Signer, error) { return NewPKIXSigner(pkixPrivateKey, "ecdsa-p256-sha256") },
+			newVerifier: func() (Verifier, error) { return NewPKIXVerifier(pkixPublicKey, "ecdsa-p256-sha256") },
+			tamper:      func(e *Envelope) { e.PayloadType = "text/plain" },
+			err:         errInvalidEnvelope,
+		},
+	}
+
+	for i := range tests {
+		tt := &tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			signer, err := tt.newSigner()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			verifier, err := tt.newVerifier()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			statement := testStatement()
+			envelope, err := Sign(statement, signer)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			if tt.tamper != nil {
+				tt.tamper(envelope)
+			}
+
+			got, err := Verify(envelope, verifier)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got.Predicate.Repo != statement.Predicate.Repo ||
+				got.Predicate.Policy.Digest[digestSHA256] != statement.Predicate.Policy.Digest[digestSHA256] {
+				t.Fatalf("unexpected statement %+v", got)
+			}
+		})
+	}
+}
+
+func TestImageSubject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		image    string
+		expected Subject
+		err      error
+	}{
+		{
+			name:     "image with digest",
+			image:    "gcr.io/foo/bar@sha256:abcd",
+			expected: Subject{Name: "gcr.io/foo/bar", Digest: map[string]string{"sha256": "abcd"}},
+		},
+		{
+			name:  "image with tag",
+			image: "gcr.io/foo/bar:latest",
+			err:   errInvalidSubject,
+		},
+		{
+			name:  "invalid digest",
+			image: "gcr.io/foo/bar@abcd",
+			err:   errInvalidSubject,
+		},
+	}
+
+	for i := range tests {
+		tt := &tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ImageSubject(tt.image)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got.Name != tt.expected.Name || got.Digest["sha256"] != tt.expected.Digest["sha256"] {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
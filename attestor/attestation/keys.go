@@ -0,0 +1,183 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/grafeas/kritis/pkg/attestlib"
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // Used by kritis for PGP keys.
+)
+
+type pkixSigner struct {
+	signer attestlib.Signer
+}
+
+// NewPKIXSigner creates a Signer for a PEM-encoded private key, and a signature
+// algorithm, e.g. ecdsa-p256-sha256.
+func NewPKIXSigner(privateKey []byte, alg string) (Signer, error) {
+	sAlg := attestlib.ParseSignatureAlgorithm(alg)
+	if sAlg == attestlib.UnknownSigningAlgorithm {
+		return nil, fmt.Errorf("empty or unknown PKIX signature algorithm: %s", alg)
+	}
+	signer, err := attestlib.NewPkixSigner(privateKey, sAlg, "")
+	if err != nil {
+		return nil, fmt.Errorf("creating pkix signer failed: %w", err)
+	}
+	return &pkixSigner{signer: signer}, nil
+}
+
+func (s *pkixSigner) Sign(data []byte) ([]byte, string, error) {
+	att, err := s.signer.CreateAttestation(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("CreateAttestation: %w", err)
+	}
+	return att.Signature, att.PublicKeyID, nil
+}
+
+type pkixVerifier struct {
+	publicKey crypto.PublicKey
+	hash      crypto.Hash
+	pss       bool
+}
+
+// NewPKIXVerifier creates a Verifier for a PEM-encoded public key, and the signature
+// algorithm of its private key.
+func NewPKIXVerifier(publicKey []byte, alg string) (Verifier, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, fmt.Errorf("%w: failed to decode PEM", errUnsupportedKeyType)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("x509.ParsePKIXPublicKey: %w", err)
+	}
+
+	v := &pkixVerifier{publicKey: key}
+	switch attestlib.ParseSignatureAlgorithm(alg) {
+	case attestlib.RsaPss2048Sha256, attestlib.RsaPss3072Sha256, attestlib.RsaPss4096Sha256:
+		v.hash, v.pss = crypto.SHA256, true
+	case attestlib.RsaPss4096Sha512:
+		v.hash, v.pss = crypto.SHA512, true
+	case attestlib.RsaSignPkcs12048Sha256, attestlib.RsaSignPkcs13072Sha256, attestlib.RsaSignPkcs14096Sha256,
+		attestlib.EcdsaP256Sha256:
+		v.hash = crypto.SHA256
+	case attestlib.EcdsaP384Sha384:
+		v.hash = crypto.SHA384
+	case attestlib.RsaSignPkcs14096Sha512, attestlib.EcdsaP521Sha512:
+		v.hash = crypto.SHA512
+	default:
+		return nil, fmt.Errorf("empty or unknown PKIX signature algorithm: %s", alg)
+	}
+	return v, nil
+}
+
+func (v *pkixVerifier) Verify(data, sig []byte) error {
+	var digest []byte
+	switch v.hash {
+	case crypto.SHA256:
+		sum := sha256.Sum256(data)
+		digest = sum[:]
+	case crypto.SHA384:
+		sum := sha512.Sum384(data)
+		digest = sum[:]
+	default:
+		sum := sha512.Sum512(data)
+		digest = sum[:]
+	}
+
+	switch key := v.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest, sig) {
+			return errSignatureMismatch
+		}
+		return nil
+	case *rsa.PublicKey:
+		if v.pss {
+			//nolint:wrapcheck
+			return rsa.VerifyPSS(key, v.hash, digest, sig, nil)
+		}
+		//nolint:wrapcheck
+		return rsa.VerifyPKCS1v15(key, v.hash, digest, sig)
+	default:
+		return fmt.Errorf("%w: %T", errUnsupportedKeyType, key)
+	}
+}
+
+type pgpSigner struct {
+	entity *openpgp.Entity
+}
+
+// NewPGPSigner creates a Signer for an ASCII-armored PGP private key, and its passphrase if any.
+// DSSE signatures are detached, unlike those of the PGP attestations of Container Analysis.
+func NewPGPSigner(privateKey []byte, passphrase string) (Signer, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("error reading armored private key: %w", err)
+	}
+	if len(keyring) != 1 {
+		return nil, fmt.Errorf("%w: expected 1 key in keyring, got %d", errUnsupportedKeyType, len(keyring))
+	}
+	entity := keyring[0]
+	if entity.PrivateKey == nil {
+		return nil, fmt.Errorf("%w: not a private key", errUnsupportedKeyType)
+	}
+	if entity.PrivateKey.Encrypted {
+		if passphrase == "" {
+			return nil, errors.New("missing passphrase for encrypted private key")
+		}
+		if err := entity.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("could not decrypt private key: %w", err)
+		}
+	}
+	return &pgpSigner{entity: entity}, nil
+}
+
+func (s *pgpSigner) Sign(data []byte) ([]byte, string, error) {
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, s.entity, bytes.NewReader(data), nil); err != nil {
+		return nil, "", fmt.Errorf("openpgp.ArmoredDetachSign: %w", err)
+	}
+	return sig.Bytes(), fmt.Sprintf("%X", s.entity.PrimaryKey.Fingerprint), nil
+}
+
+type pgpVerifier struct {
+	keyring openpgp.EntityList
+}
+
+// NewPGPVerifier creates a Verifier for an ASCII-armored PGP public key.
+func NewPGPVerifier(publicKey []byte) (Verifier, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(publicKey))
+	if err != nil {
+		return nil, fmt.Errorf("error reading armored public key: %w", err)
+	}
+	return &pgpVerifier{keyring: keyring}, nil
+}
+
+func (v *pgpVerifier) Verify(data, sig []byte) error {
+	if _, err := openpgp.CheckArmoredDetachedSignature(v.keyring, bytes.NewReader(data), bytes.NewReader(sig)); err != nil {
+		return fmt.Errorf("openpgp.CheckArmoredDetachedSignature: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package attestation creates and verifies in-toto attestations of the
+// Scorecard results of a repo, signed as DSSE envelopes.
+package attestation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ossf/scorecard-attestor/policy"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+const (
+	// StatementType is the type of in-toto Statements.
+	StatementType = "https://in-toto.io/Statement/v0.1"
+	// PredicateType is the type of the Scorecard predicate.
+	PredicateType = "https://github.com/ossf/scorecard-attestor/v0.1"
+	// digestGitCommit is the digest algorithm of git commits in in-toto digest sets.
+	digestGitCommit = "gitCommit"
+	digestSHA256    = "sha256"
+)
+
+// Statement is an in-toto Statement with a Scorecard predicate.
+type Statement struct {
+	Type          string    `json:"_type"`
+	PredicateType string    `json:"predicateType"`
+	Subject       []Subject `json:"subject"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is an artifact the Statement applies to.
+type Subject struct {
+	Digest map[string]string `json:"digest"`
+	Name   string            `json:"name"`
+}
+
+// Predicate records the Scorecard results of a repo, and the policy they passed.
+type Predicate struct {
+	Date      string          `json:"date"`
+	Repo      RepoInfo        `json:"repo"`
+	Scorecard ScorecardInfo   `json:"scorecard"`
+	Checks    []CheckResult   `json:"checks"`
+	Policy    PolicyEvaluated `json:"policy"`
+}
+
+// RepoInfo identifies the commit the checks ran on.
+type RepoInfo struct {
+	Name   string `json:"name"`
+	Commit string `json:"commit"`
+}
+
+// ScorecardInfo identifies the Scorecard version which ran the checks.
+type ScorecardInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// CheckResult is the result of a Scorecard check.
+type CheckResult struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Score  int    `json:"score"`
+}
+
+// PolicyEvaluated identifies the policy and the outcome of each of its predicates.
+type PolicyEvaluated struct {
+	Digest     map[string]string `json:"digest"`
+	Predicates []PolicyPredicate `json:"predicates"`
+	Pass       bool              `json:"pass"`
+}
+
+// PolicyPredicate is the outcome of a predicate of the policy.
+type PolicyPredicate struct {
+	Name string `json:"name"`
+	Pass bool   `json:"pass"`
+}
+
+// RepoSubject is the subject for the repo itself, at the commit the checks ran on.
+func RepoSubject(result *pkg.ScorecardResult) Subject {
+	return Subject{
+		Name:   result.Repo.Name,
+		Digest: map[string]string{digestGitCommit: result.Repo.CommitSHA},
+	}
+}
+
+// ImageSubject is the subject for a container image, e.g. gcr.io/foo/bar@sha256:abcd.
+func ImageSubject(image string) (Subject, error) {
+	name, digest, found := strings.Cut(image, "@")
+	if !found {
+		return Subject{}, fmt.Errorf("%w: image %s has no digest", errInvalidSubject, image)
+	}
+	alg, value, found := strings.Cut(digest, ":")
+	if !found || alg == "" || value == "" {
+		return Subject{}, fmt.Errorf("%w: invalid digest %s", errInvalidSubject, digest)
+	}
+	return Subject{Name: name, Digest: map[string]string{alg: value}}, nil
+}
+
+// PolicyDigest is the digest set of a policy file's content.
+func PolicyDigest(content []byte) map[string]string {
+	sum := sha256.Sum256(content)
+	return map[string]string{digestSHA256: hex.EncodeToString(sum[:])}
+}
+
+// NewStatement creates a Statement for the Scorecard results which passed the policy.
+func NewStatement(
+	subjects []Subject,
+	result *pkg.ScorecardResult,
+	policyDigest map[string]string,
+	report *policy.Report,
+) *Statement {
+	predicate := Predicate{
+		Date: result.Date.UTC().Format(time.RFC3339),
+		Repo: RepoInfo{
+			Name:   result.Repo.Name,
+			Commit: result.Repo.CommitSHA,
+		},
+		Scorecard: ScorecardInfo{
+			Version: result.Scorecard.Version,
+			Commit:  result.Scorecard.CommitSHA,
+		},
+		Policy: PolicyEvaluated{
+			Digest: policyDigest,
+			Pass:   report.Result() == policy.Pass,
+		},
+	}
+	for i := range result.Checks {
+		predicate.Checks = append(predicate.Checks, CheckResult{
+			Name:   result.Checks[i].Name,
+			Reason: result.Checks[i].Reason,
+			Score:  result.Checks[i].Score,
+		})
+	}
+	for i := range report.Predicates {
+		predicate.Policy.Predicates = append(predicate.Policy.Predicates, PolicyPredicate{
+			Name: report.Predicates[i].Name,
+			Pass: report.Predicates[i].Result == policy.Pass,
+		})
+	}
+
+	return &Statement{
+		Type:          StatementType,
+		PredicateType: PredicateType,
+		Subject:       subjects,
+		Predicate:     predicate,
+	}
+}
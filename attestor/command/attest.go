@@ -0,0 +1,88 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ossf/scorecard-attestor/attestation"
+	"github.com/ossf/scorecard-attestor/policy"
+	sclog "github.com/ossf/scorecard/v4/log"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// runAttest writes an in-toto attestation of the results, signed with a local key,
+// instead of a Container Analysis attestation.
+func runAttest(repoResult *pkg.ScorecardResult, report *policy.Report) error {
+	logger := sclog.NewLogger(sclog.DefaultLevel)
+
+	var signer attestation.Signer
+	switch {
+	case pkixPriKeyPath != "":
+		logger.Info("Using pkix key for signing.")
+		signerKey, err := os.ReadFile(pkixPriKeyPath)
+		if err != nil {
+			return fmt.Errorf("fail to read signer key: %w", err)
+		}
+		signer, err = attestation.NewPKIXSigner(signerKey, pkixAlg)
+		if err != nil {
+			return fmt.Errorf("creating pkix signer failed: %w", err)
+		}
+	case pgpPriKeyPath != "":
+		logger.Info("Using pgp key for signing.")
+		signerKey, err := os.ReadFile(pgpPriKeyPath)
+		if err != nil {
+			return fmt.Errorf("fail to read signer key: %w", err)
+		}
+		signer, err = attestation.NewPGPSigner(signerKey, pgpPassphrase)
+		if err != nil {
+			return fmt.Errorf("creating pgp signer failed: %w", err)
+		}
+	default:
+		return fmt.Errorf("neither pgp_private_key or pkix_private_key is specified")
+	}
+
+	policyContent, err := os.ReadFile(policyPath)
+	if err != nil {
+		return fmt.Errorf("fail to read scorecard attestation policy: %w", err)
+	}
+
+	// The image is the subject when there's one, the repo's commit otherwise.
+	subject := attestation.RepoSubject(repoResult)
+	if image != "" {
+		subject, err = attestation.ImageSubject(image)
+		if err != nil {
+			return fmt.Errorf("image is invalid: %w", err)
+		}
+	}
+
+	statement := attestation.NewStatement(
+		[]attestation.Subject{subject}, repoResult, attestation.PolicyDigest(policyContent), report)
+	envelope, err := attestation.Sign(statement, signer)
+	if err != nil {
+		return fmt.Errorf("signing attestation failed: %w", err)
+	}
+	content, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent: %w", err)
+	}
+	if err := os.WriteFile(outputPath, content, 0o600); err != nil {
+		return fmt.Errorf("fail to write attestation: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Wrote attestation to %s", outputPath))
+	return nil
+}
@@ -17,6 +17,7 @@ package command
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/ossf/scorecard-attestor/policy"
@@ -34,26 +35,27 @@ func (ep EmptyParameterError) Error() string {
 	return fmt.Sprintf("param %s is empty", ep.Param)
 }
 
-func runCheck() (policy.PolicyResult, error) {
+// runCheck runs the checks required by the policy, and writes the policy report.
+func runCheck(w io.Writer) (*pkg.ScorecardResult, *policy.Report, error) {
 	ctx := context.Background()
 	logger := sclog.NewLogger(sclog.DefaultLevel)
 
 	// Read the Binauthz attestation policy
 	if policyPath == "" {
-		return policy.Fail, EmptyParameterError{Param: "policy"}
+		return nil, nil, EmptyParameterError{Param: "policy"}
 	}
 
 	var attestationPolicy *policy.AttestationPolicy
 
 	attestationPolicy, err := policy.ParseAttestationPolicyFromFile(policyPath)
 	if err != nil {
-		return policy.Fail, fmt.Errorf("fail to load scorecard attestation policy: %w", err)
+		return nil, nil, fmt.Errorf("fail to load scorecard attestation policy: %w", err)
 	}
 
 	if repoURL == "" {
 		buildRepo := os.Getenv("REPO_NAME")
 		if buildRepo == "" {
-			return policy.Fail, EmptyParameterError{Param: "repoURL"}
+			return nil, nil, EmptyParameterError{Param: "repoURL"}
 		}
 		repoURL = buildRepo
 		logger.Info(fmt.Sprintf("Found repo URL %s Cloud Build environment", repoURL))
@@ -75,22 +77,20 @@ func runCheck() (policy.PolicyResult, error) {
 	repo, repoClient, ossFuzzRepoClient, ciiClient, vulnsClient, err := checker.GetClients(
 		ctx, repoURL, "", logger)
 	if err != nil {
-		return policy.Fail, fmt.Errorf("couldn't set up clients: %w", err)
+		return nil, nil, fmt.Errorf("couldn't set up clients: %w", err)
 	}
 
 	requiredChecks := attestationPolicy.GetRequiredChecksForPolicy()
 
-	enabledChecks := map[string]checker.Check{
-		"BinaryArtifacts": {
-			Fn: checks.BinaryArtifacts,
-		},
-	}
-
-	// Filter out checks that won't be needed for policy-evaluation time
-	for name := range enabledChecks {
-		if _, isRequired := requiredChecks[name]; !isRequired {
-			delete(enabledChecks, name)
+	// Only run the checks needed at policy-evaluation time
+	allChecks := checks.GetAllWithExperimental()
+	enabledChecks := checker.CheckNameToFnMap{}
+	for name := range requiredChecks {
+		check, ok := allChecks[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("unsupported check %s required by the policy", name)
 		}
+		enabledChecks[name] = check
 	}
 
 	repoResult, err := pkg.RunScorecards(
@@ -104,17 +104,20 @@ func runCheck() (policy.PolicyResult, error) {
 		vulnsClient,
 	)
 	if err != nil {
-		return policy.Fail, fmt.Errorf("RunScorecards: %w", err)
+		return nil, nil, fmt.Errorf("RunScorecards: %w", err)
 	}
 
-	result, err := attestationPolicy.EvaluateResults(&repoResult.RawResults)
+	report, err := attestationPolicy.EvaluateResults(&repoResult)
 	if err != nil {
-		return policy.Fail, fmt.Errorf("error when evaluating image %q against policy: %w", image, err)
+		return nil, nil, fmt.Errorf("error when evaluating image %q against policy: %w", image, err)
+	}
+	if err := report.Write(w); err != nil {
+		return nil, nil, fmt.Errorf("error when writing the policy report: %w", err)
 	}
-	if result != policy.Pass {
+	if report.Result() != policy.Pass {
 		logger.Info("image failed scorecard attestation policy check")
 	} else {
 		logger.Info("image passed scorecard attestation policy check")
 	}
-	return result, nil
+	return &repoResult, report, nil
 }
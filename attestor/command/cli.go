@@ -21,6 +21,8 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ossf/scorecard-attestor/policy"
 )
 
 var (
@@ -42,6 +44,12 @@ var (
 	// input flags: kms flags
 	kmsKeyName   string
 	kmsDigestAlg string
+
+	// input flags: in-toto attestation flags
+	outputPath      string
+	attestationPath string
+	pgpPubKeyPath   string
+	pkixPubKeyPath  string
 )
 
 func addCheckFlags(cmd *cobra.Command) {
@@ -53,8 +61,8 @@ func addCheckFlags(cmd *cobra.Command) {
 }
 
 func addSignFlags(cmd *cobra.Command) {
-	cmd.PersistentFlags().StringVar(&image, "image", "", "Image url, e.g., gcr.io/foo/bar@sha256:abcd")
-	cmd.MarkPersistentFlagRequired("image")
+	cmd.PersistentFlags().StringVar(&image, "image", "", "(required without --output) Image url, e.g., gcr.io/foo/bar@sha256:abcd")
+	cmd.PersistentFlags().StringVar(&outputPath, "output", "", "write a signed in-toto attestation to this path instead of Container Analysis, e.g., /tmp/scorecard.intoto.json")
 	cmd.PersistentFlags().StringVar(&attestationProject, "attestation-project", "", "project id for GCP project that stores attestation, use image project if set to empty")
 	cmd.PersistentFlags().BoolVar(&overwrite, "overwrite", false, "overwrite attestation if already existed (default false)")
 	cmd.PersistentFlags().StringVar(&kmsKeyName, "kms-key-name", "", "kms key name, in the format of in the format projects/*/locations/*/keyRings/*/cryptoKeys/*/cryptoKeyVersions/*")
@@ -66,6 +74,18 @@ func addSignFlags(cmd *cobra.Command) {
 
 }
 
+func addVerifyAttestationFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&attestationPath, "attestation", "", "(required for verify-attestation) in-toto attestation file path, e.g., /tmp/scorecard.intoto.json")
+	cmd.MarkPersistentFlagRequired("attestation")
+	cmd.PersistentFlags().StringVar(&pgpPubKeyPath, "pgp-public-key", "", "pgp public key path, e.g., /tmp/key.pub.pgp")
+	cmd.PersistentFlags().StringVar(&pkixPubKeyPath, "pkix-public-key", "", "pkix public key path, e.g., /tmp/key.pub.pem")
+	cmd.PersistentFlags().StringVar(&pkixAlg, "pkix-alg", "", "pkix signature algorithm, e.g., ecdsa-p256-sha256")
+	cmd.PersistentFlags().StringVar(&repoURL, "repo-url", "", "expected repo URL of the attestation, if any")
+	cmd.PersistentFlags().StringVar(&commitSHA, "commit", "", "expected Git SHA of the attestation, if any")
+	cmd.PersistentFlags().StringVar(&policyPath, "policy", "", "expected scorecard attestation policy file path of the attestation, if any")
+	cmd.PersistentFlags().StringVar(&image, "image", "", "expected image of the attestation, if any, e.g., gcr.io/foo/bar@sha256:abcd")
+}
+
 // Export for testability
 var RootCmd = &cobra.Command{
 	Use:   "scorecard-attestor",
@@ -76,17 +96,20 @@ var checkAndSignCmd = &cobra.Command{
 	Use:   "attest",
 	Short: "Run scorecard and sign a container image if attestation policy check passes",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		passed, err := runCheck()
+		repoResult, report, err := runCheck(cmd.OutOrStdout())
 
 		if err != nil {
 			return err
 		}
 
-		if passed {
-			return runSign()
+		if report.Result() != policy.Pass {
+			return nil
 		}
 
-		return nil
+		if outputPath != "" {
+			return runAttest(repoResult, report)
+		}
+		return runSign()
 	},
 	SilenceUsage: true,
 }
@@ -95,19 +118,30 @@ var checkCmd = &cobra.Command{
 	Use:   "verify",
 	Short: "Run scorecard and check an image against a policy",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		_, err := runCheck()
+		_, _, err := runCheck(cmd.OutOrStdout())
 		return err
 	},
 	SilenceUsage: true,
 }
 
+var verifyAttestationCmd = &cobra.Command{
+	Use:   "verify-attestation",
+	Short: "Verify an in-toto attestation written by attest --output",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVerifyAttestation(cmd.OutOrStdout())
+	},
+	SilenceUsage: true,
+}
+
 func init() {
-	RootCmd.AddCommand(checkCmd, checkAndSignCmd)
+	RootCmd.AddCommand(checkCmd, checkAndSignCmd, verifyAttestationCmd)
 
 	addCheckFlags(checkAndSignCmd)
 	addSignFlags(checkAndSignCmd)
 
 	addCheckFlags(checkCmd)
+
+	addVerifyAttestationFlags(verifyAttestationCmd)
 }
 
 func Execute() {
@@ -31,6 +31,10 @@ const scorecardNoteID = "ossf-scorecard-attestation"
 func runSign() error {
 	logger := sclog.NewLogger(sclog.DefaultLevel)
 
+	if image == "" {
+		return EmptyParameterError{Param: "image"}
+	}
+
 	// Create a client
 	client, err := containeranalysis.New()
 	if err != nil {
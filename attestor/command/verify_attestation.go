@@ -0,0 +1,139 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/ossf/scorecard-attestor/attestation"
+)
+
+var errVerification = errors.New("attestation verification failed")
+
+// runVerifyAttestation verifies an attestation offline, and checks it was issued for the
+// expected repo, commit, policy and image when they're specified.
+func runVerifyAttestation(w io.Writer) error {
+	if attestationPath == "" {
+		return EmptyParameterError{Param: "attestation"}
+	}
+
+	var verifier attestation.Verifier
+	switch {
+	case pkixPubKeyPath != "":
+		key, err := os.ReadFile(pkixPubKeyPath)
+		if err != nil {
+			return fmt.Errorf("fail to read public key: %w", err)
+		}
+		verifier, err = attestation.NewPKIXVerifier(key, pkixAlg)
+		if err != nil {
+			return fmt.Errorf("creating pkix verifier failed: %w", err)
+		}
+	case pgpPubKeyPath != "":
+		key, err := os.ReadFile(pgpPubKeyPath)
+		if err != nil {
+			return fmt.Errorf("fail to read public key: %w", err)
+		}
+		verifier, err = attestation.NewPGPVerifier(key)
+		if err != nil {
+			return fmt.Errorf("creating pgp verifier failed: %w", err)
+		}
+	default:
+		return fmt.Errorf("neither pgp_public_key or pkix_public_key is specified")
+	}
+
+	content, err := os.ReadFile(attestationPath)
+	if err != nil {
+		return fmt.Errorf("fail to read attestation: %w", err)
+	}
+	envelope := &attestation.Envelope{}
+	if err := json.Unmarshal(content, envelope); err != nil {
+		return fmt.Errorf("%w: %v", errVerification, err)
+	}
+	statement, err := attestation.Verify(envelope, verifier)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errVerification, err)
+	}
+	if err := checkStatement(statement); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	predicate := &statement.Predicate
+	sb.WriteString(fmt.Sprintf("Verified attestation of %s at %s\n", predicate.Repo.Name, predicate.Repo.Commit))
+	for _, p := range predicate.Policy.Predicates {
+		result := "PASS"
+		if !p.Pass {
+			result = "FAIL"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s\n", result, p.Name))
+	}
+	for _, c := range predicate.Checks {
+		sb.WriteString(fmt.Sprintf("%s: %d\n", c.Name, c.Score))
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("io.WriteString: %w", err)
+	}
+	return nil
+}
+
+func checkStatement(statement *attestation.Statement) error {
+	predicate := &statement.Predicate
+	if !predicate.Policy.Pass {
+		return fmt.Errorf("%w: the results failed the policy", errVerification)
+	}
+	if repoURL != "" && normalizeRepo(repoURL) != normalizeRepo(predicate.Repo.Name) {
+		return fmt.Errorf("%w: attestation is for repo %s", errVerification, predicate.Repo.Name)
+	}
+	if commitSHA != "" && commitSHA != predicate.Repo.Commit {
+		return fmt.Errorf("%w: attestation is for commit %s", errVerification, predicate.Repo.Commit)
+	}
+	if policyPath != "" {
+		policyContent, err := os.ReadFile(policyPath)
+		if err != nil {
+			return fmt.Errorf("fail to read scorecard attestation policy: %w", err)
+		}
+		if !reflect.DeepEqual(attestation.PolicyDigest(policyContent), predicate.Policy.Digest) {
+			return fmt.Errorf("%w: attestation is for another policy", errVerification)
+		}
+	}
+	if image != "" {
+		want, err := attestation.ImageSubject(image)
+		if err != nil {
+			return fmt.Errorf("image is invalid: %w", err)
+		}
+		found := false
+		for _, subject := range statement.Subject {
+			if reflect.DeepEqual(subject, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: attestation is not for image %s", errVerification, image)
+		}
+	}
+	return nil
+}
+
+func normalizeRepo(repo string) string {
+	repo = strings.TrimPrefix(strings.TrimPrefix(repo, "https://"), "http://")
+	return strings.TrimSuffix(strings.TrimSuffix(repo, "/"), ".git")
+}
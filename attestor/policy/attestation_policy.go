@@ -17,6 +17,7 @@ package policy
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/gobwas/glob"
@@ -24,7 +25,30 @@ import (
 
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/checks"
+	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// Names of the predicates of an AttestationPolicy, as they appear in policy files.
+const (
+	PredicateBinaryArtifacts      = "preventBinaryArtifacts"
+	PredicateUnpinnedDependencies = "preventUnpinnedDependencies"
+	PredicateKnownVulnerabilities = "preventKnownVulnerabilities"
+	PredicateCodeReviewed         = "ensureCodeReviewed"
+	PredicateBranchProtected      = "ensureBranchProtected"
+	PredicateTokenPermissions     = "ensureTokenPermissionsRestricted"
+	PredicateDangerousWorkflows   = "preventDangerousWorkflows"
+	PredicateReleasesSigned       = "ensureReleasesSigned"
+	PredicateMinimumCheckScores   = "minimumCheckScores"
+)
+
+// defaultReleasesToCheck matches the look back of the Signed-Releases check.
+const defaultReleasesToCheck = 5
+
+var (
+	signatureExtensions  = []string{".asc", ".minisig", ".sig", ".sign"}
+	provenanceExtensions = []string{".intoto.jsonl"}
 )
 
 //nolint:govet
@@ -56,6 +80,38 @@ type AttestationPolicy struct {
 	// CodeReviewRequirements : define specific code review requirements that the default
 	// branch must have met, e.g. required approvers
 	CodeReviewRequirements CodeReviewRequirements `yaml:"codeReviewRequirements"`
+
+	// EnsureBranchProtected : set to true to require that the default and release branches
+	// are protected, and don't allow force pushes or deletions
+	EnsureBranchProtected bool `yaml:"ensureBranchProtected"`
+
+	// BranchProtectionRequirements : define further settings the protected branches
+	// must enable, e.g. a number of approving reviews
+	BranchProtectionRequirements BranchProtectionRequirements `yaml:"branchProtectionRequirements"`
+
+	// EnsureTokenPermissionsRestricted : set to true to require that the workflows declare
+	// their top-level token permissions and don't grant write permissions
+	EnsureTokenPermissionsRestricted bool `yaml:"ensureTokenPermissionsRestricted"`
+
+	// AllowedWritePermissions : list of token permissions, e.g. packages, the workflows
+	// may grant write access to
+	AllowedWritePermissions []string `yaml:"allowedWritePermissions"`
+
+	// PreventDangerousWorkflows : set to true to require that the workflows are free of
+	// dangerous patterns, e.g. untrusted checkouts and script injections
+	PreventDangerousWorkflows bool `yaml:"preventDangerousWorkflows"`
+
+	// EnsureReleasesSigned : set to true to require that the recent releases of this project
+	// are signed
+	EnsureReleasesSigned bool `yaml:"ensureReleasesSigned"`
+
+	// SignedReleasesRequirements : define specific requirements for the releases,
+	// e.g. provenance
+	SignedReleasesRequirements SignedReleasesRequirements `yaml:"signedReleasesRequirements"`
+
+	// MinimumCheckScores : minimum score of Scorecard checks, keyed by check name,
+	// e.g. Maintained: 5
+	MinimumCheckScores map[string]int `yaml:"minimumCheckScores"`
 }
 
 type CodeReviewRequirements struct {
@@ -63,6 +119,21 @@ type CodeReviewRequirements struct {
 	MinReviewers      int      `yaml:"minReviewers"`
 }
 
+type BranchProtectionRequirements struct {
+	MinApprovingReviews     int  `yaml:"minApprovingReviews"`
+	RequireCodeOwnerReviews bool `yaml:"requireCodeOwnerReviews"`
+	DismissStaleReviews     bool `yaml:"dismissStaleReviews"`
+	RequireStatusChecks     bool `yaml:"requireStatusChecks"`
+	EnforceAdmins           bool `yaml:"enforceAdmins"`
+}
+
+type SignedReleasesRequirements struct {
+	// RequireProvenance requires SLSA provenance rather than a signature.
+	RequireProvenance bool `yaml:"requireProvenance"`
+	// Releases is the number of recent releases to check, 5 by default.
+	Releases int `yaml:"releases"`
+}
+
 type Dependency struct {
 	Filepath    string `yaml:"filepath"`
 	PackageName string `yaml:"packagename"`
@@ -90,30 +161,69 @@ func (ap *AttestationPolicy) GetRequiredChecksForPolicy() map[string]bool {
 		requiredChecks[checks.CheckPinnedDependencies] = true
 	}
 
+	if ap.EnsureBranchProtected {
+		requiredChecks[checks.CheckBranchProtection] = true
+	}
+
+	if ap.EnsureTokenPermissionsRestricted {
+		requiredChecks[checks.CheckTokenPermissions] = true
+	}
+
+	if ap.PreventDangerousWorkflows {
+		requiredChecks[checks.CheckDangerousWorkflow] = true
+	}
+
+	if ap.EnsureReleasesSigned {
+		requiredChecks[checks.CheckSignedReleases] = true
+	}
+
+	for name := range ap.MinimumCheckScores {
+		requiredChecks[name] = true
+	}
+
 	return requiredChecks
 }
 
-// Run attestation policy checks on raw data.
-func (ap *AttestationPolicy) EvaluateResults(raw *checker.RawResults) (PolicyResult, error) {
-	dl := checker.NewLogger()
+// EvaluateResults runs every predicate of the policy on the Scorecard results,
+// and reports the outcome of each of them.
+func (ap *AttestationPolicy) EvaluateResults(result *pkg.ScorecardResult) (*Report, error) {
+	raw := &result.RawResults
+	report := &Report{}
+	evaluate := func(name string, predicate func(dl checker.DetailLogger) (PolicyResult, error)) error {
+		dl := checker.NewLogger()
+		checkResult, err := predicate(dl)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		report.Predicates = append(report.Predicates, PredicateResult{
+			Name:    name,
+			Result:  checkResult,
+			Details: dl.Flush(),
+		})
+		return nil
+	}
+
 	if ap.PreventBinaryArtifacts {
-		checkResult, err := CheckPreventBinaryArtifacts(ap.AllowedBinaryArtifacts, raw, dl)
-		if !checkResult || err != nil {
-			return checkResult, err
+		if err := evaluate(PredicateBinaryArtifacts, func(dl checker.DetailLogger) (PolicyResult, error) {
+			return CheckPreventBinaryArtifacts(ap.AllowedBinaryArtifacts, raw, dl)
+		}); err != nil {
+			return nil, err
 		}
 	}
 
 	if ap.PreventUnpinnedDependencies {
-		checkResult, err := CheckNoUnpinnedDependencies(ap.AllowedUnpinnedDependencies, raw, dl)
-		if !checkResult || err != nil {
-			return checkResult, err
+		if err := evaluate(PredicateUnpinnedDependencies, func(dl checker.DetailLogger) (PolicyResult, error) {
+			return CheckNoUnpinnedDependencies(ap.AllowedUnpinnedDependencies, raw, dl)
+		}); err != nil {
+			return nil, err
 		}
 	}
 
 	if ap.PreventKnownVulnerabilities {
-		checkResult, err := CheckNoVulnerabilities(raw, dl)
-		if !checkResult || err != nil {
-			return checkResult, err
+		if err := evaluate(PredicateKnownVulnerabilities, func(dl checker.DetailLogger) (PolicyResult, error) {
+			return CheckNoVulnerabilities(raw, dl)
+		}); err != nil {
+			return nil, err
 		}
 	}
 
@@ -125,13 +235,54 @@ func (ap *AttestationPolicy) EvaluateResults(raw *checker.RawResults) (PolicyRes
 			ap.CodeReviewRequirements.MinReviewers = 1
 		}
 
-		checkResult, err := CheckCodeReviewed(ap.CodeReviewRequirements, raw, dl)
-		if !checkResult || err != nil {
-			return checkResult, err
+		if err := evaluate(PredicateCodeReviewed, func(dl checker.DetailLogger) (PolicyResult, error) {
+			return CheckCodeReviewed(ap.CodeReviewRequirements, raw, dl)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if ap.EnsureBranchProtected {
+		if err := evaluate(PredicateBranchProtected, func(dl checker.DetailLogger) (PolicyResult, error) {
+			return CheckBranchProtected(ap.BranchProtectionRequirements, raw, dl)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if ap.EnsureTokenPermissionsRestricted {
+		if err := evaluate(PredicateTokenPermissions, func(dl checker.DetailLogger) (PolicyResult, error) {
+			return CheckTokenPermissionsRestricted(ap.AllowedWritePermissions, raw, dl)
+		}); err != nil {
+			return nil, err
 		}
 	}
 
-	return Pass, nil
+	if ap.PreventDangerousWorkflows {
+		if err := evaluate(PredicateDangerousWorkflows, func(dl checker.DetailLogger) (PolicyResult, error) {
+			return CheckNoDangerousWorkflows(raw, dl)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if ap.EnsureReleasesSigned {
+		if err := evaluate(PredicateReleasesSigned, func(dl checker.DetailLogger) (PolicyResult, error) {
+			return CheckReleasesSigned(ap.SignedReleasesRequirements, raw, dl)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ap.MinimumCheckScores) > 0 {
+		if err := evaluate(PredicateMinimumCheckScores, func(dl checker.DetailLogger) (PolicyResult, error) {
+			return CheckMinimumScores(ap.MinimumCheckScores, result.Checks, dl)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
 }
 
 type PolicyResult = bool
@@ -146,6 +297,7 @@ func CheckPreventBinaryArtifacts(
 	results *checker.RawResults,
 	dl checker.DetailLogger,
 ) (PolicyResult, error) {
+	result := Pass
 	for i := range results.BinaryArtifactResults.Files {
 		artifactFile := results.BinaryArtifactResults.Files[i]
 
@@ -170,12 +322,14 @@ func CheckPreventBinaryArtifacts(
 				Offset: artifactFile.Offset,
 				Text:   "binary detected",
 			})
-			return Fail, nil
+			result = Fail
 		}
 	}
 
-	dl.Info(&checker.LogMessage{Text: "repo was free of binary artifacts"})
-	return Pass, nil
+	if result == Pass {
+		dl.Info(&checker.LogMessage{Text: "repo was free of binary artifacts"})
+	}
+	return result, nil
 }
 
 func CheckNoVulnerabilities(results *checker.RawResults, dl checker.DetailLogger) (PolicyResult, error) {
@@ -199,6 +353,7 @@ func CheckCodeReviewed(
 	results *checker.RawResults,
 	dl checker.DetailLogger,
 ) (PolicyResult, error) {
+	result := Pass
 	for _, changeset := range results.CodeReviewResults.DefaultBranchChangesets {
 		numApprovers := 0
 		approvals := make(map[string]bool)
@@ -239,7 +394,8 @@ func CheckCodeReviewed(
 					),
 				},
 			)
-			return Fail, nil
+			result = Fail
+			continue
 		}
 
 		missingApprovers := false
@@ -259,11 +415,14 @@ func CheckCodeReviewed(
 		}
 
 		if missingApprovers {
-			return Fail, nil
+			result = Fail
 		}
 	}
 
-	return Pass, nil
+	if result == Pass {
+		dl.Info(&checker.LogMessage{Text: "recent changesets met the code review requirements"})
+	}
+	return result, nil
 }
 
 func CheckNoUnpinnedDependencies(
@@ -271,16 +430,19 @@ func CheckNoUnpinnedDependencies(
 	results *checker.RawResults,
 	dl checker.DetailLogger,
 ) (PolicyResult, error) {
+	result := Pass
 	for i := range results.PinningDependenciesResults.Dependencies {
 		dep := results.PinningDependenciesResults.Dependencies[i]
 		if (dep.PinnedAt == nil || *dep.PinnedAt == "") && !isUnpinnedDependencyAllowed(dep, allowed) {
 			dl.Info(&checker.LogMessage{Text: fmt.Sprintf("found unpinned dependency %v", dep)})
-			return Fail, nil
+			result = Fail
 		}
 	}
 
-	dl.Info(&checker.LogMessage{Text: "repo was free of unpinned dependencies"})
-	return Pass, nil
+	if result == Pass {
+		dl.Info(&checker.LogMessage{Text: "repo was free of unpinned dependencies"})
+	}
+	return result, nil
 }
 
 func isUnpinnedDependencyAllowed(d checker.Dependency, allowed []Dependency) bool {
@@ -296,6 +458,234 @@ func isUnpinnedDependencyAllowed(d checker.Dependency, allowed []Dependency) boo
 	return false
 }
 
+func CheckBranchProtected(
+	reqs BranchProtectionRequirements,
+	results *checker.RawResults,
+	dl checker.DetailLogger,
+) (PolicyResult, error) {
+	result := Pass
+	fail := func(branch, text string) {
+		dl.Info(&checker.LogMessage{Text: fmt.Sprintf("branch %s: %s", branch, text)})
+		result = Fail
+	}
+	// isSet fails the branch when a required setting is disabled, or can't be read
+	// with the permissions of the token.
+	isSet := func(branch, setting string, value *bool) {
+		switch {
+		case value == nil:
+			fail(branch, fmt.Sprintf("unable to verify that %s is enabled", setting))
+		case !*value:
+			fail(branch, fmt.Sprintf("%s is disabled", setting))
+		}
+	}
+
+	if len(results.BranchProtectionResults.Branches) == 0 {
+		dl.Info(&checker.LogMessage{Text: "no branches found"})
+		return Fail, nil
+	}
+
+	for i := range results.BranchProtectionResults.Branches {
+		branch := &results.BranchProtectionResults.Branches[i]
+		name := "unknown"
+		if branch.Name != nil {
+			name = *branch.Name
+		}
+		if branch.Protected == nil || !*branch.Protected {
+			fail(name, "not protected")
+			continue
+		}
+
+		rule := &branch.BranchProtectionRule
+		if rule.AllowForcePushes != nil && *rule.AllowForcePushes {
+			fail(name, "force pushes are allowed")
+		}
+		if rule.AllowDeletions != nil && *rule.AllowDeletions {
+			fail(name, "deletions are allowed")
+		}
+
+		if reqs.MinApprovingReviews > 0 {
+			reviews := rule.RequiredPullRequestReviews.RequiredApprovingReviewCount
+			switch {
+			case reviews == nil:
+				fail(name, "unable to verify the number of required approving reviews")
+			case int(*reviews) < reqs.MinApprovingReviews:
+				fail(name, fmt.Sprintf("not enough required approving reviews (needed:%d found:%d)",
+					reqs.MinApprovingReviews, *reviews))
+			}
+		}
+		if reqs.RequireCodeOwnerReviews {
+			isSet(name, "code owner reviews", rule.RequiredPullRequestReviews.RequireCodeOwnerReviews)
+		}
+		if reqs.DismissStaleReviews {
+			isSet(name, "stale review dismissal", rule.RequiredPullRequestReviews.DismissStaleReviews)
+		}
+		if reqs.RequireStatusChecks {
+			isSet(name, "status checks", rule.CheckRules.RequiresStatusChecks)
+		}
+		if reqs.EnforceAdmins {
+			isSet(name, "enforcement on administrators", rule.EnforceAdmins)
+		}
+	}
+
+	if result == Pass {
+		dl.Info(&checker.LogMessage{Text: "branches were protected"})
+	}
+	return result, nil
+}
+
+func CheckTokenPermissionsRestricted(
+	allowedWritePermissions []string,
+	results *checker.RawResults,
+	dl checker.DetailLogger,
+) (PolicyResult, error) {
+	allowed := make(map[string]bool, len(allowedWritePermissions))
+	for _, name := range allowedWritePermissions {
+		allowed[name] = true
+	}
+
+	result := Pass
+	for i := range results.TokenPermissionsResults.TokenPermissions {
+		perm := &results.TokenPermissionsResults.TokenPermissions[i]
+		msg := checker.LogMessage{}
+		if perm.File != nil {
+			msg.Path = perm.File.Path
+			msg.Type = perm.File.Type
+			msg.Offset = perm.File.Offset
+		}
+
+		switch perm.Type {
+		case checker.PermissionLevelWrite:
+			name := "all"
+			if perm.Name != nil && *perm.Name != "" {
+				name = *perm.Name
+			}
+			if allowed[name] {
+				continue
+			}
+			msg.Text = fmt.Sprintf("write permission granted: %s", name)
+		case checker.PermissionLevelUndeclared:
+			// Job-level permissions fall back to the top-level ones.
+			if perm.LocationType == nil || *perm.LocationType != checker.PermissionLocationTop {
+				continue
+			}
+			msg.Text = "no top-level permission declared"
+		default:
+			continue
+		}
+		dl.Info(&msg)
+		result = Fail
+	}
+
+	if result == Pass {
+		dl.Info(&checker.LogMessage{Text: "token permissions were restricted"})
+	}
+	return result, nil
+}
+
+func CheckNoDangerousWorkflows(results *checker.RawResults, dl checker.DetailLogger) (PolicyResult, error) {
+	for i := range results.DangerousWorkflowResults.Workflows {
+		workflow := &results.DangerousWorkflowResults.Workflows[i]
+		dl.Info(&checker.LogMessage{
+			Path:   workflow.File.Path,
+			Type:   workflow.File.Type,
+			Offset: workflow.File.Offset,
+			Text:   fmt.Sprintf("dangerous workflow pattern detected: %s", workflow.Type),
+		})
+	}
+
+	nWorkflows := len(results.DangerousWorkflowResults.Workflows)
+	if nWorkflows == 0 {
+		dl.Info(&checker.LogMessage{Text: "workflows were free of dangerous patterns"})
+	}
+	return nWorkflows == 0, nil
+}
+
+func CheckReleasesSigned(
+	reqs SignedReleasesRequirements,
+	results *checker.RawResults,
+	dl checker.DetailLogger,
+) (PolicyResult, error) {
+	lookBack := reqs.Releases
+	if lookBack <= 0 {
+		lookBack = defaultReleasesToCheck
+	}
+	extensions := append([]string{}, provenanceExtensions...)
+	if !reqs.RequireProvenance {
+		extensions = append(extensions, signatureExtensions...)
+	}
+
+	result := Pass
+	checked := 0
+	for _, release := range results.SignedReleasesResults.Releases {
+		// Releases without assets have nothing to sign.
+		if len(release.Assets) == 0 {
+			continue
+		}
+		if checked == lookBack {
+			break
+		}
+		checked++
+		if !hasAssetWithSuffix(release.Assets, extensions) {
+			text := fmt.Sprintf("release %s is not signed", release.TagName)
+			if reqs.RequireProvenance {
+				text = fmt.Sprintf("release %s has no provenance", release.TagName)
+			}
+			dl.Info(&checker.LogMessage{Path: release.URL, Type: checker.FileTypeURL, Text: text})
+			result = Fail
+		}
+	}
+
+	if result == Pass {
+		dl.Info(&checker.LogMessage{Text: fmt.Sprintf("%d recent releases were signed", checked)})
+	}
+	return result, nil
+}
+
+func hasAssetWithSuffix(assets []clients.ReleaseAsset, suffixes []string) bool {
+	for _, asset := range assets {
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(asset.Name, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func CheckMinimumScores(
+	minimumScores map[string]int,
+	checkResults []checker.CheckResult,
+	dl checker.DetailLogger,
+) (PolicyResult, error) {
+	scores := make(map[string]int, len(checkResults))
+	for i := range checkResults {
+		scores[checkResults[i].Name] = checkResults[i].Score
+	}
+	names := make([]string, 0, len(minimumScores))
+	for name := range minimumScores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := Pass
+	for _, name := range names {
+		score, ok := scores[name]
+		switch {
+		case !ok || score == checker.InconclusiveResultScore:
+			dl.Info(&checker.LogMessage{Text: fmt.Sprintf("%s has no result", name)})
+			result = Fail
+		case score < minimumScores[name]:
+			dl.Info(&checker.LogMessage{
+				Text: fmt.Sprintf("%s scored %d, below %d", name, score, minimumScores[name]),
+			})
+			result = Fail
+		default:
+			dl.Info(&checker.LogMessage{Text: fmt.Sprintf("%s scored %d", name, score)})
+		}
+	}
+	return result, nil
+}
+
 // ParseFromFile takes a policy file and returns an AttestationPolicy.
 func ParseAttestationPolicyFromFile(policyFile string) (*AttestationPolicy, error) {
 	if policyFile != "" {
@@ -329,5 +719,16 @@ func ParseAttestationPolicyFromYAML(b []byte) (*AttestationPolicy, error) {
 		return &ap, sce.WithMessage(sce.ErrScorecardInternal, err.Error())
 	}
 
+	allChecks := checks.GetAllWithExperimental()
+	for name, score := range ap.MinimumCheckScores {
+		if _, ok := allChecks[name]; !ok {
+			return &ap, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("unknown check %s", name))
+		}
+		if score < checker.MinResultScore || score > checker.MaxResultScore {
+			return &ap, sce.WithMessage(sce.ErrScorecardInternal,
+				fmt.Sprintf("invalid minimum score for %s: %d", name, score))
+		}
+	}
+
 	return &ap, nil
 }
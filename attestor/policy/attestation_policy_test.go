@@ -21,8 +21,10 @@ import (
 	"testing"
 
 	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/checks"
 	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/pkg"
 	scut "github.com/ossf/scorecard/v4/utests"
 )
 
@@ -423,6 +425,413 @@ func TestNoUnpinnedDependencies(t *testing.T) {
 	}
 }
 
+func asBoolPointer(b bool) *bool {
+	return &b
+}
+
+func asInt32Pointer(i int32) *int32 {
+	return &i
+}
+
+func TestCheckBranchProtected(t *testing.T) {
+	t.Parallel()
+
+	protectedBranch := clients.BranchRef{
+		Name:      asPointer("main"),
+		Protected: asBoolPointer(true),
+		BranchProtectionRule: clients.BranchProtectionRule{
+			AllowDeletions:   asBoolPointer(false),
+			AllowForcePushes: asBoolPointer(false),
+			RequiredPullRequestReviews: clients.PullRequestReviewRule{
+				RequiredApprovingReviewCount: asInt32Pointer(2),
+				RequireCodeOwnerReviews:      asBoolPointer(true),
+			},
+		},
+	}
+
+	//nolint
+	tests := []struct {
+		raw      *checker.RawResults
+		reqs     BranchProtectionRequirements
+		name     string
+		expected PolicyResult
+	}{
+		{
+			name:     "no branches",
+			raw:      &checker.RawResults{},
+			expected: Fail,
+		},
+		{
+			name: "unprotected branch",
+			raw: &checker.RawResults{
+				BranchProtectionResults: checker.BranchProtectionsData{
+					Branches: []clients.BranchRef{
+						protectedBranch,
+						{Name: asPointer("release"), Protected: asBoolPointer(false)},
+					},
+				},
+			},
+			expected: Fail,
+		},
+		{
+			name: "force pushes allowed",
+			raw: &checker.RawResults{
+				BranchProtectionResults: checker.BranchProtectionsData{
+					Branches: []clients.BranchRef{{
+						Name:      asPointer("main"),
+						Protected: asBoolPointer(true),
+						BranchProtectionRule: clients.BranchProtectionRule{
+							AllowForcePushes: asBoolPointer(true),
+						},
+					}},
+				},
+			},
+			expected: Fail,
+		},
+		{
+			name: "protected branch meets the requirements",
+			reqs: BranchProtectionRequirements{MinApprovingReviews: 2, RequireCodeOwnerReviews: true},
+			raw: &checker.RawResults{
+				BranchProtectionResults: checker.BranchProtectionsData{
+					Branches: []clients.BranchRef{protectedBranch},
+				},
+			},
+			expected: Pass,
+		},
+		{
+			name: "too few approving reviews",
+			reqs: BranchProtectionRequirements{MinApprovingReviews: 3},
+			raw: &checker.RawResults{
+				BranchProtectionResults: checker.BranchProtectionsData{
+					Branches: []clients.BranchRef{protectedBranch},
+				},
+			},
+			expected: Fail,
+		},
+		{
+			name: "unknown setting",
+			reqs: BranchProtectionRequirements{EnforceAdmins: true},
+			raw: &checker.RawResults{
+				BranchProtectionResults: checker.BranchProtectionsData{
+					Branches: []clients.BranchRef{protectedBranch},
+				},
+			},
+			expected: Fail,
+		},
+	}
+
+	for i := range tests {
+		tt := &tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dl := scut.TestDetailLogger{}
+			actual, err := CheckBranchProtected(tt.reqs, tt.raw, &dl)
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", tt.name, err)
+			}
+			if actual != tt.expected {
+				t.Fatalf("%s: invalid result", tt.name)
+			}
+		})
+	}
+}
+
+func TestCheckTokenPermissionsRestricted(t *testing.T) {
+	t.Parallel()
+
+	top := checker.PermissionLocationTop
+	job := checker.PermissionLocationJob
+
+	//nolint
+	tests := []struct {
+		raw      *checker.RawResults
+		allowed  []string
+		name     string
+		expected PolicyResult
+	}{
+		{
+			name: "read permissions",
+			raw: &checker.RawResults{
+				TokenPermissionsResults: checker.TokenPermissionsData{
+					TokenPermissions: []checker.TokenPermission{
+						{Name: asPointer("contents"), LocationType: &top, Type: checker.PermissionLevelRead},
+						{LocationType: &job, Type: checker.PermissionLevelUndeclared},
+					},
+				},
+			},
+			expected: Pass,
+		},
+		{
+			name: "undeclared top-level permissions",
+			raw: &checker.RawResults{
+				TokenPermissionsResults: checker.TokenPermissionsData{
+					TokenPermissions: []checker.TokenPermission{
+						{LocationType: &top, Type: checker.PermissionLevelUndeclared},
+					},
+				},
+			},
+			expected: Fail,
+		},
+		{
+			name: "write permission",
+			raw: &checker.RawResults{
+				TokenPermissionsResults: checker.TokenPermissionsData{
+					TokenPermissions: []checker.TokenPermission{
+						{Name: asPointer("contents"), LocationType: &job, Type: checker.PermissionLevelWrite},
+					},
+				},
+			},
+			expected: Fail,
+		},
+		{
+			name:    "allowed write permission",
+			allowed: []string{"packages"},
+			raw: &checker.RawResults{
+				TokenPermissionsResults: checker.TokenPermissionsData{
+					TokenPermissions: []checker.TokenPermission{
+						{Name: asPointer("packages"), LocationType: &job, Type: checker.PermissionLevelWrite},
+					},
+				},
+			},
+			expected: Pass,
+		},
+	}
+
+	for i := range tests {
+		tt := &tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dl := scut.TestDetailLogger{}
+			actual, err := CheckTokenPermissionsRestricted(tt.allowed, tt.raw, &dl)
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", tt.name, err)
+			}
+			if actual != tt.expected {
+				t.Fatalf("%s: invalid result", tt.name)
+			}
+		})
+	}
+}
+
+func TestCheckNoDangerousWorkflows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		raw      *checker.RawResults
+		name     string
+		expected PolicyResult
+	}{
+		{
+			name:     "no dangerous workflows",
+			raw:      &checker.RawResults{},
+			expected: Pass,
+		},
+		{
+			name: "untrusted checkout",
+			raw: &checker.RawResults{
+				DangerousWorkflowResults: checker.DangerousWorkflowData{
+					Workflows: []checker.DangerousWorkflow{
+						{Type: checker.DangerousWorkflowUntrustedCheckout, File: checker.File{Path: ".github/workflows/a.yml"}},
+					},
+				},
+			},
+			expected: Fail,
+		},
+	}
+
+	for i := range tests {
+		tt := &tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dl := scut.TestDetailLogger{}
+			actual, err := CheckNoDangerousWorkflows(tt.raw, &dl)
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", tt.name, err)
+			}
+			if actual != tt.expected {
+				t.Fatalf("%s: invalid result", tt.name)
+			}
+		})
+	}
+}
+
+func TestCheckReleasesSigned(t *testing.T) {
+	t.Parallel()
+
+	signed := clients.Release{TagName: "v3", Assets: []clients.ReleaseAsset{{Name: "bin"}, {Name: "bin.sig"}}}
+	withProvenance := clients.Release{
+		TagName: "v2",
+		Assets:  []clients.ReleaseAsset{{Name: "bin"}, {Name: "bin.intoto.jsonl"}},
+	}
+	unsigned := clients.Release{TagName: "v1", Assets: []clients.ReleaseAsset{{Name: "bin"}}}
+
+	//nolint
+	tests := []struct {
+		raw      *checker.RawResults
+		reqs     SignedReleasesRequirements
+		name     string
+		expected PolicyResult
+	}{
+		{
+			name:     "no releases",
+			raw:      &checker.RawResults{},
+			expected: Pass,
+		},
+		{
+			name: "signed releases",
+			raw: &checker.RawResults{
+				SignedReleasesResults: checker.SignedReleasesData{
+					Releases: []clients.Release{signed, withProvenance, {TagName: "no-assets"}},
+				},
+			},
+			expected: Pass,
+		},
+		{
+			name: "unsigned release",
+			raw: &checker.RawResults{
+				SignedReleasesResults: checker.SignedReleasesData{
+					Releases: []clients.Release{signed, unsigned},
+				},
+			},
+			expected: Fail,
+		},
+		{
+			name: "unsigned release beyond the look back",
+			reqs: SignedReleasesRequirements{Releases: 1},
+			raw: &checker.RawResults{
+				SignedReleasesResults: checker.SignedReleasesData{
+					Releases: []clients.Release{signed, unsigned},
+				},
+			},
+			expected: Pass,
+		},
+		{
+			name: "signature without provenance",
+			reqs: SignedReleasesRequirements{RequireProvenance: true},
+			raw: &checker.RawResults{
+				SignedReleasesResults: checker.SignedReleasesData{
+					Releases: []clients.Release{withProvenance, signed},
+				},
+			},
+			expected: Fail,
+		},
+	}
+
+	for i := range tests {
+		tt := &tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dl := scut.TestDetailLogger{}
+			actual, err := CheckReleasesSigned(tt.reqs, tt.raw, &dl)
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", tt.name, err)
+			}
+			if actual != tt.expected {
+				t.Fatalf("%s: invalid result", tt.name)
+			}
+		})
+	}
+}
+
+func TestCheckMinimumScores(t *testing.T) {
+	t.Parallel()
+
+	checkResults := []checker.CheckResult{
+		{Name: checks.CheckMaintained, Score: 6},
+		{Name: checks.CheckFuzzing, Score: checker.InconclusiveResultScore},
+	}
+
+	tests := []struct {
+		minimumScores map[string]int
+		name          string
+		expected      PolicyResult
+	}{
+		{
+			name:          "score above the minimum",
+			minimumScores: map[string]int{checks.CheckMaintained: 5},
+			expected:      Pass,
+		},
+		{
+			name:          "score below the minimum",
+			minimumScores: map[string]int{checks.CheckMaintained: 7},
+			expected:      Fail,
+		},
+		{
+			name:          "inconclusive result",
+			minimumScores: map[string]int{checks.CheckFuzzing: 0},
+			expected:      Fail,
+		},
+		{
+			name:          "missing result",
+			minimumScores: map[string]int{checks.CheckLicense: 0},
+			expected:      Fail,
+		},
+	}
+
+	for i := range tests {
+		tt := &tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dl := scut.TestDetailLogger{}
+			actual, err := CheckMinimumScores(tt.minimumScores, checkResults, &dl)
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", tt.name, err)
+			}
+			if actual != tt.expected {
+				t.Fatalf("%s: invalid result", tt.name)
+			}
+		})
+	}
+}
+
+func TestEvaluateResults(t *testing.T) {
+	t.Parallel()
+
+	ap := AttestationPolicy{
+		PreventBinaryArtifacts:      true,
+		PreventKnownVulnerabilities: true,
+		EnsureCodeReviewed:          true,
+		PreventDangerousWorkflows:   true,
+		MinimumCheckScores:          map[string]int{checks.CheckMaintained: 5},
+	}
+	result := &pkg.ScorecardResult{
+		RawResults: checker.RawResults{
+			BinaryArtifactResults: checker.BinaryArtifactData{Files: []checker.File{{Path: "a"}}},
+			VulnerabilitiesResults: checker.VulnerabilitiesData{
+				Vulnerabilities: []clients.Vulnerability{{ID: "foo"}},
+			},
+		},
+		Checks: []checker.CheckResult{{Name: checks.CheckMaintained, Score: 10}},
+	}
+
+	report, err := ap.EvaluateResults(result)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// Every predicate is evaluated, even after the first failure.
+	expected := map[string]PolicyResult{
+		PredicateBinaryArtifacts:      Fail,
+		PredicateKnownVulnerabilities: Fail,
+		PredicateCodeReviewed:         Pass,
+		PredicateDangerousWorkflows:   Pass,
+		PredicateMinimumCheckScores:   Pass,
+	}
+	if len(report.Predicates) != len(expected) {
+		t.Fatalf("expected %d predicates, got %d", len(expected), len(report.Predicates))
+	}
+	for _, p := range report.Predicates {
+		if want, ok := expected[p.Name]; !ok || p.Result != want {
+			t.Errorf("%s: expected %v, got %v", p.Name, want, p.Result)
+		}
+		if len(p.Details) == 0 {
+			t.Errorf("%s: no details", p.Name)
+		}
+	}
+	if report.Result() != Fail {
+		t.Errorf("expected the report to fail")
+	}
+}
+
 func TestAttestationPolicyRead(t *testing.T) {
 	t.Parallel()
 
@@ -465,6 +874,31 @@ func TestAttestationPolicyRead(t *testing.T) {
 				CodeReviewRequirements:      CodeReviewRequirements{RequiredApprovers: []string{"alice"}, MinReviewers: 2},
 			},
 		},
+		{
+			name:     "policy with every predicate",
+			filename: "./testdata/policy-binauthz-all.yaml",
+			err:      nil,
+			result: AttestationPolicy{
+				PreventBinaryArtifacts: true,
+				EnsureBranchProtected:  true,
+				BranchProtectionRequirements: BranchProtectionRequirements{
+					MinApprovingReviews:     2,
+					RequireCodeOwnerReviews: true,
+					RequireStatusChecks:     true,
+				},
+				EnsureTokenPermissionsRestricted: true,
+				AllowedWritePermissions:          []string{"packages"},
+				PreventDangerousWorkflows:        true,
+				EnsureReleasesSigned:             true,
+				SignedReleasesRequirements:       SignedReleasesRequirements{RequireProvenance: true, Releases: 3},
+				MinimumCheckScores:               map[string]int{"Maintained": 5, "Fuzzing": 1},
+			},
+		},
+		{
+			name:     "policy with an unknown check",
+			filename: "./testdata/policy-binauthz-unknown-check.yaml",
+			err:      sce.ErrScorecardInternal,
+		},
 		{
 			name:     "policy with a single policy and no policy parameters",
 			filename: "./testdata/policy-binauthz-missingparam.yaml",
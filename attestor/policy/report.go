@@ -0,0 +1,76 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ossf/scorecard/v4/checker"
+	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/log"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// PredicateResult is the outcome of a predicate of the policy, with the details
+// explaining it.
+type PredicateResult struct {
+	Name    string
+	Details []checker.CheckDetail
+	Result  PolicyResult
+}
+
+// Report is the outcome of every predicate of the policy.
+type Report struct {
+	Predicates []PredicateResult
+}
+
+// Result is Pass when every predicate passed.
+func (r *Report) Result() PolicyResult {
+	for i := range r.Predicates {
+		if r.Predicates[i].Result == Fail {
+			return Fail
+		}
+	}
+	return Pass
+}
+
+// Write writes a human-readable report with a line per predicate.
+func (r *Report) Write(w io.Writer) error {
+	var sb strings.Builder
+	for i := range r.Predicates {
+		p := &r.Predicates[i]
+		sb.WriteString(fmt.Sprintf("%s %s\n", resultToString(p.Result), p.Name))
+		for j := range p.Details {
+			if s := pkg.DetailToString(&p.Details[j], log.InfoLevel); s != "" {
+				sb.WriteString(fmt.Sprintf("    %s\n", s))
+			}
+		}
+	}
+	sb.WriteString(fmt.Sprintf("Result: %s\n", resultToString(r.Result())))
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("io.WriteString: %v", err))
+	}
+	return nil
+}
+
+func resultToString(result PolicyResult) string {
+	if result == Pass {
+		return "PASS"
+	}
+	return "FAIL"
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ossf/scorecard/v4/checker"
+)
+
+func TestReportWrite(t *testing.T) {
+	t.Parallel()
+
+	report := Report{
+		Predicates: []PredicateResult{
+			{
+				Name:   PredicateBinaryArtifacts,
+				Result: Fail,
+				Details: []checker.CheckDetail{
+					{Type: checker.DetailInfo, Msg: checker.LogMessage{Text: "binary detected", Path: "a.exe"}},
+					{Type: checker.DetailDebug, Msg: checker.LogMessage{Text: "hidden"}},
+				},
+			},
+			{
+				Name:   PredicateKnownVulnerabilities,
+				Result: Pass,
+				Details: []checker.CheckDetail{
+					{Type: checker.DetailInfo, Msg: checker.LogMessage{Text: "found 0 vulnerabilities in package"}},
+				},
+			},
+		},
+	}
+
+	var sb strings.Builder
+	if err := report.Write(&sb); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := `FAIL preventBinaryArtifacts
+    Info: binary detected: a.exe
+PASS preventKnownVulnerabilities
+    Info: found 0 vulnerabilities in package
+Result: FAIL
+`
+	if sb.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, sb.String())
+	}
+}
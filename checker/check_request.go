@@ -30,8 +30,27 @@ type CheckRequest struct {
 	Repo                  clients.Repo
 	VulnerabilitiesClient clients.VulnerabilitiesClient
 	// UPGRADEv6: return raw results instead of scores.
-	RawResults    *RawResults
-	RequiredTypes []RequestType
+	RawResults     *RawResults
+	RequiredTypes  []RequestType
+	PolicySettings *PolicySettings
+	// RecordUsage records the ResourceUsage of the check in its result.
+	RecordUsage bool
+}
+
+// PolicySettings contains user-supplied, check-specific settings,
+// e.g. read from the policy file.
+// A nil *PolicySettings is equivalent to the zero value.
+type PolicySettings struct {
+	// AllowedLicenses and DeniedLicenses are SPDX license
+	// identifiers used by the License check.
+	AllowedLicenses []string
+	DeniedLicenses  []string
+	// AllowedBinaryHashes are SHA-256 hashes of binaries
+	// accepted by the Binary-Artifacts check.
+	AllowedBinaryHashes []string
+	// ContributorsMapping contains company and identity
+	// aliases used by the Contributors check.
+	ContributorsMapping *ContributorsMapping
 }
 
 // RequestType identifies special requirements/attributes that need to be supported by checks.
@@ -63,6 +63,10 @@ const (
 	FileTypeURL
 )
 
+// CheckResultVersion is the version of the results of the checks. Results of a different
+// version, e.g. stored by a previous scan, can't be compared or reused.
+const CheckResultVersion = 2
+
 // CheckResult captures result from a check run.
 //
 //nolint:govet
@@ -73,6 +77,8 @@ type CheckResult struct {
 	Details []CheckDetail
 	Score   int
 	Reason  string
+	// Usage is the usage of resources by the check, if recorded, see CheckRequest.RecordUsage.
+	Usage *ResourceUsage
 }
 
 // Remediation represents a remediation.
@@ -153,7 +159,7 @@ func CreateResultWithScore(name, reason string, score int) CheckResult {
 		Name: name,
 		// Old structure.
 		// New structure.
-		Version: 2,
+		Version: CheckResultVersion,
 		Error:   nil,
 		Score:   score,
 		Reason:  reason,
@@ -171,7 +177,7 @@ func CreateProportionalScoreResult(name, reason string, b, t int) CheckResult {
 		Name: name,
 		// Old structure.
 		// New structure.
-		Version: 2,
+		Version: CheckResultVersion,
 		Error:   nil,
 		Score:   score,
 		Reason:  NormalizeReason(reason, score),
@@ -200,7 +206,7 @@ func CreateInconclusiveResult(name, reason string) CheckResult {
 		Name: name,
 		// Old structure.
 		// New structure.
-		Version: 2,
+		Version: CheckResultVersion,
 		Score:   InconclusiveResultScore,
 		Reason:  reason,
 	}
@@ -212,7 +218,7 @@ func CreateRuntimeErrorResult(name string, e error) CheckResult {
 		Name: name,
 		// Old structure.
 		// New structure.
-		Version: 2,
+		Version: CheckResultVersion,
 		Error:   e,
 		Score:   InconclusiveResultScore,
 		Reason:  e.Error(), // Note: message already accessible by caller thru `Error`.
@@ -23,6 +23,7 @@ import (
 	opencensusstats "go.opencensus.io/stats"
 	"go.opencensus.io/tag"
 
+	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
 	"github.com/ossf/scorecard/v4/stats"
 )
@@ -72,9 +73,12 @@ type Check struct {
 // CheckNameToFnMap defined here for convenience.
 type CheckNameToFnMap map[string]Check
 
-func logStats(ctx context.Context, startTime time.Time, result *CheckResult) error {
+func logStats(ctx context.Context, startTime time.Time, usage *stats.Usage, result *CheckResult) error {
 	runTimeInSecs := time.Now().Unix() - startTime.Unix()
-	opencensusstats.Record(ctx, stats.CheckRuntimeInSec.M(runTimeInSecs))
+	opencensusstats.Record(ctx,
+		stats.CheckRuntimeInSec.M(runTimeInSecs),
+		stats.CheckLatencyInSec.M(time.Since(startTime).Seconds()),
+		stats.CheckHTTPRequests.M(usage.Requests()))
 
 	if result.Error != nil {
 		ctx, err := tag.New(ctx, tag.Upsert(stats.ErrorName, sce.GetName(result.Error)))
@@ -100,6 +104,8 @@ func (r *Runner) Run(ctx context.Context, c Check) CheckResult {
 	if err != nil {
 		panic(err)
 	}
+	usage := new(stats.Usage)
+	ctx = stats.WithUsage(ctx, usage)
 	startTime := time.Now()
 
 	var res CheckResult
@@ -107,6 +113,10 @@ func (r *Runner) Run(ctx context.Context, c Check) CheckResult {
 	for retriesRemaining := checkRetries; retriesRemaining > 0; retriesRemaining-- {
 		checkRequest := r.CheckRequest
 		checkRequest.Ctx = ctx
+		// Make the requests of the check with its context, so that they're attributed to it.
+		if repoClient, ok := checkRequest.RepoClient.(clients.ContextualRepoClient); ok {
+			checkRequest.RepoClient = repoClient.WithContext(ctx)
+		}
 		checkRequest.Dlogger = l
 		res = c.Fn(&checkRequest)
 		if res.Error != nil && errors.Is(res.Error, sce.ErrRepoUnreachable) {
@@ -122,7 +132,11 @@ func (r *Runner) Run(ctx context.Context, c Check) CheckResult {
 	// TODO(#1393): Remove.
 	res.Details = l.Flush()
 
-	if err := logStats(ctx, startTime, &res); err != nil {
+	if r.CheckRequest.RecordUsage {
+		res.Usage = NewResourceUsage(usage, time.Since(startTime))
+	}
+
+	if err := logStats(ctx, startTime, usage, &res); err != nil {
 		panic(err)
 	}
 	return res
@@ -20,6 +20,7 @@ import (
 
 	"github.com/ossf/scorecard/v4/clients"
 	ghrepo "github.com/ossf/scorecard/v4/clients/githubrepo"
+	"github.com/ossf/scorecard/v4/clients/githubrepo/roundtripper"
 	"github.com/ossf/scorecard/v4/clients/localdir"
 	"github.com/ossf/scorecard/v4/log"
 )
@@ -33,6 +34,19 @@ func GetClients(ctx context.Context, repoURI, localURI string, logger *log.Logge
 	clients.CIIBestPracticesClient, // ciiClient
 	clients.VulnerabilitiesClient, // vulnClient
 	error,
+) {
+	return GetClientsWithCache(ctx, repoURI, localURI, "", logger)
+}
+
+// GetClientsWithCache returns the clients of GetClients, the GitHub ones caching the responses
+// of the API in cacheDir if set.
+func GetClientsWithCache(ctx context.Context, repoURI, localURI, cacheDir string, logger *log.Logger) (
+	clients.Repo, // repo
+	clients.RepoClient, // repoClient
+	clients.RepoClient, // ossFuzzClient
+	clients.CIIBestPracticesClient, // ciiClient
+	clients.VulnerabilitiesClient, // vulnClient
+	error,
 ) {
 	var githubRepo clients.Repo
 	if localURI != "" {
@@ -45,7 +59,7 @@ func GetClients(ctx context.Context, repoURI, localURI string, logger *log.Logge
 			localdir.CreateLocalDirClient(ctx, logger), /*repoClient*/
 			nil, /*ossFuzzClient*/
 			nil, /*ciiClient*/
-			nil, /*vulnClient*/
+			clients.DefaultVulnerabilitiesClient(), /*vulnClient*/
 			retErr
 	}
 
@@ -59,14 +73,21 @@ func GetClients(ctx context.Context, repoURI, localURI string, logger *log.Logge
 			fmt.Errorf("getting local directory client: %w", errGitHub)
 	}
 
-	ossFuzzRepoClient, errOssFuzz := ghrepo.CreateOssFuzzRepoClient(ctx, logger)
+	// The clients share the transport, and so the cache and the rate limits of the tokens.
+	rt := roundtripper.NewCachingTransport(ctx, logger, cacheDir)
+	var ossFuzzRepoClient clients.RepoClient
 	var retErr error
-	if errOssFuzz != nil {
-		retErr = fmt.Errorf("getting OSS-Fuzz repo client: %w", errOssFuzz)
+	// OSS-Fuzz is on github.com, which GitHub Enterprise Server users may have no token for.
+	if !ghrepo.IsEnterpriseRepo(githubRepo) {
+		var errOssFuzz error
+		ossFuzzRepoClient, errOssFuzz = ghrepo.CreateOssFuzzRepoClientWithTransport(ctx, rt)
+		if errOssFuzz != nil {
+			retErr = fmt.Errorf("getting OSS-Fuzz repo client: %w", errOssFuzz)
+		}
 	}
 	// TODO(repo): Should we be handling the OSS-Fuzz client error like this?
 	return githubRepo, /*repo*/
-		ghrepo.CreateGithubRepoClient(ctx, logger), /*repoClient*/
+		ghrepo.CreateGithubRepoClientWithTransport(ctx, rt), /*repoClient*/
 		ossFuzzRepoClient, /*ossFuzzClient*/
 		clients.DefaultCIIBestPracticesClient(), /*ciiClient*/
 		clients.DefaultVulnerabilitiesClient(), /*vulnClient*/
@@ -128,7 +128,57 @@ type MaintainedData struct {
 // LicenseData contains the raw results
 // for the License check.
 type LicenseData struct {
-	Files []File
+	LicenseFiles []LicenseFile
+	// SourceHeaders contains the SPDX-License-Identifier
+	// tags found in source files.
+	SourceHeaders []LicenseHeader
+	// ManifestLicenses contains the licenses declared
+	// in package manifests, e.g. package.json.
+	ManifestLicenses []ManifestLicense
+}
+
+// LicenseAttributionType indicates how a license was identified.
+type LicenseAttributionType string
+
+const (
+	// LicenseAttributionTypeSPDXTag is for licenses identified
+	// by an `SPDX-License-Identifier:` tag.
+	LicenseAttributionTypeSPDXTag LicenseAttributionType = "spdxTag"
+	// LicenseAttributionTypeText is for licenses identified by their text.
+	LicenseAttributionTypeText LicenseAttributionType = "text"
+)
+
+// License represents a license identified in a file.
+type License struct {
+	// SpdxID is an SPDX license identifier or expression.
+	// It is empty if the license could not be identified.
+	SpdxID      string
+	Attribution LicenseAttributionType
+	// Confidence ranges from 0 to 1.
+	Confidence float64
+}
+
+// LicenseFile represents a license file and its license.
+type LicenseFile struct {
+	File               File
+	LicenseInformation License
+}
+
+// LicenseHeader represents an SPDX license expression declared
+// in the header of one or more source files.
+type LicenseHeader struct {
+	Expression string
+	// File is the first file found with this expression.
+	File File
+	// NumFiles is the number of files declaring this expression,
+	// among the source files read.
+	NumFiles int
+}
+
+// ManifestLicense represents a license declared in a package manifest.
+type ManifestLicense struct {
+	File       File
+	Expression string
 }
 
 // CodeReviewData contains the raw results
@@ -156,13 +206,59 @@ type Changeset struct {
 
 // ContributorsData represents contributor information.
 type ContributorsData struct {
+	// Users contains de-duplicated contributors, with normalized
+	// company names. Bots are included but flagged with IsBot.
 	Users []clients.User
+	// Organizations contains the contributions of
+	// non-bot users per organization or company.
+	Organizations []ContributorOrganization
+}
+
+// ContributorOrganization represents an organization or company
+// and the contributions made by its members.
+type ContributorOrganization struct {
+	Name             string
+	NumContributors  int
+	NumContributions int
+}
+
+// ContributorsMapping contains user-supplied aliases for the Contributors check.
+type ContributorsMapping struct {
+	// Companies maps a canonical company name to alternate names.
+	Companies map[string][]string `yaml:"companies"`
+	// Identities maps a canonical login to alternate logins and emails
+	// used by the same person.
+	Identities map[string][]string `yaml:"identities"`
 }
 
 // VulnerabilitiesData contains the raw results
 // for the Vulnerabilities check.
 type VulnerabilitiesData struct {
 	Vulnerabilities []clients.Vulnerability
+	// NotAffected are the vulnerabilities the repo's VEX documents
+	// mark as not affecting it.
+	NotAffected []NotAffectedVulnerability
+	// UnparsedLockfiles are the lockfiles skipped as they
+	// couldn't be parsed: their packages weren't queried.
+	UnparsedLockfiles []UnparsedFile
+	// UnparsedVEXDocuments are the VEX documents skipped as they
+	// couldn't be parsed: their statements weren't applied.
+	UnparsedVEXDocuments []UnparsedFile
+}
+
+// UnparsedFile is a file of the repo skipped as it couldn't be parsed.
+type UnparsedFile struct {
+	File  File
+	Error string
+}
+
+// NotAffectedVulnerability is a vulnerability a VEX document of the repo
+// marks as not affecting it.
+type NotAffectedVulnerability struct {
+	Vulnerability   clients.Vulnerability
+	Justification   string
+	ImpactStatement string
+	File            File
 }
 
 type SecurityPolicyInformationType string
@@ -228,7 +324,8 @@ type WebhooksData struct {
 // BranchProtectionsData contains the raw results
 // for the Branch-Protection check.
 type BranchProtectionsData struct {
-	Branches []clients.BranchRef
+	Branches       []clients.BranchRef
+	TagProtections []clients.TagProtectionRule
 }
 
 // Tool represents a tool.
@@ -266,7 +363,7 @@ type File struct {
 	EndOffset uint     // End of offset in the file, e.g. if the command spans multiple lines.
 	FileSize  uint     // Total size of file.
 	Type      FileType // Type of file.
-	// TODO: add hash.
+	SHA256    string   // Hex-encoded SHA-256 of the file content, for binary files.
 }
 
 // CIIBestPracticesData contains data foor CIIBestPractices check.
@@ -0,0 +1,55 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"time"
+
+	"github.com/ossf/scorecard/v4/stats"
+)
+
+// ResourceUsage is the usage of resources by a run of a check, or the sum of
+// those of several runs.
+type ResourceUsage struct {
+	// Requests is the count of HTTP requests per endpoint class, e.g. rest,
+	// graphql, search or tarball. The cache hits are counted too.
+	Requests        map[string]int64
+	WallTime        time.Duration
+	BytesDownloaded int64
+	CacheHits       int64
+}
+
+// Add adds the usage of other to u.
+func (u *ResourceUsage) Add(other *ResourceUsage) {
+	if len(other.Requests) > 0 && u.Requests == nil {
+		u.Requests = map[string]int64{}
+	}
+	for class, n := range other.Requests {
+		u.Requests[class] += n
+	}
+	u.WallTime += other.WallTime
+	u.BytesDownloaded += other.BytesDownloaded
+	u.CacheHits += other.CacheHits
+}
+
+// NewResourceUsage returns the ResourceUsage of the requests accounted for in usage over wallTime.
+func NewResourceUsage(usage *stats.Usage, wallTime time.Duration) *ResourceUsage {
+	return &ResourceUsage{
+		Requests:        usage.RequestsPerClass(),
+		WallTime:        wallTime,
+		BytesDownloaded: usage.BytesDownloaded(),
+		CacheHits:       usage.CacheHits(),
+	}
+}
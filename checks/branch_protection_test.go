@@ -67,12 +67,13 @@ func TestReleaseAndDevBranchProtected(t *testing.T) {
 
 	//nolint
 	tests := []struct {
-		name          string
-		expected      scut.TestReturn
-		branches      []*clients.BranchRef
-		defaultBranch string
-		releases      []string
-		nonadmin      bool
+		name           string
+		expected       scut.TestReturn
+		branches       []*clients.BranchRef
+		tagProtections []clients.TagProtectionRule
+		defaultBranch  string
+		releases       []string
+		nonadmin       bool
 	}{
 		{
 			name: "Nil release and main branch names",
@@ -167,6 +168,53 @@ func TestReleaseAndDevBranchProtected(t *testing.T) {
 			},
 			releases: nil,
 		},
+		{
+			name: "Signed commits and tag protections",
+			expected: scut.TestReturn{
+				Error:         nil,
+				Score:         2,
+				NumberOfWarn:  7,
+				NumberOfInfo:  5,
+				NumberOfDebug: 0,
+			},
+			defaultBranch: main,
+			branches: []*clients.BranchRef{
+				{
+					Name:      &main,
+					Protected: &trueVal,
+					BranchProtectionRule: clients.BranchProtectionRule{
+						CheckRules: clients.StatusChecksRule{
+							RequiresStatusChecks: &trueVal,
+							UpToDateBeforeMerge:  &falseVal,
+							Contexts:             nil,
+						},
+						RequiredPullRequestReviews: clients.PullRequestReviewRule{
+							DismissStaleReviews:          &falseVal,
+							RequireCodeOwnerReviews:      &falseVal,
+							RequiredApprovingReviewCount: &zeroVal,
+						},
+						EnforceAdmins:        &falseVal,
+						RequireLinearHistory: &falseVal,
+						RequireSignedCommits: &trueVal,
+						AllowForcePushes:     &falseVal,
+						AllowDeletions:       &falseVal,
+					},
+				},
+			},
+			tagProtections: []clients.TagProtectionRule{
+				{
+					Pattern:        "v*",
+					AllowDeletions: &falseVal,
+					AllowUpdates:   &falseVal,
+				},
+				{
+					Pattern:        "release-*",
+					AllowDeletions: &trueVal,
+					AllowUpdates:   &falseVal,
+				},
+			},
+			releases: nil,
+		},
 		{
 			name: "Take worst of release and development",
 			expected: scut.TestReturn{
@@ -412,6 +460,8 @@ func TestReleaseAndDevBranchProtected(t *testing.T) {
 				DoAndReturn(func(b string) (*clients.BranchRef, error) {
 					return getBranch(tt.branches, b, tt.nonadmin), nil
 				}).AnyTimes()
+			mockRepoClient.EXPECT().ListTagProtectionRules().
+				Return(tt.tagProtections, nil).AnyTimes()
 			dl := scut.TestDetailLogger{}
 			req := checker.CheckRequest{
 				Dlogger:    &dl,
@@ -18,6 +18,7 @@ import (
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/checks/evaluation"
 	"github.com/ossf/scorecard/v4/checks/raw"
+	"github.com/ossf/scorecard/v4/clients/githubrepo"
 	sce "github.com/ossf/scorecard/v4/errors"
 )
 
@@ -34,6 +35,11 @@ func init() {
 
 // CIIBestPractices will check if the maintainers have a best practice badge.
 func CIIBestPractices(c *checker.CheckRequest) checker.CheckResult {
+	if githubrepo.IsEnterpriseRepo(c.Repo) {
+		return checker.CreateInconclusiveResult(CheckCIIBestPractices,
+			"OpenSSF Best Practices badges are only looked up for public repos, not GitHub Enterprise Server ones")
+	}
+
 	rawData, err := raw.CIIBestPractices(c)
 	if err != nil {
 		e := sce.WithMessage(sce.ErrScorecardInternal, err.Error())
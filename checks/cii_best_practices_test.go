@@ -23,6 +23,7 @@ import (
 
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/clients/githubrepo"
 	mockrepo "github.com/ossf/scorecard/v4/clients/mockclients"
 	sce "github.com/ossf/scorecard/v4/errors"
 	scut "github.com/ossf/scorecard/v4/utests"
@@ -129,3 +130,25 @@ func TestCIIBestPractices(t *testing.T) {
 		})
 	}
 }
+
+//nolint:paralleltest // Uses t.Setenv.
+func TestCIIBestPracticesEnterprise(t *testing.T) {
+	t.Setenv("GH_HOST", "ghe.example.com")
+	repo, err := githubrepo.MakeGithubRepo("ghe.example.com/owner/repo")
+	if err != nil {
+		t.Fatalf("MakeGithubRepo: %v", err)
+	}
+	ctrl := gomock.NewController(t)
+	// The badges aren't looked up.
+	mockCIIClient := mockrepo.NewMockCIIBestPracticesClient(ctrl)
+
+	res := CIIBestPractices(&checker.CheckRequest{
+		Repo:      repo,
+		CIIClient: mockCIIClient,
+	})
+	expected := scut.TestReturn{Score: checker.InconclusiveResultScore}
+	if !scut.ValidateTestReturn(t, "GitHub Enterprise Server", &expected, &res, &scut.TestDetailLogger{}) {
+		t.Fail()
+	}
+	ctrl.Finish()
+}
@@ -34,7 +34,11 @@ func init() {
 
 // Contributors run Contributors check.
 func Contributors(c *checker.CheckRequest) checker.CheckResult {
-	rawData, err := raw.Contributors(c.RepoClient)
+	var mapping *checker.ContributorsMapping
+	if c.PolicySettings != nil {
+		mapping = c.PolicySettings.ContributorsMapping
+	}
+	rawData, err := raw.Contributors(c.RepoClient, mapping)
 	if err != nil {
 		e := sce.WithMessage(sce.ErrScorecardInternal, err.Error())
 		return checker.CreateRuntimeErrorResult(CheckContributors, e)
@@ -137,6 +137,31 @@ func TestContributors(t *testing.T) {
 				Score: 10,
 			},
 		},
+		{
+			err:  nil,
+			name: "Bots are not counted",
+			contrib: []clients.User{
+				{
+					Login:            "dependabot[bot]",
+					Companies:        []string{"company1"},
+					NumContributions: 10,
+				},
+				{
+					Login:            "release-bot",
+					IsBot:            true,
+					Companies:        []string{"company2"},
+					NumContributions: 10,
+				},
+				{
+					Login:            "user",
+					Companies:        []string{"company3"},
+					NumContributions: 10,
+				},
+			},
+			expected: checker.CheckResult{
+				Score: 3,
+			},
+		},
 		{
 			err:     nil,
 			name:    "No contributors",
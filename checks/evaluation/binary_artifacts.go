@@ -15,13 +15,16 @@
 package evaluation
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/ossf/scorecard/v4/checker"
 	sce "github.com/ossf/scorecard/v4/errors"
 )
 
 // BinaryArtifacts applies the score policy for the Binary-Artifacts check.
 func BinaryArtifacts(name string, dl checker.DetailLogger,
-	r *checker.BinaryArtifactData,
+	r *checker.BinaryArtifactData, settings *checker.PolicySettings,
 ) checker.CheckResult {
 	if r == nil {
 		e := sce.WithMessage(sce.ErrScorecardInternal, "empty raw data")
@@ -33,8 +36,23 @@ func BinaryArtifacts(name string, dl checker.DetailLogger,
 		return checker.CreateMaxScoreResult(name, "no binaries found in the repo")
 	}
 
+	allowed := make(map[string]bool)
+	if settings != nil {
+		for _, h := range settings.AllowedBinaryHashes {
+			allowed[normalizeSHA256(h)] = true
+		}
+	}
+
 	score := checker.MaxResultScore
 	for _, f := range r.Files {
+		if f.SHA256 != "" && allowed[normalizeSHA256(f.SHA256)] {
+			dl.Info(&checker.LogMessage{
+				Path: f.Path, Type: checker.FileTypeBinary,
+				Offset: f.Offset,
+				Text:   fmt.Sprintf("binary detected, allowed by policy (sha256:%s)", f.SHA256),
+			})
+			continue
+		}
 		dl.Warn(&checker.LogMessage{
 			Path: f.Path, Type: checker.FileTypeBinary,
 			Offset: f.Offset,
@@ -44,9 +62,19 @@ func BinaryArtifacts(name string, dl checker.DetailLogger,
 		score--
 	}
 
+	if score == checker.MaxResultScore {
+		return checker.CreateMaxScoreResult(name, "only binaries allowed by policy found in the repo")
+	}
 	if score < checker.MinResultScore {
 		score = checker.MinResultScore
 	}
 
 	return checker.CreateResultWithScore(name, "binaries present in source code", score)
 }
+
+// normalizeSHA256 lower-cases a hex-encoded SHA-256
+// and strips its optional `sha256:` prefix.
+func normalizeSHA256(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	return strings.TrimPrefix(h, "sha256:")
+}
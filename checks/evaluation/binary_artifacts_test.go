@@ -26,9 +26,10 @@ func TestBinaryArtifacts(t *testing.T) {
 	t.Parallel()
 	//nolint
 	type args struct {
-		name string
-		dl   checker.DetailLogger
-		r    *checker.BinaryArtifactData
+		name     string
+		dl       checker.DetailLogger
+		r        *checker.BinaryArtifactData
+		settings *checker.PolicySettings
 	}
 	tests := []struct {
 		name    string
@@ -270,12 +271,62 @@ func TestBinaryArtifacts(t *testing.T) {
 				Score: 0,
 			},
 		},
+		{
+			name: "binary artifacts allowed by policy",
+			args: args{
+				name: "binary artifacts allowed by policy",
+				dl:   &scut.TestDetailLogger{},
+				r: &checker.BinaryArtifactData{
+					Files: []checker.File{
+						{
+							Path:   "testdata/fixture.so",
+							SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+						{
+							Path:   "bin/tool",
+							SHA256: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+						},
+					},
+				},
+				settings: &checker.PolicySettings{
+					AllowedBinaryHashes: []string{
+						"sha256:E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855",
+					},
+				},
+			},
+			want: checker.CheckResult{
+				Score: 9,
+			},
+		},
+		{
+			name: "all binary artifacts allowed by policy",
+			args: args{
+				name: "all binary artifacts allowed by policy",
+				dl:   &scut.TestDetailLogger{},
+				r: &checker.BinaryArtifactData{
+					Files: []checker.File{
+						{
+							Path:   "testdata/fixture.so",
+							SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+				},
+				settings: &checker.PolicySettings{
+					AllowedBinaryHashes: []string{
+						"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+					},
+				},
+			},
+			want: checker.CheckResult{
+				Score: checker.MaxResultScore,
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got := BinaryArtifacts(tt.args.name, tt.args.dl, tt.args.r)
+			got := BinaryArtifacts(tt.args.name, tt.args.dl, tt.args.r, tt.args.settings)
 			if tt.wantErr {
 				if got.Error == nil {
 					t.Errorf("BinaryArtifacts() error = %v, wantErr %v", got.Error, tt.wantErr)
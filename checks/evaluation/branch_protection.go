@@ -79,10 +79,16 @@ func BranchProtection(name string, dl checker.DetailLogger,
 		// Do we want this?
 		score.scores.adminThoroughReview, score.maxes.adminThoroughReview = adminThoroughReviewProtection(&b, dl)
 		score.scores.codeownerReview, score.maxes.codeownerReview = codeownersBranchProtection(&b, dl)
+		signedCommitsProtection(&b, dl)
 
 		scores = append(scores, score)
 	}
 
+	// Tag protections are informational and do not affect the score.
+	for i := range r.TagProtections {
+		tagProtection(&r.TagProtections[i], dl)
+	}
+
 	if len(scores) == 0 {
 		return checker.CreateInconclusiveResult(name, "unable to detect any development/release branches")
 	}
@@ -358,14 +364,27 @@ func nonAdminReviewProtection(branch *clients.BranchRef) (int, int) {
 	max := 0
 
 	max++
-	if branch.BranchProtectionRule.RequiredPullRequestReviews.RequiredApprovingReviewCount != nil &&
-		*branch.BranchProtectionRule.RequiredPullRequestReviews.RequiredApprovingReviewCount > 0 {
+	if n := requiredApprovingReviewCount(branch); n != nil && *n > 0 {
 		// We do not display anything here, it's done in nonAdminThoroughReviewProtection()
 		score++
 	}
 	return score, max
 }
 
+// requiredApprovingReviewCount returns the number of approving reviews
+// required from someone other than the author of a change.
+func requiredApprovingReviewCount(branch *clients.BranchRef) *int32 {
+	reviews := branch.BranchProtectionRule.RequiredPullRequestReviews
+	if reviews.RequiredApprovingReviewCount == nil {
+		return nil
+	}
+	n := *reviews.RequiredApprovingReviewCount
+	if reviews.AllowAuthorApproval != nil && *reviews.AllowAuthorApproval && n > 0 {
+		n--
+	}
+	return &n
+}
+
 func adminReviewProtection(branch *clients.BranchRef, dl checker.DetailLogger) (int, int) {
 	score := 0
 	max := 0
@@ -419,16 +438,18 @@ func nonAdminThoroughReviewProtection(branch *clients.BranchRef, dl checker.Deta
 	// Only log information if the branch is protected.
 	log := branch.Protected != nil && *branch.Protected
 
+	if allow := branch.BranchProtectionRule.RequiredPullRequestReviews.AllowAuthorApproval; allow != nil && *allow {
+		warn(dl, log, "authors can approve their own changes on branch '%s'", *branch.Name)
+	}
+
 	max++
-	if branch.BranchProtectionRule.RequiredPullRequestReviews.RequiredApprovingReviewCount != nil {
-		switch *branch.BranchProtectionRule.RequiredPullRequestReviews.RequiredApprovingReviewCount >= minReviews {
+	if n := requiredApprovingReviewCount(branch); n != nil {
+		switch *n >= minReviews {
 		case true:
-			info(dl, log, "number of required reviewers is %d on branch '%s'",
-				*branch.BranchProtectionRule.RequiredPullRequestReviews.RequiredApprovingReviewCount, *branch.Name)
+			info(dl, log, "number of required reviewers is %d on branch '%s'", *n, *branch.Name)
 			score++
 		default:
-			warn(dl, log, "number of required reviewers is only %d on branch '%s'",
-				*branch.BranchProtectionRule.RequiredPullRequestReviews.RequiredApprovingReviewCount, *branch.Name)
+			warn(dl, log, "number of required reviewers is only %d on branch '%s'", *n, *branch.Name)
 		}
 	} else {
 		warn(dl, log, "number of required reviewers is 0 on branch '%s'", *branch.Name)
@@ -454,3 +475,24 @@ func codeownersBranchProtection(branch *clients.BranchRef, dl checker.DetailLogg
 
 	return score, max
 }
+
+// signedCommitsProtection logs whether commits must be signed. It is not scored.
+func signedCommitsProtection(branch *clients.BranchRef, dl checker.DetailLogger) {
+	// Only log information if the branch is protected.
+	log := branch.Protected != nil && *branch.Protected
+
+	if branch.BranchProtectionRule.RequireSignedCommits != nil &&
+		*branch.BranchProtectionRule.RequireSignedCommits {
+		info(dl, log, "signed commits required on branch '%s'", *branch.Name)
+	}
+}
+
+func tagProtection(rule *clients.TagProtectionRule, dl checker.DetailLogger) {
+	info(dl, true, "tag protection enabled for tags matching '%s'", rule.Pattern)
+	if rule.AllowDeletions != nil && *rule.AllowDeletions {
+		warn(dl, true, "'allow deletion' enabled on tags matching '%s'", rule.Pattern)
+	}
+	if rule.AllowUpdates != nil && *rule.AllowUpdates {
+		warn(dl, true, "'allow update' enabled on tags matching '%s'", rule.Pattern)
+	}
+}
@@ -333,6 +333,37 @@ func TestIsBranchProtected(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Branches are protected but authors can approve their own changes",
+			expected: scut.TestReturn{
+				Error:         nil,
+				Score:         4,
+				NumberOfWarn:  2,
+				NumberOfInfo:  7,
+				NumberOfDebug: 0,
+			},
+			branch: &clients.BranchRef{
+				Name:      &branchVal,
+				Protected: &trueVal,
+				BranchProtectionRule: clients.BranchProtectionRule{
+					EnforceAdmins:        &trueVal,
+					RequireLinearHistory: &trueVal,
+					AllowForcePushes:     &falseVal,
+					AllowDeletions:       &falseVal,
+					CheckRules: clients.StatusChecksRule{
+						RequiresStatusChecks: &falseVal,
+						UpToDateBeforeMerge:  &trueVal,
+						Contexts:             []string{"foo"},
+					},
+					RequiredPullRequestReviews: clients.PullRequestReviewRule{
+						DismissStaleReviews:          &trueVal,
+						RequireCodeOwnerReviews:      &trueVal,
+						RequiredApprovingReviewCount: &oneVal,
+						AllowAuthorApproval:          &trueVal,
+					},
+				},
+			},
+		},
 		{
 			name: "Branches are protected and require codeowner review",
 			expected: scut.TestReturn{
@@ -40,7 +40,7 @@ func Contributors(name string, dl checker.DetailLogger,
 	entities := make(map[string]bool)
 
 	for _, user := range r.Users {
-		if user.NumContributions < minContributionsPerUser {
+		if user.IsBot || user.NumContributions < minContributionsPerUser {
 			continue
 		}
 
@@ -60,7 +60,7 @@ func Contributors(name string, dl checker.DetailLogger,
 
 	sort.Strings(names)
 
-	if len(name) > 0 {
+	if len(names) > 0 {
 		dl.Info(&checker.LogMessage{
 			Text: fmt.Sprintf("contributors work for %v", strings.Join(names, ",")),
 		})
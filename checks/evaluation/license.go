@@ -15,13 +15,16 @@
 package evaluation
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/ossf/scorecard/v4/checker"
 	sce "github.com/ossf/scorecard/v4/errors"
 )
 
 // License applies the score policy for the License check.
 func License(name string, dl checker.DetailLogger,
-	r *checker.LicenseData,
+	r *checker.LicenseData, settings *checker.PolicySettings,
 ) checker.CheckResult {
 	if r == nil {
 		e := sce.WithMessage(sce.ErrScorecardInternal, "empty raw data")
@@ -29,17 +32,165 @@ func License(name string, dl checker.DetailLogger,
 	}
 
 	// Apply the policy evaluation.
-	if r.Files == nil || len(r.Files) == 0 {
+	if len(r.LicenseFiles) == 0 {
 		return checker.CreateMinScoreResult(name, "license file not detected")
 	}
 
-	for _, f := range r.Files {
-		dl.Info(&checker.LogMessage{
-			Path:   f.Path,
+	if settings == nil {
+		settings = &checker.PolicySettings{}
+	}
+	lp := licensePolicy{
+		allowed: settings.AllowedLicenses,
+		denied:  settings.DeniedLicenses,
+	}
+
+	var detected []string
+	violations := 0
+	for i := range r.LicenseFiles {
+		f := &r.LicenseFiles[i]
+		msg := checker.LogMessage{
+			Path:   f.File.Path,
 			Type:   checker.FileTypeSource,
 			Offset: 1,
-		})
+		}
+		id := f.LicenseInformation.SpdxID
+		if id == "" {
+			msg.Text = "license file detected, license not identified"
+			dl.Info(&msg)
+			continue
+		}
+		detected = append(detected, id)
+		msg.Text = fmt.Sprintf("license file detected: %s (%.0f%% confidence)",
+			id, 100*f.LicenseInformation.Confidence)
+		dl.Info(&msg)
+		if !lp.evaluate(id, msg, dl) {
+			violations++
+		}
+	}
+
+	for i := range r.SourceHeaders {
+		h := &r.SourceHeaders[i]
+		msg := checker.LogMessage{
+			Path:   h.File.Path,
+			Type:   checker.FileTypeSource,
+			Offset: h.File.Offset,
+		}
+		if !lp.evaluate(h.Expression, msg, dl) {
+			violations++
+		}
+	}
+
+	for i := range r.ManifestLicenses {
+		m := &r.ManifestLicenses[i]
+		msg := checker.LogMessage{
+			Path: m.File.Path,
+			Type: checker.FileTypeSource,
+		}
+		if !lp.evaluate(m.Expression, msg, dl) {
+			violations++
+		}
+		if len(detected) > 0 && !licensesConsistent(m.Expression, detected) {
+			msg.Text = fmt.Sprintf("manifest declares license '%s', license file contains '%s'",
+				m.Expression, strings.Join(detected, "', '"))
+			dl.Warn(&msg)
+		}
+	}
+
+	if violations > 0 {
+		return checker.CreateMinScoreResult(name,
+			fmt.Sprintf("%d license(s) not permitted by policy", violations))
+	}
+	if len(detected) == 0 && len(lp.allowed) > 0 {
+		return checker.CreateInconclusiveResult(name,
+			"license file detected, but its license could not be verified against the policy")
 	}
 
 	return checker.CreateMaxScoreResult(name, "license file detected")
 }
+
+type licensePolicy struct {
+	allowed []string
+	denied  []string
+}
+
+// evaluate logs a warning and returns false if the SPDX expression
+// is not permitted by the policy.
+func (p *licensePolicy) evaluate(expr string, msg checker.LogMessage, dl checker.DetailLogger) bool {
+	if len(p.allowed) == 0 && len(p.denied) == 0 {
+		return true
+	}
+	e, err := parseSPDXExpression(expr)
+	if err != nil {
+		msg.Text = fmt.Sprintf("invalid SPDX license expression '%s': %v", expr, err)
+		dl.Warn(&msg)
+		return len(p.allowed) == 0
+	}
+	if e.permitted(p) {
+		return true
+	}
+	msg.Text = fmt.Sprintf("license '%s' is not permitted by policy", expr)
+	dl.Warn(&msg)
+	return false
+}
+
+func (p *licensePolicy) permits(id string) bool {
+	if containsLicense(p.denied, id) {
+		return false
+	}
+	return len(p.allowed) == 0 || containsLicense(p.allowed, id)
+}
+
+// containsLicense reports whether id is in ids. Comparison is case-insensitive,
+// and an identifier without an `-only`/`-or-later` suffix (e.g. `GPL-3.0`)
+// matches both variants.
+func containsLicense(ids []string, id string) bool {
+	id = canonicalLicenseID(id)
+	for _, l := range ids {
+		l = canonicalLicenseID(l)
+		if l == id || l == licenseFamily(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func canonicalLicenseID(id string) string {
+	id = strings.ToLower(strings.TrimSpace(id))
+	// Deprecated `+` suffix, e.g. `GPL-2.0+`.
+	if strings.HasSuffix(id, "+") {
+		id = strings.TrimSuffix(id, "+") + "-or-later"
+	}
+	return id
+}
+
+func licenseFamily(id string) string {
+	id = canonicalLicenseID(id)
+	return strings.TrimSuffix(strings.TrimSuffix(id, "-only"), "-or-later")
+}
+
+// licensesConsistent reports whether the SPDX expression mentions
+// every license identified in the license files. The `-only` and
+// `-or-later` variants of a license are considered consistent, since
+// they cannot be told apart from the license text.
+func licensesConsistent(expr string, detected []string) bool {
+	e, err := parseSPDXExpression(expr)
+	if err != nil {
+		return false
+	}
+	families := make(map[string]bool)
+	for _, id := range e.licenseIDs() {
+		families[licenseFamily(id)] = true
+	}
+	for _, d := range detected {
+		de, err := parseSPDXExpression(d)
+		if err != nil {
+			return false
+		}
+		for _, id := range de.licenseIDs() {
+			if !families[licenseFamily(id)] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluation
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	errSPDXEmptyExpression   = errors.New("empty expression")
+	errSPDXUnexpectedToken   = errors.New("unexpected token")
+	errSPDXUnbalancedBracket = errors.New("unbalanced parenthesis")
+)
+
+// spdxExpression is a node of a parsed SPDX license expression:
+// either a license identifier or an AND/OR of sub-expressions.
+// See https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/.
+type spdxExpression struct {
+	// licenseID is set for leaves. Exceptions (`WITH ...`) are dropped.
+	licenseID string
+	// operator is "AND" or "OR" for inner nodes.
+	operator string
+	operands []*spdxExpression
+}
+
+// permitted reports whether the licensee can satisfy the expression
+// using only licenses permitted by the policy.
+func (e *spdxExpression) permitted(p *licensePolicy) bool {
+	switch e.operator {
+	case "OR":
+		for _, o := range e.operands {
+			if o.permitted(p) {
+				return true
+			}
+		}
+		return false
+	case "AND":
+		for _, o := range e.operands {
+			if !o.permitted(p) {
+				return false
+			}
+		}
+		return true
+	default:
+		return p.permits(e.licenseID)
+	}
+}
+
+func (e *spdxExpression) licenseIDs() []string {
+	if e.operator == "" {
+		return []string{e.licenseID}
+	}
+	var ids []string
+	for _, o := range e.operands {
+		ids = append(ids, o.licenseIDs()...)
+	}
+	return ids
+}
+
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseSPDXExpression(expr string) (*spdxExpression, error) {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	p := spdxParser{tokens: strings.Fields(expr)}
+	if len(p.tokens) == 0 {
+		return nil, errSPDXEmptyExpression
+	}
+	e, err := p.parseOperator("OR")
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, errSPDXUnexpectedToken
+	}
+	return e, nil
+}
+
+func (p *spdxParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// parseOperator parses a sequence of operands joined by `op`.
+// OR has lower precedence than AND, which has lower precedence than WITH.
+func (p *spdxParser) parseOperator(op string) (*spdxExpression, error) {
+	parseOperand := p.parseWith
+	if op == "OR" {
+		parseOperand = func() (*spdxExpression, error) { return p.parseOperator("AND") }
+	}
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	e := &spdxExpression{operator: op, operands: []*spdxExpression{first}}
+	for strings.EqualFold(p.peek(), op) {
+		p.pos++
+		next, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		e.operands = append(e.operands, next)
+	}
+	if len(e.operands) == 1 {
+		return first, nil
+	}
+	return e, nil
+}
+
+func (p *spdxParser) parseWith() (*spdxExpression, error) {
+	e, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(p.peek(), "WITH") {
+		p.pos += 2
+		if p.pos > len(p.tokens) {
+			return nil, errSPDXUnexpectedToken
+		}
+	}
+	return e, nil
+}
+
+func (p *spdxParser) parseAtom() (*spdxExpression, error) {
+	tok := p.peek()
+	switch {
+	case tok == "(":
+		p.pos++
+		e, err := p.parseOperator("OR")
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errSPDXUnbalancedBracket
+		}
+		p.pos++
+		return e, nil
+	case tok == "", tok == ")", isSPDXOperator(tok):
+		return nil, errSPDXUnexpectedToken
+	default:
+		p.pos++
+		return &spdxExpression{licenseID: tok}, nil
+	}
+}
+
+func isSPDXOperator(tok string) bool {
+	return strings.EqualFold(tok, "AND") || strings.EqualFold(tok, "OR") || strings.EqualFold(tok, "WITH")
+}
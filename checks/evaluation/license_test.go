@@ -0,0 +1,229 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluation
+
+import (
+	"testing"
+
+	"github.com/ossf/scorecard/v4/checker"
+	sce "github.com/ossf/scorecard/v4/errors"
+	scut "github.com/ossf/scorecard/v4/utests"
+)
+
+func licenseFile(path, id string) checker.LicenseFile {
+	return checker.LicenseFile{
+		File: checker.File{Path: path},
+		LicenseInformation: checker.License{
+			SpdxID:      id,
+			Attribution: checker.LicenseAttributionTypeText,
+			Confidence:  1,
+		},
+	}
+}
+
+func TestLicense(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		r        *checker.LicenseData
+		settings *checker.PolicySettings
+		expected scut.TestReturn
+	}{
+		{
+			name: "nil raw data",
+			expected: scut.TestReturn{
+				Error: sce.ErrScorecardInternal,
+				Score: checker.InconclusiveResultScore,
+			},
+		},
+		{
+			name: "no license file",
+			r:    &checker.LicenseData{},
+			expected: scut.TestReturn{
+				Score: checker.MinResultScore,
+			},
+		},
+		{
+			name: "unidentified license, no policy",
+			r: &checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{licenseFile("LICENSE", "")},
+			},
+			expected: scut.TestReturn{
+				Score:        checker.MaxResultScore,
+				NumberOfInfo: 1,
+			},
+		},
+		{
+			name: "unidentified license, allowlist",
+			r: &checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{licenseFile("LICENSE", "")},
+			},
+			settings: &checker.PolicySettings{AllowedLicenses: []string{"MIT"}},
+			expected: scut.TestReturn{
+				Score:        checker.InconclusiveResultScore,
+				NumberOfInfo: 1,
+			},
+		},
+		{
+			name: "allowed license",
+			r: &checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{licenseFile("LICENSE", "Apache-2.0")},
+			},
+			settings: &checker.PolicySettings{AllowedLicenses: []string{"apache-2.0", "MIT"}},
+			expected: scut.TestReturn{
+				Score:        checker.MaxResultScore,
+				NumberOfInfo: 1,
+			},
+		},
+		{
+			name: "license not in allowlist",
+			r: &checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{licenseFile("LICENSE", "GPL-3.0-only")},
+			},
+			settings: &checker.PolicySettings{AllowedLicenses: []string{"MIT"}},
+			expected: scut.TestReturn{
+				Score:        checker.MinResultScore,
+				NumberOfInfo: 1,
+				NumberOfWarn: 1,
+			},
+		},
+		{
+			name: "denied license family",
+			r: &checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{licenseFile("LICENSE", "AGPL-3.0-only")},
+			},
+			settings: &checker.PolicySettings{DeniedLicenses: []string{"AGPL-3.0"}},
+			expected: scut.TestReturn{
+				Score:        checker.MinResultScore,
+				NumberOfInfo: 1,
+				NumberOfWarn: 1,
+			},
+		},
+		{
+			name: "denied license in source header",
+			r: &checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{licenseFile("LICENSE", "MIT")},
+				SourceHeaders: []checker.LicenseHeader{
+					{Expression: "GPL-2.0+", File: checker.File{Path: "a.c", Offset: 1}, NumFiles: 3},
+				},
+			},
+			settings: &checker.PolicySettings{DeniedLicenses: []string{"GPL-2.0-or-later"}},
+			expected: scut.TestReturn{
+				Score:        checker.MinResultScore,
+				NumberOfInfo: 1,
+				NumberOfWarn: 1,
+			},
+		},
+		{
+			name: "dual license file not in allowlist",
+			r: &checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{
+					licenseFile("LICENSE-APACHE", "Apache-2.0"),
+					licenseFile("LICENSE-MIT", "MIT"),
+				},
+				ManifestLicenses: []checker.ManifestLicense{
+					{Expression: "(MIT OR Apache-2.0)", File: checker.File{Path: "Cargo.toml"}},
+				},
+			},
+			settings: &checker.PolicySettings{AllowedLicenses: []string{"Apache-2.0"}},
+			expected: scut.TestReturn{
+				Score:        checker.MinResultScore,
+				NumberOfInfo: 2,
+				NumberOfWarn: 1,
+			},
+		},
+		{
+			name: "manifest inconsistent with license file",
+			r: &checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{licenseFile("LICENSE", "Apache-2.0")},
+				ManifestLicenses: []checker.ManifestLicense{
+					{Expression: "MIT", File: checker.File{Path: "package.json"}},
+					{Expression: "Apache-2.0 WITH LLVM-exception", File: checker.File{Path: "sub/package.json"}},
+				},
+			},
+			expected: scut.TestReturn{
+				Score:        checker.MaxResultScore,
+				NumberOfInfo: 1,
+				NumberOfWarn: 1,
+			},
+		},
+		{
+			name: "invalid manifest expression",
+			r: &checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{licenseFile("LICENSE", "MIT")},
+				ManifestLicenses: []checker.ManifestLicense{
+					{Expression: "MIT OR (", File: checker.File{Path: "package.json"}},
+				},
+			},
+			settings: &checker.PolicySettings{AllowedLicenses: []string{"MIT"}},
+			expected: scut.TestReturn{
+				Score:        checker.MinResultScore,
+				NumberOfInfo: 1,
+				NumberOfWarn: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dl := scut.TestDetailLogger{}
+			res := License(tt.name, &dl, tt.r, tt.settings)
+			if !scut.ValidateTestReturn(t, tt.name, &tt.expected, &res, &dl) {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestParseSPDXExpression(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expr    string
+		ids     []string
+		wantErr bool
+	}{
+		{expr: "MIT", ids: []string{"MIT"}},
+		{expr: "MIT OR Apache-2.0", ids: []string{"MIT", "Apache-2.0"}},
+		{expr: "(MIT and BSD-3-Clause) or GPL-2.0-only WITH Classpath-exception-2.0", ids: []string{"MIT", "BSD-3-Clause", "GPL-2.0-only"}},
+		{expr: "LicenseRef-Proprietary", ids: []string{"LicenseRef-Proprietary"}},
+		{expr: "", wantErr: true},
+		{expr: "MIT AND", wantErr: true},
+		{expr: "(MIT", wantErr: true},
+		{expr: "MIT Apache-2.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+			e, err := parseSPDXExpression(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSPDXExpression(%q): unexpected error: %v", tt.expr, err)
+			}
+			if err != nil {
+				return
+			}
+			ids := e.licenseIDs()
+			if len(ids) != len(tt.ids) {
+				t.Fatalf("parseSPDXExpression(%q): got %v, want %v", tt.expr, ids, tt.ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.ids[i] {
+					t.Errorf("parseSPDXExpression(%q): got %v, want %v", tt.expr, ids, tt.ids)
+				}
+			}
+		})
+	}
+}
@@ -16,12 +16,24 @@ package evaluation
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
 )
 
+// Points deducted per vulnerability, by severity. Vulnerabilities
+// of unknown severity cost as much as medium ones.
+var severityPenalties = map[string]float64{
+	clients.SeverityCritical: 5,
+	clients.SeverityHigh:     3,
+	clients.SeverityMedium:   1,
+	clients.SeverityLow:      0.5,
+	"":                       1,
+}
+
 // Vulnerabilities applies the score policy for the Vulnerabilities check.
 func Vulnerabilities(name string, dl checker.DetailLogger,
 	r *checker.VulnerabilitiesData,
@@ -31,24 +43,112 @@ func Vulnerabilities(name string, dl checker.DetailLogger,
 		return checker.CreateRuntimeErrorResult(name, e)
 	}
 
-	score := checker.MaxResultScore
-	IDs := []string{}
-	for _, vuln := range r.Vulnerabilities {
-		IDs = append(IDs, vuln.ID)
-		score--
+	var penalty float64
+	for i := range r.Vulnerabilities {
+		vuln := &r.Vulnerabilities[i]
+		penalty += severityPenalties[vuln.Severity]
+		dl.Warn(&checker.LogMessage{
+			Text: vulnerabilityText(vuln),
+		})
 	}
 
-	if score < checker.MinResultScore {
-		score = checker.MinResultScore
+	// Vulnerabilities the repo justifies not being affected by don't lower the score.
+	for i := range r.NotAffected {
+		na := &r.NotAffected[i]
+		dl.Info(&checker.LogMessage{
+			Path: na.File.Path,
+			Type: na.File.Type,
+			Text: notAffectedText(na),
+		})
 	}
 
-	if len(IDs) > 0 {
-		dl.Warn(&checker.LogMessage{
-			Text: fmt.Sprintf("HEAD is vulnerable to %s", strings.Join(IDs, ", ")),
+	// The packages of the lockfiles which couldn't be parsed weren't queried.
+	for i := range r.UnparsedLockfiles {
+		u := &r.UnparsedLockfiles[i]
+		dl.Info(&checker.LogMessage{
+			Path: u.File.Path,
+			Type: u.File.Type,
+			Text: fmt.Sprintf("lockfile skipped, its dependencies weren't checked: %s", u.Error),
 		})
+	}
+
+	// The statements of the VEX documents which couldn't be parsed weren't applied.
+	for i := range r.UnparsedVEXDocuments {
+		u := &r.UnparsedVEXDocuments[i]
+		dl.Info(&checker.LogMessage{
+			Path: u.File.Path,
+			Type: u.File.Type,
+			Text: fmt.Sprintf("VEX document skipped, its statements weren't applied: %s", u.Error),
+		})
+	}
+
+	if len(r.Vulnerabilities) > 0 {
+		score := checker.MaxResultScore - int(math.Ceil(penalty))
+		if score < checker.MinResultScore {
+			score = checker.MinResultScore
+		}
 		return checker.CreateResultWithScore(name,
-			fmt.Sprintf("%v existing vulnerabilities detected", len(IDs)), score)
+			fmt.Sprintf("%v existing vulnerabilities detected", len(r.Vulnerabilities)), score)
 	}
 
 	return checker.CreateMaxScoreResult(name, "no vulnerabilities detected")
 }
+
+func vulnerabilityText(vuln *clients.Vulnerability) string {
+	var sb strings.Builder
+	switch {
+	case vuln.Package.Version == "":
+		sb.WriteString("HEAD is vulnerable to ")
+	case len(vuln.OtherPackages) == 0:
+		fmt.Fprintf(&sb, "dependency %s is vulnerable to ", packagesText(vuln))
+	default:
+		fmt.Fprintf(&sb, "dependencies %s are vulnerable to ", packagesText(vuln))
+	}
+	sb.WriteString(vuln.ID)
+	if len(vuln.Aliases) > 0 {
+		fmt.Fprintf(&sb, " (%s)", strings.Join(vuln.Aliases, ", "))
+	}
+	severity := vuln.Severity
+	if severity == "" {
+		severity = "unknown"
+	}
+	fmt.Fprintf(&sb, ", severity: %s", severity)
+	if vuln.CVSSScore > 0 {
+		fmt.Fprintf(&sb, " (CVSS %.1f)", vuln.CVSSScore)
+	}
+	if vuln.FixedVersion != "" {
+		fmt.Fprintf(&sb, ", fixed in %s", vuln.FixedVersion)
+	}
+	return sb.String()
+}
+
+// packagesText lists the dependencies affected by the vuln, e.g. "foo 1.0 (npm), foo 2.0 (npm)".
+func packagesText(vuln *clients.Vulnerability) string {
+	var texts []string
+	for _, p := range vuln.Packages() {
+		texts = append(texts, fmt.Sprintf("%s %s (%s)", p.Name, p.Version, p.Ecosystem))
+	}
+	return strings.Join(texts, ", ")
+}
+
+func notAffectedText(na *checker.NotAffectedVulnerability) string {
+	var sb strings.Builder
+	switch {
+	case na.Vulnerability.Package.Version == "":
+		sb.WriteString("HEAD is")
+	case len(na.Vulnerability.OtherPackages) == 0:
+		fmt.Fprintf(&sb, "dependency %s is", packagesText(&na.Vulnerability))
+	default:
+		fmt.Fprintf(&sb, "dependencies %s are", packagesText(&na.Vulnerability))
+	}
+	fmt.Fprintf(&sb, " not affected by %s", na.Vulnerability.ID)
+	reason := na.Justification
+	if na.ImpactStatement != "" {
+		if reason != "" {
+			reason += ": "
+		}
+		reason += na.ImpactStatement
+	}
+	fmt.Fprintf(&sb, " (%s)", reason)
+	return sb.String()
+}
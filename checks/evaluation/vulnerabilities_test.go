@@ -66,6 +66,119 @@ func TestVulnerabilities(t *testing.T) {
 				Score: 9,
 			},
 		},
+		{
+			name: "severity-weighted vulnerabilities",
+			args: args{
+				name: "vulnerabilities_test.go",
+				r: &checker.VulnerabilitiesData{
+					Vulnerabilities: []clients.Vulnerability{
+						{
+							ID:       "GHSA-1",
+							Severity: clients.SeverityCritical,
+							Package: clients.Package{
+								Ecosystem: clients.EcosystemPyPI,
+								Name:      "django",
+								Version:   "3.2.1",
+							},
+							FixedVersion: "3.2.4",
+						},
+						{ID: "GHSA-2", Severity: clients.SeverityHigh},
+						{ID: "GHSA-3", Severity: clients.SeverityLow},
+					},
+				},
+			},
+			want: checker.CheckResult{
+				Score: 1,
+			},
+		},
+		{
+			name: "low severity vulnerabilities",
+			args: args{
+				name: "vulnerabilities_test.go",
+				r: &checker.VulnerabilitiesData{
+					Vulnerabilities: []clients.Vulnerability{
+						{ID: "GHSA-1", Severity: clients.SeverityLow},
+						{ID: "GHSA-2", Severity: clients.SeverityLow},
+						{ID: "GHSA-3", Severity: clients.SeverityLow},
+					},
+				},
+			},
+			want: checker.CheckResult{
+				Score: 8,
+			},
+		},
+		{
+			name: "critical vulnerabilities",
+			args: args{
+				name: "vulnerabilities_test.go",
+				r: &checker.VulnerabilitiesData{
+					Vulnerabilities: []clients.Vulnerability{
+						{ID: "GHSA-1", Severity: clients.SeverityCritical},
+						{ID: "GHSA-2", Severity: clients.SeverityCritical},
+						{ID: "GHSA-3", Severity: clients.SeverityCritical},
+					},
+				},
+			},
+			want: checker.CheckResult{
+				Score: 0,
+			},
+		},
+		{
+			name: "not affected vulnerabilities",
+			args: args{
+				name: "vulnerabilities_test.go",
+				r: &checker.VulnerabilitiesData{
+					Vulnerabilities: []clients.Vulnerability{
+						{ID: "GHSA-1", Severity: clients.SeverityLow},
+					},
+					NotAffected: []checker.NotAffectedVulnerability{
+						{
+							Vulnerability: clients.Vulnerability{ID: "GHSA-2", Severity: clients.SeverityCritical},
+							Justification: "vulnerable_code_not_in_execute_path",
+							File:          checker.File{Path: ".vex", Type: checker.FileTypeSource},
+						},
+					},
+				},
+			},
+			want: checker.CheckResult{
+				Score: 9,
+			},
+		},
+		{
+			name: "unparsed lockfiles",
+			args: args{
+				name: "vulnerabilities_test.go",
+				r: &checker.VulnerabilitiesData{
+					UnparsedLockfiles: []checker.UnparsedFile{
+						{
+							File:  checker.File{Path: "package-lock.json", Type: checker.FileTypeSource},
+							Error: "unexpected end of JSON input",
+						},
+					},
+				},
+			},
+			want: checker.CheckResult{
+				Score: 10,
+			},
+		},
+		{
+			name: "unparsed VEX documents",
+			args: args{
+				name: "vulnerabilities_test.go",
+				r: &checker.VulnerabilitiesData{
+					Vulnerabilities: []clients.Vulnerability{{ID: "GHSA-1", Severity: clients.SeverityHigh}},
+					UnparsedVEXDocuments: []checker.UnparsedFile{
+						{
+							File:  checker.File{Path: ".vex", Type: checker.FileTypeSource},
+							Error: "unexpected end of JSON input",
+						},
+					},
+				},
+			},
+			want: checker.CheckResult{
+				Score: 7,
+			},
+		},
 		{
 			name: "one vulnerability",
 			args: args{
@@ -88,3 +201,50 @@ func TestVulnerabilities(t *testing.T) {
 		})
 	}
 }
+
+func TestVulnerabilityText(t *testing.T) {
+	t.Parallel()
+	django := clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}
+	tests := []struct {
+		name string
+		vuln clients.Vulnerability
+		want string
+	}{
+		{
+			name: "HEAD",
+			vuln: clients.Vulnerability{ID: "OSV-1"},
+			want: "HEAD is vulnerable to OSV-1, severity: unknown",
+		},
+		{
+			name: "one dependency",
+			vuln: clients.Vulnerability{
+				ID:           "GHSA-1",
+				Aliases:      []string{"CVE-1"},
+				Severity:     clients.SeverityHigh,
+				CVSSScore:    7.5,
+				Package:      django,
+				FixedVersion: "3.2.5",
+			},
+			want: "dependency django 3.2.1 (PyPI) is vulnerable to GHSA-1 (CVE-1), severity: HIGH (CVSS 7.5), fixed in 3.2.5",
+		},
+		{
+			name: "several dependencies",
+			vuln: clients.Vulnerability{
+				ID:            "GHSA-1",
+				Severity:      clients.SeverityMedium,
+				Package:       django,
+				OtherPackages: []clients.Package{{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.1.5"}},
+			},
+			want: "dependencies django 3.2.1 (PyPI), django 3.1.5 (PyPI) are vulnerable to GHSA-1, severity: MEDIUM",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := vulnerabilityText(&tt.vuln); got != tt.want {
+				t.Errorf("vulnerabilityText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
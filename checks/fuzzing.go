@@ -18,6 +18,7 @@ import (
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/checks/evaluation"
 	"github.com/ossf/scorecard/v4/checks/raw"
+	"github.com/ossf/scorecard/v4/clients/githubrepo"
 	sce "github.com/ossf/scorecard/v4/errors"
 )
 
@@ -45,5 +46,9 @@ func Fuzzing(c *checker.CheckRequest) checker.CheckResult {
 		c.RawResults.FuzzingResults = rawData
 	}
 
+	if len(rawData.Fuzzers) == 0 && githubrepo.IsEnterpriseRepo(c.Repo) {
+		return checker.CreateInconclusiveResult(CheckFuzzing,
+			"no fuzzer detected, and OSS-Fuzz, on github.com only, is not checked for GitHub Enterprise Server repos")
+	}
 	return evaluation.Fuzzing(CheckFuzzing, c.Dlogger, &rawData)
 }
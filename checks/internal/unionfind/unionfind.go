@@ -0,0 +1,57 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package unionfind groups the items sharing a key, transitively.
+package unionfind
+
+// GroupByKeys groups n items so that the items sharing any of their keys are
+// in the same group, even through other items: an item whose keys lead to
+// several groups merges them. It returns, for each item, the index of the
+// first item of its group.
+func GroupByKeys(n int, keys func(i int) []string) []int {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	// First item with a given key.
+	seen := make(map[string]int)
+	for i := 0; i < n; i++ {
+		for _, k := range keys(i) {
+			j, ok := seen[k]
+			if !ok {
+				seen[k] = i
+				continue
+			}
+			// The group is rooted at its first item.
+			if ri, rj := find(i), find(j); ri < rj {
+				parent[rj] = ri
+			} else {
+				parent[ri] = rj
+			}
+		}
+	}
+
+	groups := make([]int, n)
+	for i := range groups {
+		groups[i] = find(i)
+	}
+	return groups
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unionfind
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroupByKeys(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		keys [][]string
+		want []int
+	}{
+		{
+			name: "no items",
+			want: []int{},
+		},
+		{
+			name: "distinct keys",
+			keys: [][]string{{"a"}, {"b"}, {"c"}},
+			want: []int{0, 1, 2},
+		},
+		{
+			name: "shared key",
+			keys: [][]string{{"a"}, {"b"}, {"c", "a"}},
+			want: []int{0, 1, 0},
+		},
+		{
+			name: "no keys",
+			keys: [][]string{{}, {"a"}, {}},
+			want: []int{0, 1, 2},
+		},
+		{
+			name: "item merging two groups",
+			keys: [][]string{{"a"}, {"b"}, {"c"}, {"c", "b"}, {"a", "c"}},
+			want: []int{0, 0, 0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := GroupByKeys(len(tt.keys), func(i int) []string { return tt.keys[i] })
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GroupByKeys() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -49,5 +49,5 @@ func License(c *checker.CheckRequest) checker.CheckResult {
 		c.RawResults.LicenseResults = rawData
 	}
 
-	return evaluation.License(CheckLicense, c.Dlogger, &rawData)
+	return evaluation.License(CheckLicense, c.Dlogger, &rawData, c.PolicySettings)
 }
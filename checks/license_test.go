@@ -47,6 +47,17 @@ func TestLicenseFileSubdirectory(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name:        "With MIT LICENSE and inconsistent package.json",
+			inputFolder: "testdata/licensedir/withmitlicense",
+			expected: scut.TestReturn{
+				Error:        nil,
+				Score:        checker.MaxResultScore,
+				NumberOfInfo: 1,
+				NumberOfWarn: 1,
+			},
+			err: nil,
+		},
 		{
 			name:        "Without LICENSE",
 			inputFolder: "testdata/licensedir/withoutlicense",
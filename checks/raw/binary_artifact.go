@@ -15,6 +15,8 @@
 package raw
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"path/filepath"
 	"regexp"
@@ -140,32 +142,40 @@ var checkBinaryFileContent fileparser.DoWhileTrueOnFileContent = func(path strin
 	if len(content) == 0 {
 		return true, nil
 	}
+	if binaryFormat(content) != "" {
+		*pfiles = append(*pfiles, binaryFile(path, content))
+		return true, nil
+	}
 	if t, err = filetype.Get(content); err != nil {
 		return false, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("filetype.Get:%v", err))
 	}
 
 	exists1 := binaryFileTypes[t.Extension]
 	if exists1 {
-		*pfiles = append(*pfiles, checker.File{
-			Path:   path,
-			Type:   checker.FileTypeBinary,
-			Offset: checker.OffsetDefault,
-		})
+		*pfiles = append(*pfiles, binaryFile(path, content))
 		return true, nil
 	}
 
 	exists2 := binaryFileTypes[strings.ReplaceAll(filepath.Ext(path), ".", "")]
 	if !isText(content) && exists2 {
-		*pfiles = append(*pfiles, checker.File{
-			Path:   path,
-			Type:   checker.FileTypeBinary,
-			Offset: checker.OffsetDefault,
-		})
+		*pfiles = append(*pfiles, binaryFile(path, content))
 	}
 
 	return true, nil
 }
 
+// binaryFile returns the finding for a binary file, identified by its hash.
+func binaryFile(path string, content []byte) checker.File {
+	sum := sha256.Sum256(content)
+	return checker.File{
+		Path:     path,
+		Type:     checker.FileTypeBinary,
+		Offset:   checker.OffsetDefault,
+		FileSize: uint(len(content)),
+		SHA256:   hex.EncodeToString(sum[:]),
+	}
+}
+
 // determines if the first 1024 bytes are text
 //
 //	A version of golang.org/x/tools/godoc/util modified to allow carriage returns
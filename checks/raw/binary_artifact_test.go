@@ -15,6 +15,10 @@
 package raw
 
 import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"testing"
@@ -247,3 +251,130 @@ func TestBinaryArtifacts(t *testing.T) {
 		})
 	}
 }
+
+func zipContent(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range names {
+		if _, err := w.Create(name); err != nil {
+			t.Fatalf("zip.Create: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("zip.Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestBinaryFormat(t *testing.T) {
+	t.Parallel()
+	pe := make([]byte, 0x80)
+	copy(pe, "MZ")
+	pe[0x3c] = 0x40
+	copy(pe[0x40:], "PE\x00\x00")
+	dosOnly := make([]byte, 0x80)
+	copy(dosOnly, "MZ")
+	dosOnly[0x3c] = 0x40
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{
+			name:    "ELF",
+			content: []byte("\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00"),
+			want:    "elf",
+		},
+		{
+			name:    "PE",
+			content: pe,
+			want:    "pe",
+		},
+		{
+			name:    "MZ without PE signature",
+			content: dosOnly,
+		},
+		{
+			name:    "Mach-O 64-bit",
+			content: []byte{0xcf, 0xfa, 0xed, 0xfe, 0x0c, 0x00, 0x00, 0x01},
+			want:    "macho",
+		},
+		{
+			name:    "Mach-O fat binary",
+			content: []byte{0xca, 0xfe, 0xba, 0xbe, 0x00, 0x00, 0x00, 0x02},
+			want:    "macho",
+		},
+		{
+			name:    "Java class",
+			content: []byte{0xca, 0xfe, 0xba, 0xbe, 0x00, 0x00, 0x00, 0x37},
+			want:    "class",
+		},
+		{
+			name:    "JAR",
+			content: zipContent(t, "META-INF/MANIFEST.MF", "org/example/Main.class"),
+			want:    "jar",
+		},
+		{
+			name:    "plain zip",
+			content: zipContent(t, "README.md"),
+		},
+		{
+			name:    "WASM",
+			content: []byte("\x00asm\x01\x00\x00\x00\x01\x04"),
+			want:    "wasm",
+		},
+		{
+			name:    "dex",
+			content: []byte("dex\n035\x00"),
+			want:    "dex",
+		},
+		{
+			name:    "Python 3.10 pyc",
+			content: []byte{0x6f, 0x0d, 0x0d, 0x0a, 0x00, 0x00, 0x00, 0x00, 0xe3, 0x00},
+			want:    "pyc",
+		},
+		{
+			name:    "text resembling a pyc magic",
+			content: []byte("ok\r\nthis is a text file\n"),
+		},
+		{
+			name:    "text",
+			content: []byte("#!/bin/sh\necho hello\n"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Re-initializing variable so it is not changed while executing the closure below
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := binaryFormat(tt.content); got != tt.want {
+				t.Errorf("binaryFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBinaryArtifactsHash(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockRepoClient := mockrepo.NewMockRepoClient(ctrl)
+	content := []byte("\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00")
+	mockRepoClient.EXPECT().ListFiles(gomock.Any()).Return([]string{"bin/tool"}, nil)
+	mockRepoClient.EXPECT().GetFileContent("bin/tool").Return(content, nil)
+
+	f, err := BinaryArtifacts(mockRepoClient)
+	if err != nil {
+		t.Fatalf("BinaryArtifacts: %v", err)
+	}
+	if len(f.Files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(f.Files))
+	}
+	sum := sha256.Sum256(content)
+	if got, want := f.Files[0].SHA256, hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("SHA256 = %s, want %s", got, want)
+	}
+	if f.Files[0].FileSize != uint(len(content)) {
+		t.Errorf("FileSize = %d, want %d", f.Files[0].FileSize, len(content))
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raw
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"strings"
+)
+
+// Java class files store their major version where Mach-O fat binaries
+// store their number of architectures. Class files start at version 45.
+const minJavaClassVersion = 45
+
+// Python 2 and Python 3 .pyc magic number ranges.
+const (
+	minPy2Magic = 20121
+	maxPy2Magic = 62211
+	minPy3Magic = 3000
+	maxPy3Magic = 3999
+)
+
+var (
+	elfMagic    = []byte("\x7fELF")
+	wasmMagic   = []byte("\x00asm\x01\x00\x00\x00")
+	dexMagic    = []byte("dex\n")
+	zipMagic    = []byte("PK\x03\x04")
+	machoMagics = [][]byte{
+		{0xfe, 0xed, 0xfa, 0xce},
+		{0xfe, 0xed, 0xfa, 0xcf},
+		{0xce, 0xfa, 0xed, 0xfe},
+		{0xcf, 0xfa, 0xed, 0xfe},
+	}
+	cafebabeMagic = []byte{0xca, 0xfe, 0xba, 0xbe}
+)
+
+// binaryFormat returns the name of the executable or archive format
+// identified by the magic bytes of content, or "" if there is none.
+// Detection does not depend on the file's name or extension.
+func binaryFormat(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, elfMagic):
+		return "elf"
+	case isPE(content):
+		return "pe"
+	case isMachO(content):
+		return "macho"
+	case isJavaClass(content):
+		return "class"
+	case isJAR(content):
+		return "jar"
+	case bytes.HasPrefix(content, wasmMagic):
+		return "wasm"
+	case bytes.HasPrefix(content, dexMagic):
+		return "dex"
+	case isPyc(content):
+		return "pyc"
+	default:
+		return ""
+	}
+}
+
+// isPE checks for the DOS stub followed by a PE signature
+// at the offset stored at 0x3c.
+func isPE(content []byte) bool {
+	if len(content) < 0x40 || !bytes.HasPrefix(content, []byte("MZ")) {
+		return false
+	}
+	offset := binary.LittleEndian.Uint32(content[0x3c:0x40])
+	if uint64(offset)+4 > uint64(len(content)) {
+		return false
+	}
+	return bytes.Equal(content[offset:offset+4], []byte("PE\x00\x00"))
+}
+
+func isMachO(content []byte) bool {
+	for _, m := range machoMagics {
+		if bytes.HasPrefix(content, m) {
+			return true
+		}
+	}
+	// Universal (fat) binaries share their magic with Java class files.
+	return bytes.HasPrefix(content, cafebabeMagic) && len(content) >= 8 &&
+		binary.BigEndian.Uint32(content[4:8]) < minJavaClassVersion
+}
+
+func isJavaClass(content []byte) bool {
+	return bytes.HasPrefix(content, cafebabeMagic) && len(content) >= 8 &&
+		binary.BigEndian.Uint32(content[4:8]) >= minJavaClassVersion
+}
+
+// isJAR checks for a zip archive containing a manifest or class files.
+func isJAR(content []byte) bool {
+	if !bytes.HasPrefix(content, zipMagic) {
+		return false
+	}
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return false
+	}
+	for _, f := range r.File {
+		if f.Name == "META-INF/MANIFEST.MF" || strings.HasSuffix(f.Name, ".class") {
+			return true
+		}
+	}
+	return false
+}
+
+// isPyc checks for a .pyc magic number: a little-endian version
+// number followed by "\r\n". Since the magic is made of printable
+// characters for some versions, the content must also not be text.
+func isPyc(content []byte) bool {
+	if len(content) < 8 || content[2] != '\r' || content[3] != '\n' {
+		return false
+	}
+	magic := binary.LittleEndian.Uint16(content[0:2])
+	if (magic < minPy3Magic || magic > maxPy3Magic) &&
+		(magic < minPy2Magic || magic > maxPy2Magic) {
+		return false
+	}
+	return !isText(content)
+}
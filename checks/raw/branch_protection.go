@@ -15,6 +15,7 @@
 package raw
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 
@@ -56,14 +57,14 @@ func BranchProtection(c clients.RepoClient) (checker.BranchProtectionsData, erro
 	// Add default branch.
 	defaultBranch, err := c.GetDefaultBranch()
 	if err != nil {
-		return checker.BranchProtectionsData{}, fmt.Errorf("%w", err)
+		return checker.BranchProtectionsData{}, fmt.Errorf("error during GetDefaultBranch: %w", err)
 	}
 	branches.add(defaultBranch)
 
 	// Get release branches.
 	releases, err := c.ListReleases()
 	if err != nil {
-		return checker.BranchProtectionsData{}, fmt.Errorf("%w", err)
+		return checker.BranchProtectionsData{}, fmt.Errorf("error during ListReleases: %w", err)
 	}
 	for _, release := range releases {
 		if release.TargetCommitish == "" {
@@ -105,9 +106,16 @@ func BranchProtection(c clients.RepoClient) (checker.BranchProtectionsData, erro
 		// Branch doesn't exist or was deleted. Continue.
 	}
 
+	// Get tag protection rules, if the client supports them.
+	tagProtections, err := c.ListTagProtectionRules()
+	if err != nil && !errors.Is(err, clients.ErrUnsupportedFeature) {
+		return checker.BranchProtectionsData{}, fmt.Errorf("error during ListTagProtectionRules: %w", err)
+	}
+
 	// No error, return the data.
 	return checker.BranchProtectionsData{
-		Branches: branches.set,
+		Branches:       branches.set,
+		TagProtections: tagProtections,
 	}, nil
 }
 
@@ -65,12 +65,14 @@ func TestBranchProtection(t *testing.T) {
 	t.Parallel()
 	//nolint: govet
 	tests := []struct {
-		name        string
-		branches    branchesArg
-		releases    []clients.Release
-		releasesErr error
-		want        checker.BranchProtectionsData
-		wantErr     error
+		name           string
+		branches       branchesArg
+		releases       []clients.Release
+		releasesErr    error
+		tagProtections []clients.TagProtectionRule
+		tagsErr        error
+		want           checker.BranchProtectionsData
+		wantErr        error
 	}{
 		{
 			name: "default-branch-err",
@@ -235,6 +237,69 @@ func TestBranchProtection(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "tag-protections",
+			branches: branchesArg{
+				{
+					name:          defaultBranchName,
+					defaultBranch: true,
+					branchRef: &clients.BranchRef{
+						Name: &defaultBranchName,
+					},
+				},
+			},
+			tagProtections: []clients.TagProtectionRule{
+				{
+					Pattern: "v*",
+				},
+			},
+			want: checker.BranchProtectionsData{
+				Branches: []clients.BranchRef{
+					{
+						Name: &defaultBranchName,
+					},
+				},
+				TagProtections: []clients.TagProtectionRule{
+					{
+						Pattern: "v*",
+					},
+				},
+			},
+		},
+		{
+			name: "tag-protections-unsupported",
+			branches: branchesArg{
+				{
+					name:          defaultBranchName,
+					defaultBranch: true,
+					branchRef: &clients.BranchRef{
+						Name: &defaultBranchName,
+					},
+				},
+			},
+			tagsErr: clients.ErrUnsupportedFeature,
+			want: checker.BranchProtectionsData{
+				Branches: []clients.BranchRef{
+					{
+						Name: &defaultBranchName,
+					},
+				},
+			},
+		},
+		{
+			name: "tag-protections-err",
+			branches: branchesArg{
+				{
+					name:          defaultBranchName,
+					defaultBranch: true,
+					branchRef: &clients.BranchRef{
+						Name: &defaultBranchName,
+					},
+				},
+			},
+			tagsErr: errBPTest,
+			wantErr: errBPTest,
+		},
 		// TODO: Add tests for commitSHA regex matching.
 	}
 	for _, tt := range tests {
@@ -255,6 +320,10 @@ func TestBranchProtection(t *testing.T) {
 				DoAndReturn(func() ([]clients.Release, error) {
 					return tt.releases, tt.releasesErr
 				})
+			mockRepoClient.EXPECT().ListTagProtectionRules().AnyTimes().
+				DoAndReturn(func() ([]clients.TagProtectionRule, error) {
+					return tt.tagProtections, tt.tagsErr
+				})
 
 			rawData, err := BranchProtection(mockRepoClient)
 			if !errors.Is(err, tt.wantErr) {
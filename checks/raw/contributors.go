@@ -16,14 +16,164 @@ package raw
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/checks/internal/unionfind"
 	"github.com/ossf/scorecard/v4/clients"
 )
 
+// Legal-entity suffixes dropped from company names.
+var companySuffixes = map[string]bool{
+	"ab": true, "ag": true, "bv": true, "co": true, "company": true,
+	"corp": true, "corporation": true, "gmbh": true, "inc": true,
+	"incorporated": true, "kk": true, "limited": true, "llc": true,
+	"ltd": true, "oy": true, "plc": true, "pty": true, "sa": true,
+	"sarl": true, "srl": true,
+}
+
+// companyAliases maps normalized alternate names of well-known
+// companies, and of the GitHub organizations they own, to a single name.
+var companyAliases = map[string][]string{
+	"alibaba":    {"alibaba group", "alibaba cloud", "aliyun", "alibaba-inc"},
+	"amazon":     {"aws", "amazon web services", "awslabs", "amazon com"},
+	"google":     {"alphabet", "google cloud", "googlecloudplatform", "googleapis", "google deepmind"},
+	"huawei":     {"huawei technologies", "huaweicloud"},
+	"ibm":        {"ibm research", "ibm cloud"},
+	"meta":       {"facebook", "meta platforms", "facebookresearch", "facebookincubator"},
+	"microsoft":  {"msft", "microsoft research", "azure"},
+	"red hat":    {"redhat", "redhatofficial", "red hat software"},
+	"salesforce": {"salesforce com"},
+	"vmware":     {"vmware tanzu"},
+}
+
+var (
+	companySeparators = regexp.MustCompile(`[\s,.()]+`)
+	// GitHub no-reply emails: [ID+]login@users.noreply.github.com.
+	githubNoReplyEmail = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+)
+
+// contributorsNormalizer normalizes company names and user identities.
+type contributorsNormalizer struct {
+	// aliases maps a normalized alternate company name to its canonical name.
+	aliases map[string]string
+	// identities maps a lower-cased alternate login or email to a canonical login.
+	identities map[string]string
+}
+
+func newContributorsNormalizer(mapping *checker.ContributorsMapping) *contributorsNormalizer {
+	n := contributorsNormalizer{
+		aliases:    make(map[string]string),
+		identities: make(map[string]string),
+	}
+	addAliases := func(companies map[string][]string) {
+		for canonical, alts := range companies {
+			canonical = n.normalizeCompanyName(canonical)
+			n.aliases[canonical] = canonical
+			for _, alt := range alts {
+				n.aliases[n.normalizeCompanyName(alt)] = canonical
+			}
+		}
+	}
+	addAliases(companyAliases)
+	if mapping != nil {
+		// User-supplied aliases take precedence over the curated ones.
+		addAliases(mapping.Companies)
+		for canonical, alts := range mapping.Identities {
+			n.identities[strings.ToLower(canonical)] = canonical
+			for _, alt := range alts {
+				n.identities[strings.ToLower(alt)] = canonical
+			}
+		}
+	}
+	return &n
+}
+
+// normalizeCompanyName lower-cases the name, strips punctuation, a leading
+// `@` and legal-entity suffixes, e.g. `@Google, LLC.` becomes `google`.
+func (n *contributorsNormalizer) normalizeCompanyName(name string) string {
+	words := companySeparators.Split(strings.ToLower(strings.TrimSpace(name)), -1)
+	var ret []string
+	for _, w := range words {
+		w = strings.TrimLeft(w, "@")
+		if w != "" {
+			ret = append(ret, w)
+		}
+	}
+	for len(ret) > 1 && companySuffixes[ret[len(ret)-1]] {
+		ret = ret[:len(ret)-1]
+	}
+	return strings.Join(ret, " ")
+}
+
+// companies returns the canonical company names in a free-form
+// company string. Strings made of several `@org` mentions,
+// e.g. `@google @kubernetes`, yield one company per mention.
+func (n *contributorsNormalizer) companies(company string) []string {
+	fields := strings.Fields(company)
+	mentions := len(fields) > 1
+	for _, f := range fields {
+		if !strings.HasPrefix(f, "@") {
+			mentions = false
+			break
+		}
+	}
+	if !mentions {
+		fields = []string{company}
+	}
+	var ret []string
+	for _, f := range fields {
+		if c := n.canonicalCompany(f); c != "" {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func (n *contributorsNormalizer) canonicalCompany(name string) string {
+	name = n.normalizeCompanyName(name)
+	if c, ok := n.aliases[name]; ok {
+		return c
+	}
+	return name
+}
+
+// identity returns the key identifying the person behind a login or email.
+func (n *contributorsNormalizer) identity(login string) string {
+	login = strings.ToLower(login)
+	if c, ok := n.identities[login]; ok {
+		return strings.ToLower(c)
+	}
+	if m := githubNoReplyEmail.FindStringSubmatch(login); m != nil {
+		if c, ok := n.identities[m[1]]; ok {
+			return strings.ToLower(c)
+		}
+		return m[1]
+	}
+	return login
+}
+
+// keys returns the keys identifying the user behind a contributor.
+func (n *contributorsNormalizer) keys(contrib *clients.User) []string {
+	var keys []string
+	if contrib.Login != "" {
+		keys = append(keys, "login:"+n.identity(contrib.Login))
+	}
+	if contrib.ID != 0 {
+		keys = append(keys, "id:"+strconv.FormatInt(contrib.ID, 10))
+	}
+	return keys
+}
+
+func isBot(user *clients.User) bool {
+	return user.IsBot || strings.HasSuffix(strings.ToLower(user.Login), "[bot]")
+}
+
 // Contributors retrieves the raw data for the Contributors check.
-func Contributors(c clients.RepoClient) (checker.ContributorsData, error) {
+func Contributors(c clients.RepoClient, mapping *checker.ContributorsMapping) (checker.ContributorsData, error) {
 	var users []clients.User
 
 	contribs, err := c.ListContributors()
@@ -31,37 +181,99 @@ func Contributors(c clients.RepoClient) (checker.ContributorsData, error) {
 		return checker.ContributorsData{}, fmt.Errorf("Client.Repositories.ListContributors: %w", err)
 	}
 
-	for _, contrib := range contribs {
-		user := clients.User{
-			Login:            contrib.Login,
-			NumContributions: contrib.NumContributions,
+	n := newContributorsNormalizer(mapping)
+	// The contributors sharing an identity or an ID are the same user. A
+	// contributor whose keys lead to several groups merges them, e.g. an
+	// email mapped to one login while its ID is that of another login.
+	groups := unionfind.GroupByKeys(len(contribs), func(i int) []string {
+		return n.keys(&contribs[i])
+	})
+
+	// Index in users of the user of a group, in the order of their first contributor.
+	indexes := make(map[int]int)
+	for i := range contribs {
+		contrib := &contribs[i]
+		group := groups[i]
+		index, ok := indexes[group]
+		if !ok {
+			index = len(users)
+			indexes[group] = index
+			login := contrib.Login
+			if canonical, ok := n.identities[strings.ToLower(login)]; ok {
+				login = canonical
+			}
+			users = append(users, clients.User{
+				Login: login,
+				ID:    contrib.ID,
+			})
+		}
+
+		user := &users[index]
+		if user.ID == 0 {
+			user.ID = contrib.ID
 		}
+		user.NumContributions += contrib.NumContributions
+		user.IsBot = user.IsBot || isBot(contrib)
 
 		for _, org := range contrib.Organizations {
-			if org.Login != "" && !orgContains(user.Organizations, org.Login) {
-				user.Organizations = append(user.Organizations, org)
+			login := n.canonicalCompany(org.Login)
+			if login != "" && !orgContains(user.Organizations, login) {
+				user.Organizations = append(user.Organizations, clients.User{Login: login})
 			}
 		}
 
 		for _, company := range contrib.Companies {
-			if company == "" {
-				continue
-			}
-			company = strings.ToLower(company)
-			company = strings.ReplaceAll(company, "inc.", "")
-			company = strings.ReplaceAll(company, "llc", "")
-			company = strings.ReplaceAll(company, ",", "")
-			company = strings.TrimLeft(company, "@")
-			company = strings.Trim(company, " ")
-			if company != "" && !companyContains(user.Companies, company) {
-				user.Companies = append(user.Companies, company)
+			for _, name := range n.companies(company) {
+				if !companyContains(user.Companies, name) {
+					user.Companies = append(user.Companies, name)
+				}
 			}
 		}
+	}
+
+	return checker.ContributorsData{
+		Users:         users,
+		Organizations: contributorOrganizations(users),
+	}, nil
+}
 
-		users = append(users, user)
+// contributorOrganizations aggregates the contributions of
+// non-bot users per organization and company.
+func contributorOrganizations(users []clients.User) []checker.ContributorOrganization {
+	orgs := make(map[string]*checker.ContributorOrganization)
+	for i := range users {
+		user := &users[i]
+		if user.IsBot {
+			continue
+		}
+		names := append([]string{}, user.Companies...)
+		for _, org := range user.Organizations {
+			if !companyContains(names, org.Login) {
+				names = append(names, org.Login)
+			}
+		}
+		for _, name := range names {
+			o, ok := orgs[name]
+			if !ok {
+				o = &checker.ContributorOrganization{Name: name}
+				orgs[name] = o
+			}
+			o.NumContributors++
+			o.NumContributions += user.NumContributions
+		}
 	}
 
-	return checker.ContributorsData{Users: users}, nil
+	ret := make([]checker.ContributorOrganization, 0, len(orgs))
+	for _, o := range orgs {
+		ret = append(ret, *o)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].NumContributions != ret[j].NumContributions {
+			return ret[i].NumContributions > ret[j].NumContributions
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
 }
 
 func companyContains(cs []string, name string) bool {
@@ -0,0 +1,210 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raw
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/clients"
+	mockrepo "github.com/ossf/scorecard/v4/clients/mockclients"
+)
+
+func TestNormalizeCompany(t *testing.T) {
+	t.Parallel()
+	mapping := &checker.ContributorsMapping{
+		Companies: map[string][]string{
+			"Example": {"Example Holdings", "exmpl"},
+		},
+	}
+	tests := []struct {
+		company string
+		want    []string
+	}{
+		{company: "Google LLC", want: []string{"google"}},
+		{company: "@google", want: []string{"google"}},
+		{company: "  Google, Inc. ", want: []string{"google"}},
+		{company: "Alphabet Inc.", want: []string{"google"}},
+		{company: "@facebook @kubernetes", want: []string{"meta", "kubernetes"}},
+		{company: "Red Hat, Inc.", want: []string{"red hat"}},
+		{company: "EXMPL Ltd", want: []string{"example"}},
+		{company: "example holdings", want: []string{"example"}},
+		{company: "Company", want: []string{"company"}},
+		{company: "", want: nil},
+	}
+	n := newContributorsNormalizer(mapping)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.company, func(t *testing.T) {
+			t.Parallel()
+			if got := n.companies(tt.company); !cmp.Equal(got, tt.want) {
+				t.Errorf("companies(%q) = %v, want %v", tt.company, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContributors(t *testing.T) {
+	t.Parallel()
+	mapping := &checker.ContributorsMapping{
+		Identities: map[string][]string{
+			"alice": {"alice@example.com", "Alice.Work@example.com"},
+		},
+	}
+	contribs := []clients.User{
+		{
+			Login:            "alice",
+			ID:               1,
+			NumContributions: 10,
+			Companies:        []string{"Google LLC"},
+		},
+		{
+			Login:            "alice@example.com",
+			NumContributions: 5,
+			Companies:        []string{"@google"},
+		},
+		{
+			Login:            "alice.work@example.com",
+			NumContributions: 1,
+		},
+		{
+			Login:            "bob",
+			ID:               2,
+			NumContributions: 7,
+			Organizations:    []clients.User{{Login: "facebookresearch"}},
+		},
+		{
+			Login:            "12345+bob@users.noreply.github.com",
+			NumContributions: 3,
+		},
+		{
+			Login:            "dependabot[bot]",
+			NumContributions: 100,
+			Companies:        []string{"GitHub"},
+		},
+		{
+			Login:            "release-robot",
+			IsBot:            true,
+			NumContributions: 50,
+			Companies:        []string{"GitHub"},
+		},
+	}
+	want := checker.ContributorsData{
+		Users: []clients.User{
+			{
+				Login:            "alice",
+				ID:               1,
+				NumContributions: 16,
+				Companies:        []string{"google"},
+			},
+			{
+				Login:            "bob",
+				ID:               2,
+				NumContributions: 10,
+				Organizations:    []clients.User{{Login: "meta"}},
+			},
+			{
+				Login:            "dependabot[bot]",
+				NumContributions: 100,
+				Companies:        []string{"github"},
+				IsBot:            true,
+			},
+			{
+				Login:            "release-robot",
+				NumContributions: 50,
+				Companies:        []string{"github"},
+				IsBot:            true,
+			},
+		},
+		Organizations: []checker.ContributorOrganization{
+			{Name: "google", NumContributors: 1, NumContributions: 16},
+			{Name: "meta", NumContributors: 1, NumContributions: 10},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mockrepo.NewMockRepoClient(ctrl)
+	mockRepo.EXPECT().ListContributors().Return(contribs, nil)
+
+	got, err := Contributors(mockRepo, mapping)
+	if err != nil {
+		t.Fatalf("Contributors: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Contributors() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestContributorsMergesUsers(t *testing.T) {
+	t.Parallel()
+	mapping := &checker.ContributorsMapping{
+		Identities: map[string][]string{
+			"carol": {"carol@corp.example"},
+		},
+	}
+	contribs := []clients.User{
+		{
+			Login:            "carol",
+			ID:               3,
+			NumContributions: 4,
+			Companies:        []string{"Example Corp"},
+		},
+		{
+			Login:            "carol-work",
+			ID:               4,
+			NumContributions: 2,
+		},
+		{
+			Login:            "dave",
+			ID:               5,
+			NumContributions: 1,
+		},
+		// The mapping sends the email to carol while the ID is carol-work's:
+		// both are the same user.
+		{
+			Login:            "carol@corp.example",
+			ID:               4,
+			NumContributions: 1,
+		},
+	}
+	want := []clients.User{
+		{
+			Login:            "carol",
+			ID:               3,
+			NumContributions: 7,
+			Companies:        []string{"example"},
+		},
+		{
+			Login:            "dave",
+			ID:               5,
+			NumContributions: 1,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mockrepo.NewMockRepoClient(ctrl)
+	mockRepo.EXPECT().ListContributors().Return(contribs, nil)
+
+	got, err := Contributors(mockRepo, mapping)
+	if err != nil {
+		t.Fatalf("Contributors: %v", err)
+	}
+	if diff := cmp.Diff(want, got.Users); diff != "" {
+		t.Errorf("Contributors() users mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/checks/fileparser"
 	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/clients/githubrepo"
 	sce "github.com/ossf/scorecard/v4/errors"
 )
 
@@ -166,7 +167,8 @@ func checkOneFuzz(c *checker.CheckRequest) (bool, error) {
 }
 
 func checkOSSFuzz(c *checker.CheckRequest) (bool, error) {
-	if c.OssFuzzRepo == nil {
+	// OSS-Fuzz only builds public projects.
+	if c.OssFuzzRepo == nil || githubrepo.IsEnterpriseRepo(c.Repo) {
 		return false, nil
 	}
 
@@ -16,11 +16,14 @@ package raw
 
 import (
 	"fmt"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/checks/fileparser"
+	"github.com/ossf/scorecard/v4/clients"
 )
 
 type check func(str string, extCheck []string) bool
@@ -67,24 +70,67 @@ var (
 // License retrieves the raw data for the License check.
 func License(c *checker.CheckRequest) (checker.LicenseData, error) {
 	var results checker.LicenseData
-	var path string
 
-	err := fileparser.OnAllFilesDo(c.RepoClient, isLicenseFile, &path)
+	paths, err := listLicenseFiles(c.RepoClient)
 	if err != nil {
-		return results, fmt.Errorf("fileparser.OnAllFilesDo: %w", err)
+		return results, err
 	}
-
-	if path != "" {
-		results.Files = append(results.Files,
-			checker.File{
-				Path: path,
-				Type: checker.FileTypeSource,
+	for _, path := range paths {
+		content, err := c.RepoClient.GetFileContent(path)
+		if err != nil {
+			return results, fmt.Errorf("RepoClient.GetFileContent: %w", err)
+		}
+		results.LicenseFiles = append(results.LicenseFiles,
+			checker.LicenseFile{
+				File: checker.File{
+					Path:     path,
+					Type:     checker.FileTypeSource,
+					FileSize: uint(len(content)),
+				},
+				LicenseInformation: classifyLicense(content),
 			})
 	}
 
+	results.SourceHeaders, err = collectLicenseHeaders(c.RepoClient)
+	if err != nil {
+		return results, err
+	}
+
+	results.ManifestLicenses, err = collectManifestLicenses(c.RepoClient)
+	if err != nil {
+		return results, err
+	}
+
 	return results, nil
 }
 
+// listLicenseFiles returns the license files in the top-level directory
+// and in a REUSE-style `LICENSES` directory. If there are none, it falls
+// back to the first file anywhere in the repo named like a license file.
+func listLicenseFiles(c clients.RepoClient) ([]string, error) {
+	var first string
+	err := fileparser.OnAllFilesDo(c, isLicenseFile, &first)
+	if err != nil {
+		return nil, fmt.Errorf("fileparser.OnAllFilesDo: %w", err)
+	}
+
+	files, err := c.ListFiles(func(name string) (bool, error) {
+		dir := path.Dir(name)
+		if dir != "." && !strings.EqualFold(dir, "LICENSES") {
+			return false, nil
+		}
+		return checkLicense(name), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("RepoClient.ListFiles: %w", err)
+	}
+	if len(files) == 0 && first != "" {
+		files = append(files, first)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 // ExtensionMatch to check for matching extension.
 func extensionMatch(f string, exts []string) bool {
 	s := strings.Split(f, ".")
@@ -0,0 +1,239 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raw
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ossf/scorecard/v4/checker"
+)
+
+// minLicenseConfidence is the minimum fraction of a license's
+// distinguishing phrases that must be found in a file for the
+// file to be classified as that license.
+const minLicenseConfidence = 0.5
+
+// licenseTemplate describes a license by phrases that appear,
+// after normalization, in every copy of its text.
+type licenseTemplate struct {
+	spdxID  string
+	phrases []string
+}
+
+// Phrases are stored normalized: lower case, punctuation replaced by spaces.
+// When a license's phrases are a superset of another's (e.g. BSD-3-Clause and
+// BSD-2-Clause), the one with the most matched phrases wins ties.
+var licenseTemplates = []licenseTemplate{
+	{
+		spdxID: "Apache-2.0",
+		phrases: []string{
+			"apache license version 2 0 january 2004",
+			"terms and conditions for use reproduction and distribution",
+			"grant of copyright license",
+			"grant of patent license",
+			"licensed under the apache license version 2 0",
+		},
+	},
+	{
+		spdxID: "MIT",
+		phrases: []string{
+			"permission is hereby granted free of charge to any person obtaining a copy",
+			"to deal in the software without restriction",
+			"the above copyright notice and this permission notice shall be included in all copies or substantial portions of the software",
+			"the software is provided as is without warranty of any kind",
+		},
+	},
+	{
+		spdxID: "ISC",
+		phrases: []string{
+			"permission to use copy modify and or distribute this software for any purpose with or without fee is hereby granted",
+			"provided that the above copyright notice and this permission notice appear in all copies",
+			"the software is provided as is and the author disclaims all warranties",
+		},
+	},
+	{
+		spdxID: "0BSD",
+		phrases: []string{
+			"permission to use copy modify and or distribute this software for any purpose with or without fee is hereby granted",
+			"the software is provided as is and the author disclaims all warranties",
+		},
+	},
+	{
+		spdxID: "BSD-2-Clause",
+		phrases: []string{
+			"redistribution and use in source and binary forms with or without modification are permitted provided that the following conditions are met",
+			"redistributions of source code must retain the above copyright notice",
+			"redistributions in binary form must reproduce the above copyright notice",
+		},
+	},
+	{
+		spdxID: "BSD-3-Clause",
+		phrases: []string{
+			"redistribution and use in source and binary forms with or without modification are permitted provided that the following conditions are met",
+			"redistributions of source code must retain the above copyright notice",
+			"redistributions in binary form must reproduce the above copyright notice",
+			"may be used to endorse or promote products derived from this software without specific prior written permission",
+		},
+	},
+	{
+		spdxID: "GPL-2.0-only",
+		phrases: []string{
+			"gnu general public license version 2 june 1991",
+			"everyone is permitted to copy and distribute verbatim copies of this license document but changing it is not allowed",
+			"the licenses for most software are designed to take away your freedom to share and change it",
+		},
+	},
+	{
+		spdxID: "GPL-3.0-only",
+		phrases: []string{
+			"gnu general public license version 3 29 june 2007",
+			"the gnu general public license is a free copyleft license for software and other kinds of works",
+			"everyone is permitted to copy and distribute verbatim copies of this license document but changing it is not allowed",
+		},
+	},
+	{
+		spdxID: "LGPL-2.1-only",
+		phrases: []string{
+			"gnu lesser general public license version 2 1 february 1999",
+			"this is the first released version of the lesser gpl",
+			"everyone is permitted to copy and distribute verbatim copies of this license document but changing it is not allowed",
+		},
+	},
+	{
+		spdxID: "LGPL-3.0-only",
+		phrases: []string{
+			"gnu lesser general public license version 3 29 june 2007",
+			"this version of the gnu lesser general public license incorporates the terms and conditions of version 3 of the gnu general public license",
+			"everyone is permitted to copy and distribute verbatim copies of this license document but changing it is not allowed",
+		},
+	},
+	{
+		spdxID: "AGPL-3.0-only",
+		phrases: []string{
+			"gnu affero general public license version 3 19 november 2007",
+			"the gnu affero general public license is a free copyleft license for software and other kinds of works",
+			"everyone is permitted to copy and distribute verbatim copies of this license document but changing it is not allowed",
+		},
+	},
+	{
+		spdxID: "MPL-2.0",
+		phrases: []string{
+			"mozilla public license version 2 0",
+			"covered software is provided under this license on an as is basis",
+			"this source code form is subject to the terms of the mozilla public license v 2 0",
+		},
+	},
+	{
+		spdxID: "EPL-2.0",
+		phrases: []string{
+			"eclipse public license v 2 0",
+			"the accompanying program is provided under the terms of this eclipse public license",
+		},
+	},
+	{
+		spdxID: "BSL-1.0",
+		phrases: []string{
+			"boost software license version 1 0 august 17th 2003",
+			"permission is hereby granted free of charge to any person or organization obtaining a copy of the software and accompanying documentation covered by this license",
+		},
+	},
+	{
+		spdxID: "Unlicense",
+		phrases: []string{
+			"this is free and unencumbered software released into the public domain",
+			"anyone is free to copy modify publish use compile sell or distribute this software",
+			"unlicense org",
+		},
+	},
+	{
+		spdxID: "CC0-1.0",
+		phrases: []string{
+			"creative commons legal code",
+			"cc0 1 0 universal",
+			"public license fallback",
+		},
+	},
+}
+
+var (
+	nonAlphanumeric   = regexp.MustCompile(`[^a-z0-9]+`)
+	spdxIdentifierTag = regexp.MustCompile(`SPDX-License-Identifier:[ \t]*([^\r\n]+)`)
+	// Comment terminators that may follow an SPDX expression on the same line.
+	spdxTagSuffixes = []string{"*/", "-->", "#}", "--%>"}
+)
+
+// normalizeLicenseText lower-cases the text and collapses everything that
+// is not a letter or a digit into single spaces, so that line wrapping,
+// comment markers and punctuation do not affect matching.
+func normalizeLicenseText(content []byte) string {
+	s := nonAlphanumeric.ReplaceAllString(strings.ToLower(string(content)), " ")
+	return " " + strings.TrimSpace(s) + " "
+}
+
+// findSPDXIdentifier returns the expression of the first
+// `SPDX-License-Identifier:` tag found in content, if any.
+func findSPDXIdentifier(content []byte) string {
+	m := spdxIdentifierTag.FindSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	expr := string(m[1])
+	for _, suffix := range spdxTagSuffixes {
+		if i := strings.Index(expr, suffix); i >= 0 {
+			expr = expr[:i]
+		}
+	}
+	return strings.TrimSpace(expr)
+}
+
+// classifyLicense identifies the license contained in content.
+// An explicit SPDX tag takes precedence over text matching.
+func classifyLicense(content []byte) checker.License {
+	if expr := findSPDXIdentifier(content); expr != "" {
+		return checker.License{
+			SpdxID:      expr,
+			Confidence:  1,
+			Attribution: checker.LicenseAttributionTypeSPDXTag,
+		}
+	}
+
+	text := normalizeLicenseText(content)
+	var best checker.License
+	bestMatched := 0
+	for i := range licenseTemplates {
+		t := &licenseTemplates[i]
+		matched := 0
+		for _, p := range t.phrases {
+			if strings.Contains(text, " "+p+" ") {
+				matched++
+			}
+		}
+		confidence := float64(matched) / float64(len(t.phrases))
+		if confidence < minLicenseConfidence {
+			continue
+		}
+		if confidence > best.Confidence ||
+			(confidence == best.Confidence && matched > bestMatched) {
+			best = checker.License{
+				SpdxID:      t.spdxID,
+				Confidence:  confidence,
+				Attribution: checker.LicenseAttributionTypeText,
+			}
+			bestMatched = matched
+		}
+	}
+	return best
+}
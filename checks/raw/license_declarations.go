@@ -0,0 +1,223 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raw
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/clients"
+)
+
+const (
+	// SPDX tags are expected in the first lines of a source file.
+	maxLicenseHeaderLines = 30
+	// Only the headers of the first source files are read, as each file
+	// is read in full: enough to tell the licenses of the project.
+	maxLicenseHeaderFiles = 500
+)
+
+var licenseHeaderExtensions = map[string]bool{
+	".c": true, ".cc": true, ".cpp": true, ".cs": true, ".go": true,
+	".h": true, ".hpp": true, ".java": true, ".js": true, ".jsx": true,
+	".kt": true, ".m": true, ".php": true, ".pl": true, ".proto": true,
+	".py": true, ".rb": true, ".rs": true, ".scala": true, ".sh": true,
+	".swift": true, ".ts": true, ".tsx": true,
+}
+
+// Directories holding third-party code, whose licenses
+// are not the project's own.
+var vendoredDirs = []string{"vendor", "node_modules", "third_party", "testdata"}
+
+func isVendoredPath(fullpath string) bool {
+	for _, dir := range strings.Split(path.Dir(fullpath), "/") {
+		for _, v := range vendoredDirs {
+			if dir == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectLicenseHeaders returns the distinct SPDX expressions declared
+// in the headers of the repo's source files, up to maxLicenseHeaderFiles
+// files in path order.
+func collectLicenseHeaders(c clients.RepoClient) ([]checker.LicenseHeader, error) {
+	files, err := c.ListFiles(func(name string) (bool, error) {
+		return licenseHeaderExtensions[strings.ToLower(path.Ext(name))] && !isVendoredPath(name), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("RepoClient.ListFiles: %w", err)
+	}
+	sort.Strings(files)
+	if len(files) > maxLicenseHeaderFiles {
+		files = files[:maxLicenseHeaderFiles]
+	}
+
+	headers := make(map[string]*checker.LicenseHeader)
+	var order []string
+	for _, file := range files {
+		content, err := c.GetFileContent(file)
+		if err != nil {
+			return nil, fmt.Errorf("RepoClient.GetFileContent: %w", err)
+		}
+		expr, line := findSPDXHeader(content)
+		if expr == "" {
+			continue
+		}
+		h, ok := headers[expr]
+		if !ok {
+			h = &checker.LicenseHeader{
+				Expression: expr,
+				File: checker.File{
+					Path:   file,
+					Type:   checker.FileTypeSource,
+					Offset: line,
+				},
+			}
+			headers[expr] = h
+			order = append(order, expr)
+		}
+		h.NumFiles++
+	}
+	ret := make([]checker.LicenseHeader, 0, len(order))
+	for _, expr := range order {
+		ret = append(ret, *headers[expr])
+	}
+	return ret, nil
+}
+
+// findSPDXHeader returns the SPDX expression in the first lines
+// of content and the line it was found on.
+func findSPDXHeader(content []byte) (string, uint) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := uint(1); line <= maxLicenseHeaderLines && scanner.Scan(); line++ {
+		if expr := findSPDXIdentifier(scanner.Bytes()); expr != "" {
+			return expr, line
+		}
+	}
+	return "", 0
+}
+
+// Manifest file names and the function extracting the license they declare.
+var manifestLicenseParsers = map[string]func([]byte) string{
+	"package.json": parsePackageJSONLicense,
+	"Cargo.toml":   parseCargoTomlLicense,
+	"setup.cfg":    parseSetupCfgLicense,
+}
+
+// collectManifestLicenses returns the licenses declared in package manifests.
+func collectManifestLicenses(c clients.RepoClient) ([]checker.ManifestLicense, error) {
+	files, err := c.ListFiles(func(name string) (bool, error) {
+		_, ok := manifestLicenseParsers[path.Base(name)]
+		return ok && !isVendoredPath(name), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("RepoClient.ListFiles: %w", err)
+	}
+	sort.Strings(files)
+
+	var ret []checker.ManifestLicense
+	for _, file := range files {
+		content, err := c.GetFileContent(file)
+		if err != nil {
+			return nil, fmt.Errorf("RepoClient.GetFileContent: %w", err)
+		}
+		expr := manifestLicenseParsers[path.Base(file)](content)
+		if expr == "" {
+			continue
+		}
+		ret = append(ret, checker.ManifestLicense{
+			File: checker.File{
+				Path: file,
+				Type: checker.FileTypeSource,
+			},
+			Expression: expr,
+		})
+	}
+	return ret, nil
+}
+
+func parsePackageJSONLicense(content []byte) string {
+	var pkg struct {
+		// Either an SPDX expression or, in older packages,
+		// an object with a `type` field.
+		License  json.RawMessage `json:"license"`
+		Licenses []struct {
+			Type string `json:"type"`
+		} `json:"licenses"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return ""
+	}
+	var expr string
+	if err := json.Unmarshal(pkg.License, &expr); err == nil {
+		return strings.TrimSpace(expr)
+	}
+	var typed struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(pkg.License, &typed); err == nil && typed.Type != "" {
+		return typed.Type
+	}
+	var types []string
+	for _, l := range pkg.Licenses {
+		if l.Type != "" {
+			types = append(types, l.Type)
+		}
+	}
+	if len(types) > 1 {
+		return "(" + strings.Join(types, " OR ") + ")"
+	}
+	return strings.Join(types, "")
+}
+
+func parseCargoTomlLicense(content []byte) string {
+	return parseINILikeValue(content, "package", "license", `"'`)
+}
+
+func parseSetupCfgLicense(content []byte) string {
+	return parseINILikeValue(content, "metadata", "license", "")
+}
+
+// parseINILikeValue returns the value of `key` in `[section]` for
+// INI-like formats such as setup.cfg and simple TOML files.
+func parseINILikeValue(content []byte, section, key, quotes string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	inSection := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inSection = line == "["+section+"]"
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		k, v, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(k) != key {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(v), quotes)
+	}
+	return ""
+}
@@ -15,7 +15,14 @@
 package raw
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ossf/scorecard/v4/checker"
+	mockrepo "github.com/ossf/scorecard/v4/clients/mockclients"
 )
 
 func TestLicenseFileCheck(t *testing.T) {
@@ -118,3 +125,249 @@ func TestLicenseFileCheck(t *testing.T) {
 		}
 	}
 }
+
+const mitLicense = `MIT License
+
+Copyright (c) 2022 Example Authors
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY.
+`
+
+const bsd2License = `Copyright (c) 2022, Example Authors
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice, this
+   list of conditions and the following disclaimer.
+
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation
+   and/or other materials provided with the distribution.
+`
+
+const bsd3License = bsd2License + `
+3. Neither the name of the copyright holder nor the names of its
+   contributors may be used to endorse or promote products derived from
+   this software without specific prior written permission.
+`
+
+func TestClassifyLicense(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		content     string
+		spdxID      string
+		attribution checker.LicenseAttributionType
+		confidence  float64
+	}{
+		{
+			name:        "MIT",
+			content:     mitLicense,
+			spdxID:      "MIT",
+			attribution: checker.LicenseAttributionTypeText,
+			confidence:  1,
+		},
+		{
+			name:        "MIT without warranty disclaimer",
+			content:     mitLicense[:strings.Index(mitLicense, "THE SOFTWARE")],
+			spdxID:      "MIT",
+			attribution: checker.LicenseAttributionTypeText,
+			confidence:  0.75,
+		},
+		{
+			name:        "BSD-2-Clause",
+			content:     bsd2License,
+			spdxID:      "BSD-2-Clause",
+			attribution: checker.LicenseAttributionTypeText,
+			confidence:  1,
+		},
+		{
+			name:        "BSD-3-Clause",
+			content:     bsd3License,
+			spdxID:      "BSD-3-Clause",
+			attribution: checker.LicenseAttributionTypeText,
+			confidence:  1,
+		},
+		{
+			name: "Apache-2.0",
+			content: `
+                                 Apache License
+                           Version 2.0, January 2004
+                        http://www.apache.org/licenses/
+
+   TERMS AND CONDITIONS FOR USE, REPRODUCTION, AND DISTRIBUTION
+   2. Grant of Copyright License.
+   3. Grant of Patent License.
+   Licensed under the Apache License, Version 2.0 (the "License");`,
+			spdxID:      "Apache-2.0",
+			attribution: checker.LicenseAttributionTypeText,
+			confidence:  1,
+		},
+		{
+			name:        "SPDX tag",
+			content:     "SPDX-License-Identifier: MIT OR Apache-2.0\n\n" + bsd2License,
+			spdxID:      "MIT OR Apache-2.0",
+			attribution: checker.LicenseAttributionTypeSPDXTag,
+			confidence:  1,
+		},
+		{
+			name:    "unknown",
+			content: "All rights reserved.",
+		},
+		{
+			name:    "empty",
+			content: "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := classifyLicense([]byte(tt.content))
+			if got.SpdxID != tt.spdxID || got.Attribution != tt.attribution || got.Confidence != tt.confidence {
+				t.Errorf("classifyLicense() = %+v, want %s/%s/%v", got, tt.spdxID, tt.attribution, tt.confidence)
+			}
+		})
+	}
+}
+
+func TestFindSPDXHeader(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		expr    string
+		line    uint
+	}{
+		{
+			name:    "go",
+			content: "// Copyright 2022 Example\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n",
+			expr:    "Apache-2.0",
+			line:    2,
+		},
+		{
+			name:    "c block comment",
+			content: "/* SPDX-License-Identifier: GPL-2.0-only WITH Linux-syscall-note */\n",
+			expr:    "GPL-2.0-only WITH Linux-syscall-note",
+			line:    1,
+		},
+		{
+			name:    "html comment",
+			content: "<!-- SPDX-License-Identifier: CC-BY-4.0 -->",
+			expr:    "CC-BY-4.0",
+			line:    1,
+		},
+		{
+			name:    "too far from the top",
+			content: strings.Repeat("\n", maxLicenseHeaderLines) + "# SPDX-License-Identifier: MIT\n",
+		},
+		{
+			name:    "none",
+			content: "package main\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			expr, line := findSPDXHeader([]byte(tt.content))
+			if expr != tt.expr || line != tt.line {
+				t.Errorf("findSPDXHeader() = %q, %d, want %q, %d", expr, line, tt.expr, tt.line)
+			}
+		})
+	}
+}
+
+func TestCollectLicenseHeadersMaxFiles(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockRepoClient := mockrepo.NewMockRepoClient(ctrl)
+	files := make([]string, 0, maxLicenseHeaderFiles+10)
+	for i := 0; i < maxLicenseHeaderFiles+10; i++ {
+		files = append(files, fmt.Sprintf("src/file%04d.go", i))
+	}
+	mockRepoClient.EXPECT().ListFiles(gomock.Any()).Return(files, nil)
+	mockRepoClient.EXPECT().GetFileContent(gomock.Any()).
+		Return([]byte("// SPDX-License-Identifier: MIT\n"), nil).
+		Times(maxLicenseHeaderFiles)
+
+	headers, err := collectLicenseHeaders(mockRepoClient)
+	if err != nil {
+		t.Fatalf("collectLicenseHeaders: %v", err)
+	}
+	if len(headers) != 1 || headers[0].NumFiles != maxLicenseHeaderFiles {
+		t.Errorf("collectLicenseHeaders() = %+v, want MIT in %d files", headers, maxLicenseHeaderFiles)
+	}
+}
+
+func TestManifestLicenseParsers(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		manifest string
+		content  string
+		expr     string
+	}{
+		{
+			name:     "package.json expression",
+			manifest: "package.json",
+			content:  `{"name": "x", "license": "(MIT OR Apache-2.0)"}`,
+			expr:     "(MIT OR Apache-2.0)",
+		},
+		{
+			name:     "package.json legacy object",
+			manifest: "package.json",
+			content:  `{"license": {"type": "ISC", "url": "https://example.com"}}`,
+			expr:     "ISC",
+		},
+		{
+			name:     "package.json legacy list",
+			manifest: "package.json",
+			content:  `{"licenses": [{"type": "MIT"}, {"type": "Apache-2.0"}]}`,
+			expr:     "(MIT OR Apache-2.0)",
+		},
+		{
+			name:     "package.json invalid",
+			manifest: "package.json",
+			content:  `{"license":`,
+		},
+		{
+			name:     "Cargo.toml",
+			manifest: "Cargo.toml",
+			content:  "[package]\nname = \"x\"\nlicense = \"MIT OR Apache-2.0\"\n\n[dependencies]\nlicense = \"1\"\n",
+			expr:     "MIT OR Apache-2.0",
+		},
+		{
+			name:     "Cargo.toml without package license",
+			manifest: "Cargo.toml",
+			content:  "[dependencies]\nlicense = \"1\"\n",
+		},
+		{
+			name:     "setup.cfg",
+			manifest: "setup.cfg",
+			content:  "[metadata]\nname = x\nlicense = BSD-3-Clause\n",
+			expr:     "BSD-3-Clause",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := manifestLicenseParsers[tt.manifest]([]byte(tt.content)); got != tt.expr {
+				t.Errorf("got %q, want %q", got, tt.expr)
+			}
+		})
+	}
+}
@@ -15,30 +15,286 @@
 package raw
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/checks/internal/unionfind"
 	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/clients/lockfile"
+	"github.com/ossf/scorecard/v4/openvex"
 )
 
 // Vulnerabilities retrieves the raw data for the Vulnerabilities check.
+// It queries vulnerabilities affecting the HEAD commit and
+// the packages pinned by the lockfiles of the repo.
 func Vulnerabilities(c *checker.CheckRequest) (checker.VulnerabilitiesData, error) {
+	var vulns []clients.Vulnerability
+
 	commits, err := c.RepoClient.ListCommits()
-	if err != nil {
+	switch {
+	case errors.Is(err, clients.ErrUnsupportedFeature):
+		// Local directories have no commits, only lockfiles.
+	case err != nil:
 		return checker.VulnerabilitiesData{}, fmt.Errorf("repoClient.ListCommits: %w", err)
+	case len(commits) > 0 && !allOf(commits, hasEmptySHA):
+		resp, err := c.VulnerabilitiesClient.HasUnfixedVulnerabilities(c.Ctx, commits[0].SHA)
+		if err != nil && !errors.Is(err, clients.ErrUnsupportedFeature) {
+			return checker.VulnerabilitiesData{}, fmt.Errorf("vulnerabilitiesClient.HasUnfixedVulnerabilities: %w", err)
+		}
+		vulns = append(vulns, resp.Vulnerabilities...)
+	}
+
+	pkgs, unparsed, err := lockfilePackages(c.RepoClient)
+	if err != nil {
+		return checker.VulnerabilitiesData{}, err
+	}
+	if len(pkgs) > 0 {
+		resp, err := c.VulnerabilitiesClient.ListUnfixedVulnerabilities(c.Ctx, pkgs)
+		if err != nil {
+			return checker.VulnerabilitiesData{}, fmt.Errorf("vulnerabilitiesClient.ListUnfixedVulnerabilities: %w", err)
+		}
+		vulns = append(vulns, resp.Vulnerabilities...)
+	}
+
+	statements, unparsedVEX, err := vexStatements(c.RepoClient)
+	if err != nil {
+		return checker.VulnerabilitiesData{}, err
+	}
+
+	// VEX statements only apply to the repo as product.
+	var product string
+	if c.Repo != nil {
+		product = openvex.RepoPackageURL(c.Repo.URI(), "")
+	}
+	data := checker.VulnerabilitiesData{UnparsedLockfiles: unparsed, UnparsedVEXDocuments: unparsedVEX}
+	vulns = mergeVulnerabilities(vulns)
+	for i := range vulns {
+		vuln := vulns[i]
+		if s := notAffectedStatement(&vuln, statements, product); s != nil {
+			data.NotAffected = append(data.NotAffected, checker.NotAffectedVulnerability{
+				Vulnerability:   vuln,
+				Justification:   s.Justification,
+				ImpactStatement: s.ImpactStatement,
+				File:            s.file,
+			})
+			continue
+		}
+		data.Vulnerabilities = append(data.Vulnerabilities, vuln)
+	}
+	return data, nil
+}
+
+// vexStatement is a statement of a VEX document of the repo.
+type vexStatement struct {
+	openvex.Statement
+	file checker.File
+}
+
+// vexStatements returns the statements of the OpenVEX documents of the repo,
+// and the documents which couldn't be parsed.
+func vexStatements(repoClient clients.RepoClient) ([]vexStatement, []checker.UnparsedFile, error) {
+	files, err := repoClient.ListFiles(func(path string) (bool, error) {
+		isTestdata := strings.HasPrefix(path, "testdata/") || strings.Contains(path, "/testdata/")
+		return !isTestdata && openvex.IsVEXFile(path), nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("repoClient.ListFiles: %w", err)
+	}
+
+	var statements []vexStatement
+	var unparsed []checker.UnparsedFile
+	for _, file := range files {
+		content, err := repoClient.GetFileContent(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("repoClient.GetFileContent: %w", err)
+		}
+		doc, err := openvex.Parse(content)
+		if err != nil {
+			// A malformed VEX document shouldn't prevent using the others.
+			unparsed = append(unparsed, checker.UnparsedFile{
+				File: checker.File{
+					Path: file,
+					Type: checker.FileTypeSource,
+				},
+				Error: err.Error(),
+			})
+			continue
+		}
+		for i := range doc.Statements {
+			statements = append(statements, vexStatement{
+				Statement: doc.Statements[i],
+				file: checker.File{
+					Path: file,
+					Type: checker.FileTypeSource,
+				},
+			})
+		}
+	}
+	return statements, unparsed, nil
+}
+
+// notAffectedStatement returns the statement marking the vuln as not affecting the repo, if any.
+// Only statements justifying it, with a justification or an impact statement, and listing
+// the package URL of the repo as product are honored. Products listing subcomponents only
+// apply to the vulns of these packages, so they must list every package of the vuln.
+func notAffectedStatement(vuln *clients.Vulnerability, statements []vexStatement, product string) *vexStatement {
+	ids := append([]string{vuln.ID}, vuln.Aliases...)
+	for i := range statements {
+		s := &statements[i]
+		if s.Status != openvex.StatusNotAffected || (s.Justification == "" && s.ImpactStatement == "") {
+			continue
+		}
+		if !containsAny(ids, append([]string{s.Vulnerability.Name}, s.Vulnerability.Aliases...)) {
+			continue
+		}
+		for _, p := range s.Products {
+			if product != "" && openvex.SamePackage(p.ID, product) && appliesToPackages(p.Subcomponents, vuln.Packages()) {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+func appliesToPackages(subcomponents []openvex.Subcomponent, pkgs []clients.Package) bool {
+	for _, p := range pkgs {
+		if !appliesToPackage(subcomponents, p) {
+			return false
+		}
 	}
+	return true
+}
 
-	if len(commits) < 1 || allOf(commits, hasEmptySHA) {
-		return checker.VulnerabilitiesData{}, nil
+func appliesToPackage(subcomponents []openvex.Subcomponent, p clients.Package) bool {
+	if len(subcomponents) == 0 {
+		return true
 	}
+	if p.Version == "" {
+		// Vulns of HEAD have no package to match.
+		return false
+	}
+	purl := lockfile.PackageURL(p)
+	unversioned, _, _ := strings.Cut(purl, "@")
+	for _, sub := range subcomponents {
+		// Subcomponents without a version apply to every version of the package.
+		if sub.ID == purl || sub.ID == unversioned {
+			return true
+		}
+	}
+	return false
+}
 
-	resp, err := c.VulnerabilitiesClient.HasUnfixedVulnerabilities(c.Ctx, commits[0].SHA)
+// lockfilePackages returns the packages pinned by the lockfiles of the repo,
+// and the lockfiles which couldn't be parsed.
+func lockfilePackages(repoClient clients.RepoClient) ([]clients.Package, []checker.UnparsedFile, error) {
+	files, err := repoClient.ListFiles(func(path string) (bool, error) {
+		isTestdata := strings.HasPrefix(path, "testdata/") || strings.Contains(path, "/testdata/")
+		return !isTestdata && lockfile.IsLockfile(path), nil
+	})
 	if err != nil {
-		return checker.VulnerabilitiesData{}, fmt.Errorf("vulnerabilitiesClient.HasUnfixedVulnerabilities: %w", err)
+		return nil, nil, fmt.Errorf("repoClient.ListFiles: %w", err)
+	}
+
+	var pkgs []clients.Package
+	var unparsed []checker.UnparsedFile
+	for _, file := range files {
+		content, err := repoClient.GetFileContent(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("repoClient.GetFileContent: %w", err)
+		}
+		filePkgs, err := lockfile.Parse(file, content)
+		if err != nil {
+			// A malformed lockfile shouldn't prevent scanning the others.
+			unparsed = append(unparsed, checker.UnparsedFile{
+				File: checker.File{
+					Path: file,
+					Type: checker.FileTypeSource,
+				},
+				Error: err.Error(),
+			})
+			continue
+		}
+		pkgs = append(pkgs, filePkgs...)
+	}
+	return lockfile.Dedup(pkgs), unparsed, nil
+}
+
+// mergeVulnerabilities collapses the vulns sharing an ID or alias, e.g.
+// a GitHub advisory and the PyPI advisory for the same CVE, into the
+// first of them, keeping the highest severity.
+func mergeVulnerabilities(vulns []clients.Vulnerability) []clients.Vulnerability {
+	// Group the vulns, rooted at their first vuln.
+	groups := unionfind.GroupByKeys(len(vulns), func(i int) []string {
+		return append([]string{vulns[i].ID}, vulns[i].Aliases...)
+	})
+
+	var ret []clients.Vulnerability
+	pos := make(map[int]int)
+	for i := range vulns {
+		vuln := vulns[i]
+		root := groups[i]
+		p, ok := pos[root]
+		if !ok {
+			pos[root] = len(ret)
+			vuln.Aliases = append([]string(nil), vuln.Aliases...)
+			ret = append(ret, vuln)
+			continue
+		}
+		merged := &ret[p]
+		for _, id := range append([]string{vuln.ID}, vuln.Aliases...) {
+			if id != merged.ID && !containsString(merged.Aliases, id) {
+				merged.Aliases = append(merged.Aliases, id)
+			}
+		}
+		if vuln.CVSSScore > merged.CVSSScore {
+			merged.CVSSScore = vuln.CVSSScore
+		}
+		if severityRank(vuln.Severity) > severityRank(merged.Severity) {
+			merged.Severity = vuln.Severity
+		}
+		switch {
+		case merged.Package.Version == "" && vuln.Package.Version != "":
+			merged.Package = vuln.Package
+			merged.OtherPackages = append([]clients.Package(nil), vuln.OtherPackages...)
+			merged.FixedVersion = vuln.FixedVersion
+		case merged.Package.Version != "" && vuln.Package.Version != "":
+			for _, pkg := range vuln.Packages() {
+				if !containsPackage(merged.Packages(), pkg) {
+					merged.OtherPackages = append(merged.OtherPackages, pkg)
+				}
+			}
+		}
+		if merged.FixedVersion == "" {
+			merged.FixedVersion = vuln.FixedVersion
+		}
+	}
+	return ret
+}
+
+func containsPackage(pkgs []clients.Package, p clients.Package) bool {
+	for _, pkg := range pkgs {
+		if pkg == p {
+			return true
+		}
+	}
+	return false
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case clients.SeverityCritical:
+		return 4
+	case clients.SeverityHigh:
+		return 3
+	case clients.SeverityMedium:
+		return 2
+	case clients.SeverityLow:
+		return 1
+	default:
+		return 0
 	}
-	return checker.VulnerabilitiesData{
-		Vulnerabilities: resp.Vulnerabilities,
-	}, nil
 }
 
 type predicateOnCommitFn func(clients.Commit) bool
@@ -55,3 +311,21 @@ func allOf(commits []clients.Commit, predicate func(clients.Commit) bool) bool {
 	}
 	return true
 }
+
+func containsAny(list, values []string) bool {
+	for _, v := range values {
+		if containsString(list, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
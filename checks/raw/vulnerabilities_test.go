@@ -17,9 +17,12 @@ package raw
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/clients"
@@ -31,14 +34,17 @@ func TestVulnerabilities(t *testing.T) {
 	t.Parallel()
 	//nolint
 	tests := []struct {
-		name            string
-		want            checker.VulnerabilitiesData
-		err             error
-		wantErr         bool
-		vulnsResponse   clients.VulnerabilitiesResponse
-		numberofCommits int
-		expected        scut.TestReturn
-		vulnsError      bool
+		name             string
+		want             checker.VulnerabilitiesData
+		err              error
+		wantErr          bool
+		vulnsResponse    clients.VulnerabilitiesResponse
+		pkgVulnsResponse clients.VulnerabilitiesResponse
+		files            map[string]string
+		wantPackages     []clients.Package
+		numberofCommits  int
+		expected         scut.TestReturn
+		vulnsError       bool
 	}{
 		{
 			name:            "Valid response",
@@ -59,6 +65,136 @@ func TestVulnerabilities(t *testing.T) {
 			numberofCommits: 0,
 			vulnsResponse:   clients.VulnerabilitiesResponse{},
 		},
+		{
+			name:            "lockfile vulnerabilities",
+			wantErr:         false,
+			numberofCommits: 1,
+			vulnsResponse: clients.VulnerabilitiesResponse{
+				Vulnerabilities: []clients.Vulnerability{{ID: "OSV-1"}},
+			},
+			pkgVulnsResponse: clients.VulnerabilitiesResponse{
+				Vulnerabilities: []clients.Vulnerability{{ID: "OSV-1"}, {ID: "GHSA-2"}},
+			},
+			files: map[string]string{
+				"requirements.txt":              "django==3.2.1\nrequests>=2.0\n",
+				"web/package-lock.json":         `{"lockfileVersion": 3, "packages": {"node_modules/lodash": {"version": "4.17.20"}}}`,
+				"testdata/requirements.txt":     "flask==0.1\n",
+				"docs/requirements-to-read.txt": "sphinx==1.0\n",
+			},
+			wantPackages: []clients.Package{
+				{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"},
+				{Ecosystem: clients.EcosystemNPM, Name: "lodash", Version: "4.17.20"},
+			},
+			want: checker.VulnerabilitiesData{
+				Vulnerabilities: []clients.Vulnerability{{ID: "OSV-1"}, {ID: "GHSA-2"}},
+			},
+		},
+		{
+			name:    "local directory",
+			wantErr: false,
+			err:     fmt.Errorf("ListCommits: %w", clients.ErrUnsupportedFeature),
+			pkgVulnsResponse: clients.VulnerabilitiesResponse{
+				Vulnerabilities: []clients.Vulnerability{{ID: "RUSTSEC-1"}},
+			},
+			files: map[string]string{
+				"Cargo.lock": "[[package]]\nname = \"smallvec\"\nversion = \"1.6.0\"\n",
+			},
+			wantPackages: []clients.Package{
+				{Ecosystem: clients.EcosystemCratesIO, Name: "smallvec", Version: "1.6.0"},
+			},
+			want: checker.VulnerabilitiesData{
+				Vulnerabilities: []clients.Vulnerability{{ID: "RUSTSEC-1"}},
+			},
+		},
+		{
+			name:            "vex not affected",
+			wantErr:         false,
+			numberofCommits: 1,
+			vulnsResponse: clients.VulnerabilitiesResponse{
+				Vulnerabilities: []clients.Vulnerability{{ID: "OSV-1", Aliases: []string{"CVE-2022-1"}}},
+			},
+			pkgVulnsResponse: clients.VulnerabilitiesResponse{
+				Vulnerabilities: []clients.Vulnerability{
+					{ID: "GHSA-2", Package: clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}},
+					{ID: "GHSA-3", Package: clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}},
+					{ID: "GHSA-4", Package: clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}},
+					{ID: "GHSA-5", Package: clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}},
+				},
+			},
+			files: map[string]string{
+				"requirements.txt": "django==3.2.1\n",
+				".vex": `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "statements": [
+    {"vulnerability": {"name": "CVE-2022-1"}, "status": "not_affected",
+     "products": [{"@id": "pkg:github/Owner/Repo@abc"}],
+     "justification": "vulnerable_code_not_in_execute_path"},
+    {"vulnerability": {"name": "GHSA-2"}, "status": "not_affected",
+     "products": [{"@id": "pkg:github/owner/repo", "subcomponents": [{"@id": "pkg:pypi/django"}]}],
+     "impact_statement": "the admin site is disabled"},
+    {"vulnerability": {"name": "GHSA-3"}, "status": "not_affected"},
+    {"vulnerability": {"name": "GHSA-4"}, "status": "not_affected", "justification": "component_not_present",
+     "products": [{"@id": "pkg:github/owner/repo", "subcomponents": [{"@id": "pkg:pypi/flask"}]}]},
+    {"vulnerability": {"name": "GHSA-5"}, "status": "not_affected", "justification": "component_not_present",
+     "products": [{"@id": "pkg:github/other/repo"}]}
+  ]
+}`,
+				"malformed.vex.json": `{"@context": "https://openvex.dev/ns/v0.2.0", "statements": [`,
+				"testdata/.vex": `{"@context": "https://openvex.dev/ns/v0.2.0", "statements": [
+  {"vulnerability": "GHSA-3", "status": "not_affected", "justification": "component_not_present"}]}`,
+			},
+			wantPackages: []clients.Package{
+				{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"},
+			},
+			want: checker.VulnerabilitiesData{
+				Vulnerabilities: []clients.Vulnerability{
+					{ID: "GHSA-3", Package: clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}},
+					{ID: "GHSA-4", Package: clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}},
+					{ID: "GHSA-5", Package: clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}},
+				},
+				UnparsedVEXDocuments: []checker.UnparsedFile{
+					{
+						File:  checker.File{Path: "malformed.vex.json", Type: checker.FileTypeSource},
+						Error: "internal error: json.Unmarshal: unexpected end of JSON input",
+					},
+				},
+				NotAffected: []checker.NotAffectedVulnerability{
+					{
+						Vulnerability: clients.Vulnerability{ID: "OSV-1", Aliases: []string{"CVE-2022-1"}},
+						Justification: "vulnerable_code_not_in_execute_path",
+						File:          checker.File{Path: ".vex", Type: checker.FileTypeSource},
+					},
+					{
+						Vulnerability: clients.Vulnerability{
+							ID:      "GHSA-2",
+							Package: clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"},
+						},
+						ImpactStatement: "the admin site is disabled",
+						File:            checker.File{Path: ".vex", Type: checker.FileTypeSource},
+					},
+				},
+			},
+		},
+		{
+			name:            "unparsable lockfile",
+			wantErr:         false,
+			numberofCommits: 1,
+			files: map[string]string{
+				"requirements.txt":  "django==3.2.1\n",
+				"package-lock.json": `{"lockfileVersion": `,
+			},
+			wantPackages: []clients.Package{
+				{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"},
+			},
+			want: checker.VulnerabilitiesData{
+				UnparsedLockfiles: []checker.UnparsedFile{
+					{
+						File:  checker.File{Path: "package-lock.json", Type: checker.FileTypeSource},
+						Error: "internal error: package-lock.json: json.Unmarshal: unexpected end of JSON input",
+					},
+				},
+			},
+		},
 		{
 			name:            "vulns err response",
 			wantErr:         true,
@@ -84,6 +220,18 @@ func TestVulnerabilities(t *testing.T) {
 				}
 				return []clients.Commit{{SHA: "test"}}, nil
 			}).AnyTimes()
+			mockRepo.EXPECT().ListFiles(gomock.Any()).DoAndReturn(func(predicate func(string) (bool, error)) ([]string, error) {
+				var files []string
+				for file := range tt.files {
+					if match, err := predicate(file); err == nil && match {
+						files = append(files, file)
+					}
+				}
+				return files, nil
+			}).AnyTimes()
+			mockRepo.EXPECT().GetFileContent(gomock.Any()).DoAndReturn(func(file string) ([]byte, error) {
+				return []byte(tt.files[file]), nil
+			}).AnyTimes()
 
 			mockVulnClient := mockrepo.NewMockVulnerabilitiesClient(ctrl)
 			mockVulnClient.EXPECT().HasUnfixedVulnerabilities(context.TODO(), gomock.Any()).DoAndReturn(
@@ -94,9 +242,20 @@ func TestVulnerabilities(t *testing.T) {
 					}
 					return tt.vulnsResponse, tt.err
 				}).AnyTimes()
+			mockVulnClient.EXPECT().ListUnfixedVulnerabilities(context.TODO(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, pkgs []clients.Package) (clients.VulnerabilitiesResponse, error) {
+					if diff := cmp.Diff(tt.wantPackages, pkgs); diff != "" {
+						t.Errorf("ListUnfixedVulnerabilities() packages mismatch (-want +got):\n%s", diff)
+					}
+					return tt.pkgVulnsResponse, nil
+				}).AnyTimes()
+
+			repo := mockrepo.NewMockRepo(ctrl)
+			repo.EXPECT().URI().Return("github.com/owner/repo").AnyTimes()
 
 			dl := scut.TestDetailLogger{}
 			req := checker.CheckRequest{
+				Repo:                  repo,
 				RepoClient:            mockRepo,
 				Ctx:                   context.TODO(),
 				VulnerabilitiesClient: mockVulnClient,
@@ -110,6 +269,9 @@ func TestVulnerabilities(t *testing.T) {
 				if len(got.Vulnerabilities) != len(tt.want.Vulnerabilities) {
 					t.Errorf("Vulnerabilities() got = %v, want %v", len(got.Vulnerabilities), len(tt.want.Vulnerabilities))
 				}
+				if diff := cmp.Diff(tt.want, got, cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("Vulnerabilities() mismatch (-want +got):\n%s", diff)
+				}
 			}
 
 			if !scut.ValidateTestReturn(t, tt.name, &tt.expected, &checker.CheckResult{}, &dl) {
@@ -118,3 +280,79 @@ func TestVulnerabilities(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeVulnerabilities(t *testing.T) {
+	t.Parallel()
+	django := clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}
+	otherDjango := clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.1.5"}
+	flask := clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "flask", Version: "2.0.1"}
+	tests := []struct {
+		name  string
+		vulns []clients.Vulnerability
+		want  []clients.Vulnerability
+	}{
+		{
+			name: "distinct vulnerabilities",
+			vulns: []clients.Vulnerability{
+				{ID: "GHSA-1"},
+				{ID: "GHSA-2", Aliases: []string{"CVE-2"}},
+			},
+			want: []clients.Vulnerability{
+				{ID: "GHSA-1"},
+				{ID: "GHSA-2", Aliases: []string{"CVE-2"}},
+			},
+		},
+		{
+			name: "aliases collapsed",
+			vulns: []clients.Vulnerability{
+				{ID: "OSV-1", Severity: clients.SeverityMedium},
+				{
+					ID:           "GHSA-1",
+					Aliases:      []string{"CVE-1"},
+					Severity:     clients.SeverityCritical,
+					CVSSScore:    9.8,
+					Package:      django,
+					FixedVersion: "3.2.4",
+				},
+				{ID: "PYSEC-1", Aliases: []string{"CVE-1", "GHSA-1"}, Severity: clients.SeverityHigh},
+				{ID: "CVE-1", Aliases: []string{"OSV-1"}},
+			},
+			want: []clients.Vulnerability{
+				{
+					ID:           "OSV-1",
+					Aliases:      []string{"GHSA-1", "CVE-1", "PYSEC-1"},
+					Severity:     clients.SeverityCritical,
+					CVSSScore:    9.8,
+					Package:      django,
+					FixedVersion: "3.2.4",
+				},
+			},
+		},
+		{
+			name: "packages collected",
+			vulns: []clients.Vulnerability{
+				{ID: "GHSA-1", Package: django, OtherPackages: []clients.Package{otherDjango}},
+				{ID: "PYSEC-1", Aliases: []string{"GHSA-1"}, Package: otherDjango},
+				{ID: "CVE-1", Aliases: []string{"GHSA-1"}, Package: flask},
+			},
+			want: []clients.Vulnerability{
+				{
+					ID:            "GHSA-1",
+					Aliases:       []string{"PYSEC-1", "CVE-1"},
+					Package:       django,
+					OtherPackages: []clients.Package{otherDjango, flask},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := mergeVulnerabilities(tt.vulns)
+			if diff := cmp.Diff(tt.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("mergeVulnerabilities() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ const CheckVulnerabilities = "Vulnerabilities"
 //nolint:gochecknoinits
 func init() {
 	supportedRequestTypes := []checker.RequestType{
+		checker.FileBased,
 		checker.CommitBased,
 	}
 	if err := registerCheck(CheckVulnerabilities, Vulnerabilities, supportedRequestTypes); err != nil {
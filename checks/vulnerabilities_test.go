@@ -54,6 +54,7 @@ func TestVulnerabilities(t *testing.T) {
 				}
 				return []clients.Commit{{SHA: "test"}}, nil
 			}).MinTimes(1)
+			mockRepo.EXPECT().ListFiles(gomock.Any()).Return(nil, nil).AnyTimes()
 
 			mockVulnClient := mockrepo.NewMockVulnerabilitiesClient(ctrl)
 			mockVulnClient.EXPECT().HasUnfixedVulnerabilities(context.TODO(), gomock.Any()).DoAndReturn(
@@ -27,6 +27,7 @@ type BranchProtectionRule struct {
 	AllowDeletions             *bool
 	AllowForcePushes           *bool
 	RequireLinearHistory       *bool
+	RequireSignedCommits       *bool
 	EnforceAdmins              *bool
 	CheckRules                 StatusChecksRule
 }
@@ -43,4 +44,14 @@ type PullRequestReviewRule struct {
 	RequiredApprovingReviewCount *int32
 	DismissStaleReviews          *bool
 	RequireCodeOwnerReviews      *bool
+	// AllowAuthorApproval is set when authors may approve their own
+	// changes, which counts towards the required approving reviews.
+	AllowAuthorApproval *bool
+}
+
+// TagProtectionRule captures the settings protecting tags matching a pattern.
+type TagProtectionRule struct {
+	Pattern        string
+	AllowDeletions *bool
+	AllowUpdates   *bool
 }
@@ -25,6 +25,7 @@ import (
 
 	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/stats"
 )
 
 const (
@@ -116,21 +117,20 @@ type branchesHandler struct {
 	graphClient      *githubv4.Client
 	data             *defaultBranchData
 	once             *sync.Once
-	ctx              context.Context
 	errSetup         error
 	repourl          *repoURL
 	defaultBranchRef *clients.BranchRef
 }
 
-func (handler *branchesHandler) init(ctx context.Context, repourl *repoURL) {
-	handler.ctx = ctx
+func (handler *branchesHandler) init(repourl *repoURL) {
 	handler.repourl = repourl
 	handler.errSetup = nil
 	handler.once = new(sync.Once)
 }
 
-func (handler *branchesHandler) setup() error {
+func (handler *branchesHandler) setup(ctx context.Context) error {
 	handler.once.Do(func() {
+		ctx := stats.ForSetup(ctx)
 		if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
 			handler.errSetup = fmt.Errorf("%w: branches only supported for HEAD queries", clients.ErrUnsupportedFeature)
 			return
@@ -140,16 +140,20 @@ func (handler *branchesHandler) setup() error {
 			"name":  githubv4.String(handler.repourl.repo),
 		}
 		handler.data = new(defaultBranchData)
-		if err := handler.graphClient.Query(handler.ctx, handler.data, vars); err != nil {
+		if err := handler.graphClient.Query(ctx, handler.data, vars); err != nil {
 			handler.errSetup = sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("githubv4.Query: %v", err))
 			return
 		}
 		handler.defaultBranchRef = getBranchRefFrom(handler.data.Repository.DefaultBranchRef)
+		if err := handler.applyRulesets(ctx, handler.defaultBranchRef); err != nil {
+			handler.errSetup = sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("applyRulesets: %v", err))
+			return
+		}
 	})
 	return handler.errSetup
 }
 
-func (handler *branchesHandler) query(branchName string) (*clients.BranchRef, error) {
+func (handler *branchesHandler) query(ctx context.Context, branchName string) (*clients.BranchRef, error) {
 	if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
 		return nil, fmt.Errorf("%w: branches only supported for HEAD queries", clients.ErrUnsupportedFeature)
 	}
@@ -159,21 +163,25 @@ func (handler *branchesHandler) query(branchName string) (*clients.BranchRef, er
 		"branchRefName": githubv4.String(refPrefix + branchName),
 	}
 	queryData := new(branchData)
-	if err := handler.graphClient.Query(handler.ctx, queryData, vars); err != nil {
+	if err := handler.graphClient.Query(ctx, queryData, vars); err != nil {
 		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("githubv4.Query: %v", err))
 	}
-	return getBranchRefFrom(queryData.Repository.Ref), nil
+	branchRef := getBranchRefFrom(queryData.Repository.Ref)
+	if err := handler.applyRulesets(ctx, branchRef); err != nil {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("applyRulesets: %v", err))
+	}
+	return branchRef, nil
 }
 
-func (handler *branchesHandler) getDefaultBranch() (*clients.BranchRef, error) {
-	if err := handler.setup(); err != nil {
+func (handler *branchesHandler) getDefaultBranch(ctx context.Context) (*clients.BranchRef, error) {
+	if err := handler.setup(ctx); err != nil {
 		return nil, fmt.Errorf("error during branchesHandler.setup: %w", err)
 	}
 	return handler.defaultBranchRef, nil
 }
 
-func (handler *branchesHandler) getBranch(branch string) (*clients.BranchRef, error) {
-	branchRef, err := handler.query(branch)
+func (handler *branchesHandler) getBranch(ctx context.Context, branch string) (*clients.BranchRef, error) {
+	branchRef, err := handler.query(ctx, branch)
 	if err != nil {
 		return nil, fmt.Errorf("error during branchesHandler.query: %w", err)
 	}
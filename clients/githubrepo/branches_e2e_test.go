@@ -41,8 +41,8 @@ var _ = Describe("E2E TEST: githubrepo.branchesHandler", func() {
 				repo:      "scorecard",
 				commitSHA: clients.HeadSHA,
 			}
-			brancheshandler.init(context.Background(), repourl)
-			Expect(brancheshandler.setup()).Should(BeNil())
+			brancheshandler.init(repourl)
+			Expect(brancheshandler.setup(context.Background())).Should(BeNil())
 			Expect(brancheshandler.data).ShouldNot(BeNil())
 			Expect(brancheshandler.data.RateLimit.Cost).ShouldNot(BeNil())
 			Expect(*brancheshandler.data.RateLimit.Cost).Should(BeNumerically("<=", 1))
@@ -55,8 +55,8 @@ var _ = Describe("E2E TEST: githubrepo.branchesHandler", func() {
 				repo:      "scorecard",
 				commitSHA: "de5224bbc56eceb7a25aece55d2d53bbc561ed2d",
 			}
-			brancheshandler.init(context.Background(), repourl)
-			Expect(brancheshandler.setup()).ShouldNot(BeNil())
+			brancheshandler.init(repourl)
+			Expect(brancheshandler.setup(context.Background())).ShouldNot(BeNil())
 			Expect(brancheshandler.data).Should(BeNil())
 		})
 	})
@@ -26,18 +26,16 @@ import (
 
 type checkrunsHandler struct {
 	client  *github.Client
-	ctx     context.Context
 	repourl *repoURL
 }
 
-func (handler *checkrunsHandler) init(ctx context.Context, repourl *repoURL) {
-	handler.ctx = ctx
+func (handler *checkrunsHandler) init(repourl *repoURL) {
 	handler.repourl = repourl
 }
 
-func (handler *checkrunsHandler) listCheckRunsForRef(ref string) ([]clients.CheckRun, error) {
+func (handler *checkrunsHandler) listCheckRunsForRef(ctx context.Context, ref string) ([]clients.CheckRun, error) {
 	checkRuns, _, err := handler.client.Checks.ListCheckRunsForRef(
-		handler.ctx, handler.repourl.owner, handler.repourl.repo, ref, &github.ListCheckRunsOptions{})
+		ctx, handler.repourl.owner, handler.repourl.repo, ref, &github.ListCheckRunsOptions{})
 	if err != nil {
 		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("ListCheckRunsForRef: %v", err))
 	}
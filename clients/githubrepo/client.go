@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v38/github"
@@ -32,9 +33,9 @@ import (
 )
 
 var (
-	_                     clients.RepoClient = &Client{}
-	errInputRepoType                         = errors.New("input repo should be of type repoURL")
-	errDefaultBranchEmpty                    = errors.New("default branch name is empty")
+	_                     clients.ContextualRepoClient = &Client{}
+	errInputRepoType                                   = errors.New("input repo should be of type repoURL")
+	errDefaultBranchEmpty                              = errors.New("default branch name is empty")
 )
 
 // Client is GitHub-specific implementation of RepoClient.
@@ -54,7 +55,10 @@ type Client struct {
 	webhook       *webhookHandler
 	languages     *languagesHandler
 	ctx           context.Context
-	tarball       tarballHandler
+	tarball       *tarballHandler
+	httpClient    *http.Client
+	// host is the one the API clients are pointed to, github.com or a GitHub Enterprise Server.
+	host string
 }
 
 // InitRepo sets up the GitHub repo in local storage for improving performance and GitHub token usage efficiency.
@@ -64,6 +68,16 @@ func (client *Client) InitRepo(inputRepo clients.Repo, commitSHA string) error {
 		return fmt.Errorf("%w: %v", errInputRepoType, inputRepo)
 	}
 
+	host := ghRepo.host
+	if host == "" {
+		host = githubHost
+	}
+	if !strings.EqualFold(host, client.host) {
+		if err := client.connect(host); err != nil {
+			return err
+		}
+	}
+
 	// Sanity check.
 	repo, _, err := client.repoClient.Repositories.Get(client.ctx, ghRepo.owner, ghRepo.repo)
 	if err != nil {
@@ -72,6 +86,7 @@ func (client *Client) InitRepo(inputRepo clients.Repo, commitSHA string) error {
 
 	client.repo = repo
 	client.repourl = &repoURL{
+		host:          client.host,
 		owner:         repo.Owner.GetLogin(),
 		repo:          repo.GetName(),
 		defaultBranch: repo.GetDefaultBranch(),
@@ -79,86 +94,91 @@ func (client *Client) InitRepo(inputRepo clients.Repo, commitSHA string) error {
 	}
 
 	// Init tarballHandler.
-	client.tarball.init(client.ctx, client.repo, commitSHA)
+	client.tarball.init(client.repo, commitSHA)
 
 	// Setup GraphQL.
-	client.graphClient.init(client.ctx, client.repourl)
+	client.graphClient.init(client.repourl)
 
 	// Setup contributorsHandler.
-	client.contributors.init(client.ctx, client.repourl)
+	client.contributors.init(client.repourl)
 
 	// Setup branchesHandler.
-	client.branches.init(client.ctx, client.repourl)
+	client.branches.init(client.repourl)
 
 	// Setup releasesHandler.
-	client.releases.init(client.ctx, client.repourl)
+	client.releases.init(client.repourl)
 
 	// Setup workflowsHandler.
-	client.workflows.init(client.ctx, client.repourl)
+	client.workflows.init(client.repourl)
 
 	// Setup checkrunsHandler.
-	client.checkruns.init(client.ctx, client.repourl)
+	client.checkruns.init(client.repourl)
 
 	// Setup statusesHandler.
-	client.statuses.init(client.ctx, client.repourl)
+	client.statuses.init(client.repourl)
 
 	// Setup searchHandler.
-	client.search.init(client.ctx, client.repourl)
+	client.search.init(client.repourl)
 
 	// Setup searchCommitsHandler
-	client.searchCommits.init(client.ctx, client.repourl)
+	client.searchCommits.init(client.repourl)
 
 	// Setup webhookHandler.
-	client.webhook.init(client.ctx, client.repourl)
+	client.webhook.init(client.repourl)
 
 	// Setup languagesHandler.
-	client.languages.init(client.ctx, client.repourl)
+	client.languages.init(client.repourl)
 	return nil
 }
 
 // URI implements RepoClient.URI.
 func (client *Client) URI() string {
-	return fmt.Sprintf("github.com/%s/%s", client.repourl.owner, client.repourl.repo)
+	return client.repourl.URI()
 }
 
 // ListFiles implements RepoClient.ListFiles.
 func (client *Client) ListFiles(predicate func(string) (bool, error)) ([]string, error) {
-	return client.tarball.listFiles(predicate)
+	return client.tarball.listFiles(client.ctx, predicate)
 }
 
 // GetFileContent implements RepoClient.GetFileContent.
 func (client *Client) GetFileContent(filename string) ([]byte, error) {
-	return client.tarball.getFileContent(filename)
+	return client.tarball.getFileContent(client.ctx, filename)
 }
 
 // ListCommits implements RepoClient.ListCommits.
 func (client *Client) ListCommits() ([]clients.Commit, error) {
-	return client.graphClient.getCommits()
+	return client.graphClient.getCommits(client.ctx)
 }
 
 // ListIssues implements RepoClient.ListIssues.
 func (client *Client) ListIssues() ([]clients.Issue, error) {
-	return client.graphClient.getIssues()
+	return client.graphClient.getIssues(client.ctx)
 }
 
 // ListReleases implements RepoClient.ListReleases.
 func (client *Client) ListReleases() ([]clients.Release, error) {
-	return client.releases.getReleases()
+	return client.releases.getReleases(client.ctx)
+}
+
+// ListTagProtectionRules implements RepoClient.ListTagProtectionRules.
+func (client *Client) ListTagProtectionRules() ([]clients.TagProtectionRule, error) {
+	return client.branches.listTagProtectionRules(client.ctx)
 }
 
 // ListContributors implements RepoClient.ListContributors.
 func (client *Client) ListContributors() ([]clients.User, error) {
-	return client.contributors.getContributors()
+	return client.contributors.getContributors(client.ctx)
 }
 
 // IsArchived implements RepoClient.IsArchived.
 func (client *Client) IsArchived() (bool, error) {
-	return client.graphClient.isArchived()
+	return client.graphClient.isArchived(client.ctx)
 }
 
 // GetDefaultBranch implements RepoClient.GetDefaultBranch.
 func (client *Client) GetDefaultBranch() (*clients.BranchRef, error) {
-	return client.branches.getDefaultBranch()
+	return client.branches.getDefaultBranch(client.ctx)
 }
 
 // GetDefaultBranchName implements RepoClient.GetDefaultBranchName.
@@ -172,7 +192,7 @@ func (client *Client) GetDefaultBranchName() (string, error) {
 
 // GetBranch implements RepoClient.GetBranch.
 func (client *Client) GetBranch(branch string) (*clients.BranchRef, error) {
-	return client.branches.getBranch(branch)
+	return client.branches.getBranch(client.ctx, branch)
 }
 
 // GetCreatedAt is a getter for repo.CreatedAt.
@@ -182,19 +202,19 @@ func (client *Client) GetCreatedAt() (time.Time, error) {
 
 // ListWebhooks implements RepoClient.ListWebhooks.
 func (client *Client) ListWebhooks() ([]clients.Webhook, error) {
-	return client.webhook.listWebhooks()
+	return client.webhook.listWebhooks(client.ctx)
 }
 
 // ListSuccessfulWorkflowRuns implements RepoClient.WorkflowRunsByFilename.
 func (client *Client) ListSuccessfulWorkflowRuns(filename string) ([]clients.WorkflowRun, error) {
-	return client.workflows.listSuccessfulWorkflowRuns(filename)
+	return client.workflows.listSuccessfulWorkflowRuns(client.ctx, filename)
 }
 
 // ListCheckRunsForRef implements RepoClient.ListCheckRunsForRef.
 func (client *Client) ListCheckRunsForRef(ref string) ([]clients.CheckRun, error) {
-	cachedCrs, err := client.graphClient.listCheckRunsForRef(ref)
+	cachedCrs, err := client.graphClient.listCheckRunsForRef(client.ctx, ref)
 	if errors.Is(err, errNotCached) {
-		crs, err := client.checkruns.listCheckRunsForRef(ref)
+		crs, err := client.checkruns.listCheckRunsForRef(client.ctx, ref)
 		if err == nil {
 			client.graphClient.cacheCheckRunsForRef(ref, crs)
 		}
@@ -205,22 +225,29 @@ func (client *Client) ListCheckRunsForRef(ref string) ([]clients.CheckRun, error
 
 // ListStatuses implements RepoClient.ListStatuses.
 func (client *Client) ListStatuses(ref string) ([]clients.Status, error) {
-	return client.statuses.listStatuses(ref)
+	return client.statuses.listStatuses(client.ctx, ref)
 }
 
 // ListProgrammingLanguages implements RepoClient.ListProgrammingLanguages.
 func (client *Client) ListProgrammingLanguages() ([]clients.Language, error) {
-	return client.languages.listProgrammingLanguages()
+	return client.languages.listProgrammingLanguages(client.ctx)
 }
 
 // Search implements RepoClient.Search.
 func (client *Client) Search(request clients.SearchRequest) (clients.SearchResponse, error) {
-	return client.search.search(request)
+	return client.search.search(client.ctx, request)
 }
 
 // SearchCommits implements RepoClient.SearchCommits.
 func (client *Client) SearchCommits(request clients.SearchCommitsOptions) ([]clients.Commit, error) {
-	return client.searchCommits.search(request)
+	return client.searchCommits.search(client.ctx, request)
+}
+
+// WithContext implements ContextualRepoClient.WithContext.
+func (client *Client) WithContext(ctx context.Context) clients.RepoClient {
+	view := *client
+	view.ctx = ctx
+	return &view
 }
 
 // Close implements RepoClient.Close.
@@ -233,50 +260,72 @@ func CreateGithubRepoClientWithTransport(ctx context.Context, rt http.RoundTripp
 	httpClient := &http.Client{
 		Transport: rt,
 	}
-	client := github.NewClient(httpClient)
-	graphClient := githubv4.NewClient(httpClient)
-
-	return &Client{
+	client := &Client{
 		ctx:        ctx,
-		repoClient: client,
-		graphClient: &graphqlHandler{
-			client: graphClient,
-		},
-		contributors: &contributorsHandler{
-			ghClient: client,
-		},
-		branches: &branchesHandler{
-			ghClient:    client,
-			graphClient: graphClient,
-		},
-		releases: &releasesHandler{
-			client: client,
-		},
-		workflows: &workflowsHandler{
-			client: client,
-		},
-		checkruns: &checkrunsHandler{
-			client: client,
-		},
-		statuses: &statusesHandler{
-			client: client,
-		},
-		search: &searchHandler{
-			ghClient: client,
-		},
-		searchCommits: &searchCommitsHandler{
-			ghClient: client,
-		},
-		webhook: &webhookHandler{
-			ghClient: client,
-		},
-		languages: &languagesHandler{
-			ghclient: client,
-		},
-		tarball: tarballHandler{
+		httpClient: httpClient,
+		tarball: &tarballHandler{
 			httpClient: httpClient,
 		},
 	}
+	client.setAPIClients(githubHost, github.NewClient(httpClient), githubv4.NewClient(httpClient))
+	return client
+}
+
+// connect points the API clients to the REST and GraphQL endpoints of host, github.com or
+// a GitHub Enterprise Server.
+func (client *Client) connect(host string) error {
+	if !strings.EqualFold(host, githubHost) {
+		baseURL := fmt.Sprintf("https://%s/api/v3/", host)
+		uploadURL := fmt.Sprintf("https://%s/api/uploads/", host)
+		restClient, err := github.NewEnterpriseClient(baseURL, uploadURL, client.httpClient)
+		if err != nil {
+			return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("github.NewEnterpriseClient: %v", err))
+		}
+		graphClient := githubv4.NewEnterpriseClient(fmt.Sprintf("https://%s/api/graphql", host), client.httpClient)
+		client.setAPIClients(host, restClient, graphClient)
+		return nil
+	}
+	client.setAPIClients(githubHost, github.NewClient(client.httpClient), githubv4.NewClient(client.httpClient))
+	return nil
+}
+
+func (client *Client) setAPIClients(host string, restClient *github.Client, graphClient *githubv4.Client) {
+	client.host = host
+	client.repoClient = restClient
+	client.graphClient = &graphqlHandler{
+		client: graphClient,
+	}
+	client.contributors = &contributorsHandler{
+		ghClient: restClient,
+	}
+	client.branches = &branchesHandler{
+		ghClient:    restClient,
+		graphClient: graphClient,
+	}
+	client.releases = &releasesHandler{
+		client: restClient,
+	}
+	client.workflows = &workflowsHandler{
+		client: restClient,
+	}
+	client.checkruns = &checkrunsHandler{
+		client: restClient,
+	}
+	client.statuses = &statusesHandler{
+		client: restClient,
+	}
+	client.search = &searchHandler{
+		ghClient: restClient,
+	}
+	client.searchCommits = &searchCommitsHandler{
+		ghClient: restClient,
+	}
+	client.webhook = &webhookHandler{
+		ghClient: restClient,
+	}
+	client.languages = &languagesHandler{
+		ghclient: restClient,
+	}
 }
 
 // CreateGithubRepoClient returns a Client which implements RepoClient interface.
@@ -289,12 +338,18 @@ func CreateGithubRepoClient(ctx context.Context, logger *log.Logger) clients.Rep
 // CreateOssFuzzRepoClient returns a RepoClient implementation
 // intialized to `google/oss-fuzz` GitHub repository.
 func CreateOssFuzzRepoClient(ctx context.Context, logger *log.Logger) (clients.RepoClient, error) {
+	return CreateOssFuzzRepoClientWithTransport(ctx, roundtripper.NewTransport(ctx, logger))
+}
+
+// CreateOssFuzzRepoClientWithTransport returns a RepoClient implementation
+// intialized to `google/oss-fuzz` GitHub repository, making its requests through rt.
+func CreateOssFuzzRepoClientWithTransport(ctx context.Context, rt http.RoundTripper) (clients.RepoClient, error) {
 	ossFuzzRepo, err := MakeGithubRepo("google/oss-fuzz")
 	if err != nil {
 		return nil, fmt.Errorf("error during MakeGithubRepo: %w", err)
 	}
 
-	ossFuzzRepoClient := CreateGithubRepoClient(ctx, logger)
+	ossFuzzRepoClient := CreateGithubRepoClientWithTransport(ctx, rt)
 	if err := ossFuzzRepoClient.InitRepo(ossFuzzRepo, clients.HeadSHA); err != nil {
 		return nil, fmt.Errorf("error during InitRepo: %w", err)
 	}
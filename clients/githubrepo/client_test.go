@@ -0,0 +1,184 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ossf/scorecard/v4/clients"
+)
+
+// recordingTransport answers all the requests with a 404, recording their URLs.
+type recordingTransport struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.urls = append(rt.urls, r.URL.String())
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		Request:    r,
+	}, nil
+}
+
+func TestInitRepoHost(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		repos   []*repoURL
+		wantURL string
+	}{
+		{
+			name:    "github.com",
+			repos:   []*repoURL{{host: "github.com", owner: "o", repo: "r"}},
+			wantURL: "https://api.github.com/repos/o/r",
+		},
+		{
+			name:    "GitHub Enterprise Server",
+			repos:   []*repoURL{{host: "ghe.example.com", owner: "o", repo: "r"}},
+			wantURL: "https://ghe.example.com/api/v3/repos/o/r",
+		},
+		{
+			name: "back to github.com",
+			repos: []*repoURL{
+				{host: "ghe.example.com", owner: "o", repo: "r"},
+				{host: "github.com", owner: "o", repo: "r"},
+			},
+			wantURL: "https://api.github.com/repos/o/r",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rt := &recordingTransport{}
+			client := CreateGithubRepoClientWithTransport(context.Background(), rt)
+			for _, repo := range tt.repos {
+				if err := client.InitRepo(repo, clients.HeadSHA); err == nil {
+					t.Fatalf("InitRepo: got no error for a missing repo")
+				}
+			}
+			if got := rt.urls[len(rt.urls)-1]; got != tt.wantURL {
+				t.Errorf("URL = %q, want %q", got, tt.wantURL)
+			}
+		})
+	}
+}
+
+type contextKey struct{}
+
+// contextTransport serves the repo o/r, answering the other requests with a 404, and records
+// the contextKey value of the context of the requests. Like http.Transport, it fails the requests
+// of a done context.
+type contextTransport struct {
+	mu     sync.Mutex
+	values []interface{}
+}
+
+func (ct *contextTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if err := r.Context().Err(); err != nil {
+		return nil, err
+	}
+	ct.values = append(ct.values, r.Context().Value(contextKey{}))
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		Request:    r,
+	}
+	if r.URL.Path == "/repos/o/r" {
+		resp.StatusCode = http.StatusOK
+		resp.Body = io.NopCloser(strings.NewReader(`{"name": "r", "owner": {"login": "o"}}`))
+	}
+	return resp, nil
+}
+
+func TestWithContext(t *testing.T) {
+	t.Parallel()
+	ct := &contextTransport{}
+	client := CreateGithubRepoClientWithTransport(context.Background(), ct)
+	if err := client.InitRepo(&repoURL{host: "github.com", owner: "o", repo: "r"}, clients.HeadSHA); err != nil {
+		t.Fatalf("InitRepo: %v", err)
+	}
+	contextual, ok := client.(clients.ContextualRepoClient)
+	if !ok {
+		t.Fatalf("%T isn't a ContextualRepoClient", client)
+	}
+	view := contextual.WithContext(context.WithValue(context.Background(), contextKey{}, "check"))
+	if _, err := view.ListReleases(); err == nil {
+		t.Errorf("ListReleases: got no error for missing releases")
+	}
+	if _, err := client.ListWebhooks(); err == nil {
+		t.Errorf("ListWebhooks: got no error for missing webhooks")
+	}
+	want := []interface{}{nil, "check", nil}
+	if len(ct.values) != len(want) {
+		t.Fatalf("context values = %v, want %v", ct.values, want)
+	}
+	for i := range want {
+		if ct.values[i] != want[i] {
+			t.Errorf("context values = %v, want %v", ct.values, want)
+		}
+	}
+}
+
+func TestWithContextInitRepo(t *testing.T) {
+	t.Parallel()
+	ct := &contextTransport{}
+	client := CreateGithubRepoClientWithTransport(context.Background(), ct)
+	contextual, ok := client.(clients.ContextualRepoClient)
+	if !ok {
+		t.Fatalf("%T isn't a ContextualRepoClient", client)
+	}
+	view := contextual.WithContext(context.WithValue(context.Background(), contextKey{}, "scan"))
+	if err := view.InitRepo(&repoURL{host: "github.com", owner: "o", repo: "r"}, clients.HeadSHA); err != nil {
+		t.Fatalf("InitRepo: %v", err)
+	}
+	if got := view.URI(); got != "github.com/o/r" {
+		t.Errorf("URI() = %q, want %q", got, "github.com/o/r")
+	}
+	if _, err := view.ListReleases(); err == nil {
+		t.Errorf("ListReleases: got no error for missing releases")
+	}
+	want := []interface{}{"scan", "scan"}
+	if len(ct.values) != len(want) {
+		t.Fatalf("context values = %v, want %v", ct.values, want)
+	}
+	for i := range want {
+		if ct.values[i] != want[i] {
+			t.Errorf("context values = %v, want %v", ct.values, want)
+		}
+	}
+
+	// A canceled context fails InitRepo.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := contextual.WithContext(ctx).InitRepo(&repoURL{host: "github.com", owner: "o", repo: "r"},
+		clients.HeadSHA); err == nil {
+		t.Errorf("InitRepo: got no error for a canceled context")
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
 	"github.com/ossf/scorecard/v4/log"
+	"github.com/ossf/scorecard/v4/stats"
 )
 
 const (
@@ -177,7 +178,6 @@ type graphqlHandler struct {
 	setupCheckRunsOnce *sync.Once
 	errSetupCheckRuns  error
 	logger             *log.Logger
-	ctx                context.Context
 	errSetup           error
 	repourl            *repoURL
 	commits            []clients.Commit
@@ -185,8 +185,7 @@ type graphqlHandler struct {
 	archived           bool
 }
 
-func (handler *graphqlHandler) init(ctx context.Context, repourl *repoURL) {
-	handler.ctx = ctx
+func (handler *graphqlHandler) init(repourl *repoURL) {
 	handler.repourl = repourl
 	handler.data = new(graphqlData)
 	handler.errSetup = nil
@@ -197,8 +196,9 @@ func (handler *graphqlHandler) init(ctx context.Context, repourl *repoURL) {
 	handler.logger = log.NewLogger(log.DefaultLevel)
 }
 
-func (handler *graphqlHandler) setup() error {
+func (handler *graphqlHandler) setup(ctx context.Context) error {
 	handler.setupOnce.Do(func() {
+		ctx := stats.ForSetup(ctx)
 		commitExpression := handler.commitExpression()
 		vars := map[string]interface{}{
 			"owner":                  githubv4.String(handler.repourl.owner),
@@ -211,7 +211,7 @@ func (handler *graphqlHandler) setup() error {
 			"commitsToAnalyze":       githubv4.Int(commitsToAnalyze),
 			"commitExpression":       githubv4.String(commitExpression),
 		}
-		if err := handler.client.Query(handler.ctx, handler.data, vars); err != nil {
+		if err := handler.client.Query(ctx, handler.data, vars); err != nil {
 			handler.errSetup = sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("githubv4.Query: %v", err))
 			return
 		}
@@ -225,8 +225,9 @@ func (handler *graphqlHandler) setup() error {
 	return handler.errSetup
 }
 
-func (handler *graphqlHandler) setupCheckRuns() error {
+func (handler *graphqlHandler) setupCheckRuns(ctx context.Context) error {
 	handler.setupCheckRunsOnce.Do(func() {
+		ctx := stats.ForSetup(ctx)
 		commitExpression := handler.commitExpression()
 		vars := map[string]interface{}{
 			"owner":                 githubv4.String(handler.repourl.owner),
@@ -236,7 +237,7 @@ func (handler *graphqlHandler) setupCheckRuns() error {
 			"commitExpression":      githubv4.String(commitExpression),
 			"checksToAnalyze":       githubv4.Int(checksToAnalyze),
 		}
-		if err := handler.client.Query(handler.ctx, handler.checkData, vars); err != nil {
+		if err := handler.client.Query(ctx, handler.checkData, vars); err != nil {
 			// quit early without setting crsErrSetup for "Resource not accessible by integration" error
 			// for whatever reason, this check doesn't work with a GITHUB_TOKEN, only a PAT
 			if strings.Contains(err.Error(), "Resource not accessible by integration") {
@@ -250,8 +251,8 @@ func (handler *graphqlHandler) setupCheckRuns() error {
 	return handler.errSetupCheckRuns
 }
 
-func (handler *graphqlHandler) getCommits() ([]clients.Commit, error) {
-	if err := handler.setup(); err != nil {
+func (handler *graphqlHandler) getCommits(ctx context.Context) ([]clients.Commit, error) {
+	if err := handler.setup(ctx); err != nil {
 		return nil, fmt.Errorf("error during graphqlHandler.setup: %w", err)
 	}
 	return handler.commits, nil
@@ -261,8 +262,8 @@ func (handler *graphqlHandler) cacheCheckRunsForRef(ref string, crs []clients.Ch
 	handler.checkRuns[ref] = crs
 }
 
-func (handler *graphqlHandler) listCheckRunsForRef(ref string) ([]clients.CheckRun, error) {
-	if err := handler.setupCheckRuns(); err != nil {
+func (handler *graphqlHandler) listCheckRunsForRef(ctx context.Context, ref string) ([]clients.CheckRun, error) {
+	if err := handler.setupCheckRuns(ctx); err != nil {
 		return nil, fmt.Errorf("error during graphqlHandler.setupCheckRuns: %w", err)
 	}
 	if crs, ok := handler.checkRuns[ref]; ok {
@@ -273,21 +274,21 @@ func (handler *graphqlHandler) listCheckRunsForRef(ref string) ([]clients.CheckR
 	return nil, errNotCached
 }
 
-func (handler *graphqlHandler) getIssues() ([]clients.Issue, error) {
+func (handler *graphqlHandler) getIssues(ctx context.Context) ([]clients.Issue, error) {
 	if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
 		return nil, fmt.Errorf("%w: ListIssues only supported for HEAD queries", clients.ErrUnsupportedFeature)
 	}
-	if err := handler.setup(); err != nil {
+	if err := handler.setup(ctx); err != nil {
 		return nil, fmt.Errorf("error during graphqlHandler.setup: %w", err)
 	}
 	return handler.issues, nil
 }
 
-func (handler *graphqlHandler) isArchived() (bool, error) {
+func (handler *graphqlHandler) isArchived(ctx context.Context) (bool, error) {
 	if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
 		return false, fmt.Errorf("%w: IsArchived only supported for HEAD queries", clients.ErrUnsupportedFeature)
 	}
-	if err := handler.setup(); err != nil {
+	if err := handler.setup(ctx); err != nil {
 		return false, fmt.Errorf("error during graphqlHandler.setup: %w", err)
 	}
 	return handler.archived, nil
@@ -325,7 +326,7 @@ func parseCheckRuns(data *checkRunsGraphqlData) checkRunCache {
 	return checkCache
 }
 
-//nolint
+// nolint
 func commitsFrom(data *graphqlData, repoOwner, repoName string) ([]clients.Commit, error) {
 	ret := make([]clients.Commit, 0)
 	for _, commit := range data.Repository.Object.Commit.History.Nodes {
@@ -39,8 +39,8 @@ var _ = Describe("E2E TEST: githubrepo.graphqlHandler", func() {
 				repo:      "scorecard",
 				commitSHA: clients.HeadSHA,
 			}
-			graphqlhandler.init(context.Background(), repourl)
-			Expect(graphqlhandler.setup()).Should(BeNil())
+			graphqlhandler.init(repourl)
+			Expect(graphqlhandler.setup(context.Background())).Should(BeNil())
 			Expect(graphqlhandler.data).ShouldNot(BeNil())
 			Expect(graphqlhandler.data.RateLimit.Cost).ShouldNot(BeNil())
 			Expect(*graphqlhandler.data.RateLimit.Cost).Should(BeNumerically("<=", 1))
@@ -51,8 +51,8 @@ var _ = Describe("E2E TEST: githubrepo.graphqlHandler", func() {
 				repo:      "scorecard",
 				commitSHA: "de5224bbc56eceb7a25aece55d2d53bbc561ed2d",
 			}
-			graphqlhandler.init(context.Background(), repourl)
-			Expect(graphqlhandler.setup()).Should(BeNil())
+			graphqlhandler.init(repourl)
+			Expect(graphqlhandler.setup(context.Background())).Should(BeNil())
 			Expect(graphqlhandler.data).ShouldNot(BeNil())
 			Expect(graphqlhandler.data.RateLimit.Cost).ShouldNot(BeNil())
 			Expect(*graphqlhandler.data.RateLimit.Cost).Should(BeNumerically("<=", 1))
@@ -63,8 +63,8 @@ var _ = Describe("E2E TEST: githubrepo.graphqlHandler", func() {
 				repo:      "scorecard",
 				commitSHA: clients.HeadSHA,
 			}
-			graphqlhandler.init(context.Background(), repourl)
-			Expect(graphqlhandler.setupCheckRuns()).Should(BeNil())
+			graphqlhandler.init(repourl)
+			Expect(graphqlhandler.setupCheckRuns(context.Background())).Should(BeNil())
 			Expect(graphqlhandler.checkData).ShouldNot(BeNil())
 			Expect(graphqlhandler.checkData.RateLimit.Cost).ShouldNot(BeNil())
 			Expect(*graphqlhandler.checkData.RateLimit.Cost).Should(BeNumerically("<=", 1))
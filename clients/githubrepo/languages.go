@@ -23,19 +23,18 @@ import (
 	"github.com/google/go-github/v38/github"
 
 	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/stats"
 )
 
 type languagesHandler struct {
 	ghclient  *github.Client
 	once      *sync.Once
-	ctx       context.Context
 	errSetup  error
 	repourl   *repoURL
 	languages []clients.Language
 }
 
-func (handler *languagesHandler) init(ctx context.Context, repourl *repoURL) {
-	handler.ctx = ctx
+func (handler *languagesHandler) init(repourl *repoURL) {
 	handler.repourl = repourl
 	handler.errSetup = nil
 	handler.once = new(sync.Once)
@@ -43,8 +42,9 @@ func (handler *languagesHandler) init(ctx context.Context, repourl *repoURL) {
 
 // TODO: Can add support to parse the raw response JSON and mark languages that are not in
 // our defined Language consts in clients/languages.go as "not supported languages".
-func (handler *languagesHandler) setup() error {
+func (handler *languagesHandler) setup(ctx context.Context) error {
 	handler.once.Do(func() {
+		ctx := stats.ForSetup(ctx)
 		client := handler.ghclient
 		reqURL := path.Join("repos", handler.repourl.owner, handler.repourl.repo, "languages")
 		req, err := client.NewRequest("GET", reqURL, nil)
@@ -56,7 +56,7 @@ func (handler *languagesHandler) setup() error {
 		// The client.repoClient.Do API writes the response body to var bodyJSON,
 		// so we can ignore the first returned variable (the entire http response object)
 		// since we only need the response body here.
-		_, err = client.Do(handler.ctx, req, &bodyJSON)
+		_, err = client.Do(ctx, req, &bodyJSON)
 		if err != nil {
 			handler.errSetup = fmt.Errorf("response for repo languages failed with %w", err)
 			return
@@ -79,8 +79,8 @@ func (handler *languagesHandler) setup() error {
 	return handler.errSetup
 }
 
-func (handler *languagesHandler) listProgrammingLanguages() ([]clients.Language, error) {
-	if err := handler.setup(); err != nil {
+func (handler *languagesHandler) listProgrammingLanguages(ctx context.Context) ([]clients.Language, error) {
+	if err := handler.setup(ctx); err != nil {
 		return nil, fmt.Errorf("error during languagesHandler.setup: %w", err)
 	}
 	return handler.languages, nil
@@ -24,32 +24,32 @@ import (
 
 	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/stats"
 )
 
 type releasesHandler struct {
 	client   *github.Client
 	once     *sync.Once
-	ctx      context.Context
 	errSetup error
 	repourl  *repoURL
 	releases []clients.Release
 }
 
-func (handler *releasesHandler) init(ctx context.Context, repourl *repoURL) {
-	handler.ctx = ctx
+func (handler *releasesHandler) init(repourl *repoURL) {
 	handler.repourl = repourl
 	handler.errSetup = nil
 	handler.once = new(sync.Once)
 }
 
-func (handler *releasesHandler) setup() error {
+func (handler *releasesHandler) setup(ctx context.Context) error {
 	handler.once.Do(func() {
+		ctx := stats.ForSetup(ctx)
 		if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
 			handler.errSetup = fmt.Errorf("%w: ListReleases only supported for HEAD queries", clients.ErrUnsupportedFeature)
 			return
 		}
 		releases, _, err := handler.client.Repositories.ListReleases(
-			handler.ctx, handler.repourl.owner, handler.repourl.repo, &github.ListOptions{})
+			ctx, handler.repourl.owner, handler.repourl.repo, &github.ListOptions{})
 		if err != nil {
 			handler.errSetup = sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("githubv4.Query: %v", err))
 		}
@@ -58,8 +58,8 @@ func (handler *releasesHandler) setup() error {
 	return handler.errSetup
 }
 
-func (handler *releasesHandler) getReleases() ([]clients.Release, error) {
-	if err := handler.setup(); err != nil {
+func (handler *releasesHandler) getReleases(ctx context.Context) ([]clients.Release, error) {
+	if err := handler.setup(ctx); err != nil {
 		return nil, fmt.Errorf("error during graphqlHandler.setup: %w", err)
 	}
 	return handler.releases, nil
@@ -20,11 +20,13 @@ import (
 	"strings"
 
 	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/clients/githubrepo/roundtripper"
 	sce "github.com/ossf/scorecard/v4/errors"
 )
 
 const (
 	githubOrgRepo = ".github"
+	githubHost    = "github.com"
 )
 
 type repoURL struct {
@@ -33,7 +35,7 @@ type repoURL struct {
 }
 
 // Parses input string into repoURL struct.
-// Accepts "owner/repo" or "github.com/owner/repo".
+// Accepts "owner/repo", "github.com/owner/repo" or "<GitHub Enterprise Server host>/owner/repo".
 func (r *repoURL) parse(input string) error {
 	var t string
 
@@ -46,7 +48,7 @@ func (r *repoURL) parse(input string) error {
 	// This will takes care for repo/owner format.
 	// By default it will use github.com
 	case l == two:
-		t = "github.com/" + c[0] + "/" + c[1]
+		t = githubHost + "/" + c[0] + "/" + c[1]
 	case l >= three:
 		t = input
 	}
@@ -92,10 +94,12 @@ func (r *repoURL) Org() clients.Repo {
 
 // IsValid implements Repo.IsValid.
 func (r *repoURL) IsValid() error {
-	switch r.host {
-	case "github.com":
+	switch host := strings.ToLower(r.host); {
+	case host == githubHost:
+	case host == roundtripper.EnterpriseHost():
 	default:
-		return sce.WithMessage(sce.ErrorUnsupportedHost, r.host)
+		return sce.WithMessage(sce.ErrorUnsupportedHost,
+			fmt.Sprintf("%s (set %s=%s if it's a GitHub Enterprise Server)", r.host, roundtripper.GitHubHostEnvVar, r.host))
 	}
 
 	if strings.TrimSpace(r.owner) == "" || strings.TrimSpace(r.repo) == "" {
@@ -114,8 +118,20 @@ func (r *repoURL) Metadata() []string {
 	return r.metadata
 }
 
-// MakeGithubRepo takes input of form "owner/repo" or "github.com/owner/repo"
-// and returns an implementation of clients.Repo interface.
+// isEnterprise returns whether the repo is hosted by a GitHub Enterprise Server rather than github.com.
+func (r *repoURL) isEnterprise() bool {
+	return r.host != "" && !strings.EqualFold(r.host, githubHost)
+}
+
+// IsEnterpriseRepo returns whether repo is a GitHub repo hosted by a GitHub Enterprise Server, where the
+// features only github.com has, e.g. OSS-Fuzz, aren't available.
+func IsEnterpriseRepo(repo clients.Repo) bool {
+	r, ok := repo.(*repoURL)
+	return ok && r.isEnterprise()
+}
+
+// MakeGithubRepo takes input of form "owner/repo", "github.com/owner/repo" or "ghe.example.com/owner/repo"
+// if GH_HOST is ghe.example.com, and returns an implementation of clients.Repo interface.
 func MakeGithubRepo(input string) (clients.Repo, error) {
 	var repo repoURL
 	if err := repo.parse(input); err != nil {
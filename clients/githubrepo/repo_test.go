@@ -100,3 +100,50 @@ func TestRepoURL_IsValid(t *testing.T) {
 		})
 	}
 }
+
+//nolint:paralleltest // Uses t.Setenv.
+func TestRepoURL_IsValidEnterprise(t *testing.T) {
+	tests := []struct {
+		name       string
+		ghHost     string
+		inputURL   string
+		wantErr    bool
+		enterprise bool
+	}{
+		{
+			name:       "GitHub Enterprise Server repository",
+			ghHost:     "ghe.example.com",
+			inputURL:   "ghe.example.com/foo/kubeflow",
+			enterprise: true,
+		},
+		{
+			name:     "github repository with GH_HOST",
+			ghHost:   "ghe.example.com",
+			inputURL: "foo/kubeflow",
+		},
+		{
+			name:     "GitHub Enterprise Server repository without GH_HOST",
+			inputURL: "ghe.example.com/foo/kubeflow",
+			wantErr:  true,
+		},
+		{
+			name:     "other host",
+			ghHost:   "ghe.example.com",
+			inputURL: "https://gitlab.com/foo/kubeflow",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GH_HOST", tt.ghHost)
+			repo, err := MakeGithubRepo(tt.inputURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MakeGithubRepo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && IsEnterpriseRepo(repo) != tt.enterprise {
+				t.Errorf("IsEnterpriseRepo() = %t, want %t", IsEnterpriseRepo(repo), tt.enterprise)
+			}
+		})
+	}
+}
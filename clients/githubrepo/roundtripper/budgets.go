@@ -0,0 +1,119 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// budgetKey identifies the rate limit of a resource (core, search, graphql...) for a token of a host,
+// github.com or a GitHub Enterprise Server.
+type budgetKey struct {
+	host     string
+	token    string
+	resource string
+}
+
+// budget is the state of a rate limit, as of the last response counted against it.
+type budget struct {
+	remaining int
+	reset     time.Time
+	// cost is the number of points the last request consumed, e.g. the cost of a GraphQL query.
+	cost int
+}
+
+// rateLimits tracks the budgets of the tokens from the X-RateLimit-* headers of their responses.
+// It's safe for concurrent use.
+type rateLimits struct {
+	mu      sync.Mutex
+	budgets map[budgetKey]*budget
+}
+
+func newRateLimits() *rateLimits {
+	return &rateLimits{budgets: map[budgetKey]*budget{}}
+}
+
+// update records the budget of a response to a request of resource made to host with token.
+func (l *rateLimits) update(host, token, resource string, header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	if r := header.Get("X-RateLimit-Resource"); r != "" {
+		resource = r
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := budgetKey{host: host, token: token, resource: resource}
+	b, ok := l.budgets[key]
+	if !ok {
+		b = &budget{cost: 1}
+		l.budgets[key] = b
+	}
+	resetTime := time.Unix(reset, 0)
+	if ok && resetTime.Equal(b.reset) {
+		if cost := b.remaining - remaining; cost > 0 {
+			b.cost = cost
+		} else if remaining > b.remaining {
+			// A concurrent response was recorded first: keep the lowest remaining.
+			return
+		}
+	}
+	b.remaining = remaining
+	b.reset = resetTime
+}
+
+// available returns whether token has budget left for a request of resource to host, assuming it does if unknown.
+func (l *rateLimits) available(host, token, resource string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.budgets[budgetKey{host: host, token: token, resource: resource}]
+	return !ok || b.available(now)
+}
+
+// wait returns how long to wait before a request of resource to host: until the earliest reset if all the
+// known tokens of host exhausted their budget, zero otherwise.
+func (l *rateLimits) wait(host, resource string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var reset time.Time
+	for key, b := range l.budgets {
+		if key.host != host || key.resource != resource {
+			continue
+		}
+		if b.available(now) {
+			return 0
+		}
+		if reset.IsZero() || b.reset.Before(reset) {
+			reset = b.reset
+		}
+	}
+	if reset.IsZero() {
+		return 0
+	}
+	return reset.Sub(now)
+}
+
+func (b *budget) available(now time.Time) bool {
+	return b.remaining >= b.cost || !now.Before(b.reset)
+}
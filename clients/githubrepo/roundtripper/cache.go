@@ -0,0 +1,244 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sce "github.com/ossf/scorecard/v4/errors"
+)
+
+const (
+	// CacheMaxSizeEnvVar is the environment variable of the max size of the cache in MiB.
+	CacheMaxSizeEnvVar = "SCORECARD_CACHE_MAX_SIZE_MB"
+	// defaultCacheMaxSize is the max size of the cache in MiB if CacheMaxSizeEnvVar isn't set.
+	defaultCacheMaxSize = 1024
+)
+
+var (
+	commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	// credentialHeaders are the headers authenticating the requests to GitHub and GitLab.
+	credentialHeaders = []string{"Authorization", "PRIVATE-TOKEN", "JOB-TOKEN"}
+)
+
+// cacheTransport caches the responses to the GET requests on disk. The cached responses are
+// revalidated with conditional requests, which don't count against the GitHub rate limits when
+// the response didn't change, except the anonymous requests of the tarballs of commits, which never
+// change. The responses are cached by credentials, so that a token is never served a response to another.
+type cacheTransport struct {
+	innerTransport http.RoundTripper
+	cache          *diskCache
+}
+
+// NewCacheTransport returns a transport caching in dir the responses of the GitHub or GitLab API
+// requested through innerTransport, up to CacheMaxSizeEnvVar MiB.
+func NewCacheTransport(innerTransport http.RoundTripper, dir string) (http.RoundTripper, error) {
+	maxSize, err := cacheMaxSize()
+	if err != nil {
+		return nil, err
+	}
+	return makeCacheTransport(innerTransport, dir, maxSize)
+}
+
+func makeCacheTransport(innerTransport http.RoundTripper, dir string, maxSize int64) (http.RoundTripper, error) {
+	cache, err := openDiskCache(dir, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return &cacheTransport{
+		innerTransport: innerTransport,
+		cache:          cache,
+	}, nil
+}
+
+// cacheMaxSize returns the max size of the cache in bytes.
+func cacheMaxSize() (int64, error) {
+	value := os.Getenv(CacheMaxSizeEnvVar)
+	if value == "" {
+		return defaultCacheMaxSize << 20, nil
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		return 0, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("invalid %s: %q", CacheMaxSizeEnvVar, value))
+	}
+	return size << 20, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (ct *cacheTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodGet || r.Header.Get("Range") != "" {
+		return ct.roundTrip(r)
+	}
+	if ref, ok := tarballRef(r.URL); ok && ref == "" {
+		// Cache the tarball of HEAD by the commit it points to.
+		if sha, err := ct.headSHA(r); err == nil {
+			r = withTarballRef(r, sha)
+		}
+	}
+
+	key := cacheKey(r)
+	// The authenticated responses are always revalidated, in case the token lost its access.
+	authenticated := credentials(r) != ""
+	cached, ok := ct.cache.get(key)
+	if ok && !authenticated && isImmutable(r.URL) {
+		if resp, err := ct.cache.response(key, cached, r, nil); err == nil {
+			return resp, nil
+		}
+		ok = false
+	}
+	req := r
+	if ok {
+		req = r.Clone(r.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+	resp, err := ct.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok && resp.StatusCode == http.StatusNotModified {
+		if cachedResp, err := ct.cache.response(key, cached, r, resp.Header); err == nil {
+			resp.Body.Close()
+			return cachedResp, nil
+		}
+		// The entry was evicted meanwhile: request it again, unconditionally.
+		resp.Body.Close()
+		return ct.roundTrip(r)
+	}
+	if isCacheable(r.URL, resp, authenticated) {
+		resp.Body = ct.cache.tee(key, resp)
+	}
+	return resp, nil
+}
+
+func (ct *cacheTransport) roundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := ct.innerTransport.RoundTrip(r)
+	if err != nil {
+		return nil, fmt.Errorf("innerTransport.RoundTrip: %w", err)
+	}
+	return resp, nil
+}
+
+// headSHA returns the commit of HEAD of the repo of a tarball request.
+func (ct *cacheTransport) headSHA(r *http.Request) (string, error) {
+	commitURL := *r.URL
+	commitURL.Path = strings.TrimSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/tarball") + "/commits/HEAD"
+	commitURL.RawPath = ""
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, commitURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github.sha")
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := ct.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("io.ReadAll: %w", err)
+	}
+	sha := strings.TrimSpace(string(body))
+	if resp.StatusCode != http.StatusOK || !commitSHAPattern.MatchString(sha) {
+		return "", sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("no commit for HEAD: %s", resp.Status))
+	}
+	return sha, nil
+}
+
+// tarballRef returns the ref of a request of the tarball of a repo, e.g. /repos/owner/repo/tarball/ref.
+func tarballRef(u *url.URL) (string, bool) {
+	path := u.Path
+	i := strings.Index(path, "/repos/")
+	if i < 0 {
+		return "", false
+	}
+	parts := strings.Split(strings.TrimSuffix(path[i+len("/repos/"):], "/"), "/")
+	switch {
+	case len(parts) == 3 && parts[2] == "tarball":
+		return "", true
+	case len(parts) == 4 && parts[2] == "tarball":
+		return parts[3], true
+	default:
+		return "", false
+	}
+}
+
+func withTarballRef(r *http.Request, ref string) *http.Request {
+	req := r.Clone(r.Context())
+	req.URL.Path = strings.TrimSuffix(r.URL.Path, "/") + "/" + ref
+	req.URL.RawPath = ""
+	return req
+}
+
+// isImmutable returns whether the response to a request of u never changes: those of the tarballs of commits,
+// through the API or the download host it redirects to, or the download path of a GitHub Enterprise Server.
+func isImmutable(u *url.URL) bool {
+	if ref, ok := tarballRef(u); ok {
+		return commitSHAPattern.MatchString(ref)
+	}
+	if !strings.HasPrefix(u.Host, "codeload.") && !strings.HasPrefix(u.Path, "/_codeload/") {
+		return false
+	}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if commitSHAPattern.MatchString(segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCacheable returns whether the response to a request of u can be served again: the successful responses
+// with validators for the conditional requests, or which never change if the request is anonymous.
+func isCacheable(u *url.URL, resp *http.Response, authenticated bool) bool {
+	if resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return false
+	}
+	hasValidators := resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+	return hasValidators || (!authenticated && isImmutable(u))
+}
+
+// credentials returns the credentials of a request, empty if it's anonymous.
+func credentials(r *http.Request) string {
+	var values []string
+	for _, header := range credentialHeaders {
+		if value := r.Header.Get(header); value != "" {
+			values = append(values, header+": "+value)
+		}
+	}
+	return strings.Join(values, "\n")
+}
+
+// cacheKey identifies a request by its URL, the representation it accepts and its credentials,
+// which are only stored hashed.
+func cacheKey(r *http.Request) string {
+	hash := sha256.Sum256([]byte(r.URL.String() + "\n" + r.Header.Get("Accept") + "\n" + credentials(r)))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,323 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	headSHA  = "0123456789abcdef0123456789abcdef01234567"
+	otherSHA = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+// fakeGitHub serves a repo through the API and its tarballs through codeload, counting the requests by path.
+type fakeGitHub struct {
+	mu       sync.Mutex
+	requests map[string]int
+	// conditional counts the conditional requests answered with 304.
+	conditional int
+}
+
+func (gh *fakeGitHub) RoundTrip(r *http.Request) (*http.Response, error) {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	gh.requests[r.URL.Path]++
+	w := httptest.NewRecorder()
+	switch path := r.URL.Path; {
+	case path == "/repos/owner/repo/commits/HEAD":
+		w.Header().Set("ETag", `"head"`)
+		if r.Header.Get("If-None-Match") == `"head"` {
+			gh.conditional++
+			w.WriteHeader(http.StatusNotModified)
+			break
+		}
+		io.WriteString(w, headSHA)
+	case path == "/repos/owner/repo/tarball/"+headSHA:
+		w.Header().Set("Location", "https://codeload.github.com/owner/repo/legacy.tar.gz/"+headSHA)
+		w.WriteHeader(http.StatusFound)
+	case strings.HasPrefix(path, "/owner/repo/legacy.tar.gz/"):
+		io.WriteString(w, "tarball of "+strings.TrimPrefix(path, "/owner/repo/legacy.tar.gz/"))
+	case path == "/repos/owner/repo":
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-Modified-Since") != "" {
+			gh.conditional++
+			w.WriteHeader(http.StatusNotModified)
+			break
+		}
+		io.WriteString(w, `{"name": "repo"}`)
+	case path == "/repos/owner/repo/issues":
+		io.WriteString(w, `[]`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+	resp := w.Result()
+	resp.Request = r
+	return resp, nil
+}
+
+func get(t *testing.T, client *http.Client, url string) (string, *http.Response) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("io.ReadAll: %v", err)
+	}
+	return string(body), resp
+}
+
+func TestCacheTransport(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	gh := &fakeGitHub{requests: map[string]int{}}
+	transport, err := makeCacheTransport(gh, dir, 1<<20)
+	if err != nil {
+		t.Fatalf("makeCacheTransport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		body, resp := get(t, client, "https://api.github.com/repos/owner/repo/tarball/")
+		if body != "tarball of "+headSHA {
+			t.Errorf("tarball = %q, want the tarball of HEAD", body)
+		}
+		if fromCache := resp.Header.Get(fromCacheHeader) != ""; fromCache != (i > 0) {
+			t.Errorf("tarball %d from the cache: %t", i, fromCache)
+		}
+	}
+	// The tarball of HEAD is resolved to its commit, revalidated the second time,
+	// and downloaded once.
+	if gh.requests["/repos/owner/repo/commits/HEAD"] != 2 || gh.conditional != 1 {
+		t.Errorf("HEAD requests = %d, conditional = %d, want 2 and 1",
+			gh.requests["/repos/owner/repo/commits/HEAD"], gh.conditional)
+	}
+	if got := gh.requests["/owner/repo/legacy.tar.gz/"+headSHA]; got != 1 {
+		t.Errorf("tarball downloads = %d, want 1", got)
+	}
+
+	// Revalidated with Last-Modified: the rate limit is the one of the 304.
+	for i := 0; i < 2; i++ {
+		body, resp := get(t, client, "https://api.github.com/repos/owner/repo")
+		if body != `{"name": "repo"}` {
+			t.Errorf("repo = %q", body)
+		}
+		if got := resp.Header.Get("X-RateLimit-Remaining"); got != "4999" {
+			t.Errorf("X-RateLimit-Remaining = %q, want the one of the response", got)
+		}
+	}
+	if gh.conditional != 2 {
+		t.Errorf("conditional = %d, want 2", gh.conditional)
+	}
+
+	// Without validators, responses aren't cached.
+	for i := 0; i < 2; i++ {
+		get(t, client, "https://api.github.com/repos/owner/repo/issues")
+	}
+	if got := gh.requests["/repos/owner/repo/issues"]; got != 2 {
+		t.Errorf("issues requests = %d, want 2", got)
+	}
+
+	// The cache persists across runs.
+	transport, err = makeCacheTransport(gh, dir, 1<<20)
+	if err != nil {
+		t.Fatalf("makeCacheTransport: %v", err)
+	}
+	client = &http.Client{Transport: transport}
+	get(t, client, "https://codeload.github.com/owner/repo/legacy.tar.gz/"+headSHA)
+	if got := gh.requests["/owner/repo/legacy.tar.gz/"+headSHA]; got != 1 {
+		t.Errorf("tarball downloads = %d, want 1", got)
+	}
+}
+
+func TestCacheTransportPartialRead(t *testing.T) {
+	t.Parallel()
+	gh := &fakeGitHub{requests: map[string]int{}}
+	transport, err := makeCacheTransport(gh, t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("makeCacheTransport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+	url := "https://codeload.github.com/owner/repo/legacy.tar.gz/" + headSHA
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do: %v", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := resp.Body.Read(buf); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	resp.Body.Close()
+
+	get(t, client, url)
+	if got := gh.requests["/owner/repo/legacy.tar.gz/"+headSHA]; got != 2 {
+		t.Errorf("tarball downloads = %d, want 2 as the first one wasn't read entirely", got)
+	}
+}
+
+func TestCacheTransportEviction(t *testing.T) {
+	t.Parallel()
+	gh := &fakeGitHub{requests: map[string]int{}}
+	// Room for two tarballs and their metadata, about 130 bytes each.
+	transport, err := makeCacheTransport(gh, t.TempDir(), 300)
+	if err != nil {
+		t.Fatalf("makeCacheTransport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+	tarball := func(sha string) string {
+		return "https://codeload.github.com/owner/repo/legacy.tar.gz/" + sha
+	}
+	const thirdSHA = "fedcba9876543210fedcba9876543210fedcba98"
+
+	get(t, client, tarball(headSHA))
+	get(t, client, tarball(otherSHA))
+	// Use the first one again: the second one is the least recently used.
+	get(t, client, tarball(headSHA))
+	get(t, client, tarball(thirdSHA))
+	get(t, client, tarball(headSHA))
+	get(t, client, tarball(otherSHA))
+
+	want := map[string]int{headSHA: 1, otherSHA: 2, thirdSHA: 1}
+	for sha, n := range want {
+		if got := gh.requests["/owner/repo/legacy.tar.gz/"+sha]; got != n {
+			t.Errorf("downloads of %s = %d, want %d", sha, got, n)
+		}
+	}
+}
+
+// fakeGitLab serves a project with an ETag, answering the conditional requests with a 304,
+// and counts the full responses by token.
+type fakeGitLab struct {
+	mu   sync.Mutex
+	full map[string]int
+}
+
+func (gl *fakeGitLab) RoundTrip(r *http.Request) (*http.Response, error) {
+	gl.mu.Lock()
+	defer gl.mu.Unlock()
+	w := httptest.NewRecorder()
+	w.Header().Set("ETag", `"project"`)
+	if r.Header.Get("If-None-Match") == `"project"` {
+		w.WriteHeader(http.StatusNotModified)
+	} else {
+		gl.full[r.Header.Get("PRIVATE-TOKEN")]++
+		io.WriteString(w, `{"id": 1}`)
+	}
+	resp := w.Result()
+	resp.Request = r
+	return resp, nil
+}
+
+func getWithHeader(t *testing.T, client *http.Client, url, header, value string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+	req.Header.Set(header, value)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do: %v", err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatalf("io.ReadAll: %v", err)
+	}
+	return resp
+}
+
+func TestCacheTransportCredentials(t *testing.T) {
+	t.Parallel()
+	gl := &fakeGitLab{full: map[string]int{}}
+	transport, err := makeCacheTransport(gl, t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("makeCacheTransport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+	const project = "https://gitlab.com/api/v4/projects/1"
+
+	// The responses to a token are revalidated, and never served to another one.
+	for i := 0; i < 2; i++ {
+		resp := getWithHeader(t, client, project, "PRIVATE-TOKEN", "token1")
+		if fromCache := resp.Header.Get(fromCacheHeader) != ""; fromCache != (i > 0) {
+			t.Errorf("project %d from the cache: %t", i, fromCache)
+		}
+	}
+	getWithHeader(t, client, project, "PRIVATE-TOKEN", "token2")
+	if gl.full["token1"] != 1 || gl.full["token2"] != 1 {
+		t.Errorf("full responses by token = %v, want one each", gl.full)
+	}
+
+	// The authenticated requests of tarballs without validators aren't cached.
+	gh := &fakeGitHub{requests: map[string]int{}}
+	transport, err = makeCacheTransport(gh, t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("makeCacheTransport: %v", err)
+	}
+	client = &http.Client{Transport: transport}
+	for i := 0; i < 2; i++ {
+		getWithHeader(t, client, "https://codeload.github.com/owner/repo/legacy.tar.gz/"+headSHA,
+			"Authorization", "Bearer token")
+	}
+	if got := gh.requests["/owner/repo/legacy.tar.gz/"+headSHA]; got != 2 {
+		t.Errorf("tarball downloads = %d, want 2", got)
+	}
+}
+
+//nolint:paralleltest // Uses t.Setenv.
+func TestCacheMaxSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{name: "default", value: "", want: defaultCacheMaxSize << 20},
+		{name: "set", value: "16", want: 16 << 20},
+		{name: "not a number", value: "16MB", wantErr: true},
+		{name: "zero", value: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(CacheMaxSizeEnvVar, tt.value)
+			got, err := cacheMaxSize()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cacheMaxSize() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("cacheMaxSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
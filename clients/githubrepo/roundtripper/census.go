@@ -15,8 +15,12 @@
 package roundtripper
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"go.opencensus.io/plugin/ochttp"
 	opencensusstats "go.opencensus.io/stats"
@@ -28,6 +32,15 @@ import (
 
 const fromCacheHeader = "X-From-Cache"
 
+// Endpoint classes of the requests, see stats.Usage.
+const (
+	endpointREST    = "rest"
+	endpointGraphQL = "graphql"
+	endpointSearch  = "search"
+	endpointTarball = "tarball"
+	endpointOther   = "other"
+)
+
 // MakeCensusTransport wraps input Roundtripper with monitoring logic.
 func MakeCensusTransport(innerTransport http.RoundTripper) http.RoundTripper {
 	return &ochttp.Transport{
@@ -61,5 +74,43 @@ func (ct *censusTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 		}
 	}
 	opencensusstats.Record(ctx, stats.HTTPRequests.M(1))
+
+	fromCache := resp.Header.Get(fromCacheHeader) != ""
+	stats.RecordRequest(ctx, endpointClass(r.URL), fromCache)
+	if !fromCache && resp.Body != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, ctx: ctx}
+	}
 	return resp, nil
 }
+
+// endpointClass returns the class of the endpoint of a request to github.com or
+// a GitHub Enterprise Server, whose APIs are under /api.
+func endpointClass(u *url.URL) string {
+	path := strings.TrimPrefix(u.Path, "/api/v3")
+	switch {
+	case strings.HasSuffix(u.Path, "/graphql"):
+		return endpointGraphQL
+	case strings.HasPrefix(path, "/search/"):
+		return endpointSearch
+	case strings.HasPrefix(u.Host, "codeload."), strings.HasPrefix(u.Path, "/_codeload/"),
+		strings.HasPrefix(path, "/repos/") && strings.Contains(path, "/tarball/"):
+		return endpointTarball
+	case u.Host == "api.github.com", strings.HasPrefix(u.Path, "/api/v3/"):
+		return endpointREST
+	default:
+		return endpointOther
+	}
+}
+
+// countingBody counts the bytes read from the body of a response in the stats.Usage of its request.
+type countingBody struct {
+	io.ReadCloser
+	ctx context.Context
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	stats.RecordBytes(b.ctx, int64(n))
+	//nolint:wrapcheck // The errors of the body, e.g. io.EOF, are returned as is.
+	return n, err
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/stats"
+)
+
+func TestEndpointClass(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://api.github.com/repos/owner/repo", want: endpointREST},
+		{url: "https://api.github.com/graphql", want: endpointGraphQL},
+		{url: "https://api.github.com/search/code?q=x", want: endpointSearch},
+		{url: "https://api.github.com/repos/owner/repo/tarball/", want: endpointTarball},
+		{url: "https://codeload.github.com/owner/repo/legacy.tar.gz/main", want: endpointTarball},
+		{url: "https://ghe.example.com/api/v3/repos/owner/repo", want: endpointREST},
+		{url: "https://ghe.example.com/api/graphql", want: endpointGraphQL},
+		{url: "https://ghe.example.com/api/v3/search/commits", want: endpointSearch},
+		{url: "https://ghe.example.com/api/v3/repos/owner/repo/tarball/main", want: endpointTarball},
+		{url: "https://ghe.example.com/_codeload/owner/repo/legacy.tar.gz/main", want: endpointTarball},
+		{url: "https://raw.githubusercontent.com/owner/repo/main/README.md", want: endpointOther},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatalf("url.Parse: %v", err)
+		}
+		if got := endpointClass(u); got != tt.want {
+			t.Errorf("endpointClass(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+// cachingGitHub answers the requests to /cached from the cache.
+type cachingGitHub struct{}
+
+func (cachingGitHub) RoundTrip(r *http.Request) (*http.Response, error) {
+	w := httptest.NewRecorder()
+	if r.URL.Path == "/cached" {
+		w.Header().Set(fromCacheHeader, "1")
+	}
+	io.WriteString(w, "0123456789")
+	resp := w.Result()
+	resp.Request = r
+	return resp, nil
+}
+
+func TestCensusTransportUsage(t *testing.T) {
+	t.Parallel()
+	var usage stats.Usage
+	ctx := stats.WithUsage(context.Background(), &usage)
+	client := &http.Client{Transport: MakeCensusTransport(cachingGitHub{})}
+	for _, u := range []string{
+		"https://api.github.com/repos/owner/repo",
+		"https://api.github.com/cached",
+		"https://api.github.com/graphql",
+	} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			t.Fatalf("http.NewRequest: %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("client.Do: %v", err)
+		}
+		if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+			t.Fatalf("io.Copy: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	want := map[string]int64{endpointREST: 2, endpointGraphQL: 1}
+	if diff := cmp.Diff(want, usage.RequestsPerClass()); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+	// The cached response isn't downloaded.
+	if got := usage.BytesDownloaded(); got != 20 {
+		t.Errorf("bytes downloaded = %d, want 20", got)
+	}
+	if got := usage.CacheHits(); got != 1 {
+		t.Errorf("cache hits = %d, want 1", got)
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	bodySuffix = ".body"
+	metaSuffix = ".json"
+	tempSuffix = ".tmp"
+)
+
+// cachedResponse is the metadata of a cached response, stored next to its body.
+type cachedResponse struct {
+	StatusCode int
+	Header     http.Header
+}
+
+// cacheEntry is a response of the cache, for its LRU eviction.
+type cacheEntry struct {
+	size int64
+	used time.Time
+}
+
+// diskCache stores the responses in a directory, a body file and a metadata file per response.
+// Once its size exceeds maxSize, it evicts the least recently used responses.
+type diskCache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	size    int64
+}
+
+// openDiskCache indexes the responses cached in dir by former runs.
+func openDiskCache(dir string, maxSize int64) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadDir: %w", err)
+	}
+	cache := &diskCache{
+		dir:     dir,
+		maxSize: maxSize,
+		entries: map[string]*cacheEntry{},
+	}
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasSuffix(name, tempSuffix) {
+			// Left by an interrupted run.
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		key := strings.TrimSuffix(name, bodySuffix)
+		if key == name {
+			continue
+		}
+		body, errBody := file.Info()
+		meta, errMeta := os.Stat(cache.path(key, metaSuffix))
+		if errBody != nil || errMeta != nil {
+			continue
+		}
+		entry := &cacheEntry{size: body.Size() + meta.Size(), used: body.ModTime()}
+		cache.entries[key] = entry
+		cache.size += entry.size
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.evict("")
+	return cache, nil
+}
+
+func (cache *diskCache) path(key, suffix string) string {
+	return filepath.Join(cache.dir, key+suffix)
+}
+
+// get returns the metadata of the cached response of key, if any.
+func (cache *diskCache) get(key string) (*cachedResponse, bool) {
+	cache.mu.Lock()
+	_, ok := cache.entries[key]
+	cache.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	content, err := os.ReadFile(cache.path(key, metaSuffix))
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(content, &cached); err != nil {
+		return nil, false
+	}
+	return &cached, true
+}
+
+// response returns the cached response of key to r, with the headers of fresh if it was revalidated.
+func (cache *diskCache) response(key string, cached *cachedResponse, r *http.Request,
+	fresh http.Header,
+) (*http.Response, error) {
+	body, err := os.Open(cache.path(key, bodySuffix))
+	if err != nil {
+		return nil, fmt.Errorf("os.Open: %w", err)
+	}
+	info, err := body.Stat()
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("body.Stat: %w", err)
+	}
+	now := time.Now()
+	cache.mu.Lock()
+	if entry, ok := cache.entries[key]; ok {
+		entry.used = now
+	}
+	cache.mu.Unlock()
+	//nolint:errcheck // Only used for the eviction order of the next runs.
+	os.Chtimes(body.Name(), now, now)
+
+	header := cached.Header.Clone()
+	for name, values := range fresh {
+		if name != "Content-Length" && name != "Transfer-Encoding" {
+			header[name] = values
+		}
+	}
+	header.Set(fromCacheHeader, "1")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", cached.StatusCode, http.StatusText(cached.StatusCode)),
+		StatusCode:    cached.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          body,
+		ContentLength: info.Size(),
+		Request:       r,
+	}, nil
+}
+
+// tee returns the body of resp, caching it under key once it's read entirely.
+func (cache *diskCache) tee(key string, resp *http.Response) io.ReadCloser {
+	temp, err := os.CreateTemp(cache.dir, key+"-*"+tempSuffix)
+	if err != nil {
+		return resp.Body
+	}
+	header := resp.Header.Clone()
+	// The rate limits of a cached response are stale.
+	for name := range header {
+		if strings.HasPrefix(name, "X-Ratelimit-") {
+			header.Del(name)
+		}
+	}
+	return &cachingBody{
+		body:   resp.Body,
+		temp:   temp,
+		cache:  cache,
+		key:    key,
+		meta:   cachedResponse{StatusCode: resp.StatusCode, Header: header},
+		length: resp.ContentLength,
+	}
+}
+
+// commit stores the body in temp and the metadata of a response under key.
+func (cache *diskCache) commit(key string, temp string, meta *cachedResponse) error {
+	content, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err := os.WriteFile(cache.path(key, metaSuffix), content, 0o600); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	if err := os.Rename(temp, cache.path(key, bodySuffix)); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+	info, err := os.Stat(cache.path(key, bodySuffix))
+	if err != nil {
+		return fmt.Errorf("os.Stat: %w", err)
+	}
+	if entry, ok := cache.entries[key]; ok {
+		cache.size -= entry.size
+	}
+	entry := &cacheEntry{size: info.Size() + int64(len(content)), used: time.Now()}
+	cache.entries[key] = entry
+	cache.size += entry.size
+	cache.evict(key)
+	return nil
+}
+
+// evict removes the least recently used responses until the cache fits in its max size,
+// the response of key last.
+func (cache *diskCache) evict(key string) {
+	if cache.size <= cache.maxSize {
+		return
+	}
+	keys := make([]string, 0, len(cache.entries))
+	for k := range cache.entries {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i] == key || keys[j] == key {
+			return keys[j] == key
+		}
+		return cache.entries[keys[i]].used.Before(cache.entries[keys[j]].used)
+	})
+	for _, k := range keys {
+		if cache.size <= cache.maxSize {
+			return
+		}
+		os.Remove(cache.path(k, bodySuffix))
+		os.Remove(cache.path(k, metaSuffix))
+		cache.size -= cache.entries[k].size
+		delete(cache.entries, k)
+	}
+}
+
+// cachingBody copies the body of a response to a temporary file as it's read,
+// and caches it once it's read entirely.
+type cachingBody struct {
+	body   io.ReadCloser
+	temp   *os.File
+	cache  *diskCache
+	key    string
+	meta   cachedResponse
+	length int64
+	read   int64
+}
+
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 && b.temp != nil {
+		if _, errWrite := b.temp.Write(p[:n]); errWrite != nil {
+			b.discard()
+		}
+		b.read += int64(n)
+	}
+	if err == io.EOF && b.temp != nil {
+		b.store()
+	}
+	//nolint:wrapcheck // The errors of the body are returned as is.
+	return n, err
+}
+
+func (b *cachingBody) Close() error {
+	if b.temp != nil {
+		// Not read entirely.
+		b.discard()
+	}
+	//nolint:wrapcheck // The errors of the body are returned as is.
+	return b.body.Close()
+}
+
+func (b *cachingBody) store() {
+	temp := b.temp
+	b.temp = nil
+	if err := temp.Close(); err != nil || (b.length >= 0 && b.read != b.length) {
+		os.Remove(temp.Name())
+		return
+	}
+	if err := b.cache.commit(b.key, temp.Name(), &b.meta); err != nil {
+		os.Remove(temp.Name())
+	}
+}
+
+func (b *cachingBody) discard() {
+	b.temp.Close()
+	os.Remove(b.temp.Name())
+	b.temp = nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	// GitHubHostEnvVar is the environment variable of the host of a GitHub Enterprise Server,
+	// e.g. ghe.example.com, as for the GitHub CLI.
+	GitHubHostEnvVar = "GH_HOST"
+
+	githubHost = "github.com"
+)
+
+// EnterpriseHost returns the host of the GitHub Enterprise Server configured in the environment,
+// or "" if there's none.
+func EnterpriseHost() string {
+	host := strings.ToLower(strings.TrimSpace(os.Getenv(GitHubHostEnvVar)))
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "https://"), "/")
+	if host == githubHost {
+		return ""
+	}
+	return host
+}
+
+// isHostOf returns whether requests to host are requests to the GitHub instance at base, including
+// those to its subdomains, e.g. codeload.github.com for github.com.
+func isHostOf(host, base string) bool {
+	host = strings.ToLower(host)
+	return host == base || strings.HasSuffix(host, "."+base)
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+//nolint:paralleltest // Uses t.Setenv.
+func TestEnterpriseHost(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "not set", value: "", want: ""},
+		{name: "host", value: "ghe.example.com", want: "ghe.example.com"},
+		{name: "URL", value: "https://GHE.example.com/", want: "ghe.example.com"},
+		{name: "github.com", value: "github.com", want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(GitHubHostEnvVar, tt.value)
+			if got := EnterpriseHost(); got != tt.want {
+				t.Errorf("EnterpriseHost() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitHubTransportEnterpriseHost(t *testing.T) {
+	t.Parallel()
+	//nolint:govet
+	tests := []struct {
+		name             string
+		url              string
+		enterpriseTokens bool
+		wantToken        string
+	}{
+		{
+			name:             "github.com API",
+			url:              "https://api.github.com/repos/o/r",
+			enterpriseTokens: true,
+			wantToken:        "Bearer token0",
+		},
+		{
+			name:             "enterprise API",
+			url:              "https://ghe.example.com/api/v3/repos/o/r",
+			enterpriseTokens: true,
+			wantToken:        "Bearer ghe-token0",
+		},
+		{
+			name:             "enterprise GraphQL",
+			url:              "https://ghe.example.com/api/graphql",
+			enterpriseTokens: true,
+			wantToken:        "Bearer ghe-token0",
+		},
+		{
+			name:             "enterprise subdomain",
+			url:              "https://codeload.ghe.example.com/o/r/legacy.tar.gz/main",
+			enterpriseTokens: true,
+			wantToken:        "Bearer ghe-token0",
+		},
+		{
+			name:      "enterprise without tokens",
+			url:       "https://ghe.example.com/api/v3/repos/o/r",
+			wantToken: "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			inner := &fakeTransport{responses: []*http.Response{response(http.StatusOK)}}
+			transport := makeGitHubTransport(inner, &fakeAccessor{count: 1}, newRateLimits())
+			transport.enterpriseHost = "ghe.example.com"
+			if tt.enterpriseTokens {
+				transport.enterpriseTokens = &fakeAccessor{prefix: "ghe-", count: 1}
+			}
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest: %v", err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip: %v", err)
+			}
+			resp.Body.Close()
+			if got := inner.requests[0].Header.Get("Authorization"); got != tt.wantToken {
+				t.Errorf("Authorization = %q, want %q", got, tt.wantToken)
+			}
+		})
+	}
+}
@@ -15,57 +15,190 @@
 package roundtripper
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	opencensusstats "go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	"github.com/ossf/scorecard/v4/clients/githubrepo/roundtripper/tokens"
+	githubstats "github.com/ossf/scorecard/v4/clients/githubrepo/stats"
 	sce "github.com/ossf/scorecard/v4/errors"
 	"github.com/ossf/scorecard/v4/log"
+	"github.com/ossf/scorecard/v4/stats"
+)
+
+const (
+	// maxRateLimitAttempts bounds the attempts at a request hitting rate limits.
+	maxRateLimitAttempts = 5
+	// secondaryLimitDelay is the delay before retrying a request hitting a secondary rate limit
+	// without Retry-After, see https://docs.github.com/en/rest/overview/resources-in-the-rest-api#secondary-rate-limits.
+	secondaryLimitDelay = time.Minute
+
+	reasonPrimary   = "primary"
+	reasonSecondary = "secondary"
 )
 
 // MakeRateLimitedTransport returns a RoundTripper which rate limits GitHub requests.
 func MakeRateLimitedTransport(innerTransport http.RoundTripper, logger *log.Logger) http.RoundTripper {
+	return makeRateLimitedTransport(innerTransport, logger, newRateLimits())
+}
+
+func makeRateLimitedTransport(innerTransport http.RoundTripper, logger *log.Logger,
+	limits *rateLimits,
+) http.RoundTripper {
 	return &rateLimitTransport{
 		logger:         logger,
 		innerTransport: innerTransport,
+		limits:         limits,
 	}
 }
 
 // rateLimitTransport is a rate-limit aware http.Transport for Github.
+// It waits for the reset of the rate limit of a resource once all the tokens exhausted it,
+// and retries the requests hitting secondary rate limits after their Retry-After.
 type rateLimitTransport struct {
 	logger         *log.Logger
 	innerTransport http.RoundTripper
+	limits         *rateLimits
 }
 
 // Roundtrip handles caching and ratelimiting of responses from GitHub.
 func (gh *rateLimitTransport) RoundTrip(r *http.Request) (*http.Response, error) {
-	resp, err := gh.innerTransport.RoundTrip(r)
-	if err != nil {
-		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("innerTransport.RoundTrip: %v", err))
-	}
-	rateLimit := resp.Header.Get("X-RateLimit-Remaining")
-	remaining, err := strconv.Atoi(rateLimit)
-	if err != nil {
-		return resp, nil
+	resource := resourceOf(r)
+	if wait := gh.limits.wait(r.URL.Host, resource, time.Now()); wait > 0 {
+		if err := gh.sleep(r.Context(), wait, resource, reasonPrimary); err != nil {
+			return nil, err
+		}
 	}
 
-	if remaining <= 0 {
-		reset, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Reset"))
+	for attempt := 1; ; attempt++ {
+		resp, err := gh.innerTransport.RoundTrip(r)
+		if err != nil {
+			return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("innerTransport.RoundTrip: %v", err))
+		}
+		gh.limits.update(r.URL.Host, tokenOf(resp), resource, resp.Header)
+
+		wait, reason, limited := retryDelay(resp, time.Now())
+		if !limited || attempt == maxRateLimitAttempts {
+			return resp, nil
+		}
+		retry, err := rewind(r)
 		if err != nil {
 			return resp, nil
 		}
+		resp.Body.Close()
+		if err := gh.sleep(r.Context(), wait, resource, reason); err != nil {
+			return nil, err
+		}
+		r = retry
+	}
+}
 
-		duration := time.Until(time.Unix(int64(reset), 0))
-		// TODO(log): Previously Warn. Consider logging an error here.
-		gh.logger.Info(fmt.Sprintf("Rate limit exceeded. Waiting %s to retry...", duration))
+// sleep waits for a rate limit to reset, unless ctx is done or its deadline is before the reset.
+func (gh *rateLimitTransport) sleep(ctx context.Context, wait time.Duration, resource, reason string) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+		return fmt.Errorf("%w: the %s %s rate limit resets in %s", context.DeadlineExceeded, resource, reason, wait)
+	}
+	// TODO(log): Previously Warn. Consider logging an error here.
+	gh.logger.Info(fmt.Sprintf("%s %s rate limit exceeded. Waiting %s to retry...", resource, reason, wait))
+	recordWait(ctx, wait, resource, reason)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for the %s %s rate limit: %w", resource, reason, ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
 
-		// Retry
-		time.Sleep(duration)
-		// TODO(log): Previously Warn. Consider logging an error here.
-		gh.logger.Info("Rate limit exceeded. Retrying...")
-		return gh.RoundTrip(r)
+func recordWait(ctx context.Context, wait time.Duration, resource, reason string) {
+	ctx, err := tag.New(ctx,
+		tag.Upsert(stats.RateLimitResource, resource),
+		tag.Upsert(stats.RateLimitReason, reason))
+	if err != nil {
+		return
 	}
+	opencensusstats.Record(ctx, stats.RateLimitWaitInSec.M(wait.Seconds()))
+}
 
-	return resp, nil
+// retryDelay returns how long to wait before retrying a request whose response hit a rate limit, if it did.
+func retryDelay(resp *http.Response, now time.Time) (time.Duration, string, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, "", false
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second, reasonSecondary, true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return nonNegative(date.Sub(now)), reasonSecondary, true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return nonNegative(time.Unix(reset, 0).Sub(now)), reasonPrimary, true
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return secondaryLimitDelay, reasonSecondary, true
+	}
+	// Any other 403 is a permission error.
+	return 0, "", false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// rewind returns a copy of r to send again, with a new body if it has one.
+func rewind(r *http.Request) (*http.Request, error) {
+	retry := r.Clone(r.Context())
+	if r.Body == nil || r.Body == http.NoBody {
+		return retry, nil
+	}
+	if r.GetBody == nil {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, "request body can't be sent again")
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("GetBody: %v", err))
+	}
+	retry.Body = body
+	return retry, nil
+}
+
+// resourceOf returns the rate limit resource of a GitHub API request.
+func resourceOf(r *http.Request) string {
+	switch path := r.URL.Path; {
+	case strings.HasSuffix(path, "/graphql"):
+		return "graphql"
+	case strings.HasPrefix(path, "/search/") || strings.Contains(path, "/api/v3/search/"):
+		return "search"
+	default:
+		return tokens.ResourceCore
+	}
+}
+
+// tokenOf returns the index of the token of the request of a response, tagged by githubTransport,
+// or "" if it isn't authenticated with a token of a TokenAccessor.
+func tokenOf(resp *http.Response) string {
+	if resp.Request == nil {
+		return ""
+	}
+	tags := tag.FromContext(resp.Request.Context())
+	if tags == nil {
+		return ""
+	}
+	token, _ := tags.Value(githubstats.TokenIndex)
+	return token
 }
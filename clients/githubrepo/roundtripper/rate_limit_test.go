@@ -0,0 +1,371 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roundtripper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ossf/scorecard/v4/log"
+)
+
+const apiHost = "api.github.com"
+
+// fakeTransport returns its responses in order, recording the requests and their bodies.
+type fakeTransport struct {
+	mu        sync.Mutex
+	responses []*http.Response
+	requests  []*http.Request
+	bodies    []string
+}
+
+func (f *fakeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var body string
+	if r.Body != nil {
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("io.ReadAll: %w", err)
+		}
+		body = string(content)
+	}
+	f.requests = append(f.requests, r)
+	f.bodies = append(f.bodies, body)
+	resp := f.responses[0]
+	if len(f.responses) > 1 {
+		f.responses = f.responses[1:]
+	}
+	clone := *resp
+	clone.Body = io.NopCloser(strings.NewReader(""))
+	clone.Request = r
+	return &clone, nil
+}
+
+func response(status int, headers ...string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}}
+	for i := 0; i+1 < len(headers); i += 2 {
+		resp.Header.Set(headers[i], headers[i+1])
+	}
+	return resp
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	t.Parallel()
+	reset := fmt.Sprint(time.Now().Add(time.Hour).Unix())
+	//nolint:govet
+	tests := []struct {
+		name         string
+		responses    []*http.Response
+		timeout      time.Duration
+		wantStatus   int
+		wantRequests int
+		wantErr      error
+	}{
+		{
+			name:         "success",
+			responses:    []*http.Response{response(http.StatusOK, "X-RateLimit-Remaining", "10")},
+			wantStatus:   http.StatusOK,
+			wantRequests: 1,
+		},
+		{
+			name: "secondary limit with Retry-After",
+			responses: []*http.Response{
+				response(http.StatusForbidden, "Retry-After", "0"),
+				response(http.StatusTooManyRequests, "Retry-After", "0"),
+				response(http.StatusOK),
+			},
+			wantStatus:   http.StatusOK,
+			wantRequests: 3,
+		},
+		{
+			name:         "gives up after the max attempts",
+			responses:    []*http.Response{response(http.StatusTooManyRequests, "Retry-After", "0")},
+			wantStatus:   http.StatusTooManyRequests,
+			wantRequests: maxRateLimitAttempts,
+		},
+		{
+			name:         "permission error",
+			responses:    []*http.Response{response(http.StatusForbidden)},
+			wantStatus:   http.StatusForbidden,
+			wantRequests: 1,
+		},
+		{
+			name:         "Retry-After past the deadline",
+			responses:    []*http.Response{response(http.StatusForbidden, "Retry-After", "60")},
+			timeout:      time.Second,
+			wantRequests: 1,
+			wantErr:      context.DeadlineExceeded,
+		},
+		{
+			name: "primary limit reset past the deadline",
+			responses: []*http.Response{
+				response(http.StatusForbidden, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset),
+			},
+			timeout:      time.Second,
+			wantRequests: 1,
+			wantErr:      context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			inner := &fakeTransport{responses: tt.responses}
+			transport := MakeRateLimitedTransport(inner, log.NewLogger(log.DefaultLevel))
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/graphql",
+				strings.NewReader("query"))
+			if err != nil {
+				t.Fatalf("http.NewRequest: %v", err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RoundTrip() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				defer resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			}
+			if len(inner.requests) != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", len(inner.requests), tt.wantRequests)
+			}
+			for i, body := range inner.bodies {
+				if body != "query" {
+					t.Errorf("body of request %d = %q, want the body sent again", i, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitTransportWaitsForExhaustedBudget(t *testing.T) {
+	t.Parallel()
+	reset := fmt.Sprint(time.Now().Add(time.Hour).Unix())
+	inner := &fakeTransport{responses: []*http.Response{
+		response(http.StatusOK, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset, "X-RateLimit-Resource", "core"),
+	}}
+	transport := MakeRateLimitedTransport(inner, log.NewLogger(log.DefaultLevel))
+	// The exhausting response is returned as is.
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://api.github.com/repos/o/r", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	resp.Body.Close()
+
+	// The next core request waits for the reset, the search ones don't.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/repos/o/r/commits", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+	//nolint:bodyclose // No response on error.
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, context.Canceled)
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/search/code", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+	resp, err = transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	resp.Body.Close()
+	if len(inner.requests) != 2 {
+		t.Errorf("requests = %d, want 2", len(inner.requests))
+	}
+}
+
+func rateLimitHeader(remaining int, reset time.Time, resource string) http.Header {
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", fmt.Sprint(remaining))
+	header.Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+	if resource != "" {
+		header.Set("X-RateLimit-Resource", resource)
+	}
+	return header
+}
+
+func TestRateLimits(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	reset := now.Add(time.Hour)
+	limits := newRateLimits()
+
+	if !limits.available(apiHost, "0", "graphql", now) || limits.wait(apiHost, "graphql", now) != 0 {
+		t.Fatal("unknown budgets are assumed available")
+	}
+	// A GraphQL query costing 40 points.
+	limits.update(apiHost, "0", "graphql", rateLimitHeader(100, reset, "graphql"))
+	limits.update(apiHost, "0", "graphql", rateLimitHeader(60, reset, "graphql"))
+	if !limits.available(apiHost, "0", "graphql", now) {
+		t.Error("token 0 has budget for a query")
+	}
+	limits.update(apiHost, "0", "graphql", rateLimitHeader(20, reset, "graphql"))
+	if limits.available(apiHost, "0", "graphql", now) {
+		t.Error("token 0 has no budget left for a 40 points query")
+	}
+	if wait := limits.wait(apiHost, "graphql", now); wait <= 0 || wait > time.Hour {
+		t.Errorf("wait = %s, want until the reset", wait)
+	}
+	// A stale response doesn't restore the budget.
+	limits.update(apiHost, "0", "graphql", rateLimitHeader(60, reset, "graphql"))
+	if limits.available(apiHost, "0", "graphql", now) {
+		t.Error("stale response restored the budget of token 0")
+	}
+	if !limits.available(apiHost, "0", "graphql", reset) {
+		t.Error("the budget of token 0 is available again after the reset")
+	}
+	// Another token has budget: no need to wait.
+	limits.update(apiHost, "1", "graphql", rateLimitHeader(5000, reset, "graphql"))
+	if wait := limits.wait(apiHost, "graphql", now); wait != 0 {
+		t.Errorf("wait = %s, want 0 with token 1 available", wait)
+	}
+	// The resource of the response header prevails.
+	limits.update(apiHost, "1", "core", rateLimitHeader(0, reset, "search"))
+	if limits.available(apiHost, "1", "search", now) || !limits.available(apiHost, "1", "core", now) {
+		t.Error("budget recorded for the wrong resource")
+	}
+}
+
+type fakeAccessor struct {
+	mu       sync.Mutex
+	err      error
+	prefix   string
+	next     uint64
+	count    uint64
+	released []uint64
+}
+
+func (a *fakeAccessor) Next() (uint64, string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return 0, "", a.err
+	}
+	id := a.next % a.count
+	a.next++
+	return id, fmt.Sprintf("%stoken%d", a.prefix, id), nil
+}
+
+func (a *fakeAccessor) Release(id uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.released = append(a.released, id)
+}
+
+func TestGitHubTransportPrefersTokensWithBudget(t *testing.T) {
+	t.Parallel()
+	reset := time.Now().Add(time.Hour)
+	limits := newRateLimits()
+	limits.update(apiHost, "0", "core", rateLimitHeader(0, reset, "core"))
+	limits.update(apiHost, "1", "core", rateLimitHeader(0, reset, "core"))
+	limits.update(apiHost, "1", "search", rateLimitHeader(10, reset, "search"))
+
+	//nolint:govet
+	tests := []struct {
+		name      string
+		url       string
+		wantToken string
+	}{
+		{name: "token with budget", url: "https://api.github.com/repos/o/r", wantToken: "Bearer token2"},
+		{name: "first token with budget", url: "https://api.github.com/search/code", wantToken: "Bearer token0"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			inner := &fakeTransport{responses: []*http.Response{response(http.StatusOK)}}
+			transport := makeGitHubTransport(inner, &fakeAccessor{count: 3}, limits)
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest: %v", err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip: %v", err)
+			}
+			resp.Body.Close()
+			if got := inner.requests[0].Header.Get("Authorization"); got != tt.wantToken {
+				t.Errorf("Authorization = %q, want %q", got, tt.wantToken)
+			}
+		})
+	}
+
+	// All exhausted: the first token seen again is used.
+	exhausted := newRateLimits()
+	exhausted.update(apiHost, "0", "core", rateLimitHeader(0, reset, "core"))
+	exhausted.update(apiHost, "1", "core", rateLimitHeader(0, reset, "core"))
+	inner := &fakeTransport{responses: []*http.Response{response(http.StatusOK)}}
+	accessor := &fakeAccessor{count: 2}
+	transport := makeGitHubTransport(inner, accessor, exhausted)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://api.github.com/repos/o/r", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	resp.Body.Close()
+	if got := inner.requests[0].Header.Get("Authorization"); got != "Bearer token0" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer token0")
+	}
+	if len(accessor.released) != 3 {
+		t.Errorf("released tokens = %v, want the 2 skipped ones and the used one", accessor.released)
+	}
+}
+
+func TestGitHubTransportTokenError(t *testing.T) {
+	t.Parallel()
+	errMint := errors.New("minting error")
+	inner := &fakeTransport{responses: []*http.Response{response(http.StatusOK)}}
+	accessor := &fakeAccessor{count: 1, err: errMint}
+	transport := makeGitHubTransport(inner, accessor, newRateLimits())
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://api.github.com/repos/o/r", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+	// The request isn't sent unauthenticated.
+	if _, err := transport.RoundTrip(req); !errors.Is(err, errMint) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, errMint)
+	}
+	if len(inner.requests) != 0 {
+		t.Errorf("requests sent = %d, want none", len(inner.requests))
+	}
+	if len(accessor.released) != 0 {
+		t.Errorf("released tokens = %v, want none", accessor.released)
+	}
+}
@@ -19,49 +19,54 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"os"
-	"strconv"
-
-	"github.com/bradleyfalzon/ghinstallation/v2"
 
 	"github.com/ossf/scorecard/v4/clients/githubrepo/roundtripper/tokens"
 	"github.com/ossf/scorecard/v4/log"
 )
 
-const (
-	// githubAppKeyPath is the path to file for GitHub App key.
-	githubAppKeyPath = "GITHUB_APP_KEY_PATH"
-	// githubAppID is the app ID for the GitHub App.
-	githubAppID = "GITHUB_APP_ID"
-	// githubAppInstallationID is the installation ID for the GitHub App.
-	githubAppInstallationID = "GITHUB_APP_INSTALLATION_ID"
-)
-
 // NewTransport returns a configured http.Transport for use with GitHub.
 func NewTransport(ctx context.Context, logger *log.Logger) http.RoundTripper {
+	return NewCachingTransport(ctx, logger, "")
+}
+
+// NewCachingTransport returns a configured http.Transport for use with GitHub, caching the
+// responses in cacheDir if set, see NewCacheTransport.
+func NewCachingTransport(ctx context.Context, logger *log.Logger, cacheDir string) http.RoundTripper {
 	transport := http.DefaultTransport
+	limits := newRateLimits()
 
-	//nolint
-	if tokenAccessor := tokens.MakeTokenAccessor(); tokenAccessor != nil {
-		// Use GitHub PAT
-		transport = makeGitHubTransport(transport, tokenAccessor)
-	} else if keyPath := os.Getenv(githubAppKeyPath); keyPath != "" { // Also try a GITHUB_APP
-		appID, err := strconv.Atoi(os.Getenv(githubAppID))
-		if err != nil {
-			logger.Error(err, "getting GitHub application ID from environment")
-		}
-		installationID, err := strconv.Atoi(os.Getenv(githubAppInstallationID))
-		if err != nil {
-			logger.Error(err, "getting GitHub application installation ID")
+	if cacheDir != "" {
+		if cache, err := NewCacheTransport(transport, cacheDir); err != nil {
+			logger.Error(err, "setting up the HTTP cache, requests are not cached")
+		} else {
+			transport = cache
 		}
-		transport, err = ghinstallation.NewKeyFromFile(transport, int64(appID), int64(installationID), keyPath)
-		if err != nil {
-			logger.Error(err, "getting a private key from file")
+	}
+
+	tokenAccessor, err := tokens.MakeTokenAccessor()
+	if err != nil {
+		logger.Error(err, "setting up the GitHub App credentials")
+	}
+	var enterpriseTokens tokens.TokenAccessor
+	enterpriseHost := EnterpriseHost()
+	if enterpriseHost != "" {
+		if enterpriseTokens = tokens.MakeEnterpriseTokenAccessor(); enterpriseTokens == nil {
+			logger.Error(fmt.Errorf("an error occurred while getting GitHub Enterprise Server credentials"),
+				fmt.Sprintf("GitHub Enterprise Server token env var is not set for %s. "+
+					"Please read https://github.com/ossf/scorecard#github-enterprise-server", enterpriseHost))
 		}
+	}
+
+	//nolint
+	if tokenAccessor != nil || enterpriseTokens != nil {
+		// Use GitHub PATs or the installation tokens of a GitHub App.
+		githubTransport := makeGitHubTransport(transport, tokenAccessor, limits)
+		githubTransport.enterpriseHost, githubTransport.enterpriseTokens = enterpriseHost, enterpriseTokens
+		transport = githubTransport
 	} else {
 		// TODO(log): Improve error message
 		logger.Error(fmt.Errorf("an error occurred while getting GitHub credentials"), "GitHub token env var is not set. Please read https://github.com/ossf/scorecard#authentication")
 	}
 
-	return MakeCensusTransport(MakeRateLimitedTransport(transport, logger))
+	return MakeCensusTransport(makeRateLimitedTransport(transport, logger, limits))
 }
@@ -16,23 +16,53 @@
 package tokens
 
 import (
+	"fmt"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
 )
 
-// githubAuthServer is the RPC URL for the token server.
-const githubAuthServer = "GITHUB_AUTH_SERVER"
+const (
+	// githubAuthServer is the RPC URL for the token server.
+	githubAuthServer = "GITHUB_AUTH_SERVER"
+	// githubAppKeyPath is the path to file for GitHub App key.
+	githubAppKeyPath = "GITHUB_APP_KEY_PATH"
+	// githubAppID is the app ID for the GitHub App.
+	githubAppID = "GITHUB_APP_ID"
+	// githubAppInstallationID is the installation ID for the GitHub App.
+	githubAppInstallationID = "GITHUB_APP_INSTALLATION_ID"
+
+	// ResourceCore is the rate limit resource of the REST API requests, but the search ones.
+	ResourceCore = "core"
+)
 
 // TokenAccessor interface defines a `retrieve-once` data structure.
 // Implementations of this interface must be thread-safe.
 type TokenAccessor interface {
-	Next() (uint64, string)
+	// Next returns the next token, or the error getting it, e.g. minting it.
+	Next() (uint64, string, error)
 	Release(uint64)
 }
 
-func readGitHubTokens() (string, bool) {
-	githubAuthTokens := []string{"GITHUB_AUTH_TOKEN", "GITHUB_TOKEN", "GH_TOKEN", "GH_AUTH_TOKEN"}
-	for _, name := range githubAuthTokens {
+// QuotaAccessor is a TokenAccessor handing out its tokens by their remaining rate limit budget,
+// learned from the responses to their requests. Implementations of this interface must be thread-safe.
+type QuotaAccessor interface {
+	TokenAccessor
+	// NextFor returns the token with the most budget left for resource, e.g. core, search or graphql.
+	NextFor(resource string) (uint64, string, error)
+	// Observe records the response to a request of resource made with token id.
+	Observe(id uint64, resource string, status int, header http.Header)
+}
+
+var (
+	githubAuthTokens = []string{"GITHUB_AUTH_TOKEN", "GITHUB_TOKEN", "GH_TOKEN", "GH_AUTH_TOKEN"}
+	// githubEnterpriseTokens are the PATs of the GitHub Enterprise Server, as for the GitHub CLI.
+	githubEnterpriseTokens = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+)
+
+func readGitHubTokens(names []string) (string, bool) {
+	for _, name := range names {
 		if token, exists := os.LookupEnv(name); exists && token != "" {
 			return token, exists
 		}
@@ -40,13 +70,43 @@ func readGitHubTokens() (string, bool) {
 	return "", false
 }
 
-// MakeTokenAccessor is a factory function of TokenAccessor.
-func MakeTokenAccessor() TokenAccessor {
-	if value, exists := readGitHubTokens(); exists {
-		return makeRoundRobinAccessor(strings.Split(value, ","))
+// MakeTokenAccessor is a factory function of TokenAccessor: it returns the accessor of the
+// GitHub PATs, of the token server or of the GitHub App configured in the environment, if any.
+// It returns an error if the GitHub App is misconfigured.
+func MakeTokenAccessor() (TokenAccessor, error) {
+	if value, exists := readGitHubTokens(githubAuthTokens); exists {
+		return makeQuotaAccessor(strings.Split(value, ",")), nil
 	}
 	if value, exists := os.LookupEnv(githubAuthServer); exists {
-		return makeRPCAccessor(value)
+		return makeRPCAccessor(value), nil
+	}
+	if keyPath := os.Getenv(githubAppKeyPath); keyPath != "" {
+		return readGitHubApp(keyPath)
+	}
+	return nil, nil
+}
+
+// MakeEnterpriseTokenAccessor returns the accessor of the GitHub Enterprise Server PATs
+// configured in the environment, if any. They're kept apart from the github.com ones.
+func MakeEnterpriseTokenAccessor() TokenAccessor {
+	if value, exists := readGitHubTokens(githubEnterpriseTokens); exists {
+		return makeQuotaAccessor(strings.Split(value, ","))
 	}
 	return nil
 }
+
+func readGitHubApp(keyPath string) (QuotaAccessor, error) {
+	appID, err := strconv.ParseInt(os.Getenv(githubAppID), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("getting GitHub application ID from environment: %w", err)
+	}
+	installationID, err := strconv.ParseInt(os.Getenv(githubAppInstallationID), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("getting GitHub application installation ID: %w", err)
+	}
+	privateKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading the GitHub App private key: %w", err)
+	}
+	return makeAppAccessor(http.DefaultTransport, "", appID, installationID, privateKey)
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokens
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//nolint:paralleltest // Sets environment variables.
+func TestMakeTokenAccessorGitHubApp(t *testing.T) {
+	for _, name := range append(githubAuthTokens, githubAuthServer) {
+		t.Setenv(name, "")
+		if err := os.Unsetenv(name); err != nil {
+			t.Fatalf("os.Unsetenv: %v", err)
+		}
+	}
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(keyPath, []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		appID          string
+		installationID string
+		keyPath        string
+	}{
+		{
+			name:           "invalid app ID",
+			appID:          "app",
+			installationID: "2",
+			keyPath:        keyPath,
+		},
+		{
+			name:           "invalid installation ID",
+			appID:          "1",
+			installationID: "installation",
+			keyPath:        keyPath,
+		},
+		{
+			name:           "missing key file",
+			appID:          "1",
+			installationID: "2",
+			keyPath:        filepath.Join(t.TempDir(), "missing.pem"),
+		},
+		{
+			name:           "invalid key",
+			appID:          "1",
+			installationID: "2",
+			keyPath:        keyPath,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(githubAppID, tt.appID)
+			t.Setenv(githubAppInstallationID, tt.installationID)
+			t.Setenv(githubAppKeyPath, tt.keyPath)
+			// The errors are returned rather than crashing the caller.
+			accessor, err := MakeTokenAccessor()
+			if err == nil {
+				t.Errorf("MakeTokenAccessor() = %v, want an error", accessor)
+			}
+		})
+	}
+}
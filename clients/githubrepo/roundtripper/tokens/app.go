@@ -0,0 +1,91 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+)
+
+// appAccessor implements QuotaAccessor with the installation tokens of a GitHub App,
+// minted from the private key of the app when the current one is about to expire.
+type appAccessor struct {
+	mu           sync.Mutex
+	apps         *ghinstallation.AppsTransport
+	installation *ghinstallation.Transport
+	id           int64
+	baseURL      string
+}
+
+// Next implements TokenAccessor.Next.
+func (accessor *appAccessor) Next() (uint64, string, error) {
+	accessor.mu.Lock()
+	installation := accessor.installation
+	accessor.mu.Unlock()
+	token, err := installation.Token(context.Background())
+	if err != nil {
+		return 0, "", fmt.Errorf("error minting a GitHub App installation token: %w", err)
+	}
+	return 0, token, nil
+}
+
+// NextFor implements QuotaAccessor.NextFor: the installation has a single token at a time.
+func (accessor *appAccessor) NextFor(resource string) (uint64, string, error) {
+	return accessor.Next()
+}
+
+// Release implements TokenAccessor.Release.
+func (accessor *appAccessor) Release(id uint64) {}
+
+// Observe implements QuotaAccessor.Observe: the token of an unauthorized request is discarded,
+// the next one is minted again.
+func (accessor *appAccessor) Observe(id uint64, resource string, status int, header http.Header) {
+	if status != http.StatusUnauthorized {
+		return
+	}
+	accessor.mu.Lock()
+	defer accessor.mu.Unlock()
+	accessor.installation = accessor.newInstallation()
+}
+
+func (accessor *appAccessor) newInstallation() *ghinstallation.Transport {
+	installation := ghinstallation.NewFromAppsTransport(accessor.apps, accessor.id)
+	if accessor.baseURL != "" {
+		installation.BaseURL = accessor.baseURL
+	}
+	return installation
+}
+
+// makeAppAccessor returns the accessor of the installation tokens of a GitHub App, requested
+// through transport to the API at baseURL, or to api.github.com if empty.
+func makeAppAccessor(transport http.RoundTripper, baseURL string, appID, installationID int64,
+	privateKey []byte,
+) (QuotaAccessor, error) {
+	apps, err := ghinstallation.NewAppsTransport(transport, appID, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("ghinstallation.NewAppsTransport: %w", err)
+	}
+	accessor := &appAccessor{
+		apps:    apps,
+		id:      installationID,
+		baseURL: baseURL,
+	}
+	accessor.installation = accessor.newInstallation()
+	return accessor, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGitHubApp stands in for the GitHub API minting the installation tokens of an app.
+type fakeGitHubApp struct {
+	mu       sync.Mutex
+	minted   int
+	failures int
+}
+
+func (app *fakeGitHubApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+		http.NotFound(w, r)
+		return
+	}
+	// The app authenticates with a JWT signed by its private key.
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ey") {
+		http.Error(w, "missing JWT", http.StatusUnauthorized)
+		return
+	}
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	if app.failures > 0 {
+		app.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	app.minted++
+	w.WriteHeader(http.StatusCreated)
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(map[string]string{
+		"token":      fmt.Sprintf("ghs_%d", app.minted),
+		"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+}
+
+func TestAppAccessor(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	app := &fakeGitHubApp{failures: 1}
+	server := httptest.NewTLSServer(app)
+	defer server.Close()
+
+	accessor, err := makeAppAccessor(server.Client().Transport, server.URL, 1, 42, privateKey)
+	if err != nil {
+		t.Fatalf("makeAppAccessor: %v", err)
+	}
+	if _, token, err := accessor.Next(); err == nil {
+		t.Errorf("token = %q, want an error while the API fails", token)
+	}
+	// The token is minted once and reused until it's about to expire.
+	for i := 0; i < 2; i++ {
+		if _, token, err := accessor.NextFor("graphql"); err != nil || token != "ghs_1" {
+			t.Errorf("token = %q, %v, want %q", token, err, "ghs_1")
+		}
+	}
+	// A token whose request is unauthorized is minted again.
+	accessor.Observe(0, ResourceCore, http.StatusOK, http.Header{})
+	if _, token, err := accessor.Next(); err != nil || token != "ghs_1" {
+		t.Errorf("token = %q, %v, want %q", token, err, "ghs_1")
+	}
+	accessor.Observe(0, ResourceCore, http.StatusUnauthorized, http.Header{})
+	if _, token, err := accessor.Next(); err != nil || token != "ghs_2" {
+		t.Errorf("token = %q, %v, want %q", token, err, "ghs_2")
+	}
+
+	if _, err := makeAppAccessor(server.Client().Transport, server.URL, 1, 42, []byte("not a key")); err == nil {
+		t.Error("makeAppAccessor: want an error for an invalid private key")
+	}
+}
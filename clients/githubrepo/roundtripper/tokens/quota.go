@@ -0,0 +1,121 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokens
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// quota is the rate limit budget of a token for a resource, as of its last response.
+type quota struct {
+	remaining int
+	reset     time.Time
+}
+
+// quotaAccessor implements QuotaAccessor. Unlike roundRobinAccessor, its tokens are shared by
+// concurrent requests: Release is a no-op.
+type quotaAccessor struct {
+	mu           sync.Mutex
+	accessTokens []string
+	quotas       []map[string]*quota
+	quarantined  []bool
+	counter      int
+}
+
+// Next implements TokenAccessor.Next.
+func (tokens *quotaAccessor) Next() (uint64, string, error) {
+	return tokens.NextFor(ResourceCore)
+}
+
+// NextFor implements QuotaAccessor.NextFor. The tokens without known budget, e.g. not used yet
+// or whose budget was reset since, are preferred. Ties are broken in round robin.
+func (tokens *quotaAccessor) NextFor(resource string) (uint64, string, error) {
+	tokens.mu.Lock()
+	defer tokens.mu.Unlock()
+	now := time.Now()
+	l := len(tokens.accessTokens)
+	best, bestRemaining := -1, 0
+	for i := 0; i < l; i++ {
+		index := (tokens.counter + i) % l
+		if tokens.quarantined[index] {
+			continue
+		}
+		if remaining := tokens.remaining(index, resource, now); best == -1 || remaining > bestRemaining {
+			best, bestRemaining = index, remaining
+		}
+	}
+	tokens.counter++
+	if best == -1 {
+		// All the tokens are quarantined: their requests will fail with their authentication error.
+		best = tokens.counter % l
+	}
+	if q, ok := tokens.quotas[best][resource]; ok && bestRemaining != math.MaxInt {
+		// Count the request until its response updates the budget.
+		q.remaining--
+	}
+	return uint64(best), tokens.accessTokens[best], nil
+}
+
+func (tokens *quotaAccessor) remaining(index int, resource string, now time.Time) int {
+	q, ok := tokens.quotas[index][resource]
+	if !ok || !now.Before(q.reset) {
+		return math.MaxInt
+	}
+	return q.remaining
+}
+
+// Release implements TokenAccessor.Release.
+func (tokens *quotaAccessor) Release(id uint64) {}
+
+// Observe implements QuotaAccessor.Observe: a token whose request is unauthorized is quarantined.
+func (tokens *quotaAccessor) Observe(id uint64, resource string, status int, header http.Header) {
+	tokens.mu.Lock()
+	defer tokens.mu.Unlock()
+	if id >= uint64(len(tokens.accessTokens)) {
+		return
+	}
+	if status == http.StatusUnauthorized {
+		tokens.quarantined[id] = true
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	if r := header.Get("X-RateLimit-Resource"); r != "" {
+		resource = r
+	}
+	tokens.quotas[id][resource] = &quota{remaining: remaining, reset: time.Unix(reset, 0)}
+}
+
+func makeQuotaAccessor(accessTokens []string) QuotaAccessor {
+	quotas := make([]map[string]*quota, len(accessTokens))
+	for i := range quotas {
+		quotas[i] = map[string]*quota{}
+	}
+	return &quotaAccessor{
+		accessTokens: accessTokens,
+		quotas:       quotas,
+		quarantined:  make([]bool, len(accessTokens)),
+	}
+}
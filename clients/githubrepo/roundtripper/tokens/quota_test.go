@@ -0,0 +1,115 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokens
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func rateLimitHeader(remaining int, reset time.Time, resource string) http.Header {
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", fmt.Sprint(remaining))
+	header.Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+	header.Set("X-RateLimit-Resource", resource)
+	return header
+}
+
+func TestQuotaAccessor(t *testing.T) {
+	t.Parallel()
+	reset := time.Now().Add(time.Hour)
+	//nolint:govet
+	tests := []struct {
+		name     string
+		observe  func(accessor QuotaAccessor)
+		resource string
+		want     []string
+	}{
+		{
+			name:     "unknown budgets in round robin",
+			resource: ResourceCore,
+			want:     []string{"a", "b", "c", "a"},
+		},
+		{
+			name: "most remaining budget",
+			observe: func(accessor QuotaAccessor) {
+				accessor.Observe(0, ResourceCore, http.StatusOK, rateLimitHeader(10, reset, ResourceCore))
+				accessor.Observe(1, ResourceCore, http.StatusOK, rateLimitHeader(12, reset, ResourceCore))
+				accessor.Observe(2, ResourceCore, http.StatusOK, rateLimitHeader(5, reset, ResourceCore))
+			},
+			resource: ResourceCore,
+			// The requests handed out count against the budgets.
+			want: []string{"b", "b", "a", "b"},
+		},
+		{
+			name: "budget of another resource",
+			observe: func(accessor QuotaAccessor) {
+				accessor.Observe(0, ResourceCore, http.StatusOK, rateLimitHeader(10, reset, "graphql"))
+				accessor.Observe(1, ResourceCore, http.StatusOK, rateLimitHeader(0, reset, ResourceCore))
+				accessor.Observe(2, ResourceCore, http.StatusOK, rateLimitHeader(0, reset, ResourceCore))
+			},
+			resource: ResourceCore,
+			want:     []string{"a", "a"},
+		},
+		{
+			name: "reset budget",
+			observe: func(accessor QuotaAccessor) {
+				accessor.Observe(0, ResourceCore, http.StatusOK, rateLimitHeader(0, time.Now(), ResourceCore))
+				accessor.Observe(1, ResourceCore, http.StatusOK, rateLimitHeader(100, reset, ResourceCore))
+				accessor.Observe(2, ResourceCore, http.StatusOK, rateLimitHeader(100, reset, ResourceCore))
+			},
+			resource: ResourceCore,
+			want:     []string{"a", "a"},
+		},
+		{
+			name: "unauthorized tokens are quarantined",
+			observe: func(accessor QuotaAccessor) {
+				accessor.Observe(0, ResourceCore, http.StatusUnauthorized, http.Header{})
+				accessor.Observe(2, ResourceCore, http.StatusUnauthorized, http.Header{})
+			},
+			resource: "search",
+			want:     []string{"b", "b", "b"},
+		},
+		{
+			name: "all tokens quarantined",
+			observe: func(accessor QuotaAccessor) {
+				for id := uint64(0); id < 3; id++ {
+					accessor.Observe(id, ResourceCore, http.StatusUnauthorized, http.Header{})
+				}
+			},
+			resource: ResourceCore,
+			want:     []string{"b", "c", "a"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			accessor := makeQuotaAccessor([]string{"a", "b", "c"})
+			if tt.observe != nil {
+				tt.observe(accessor)
+			}
+			for i, want := range tt.want {
+				id, token, err := accessor.NextFor(tt.resource)
+				if err != nil || token != want {
+					t.Errorf("token %d = %q, %v, want %q", i, token, err, want)
+				}
+				accessor.Release(id)
+			}
+		})
+	}
+}
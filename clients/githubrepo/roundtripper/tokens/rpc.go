@@ -14,6 +14,8 @@
 
 package tokens
 
+import "fmt"
+
 // Token is used for GitHub token server RPC request/response.
 type Token struct {
 	Value string
@@ -28,7 +30,10 @@ type TokenOverRPC struct {
 // Next requests for the next available GitHub token.
 // Server blocks the call until a token becomes available.
 func (accessor *TokenOverRPC) Next(args struct{}, token *Token) error {
-	id, val := accessor.client.Next()
+	id, val, err := accessor.client.Next()
+	if err != nil {
+		return fmt.Errorf("error getting the next token: %w", err)
+	}
 	*token = Token{
 		ID:    id,
 		Value: val,
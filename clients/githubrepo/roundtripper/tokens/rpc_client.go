@@ -15,6 +15,7 @@
 package tokens
 
 import (
+	"fmt"
 	"log"
 	"net/rpc"
 )
@@ -25,13 +26,12 @@ type rpcAccessor struct {
 }
 
 // Next implements TokenAccessor.Next.
-func (accessor *rpcAccessor) Next() (uint64, string) {
+func (accessor *rpcAccessor) Next() (uint64, string, error) {
 	var token Token
 	if err := accessor.client.Call("TokenOverRPC.Next", struct{}{}, &token); err != nil {
-		log.Printf("error during RPC call Next: %v", err)
-		return 0, ""
+		return 0, "", fmt.Errorf("error during RPC call Next: %w", err)
 	}
-	return token.ID, token.Value
+	return token.ID, token.Value, nil
 }
 
 // Release implements TokenAccessor.Release.
@@ -25,7 +25,10 @@ import (
 
 func main() {
 	// Sanity check
-	tokenAccessor := tokens.MakeTokenAccessor()
+	tokenAccessor, err := tokens.MakeTokenAccessor()
+	if err != nil {
+		panic(err)
+	}
 	if tokenAccessor == nil {
 		panic("")
 	}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
@@ -27,22 +28,43 @@ import (
 )
 
 // makeGitHubTransport wraps input RoundTripper with GitHub authorization logic.
-func makeGitHubTransport(innerTransport http.RoundTripper, accessor tokens.TokenAccessor) http.RoundTripper {
+func makeGitHubTransport(innerTransport http.RoundTripper, accessor tokens.TokenAccessor,
+	limits *rateLimits,
+) *githubTransport {
 	return &githubTransport{
 		innerTransport: innerTransport,
 		tokens:         accessor,
+		limits:         limits,
 	}
 }
 
 // githubTransport handles authorization using GitHub personal access tokens (PATs) during HTTP requests.
+// It prefers the tokens with budget left for the resource of the request, see rateLimitTransport.
+// The requests to the GitHub Enterprise Server, if any, are authorized with its own tokens only.
 type githubTransport struct {
-	innerTransport http.RoundTripper
-	tokens         tokens.TokenAccessor
+	innerTransport   http.RoundTripper
+	tokens           tokens.TokenAccessor
+	enterpriseHost   string
+	enterpriseTokens tokens.TokenAccessor
+	limits           *rateLimits
 }
 
 func (gt *githubTransport) RoundTrip(r *http.Request) (*http.Response, error) {
-	id, token := gt.tokens.Next()
-	defer gt.tokens.Release(id)
+	accessor := gt.accessorFor(r.URL.Host)
+	if accessor == nil {
+		// No token for this host: never send it the tokens of another one.
+		resp, err := gt.innerTransport.RoundTrip(r)
+		if err != nil {
+			return nil, fmt.Errorf("error in HTTP: %w", err)
+		}
+		return resp, nil
+	}
+	resource := resourceOf(r)
+	id, token, err := gt.next(accessor, r.URL.Host, resource)
+	if err != nil {
+		return nil, fmt.Errorf("error getting a token: %w", err)
+	}
+	defer accessor.Release(id)
 
 	ctx, err := tag.New(r.Context(), tag.Upsert(githubstats.TokenIndex, fmt.Sprint(id)))
 	if err != nil {
@@ -50,11 +72,14 @@ func (gt *githubTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	}
 	*r = *r.WithContext(ctx)
 
-	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	resp, err := gt.innerTransport.RoundTrip(r)
 	if err != nil {
 		return nil, fmt.Errorf("error in HTTP: %w", err)
 	}
+	if quota, ok := accessor.(tokens.QuotaAccessor); ok {
+		quota.Observe(id, resource, resp.StatusCode, resp.Header)
+	}
 
 	ctx, err = tag.New(r.Context(), tag.Upsert(githubstats.ResourceType, resp.Header.Get("X-RateLimit-Resource")))
 	if err != nil {
@@ -66,3 +91,34 @@ func (gt *githubTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	}
 	return resp, nil
 }
+
+// accessorFor returns the tokens of the GitHub Enterprise Server for its requests,
+// those of github.com for the others.
+func (gt *githubTransport) accessorFor(host string) tokens.TokenAccessor {
+	if gt.enterpriseHost != "" && isHostOf(host, gt.enterpriseHost) {
+		return gt.enterpriseTokens
+	}
+	return gt.tokens
+}
+
+// next returns the next token of accessor with budget left for resource on host,
+// or the first token seen again if none has.
+func (gt *githubTransport) next(accessor tokens.TokenAccessor, host, resource string) (uint64, string, error) {
+	if quota, ok := accessor.(tokens.QuotaAccessor); ok {
+		//nolint:wrapcheck // wrapped by RoundTrip.
+		return quota.NextFor(resource)
+	}
+	exhausted := map[uint64]bool{}
+	for {
+		id, token, err := accessor.Next()
+		if err != nil {
+			//nolint:wrapcheck // wrapped by RoundTrip.
+			return 0, "", err
+		}
+		if exhausted[id] || gt.limits.available(host, fmt.Sprint(id), resource, time.Now()) {
+			return id, token, nil
+		}
+		exhausted[id] = true
+		accessor.Release(id)
+	}
+}
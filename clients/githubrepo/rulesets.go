@@ -0,0 +1,295 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v38/github"
+
+	"github.com/ossf/scorecard/v4/clients"
+)
+
+const (
+	tagRefPrefix = "refs/tags/"
+	// rulesetsPageSize is the maximum page size of the rules and rulesets lists.
+	rulesetsPageSize = 100
+
+	rulesetTargetTag         = "tag"
+	rulesetEnforcementActive = "active"
+
+	ruleTypeUpdate                = "update"
+	ruleTypeDeletion              = "deletion"
+	ruleTypeNonFastForward        = "non_fast_forward"
+	ruleTypeRequiredLinearHistory = "required_linear_history"
+	ruleTypeRequiredSignatures    = "required_signatures"
+	ruleTypePullRequest           = "pull_request"
+	ruleTypeRequiredStatusChecks  = "required_status_checks"
+)
+
+// rulesetRule is a rule of a repository ruleset.
+// See https://docs.github.com/en/rest/repos/rules.
+type rulesetRule struct {
+	Type       string `json:"type"`
+	Parameters struct {
+		// pull_request.
+		RequiredApprovingReviewCount *int32 `json:"required_approving_review_count"`
+		DismissStaleReviewsOnPush    *bool  `json:"dismiss_stale_reviews_on_push"`
+		RequireCodeOwnerReview       *bool  `json:"require_code_owner_review"`
+		// required_status_checks.
+		StrictRequiredStatusChecksPolicy *bool `json:"strict_required_status_checks_policy"`
+		RequiredStatusChecks             []struct {
+			Context string `json:"context"`
+		} `json:"required_status_checks"`
+	} `json:"parameters"`
+}
+
+type rulesetSummary struct {
+	ID          int64  `json:"id"`
+	Target      string `json:"target"`
+	Enforcement string `json:"enforcement"`
+}
+
+type ruleset struct {
+	rulesetSummary
+	Conditions struct {
+		RefName struct {
+			Include []string `json:"include"`
+		} `json:"ref_name"`
+	} `json:"conditions"`
+	Rules []rulesetRule `json:"rules"`
+}
+
+// getRulesetRules returns the rules of the active rulesets
+// applying to the branch, across the repo and its organization.
+func (handler *branchesHandler) getRulesetRules(ctx context.Context, branchName string) ([]rulesetRule, error) {
+	reqURL := path.Join("repos", handler.repourl.owner, handler.repourl.repo, "rules", "branches",
+		url.PathEscape(branchName))
+	var rules []rulesetRule
+	for page := 1; page != 0; {
+		var pageRules []rulesetRule
+		next, err := handler.getJSONPage(ctx, reqURL, page, &pageRules)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, pageRules...)
+		page = next
+	}
+	return rules, nil
+}
+
+// applyRulesets merges the rules of the rulesets applying to the branch
+// into its protection settings. Rulesets add up with classic branch
+// protection rules, so the most restrictive setting wins.
+func (handler *branchesHandler) applyRulesets(ctx context.Context, branchRef *clients.BranchRef) error {
+	if branchRef == nil || branchRef.Name == nil {
+		return nil
+	}
+	rules, err := handler.getRulesetRules(ctx, *branchRef.Name)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	if branchRef.Protected == nil {
+		branchRef.Protected = new(bool)
+	}
+	*branchRef.Protected = true
+	for i := range rules {
+		applyRulesetRule(&rules[i], &branchRef.BranchProtectionRule)
+	}
+	return nil
+}
+
+func applyRulesetRule(rule *rulesetRule, dst *clients.BranchProtectionRule) {
+	switch rule.Type {
+	case ruleTypeDeletion:
+		setBoolPtr(&dst.AllowDeletions, false)
+	case ruleTypeNonFastForward:
+		setBoolPtr(&dst.AllowForcePushes, false)
+	case ruleTypeRequiredLinearHistory:
+		setBoolPtr(&dst.RequireLinearHistory, true)
+	case ruleTypeRequiredSignatures:
+		setBoolPtr(&dst.RequireSignedCommits, true)
+	case ruleTypePullRequest:
+		reviews := &dst.RequiredPullRequestReviews
+		if n := rule.Parameters.RequiredApprovingReviewCount; n != nil &&
+			(reviews.RequiredApprovingReviewCount == nil || *n > *reviews.RequiredApprovingReviewCount) {
+			copyInt32Ptr(n, &reviews.RequiredApprovingReviewCount)
+		}
+		if p := rule.Parameters.DismissStaleReviewsOnPush; p != nil && *p {
+			setBoolPtr(&reviews.DismissStaleReviews, true)
+		}
+		if p := rule.Parameters.RequireCodeOwnerReview; p != nil && *p {
+			setBoolPtr(&reviews.RequireCodeOwnerReviews, true)
+		}
+	case ruleTypeRequiredStatusChecks:
+		checks := &dst.CheckRules
+		setBoolPtr(&checks.RequiresStatusChecks, true)
+		if p := rule.Parameters.StrictRequiredStatusChecksPolicy; p != nil && *p {
+			setBoolPtr(&checks.UpToDateBeforeMerge, true)
+		}
+		for _, c := range rule.Parameters.RequiredStatusChecks {
+			if !containsString(checks.Contexts, c.Context) {
+				checks.Contexts = append(checks.Contexts, c.Context)
+			}
+		}
+	}
+}
+
+// listTagProtectionRules returns the tags protected by the classic tag
+// protection API and by the active tag rulesets. The classic API is only
+// available to admins, so, like the rulesets, its rules are ignored if
+// access is denied.
+func (handler *branchesHandler) listTagProtectionRules(ctx context.Context) ([]clients.TagProtectionRule, error) {
+	if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
+		return nil, fmt.Errorf("%w: tags only supported for HEAD queries", clients.ErrUnsupportedFeature)
+	}
+	var ret []clients.TagProtectionRule
+
+	var tagProtections []struct {
+		Pattern string `json:"pattern"`
+	}
+	reqURL := path.Join("repos", handler.repourl.owner, handler.repourl.repo, "tags", "protection")
+	if err := handler.getJSON(ctx, reqURL, &tagProtections); err != nil {
+		return nil, err
+	}
+	for _, p := range tagProtections {
+		// Only users with admin or maintain permissions
+		// can create or delete protected tags.
+		ret = append(ret, clients.TagProtectionRule{
+			Pattern:        p.Pattern,
+			AllowDeletions: new(bool),
+			AllowUpdates:   new(bool),
+		})
+	}
+
+	var summaries []rulesetSummary
+	reqURL = path.Join("repos", handler.repourl.owner, handler.repourl.repo, "rulesets") + "?includes_parents=true"
+	for page := 1; page != 0; {
+		var pageSummaries []rulesetSummary
+		next, err := handler.getJSONPage(ctx, reqURL, page, &pageSummaries)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, pageSummaries...)
+		page = next
+	}
+	for _, summary := range summaries {
+		if summary.Target != rulesetTargetTag || summary.Enforcement != rulesetEnforcementActive {
+			continue
+		}
+		var rs ruleset
+		reqURL = path.Join("repos", handler.repourl.owner, handler.repourl.repo,
+			"rulesets", strconv.FormatInt(summary.ID, 10))
+		if err := handler.getJSON(ctx, reqURL, &rs); err != nil {
+			return nil, err
+		}
+		allowDeletions, allowUpdates := true, true
+		for _, rule := range rs.Rules {
+			switch rule.Type {
+			case ruleTypeDeletion:
+				allowDeletions = false
+			case ruleTypeUpdate, ruleTypeNonFastForward:
+				allowUpdates = false
+			}
+		}
+		for _, include := range rs.Conditions.RefName.Include {
+			ret = append(ret, clients.TagProtectionRule{
+				Pattern:        strings.TrimPrefix(include, tagRefPrefix),
+				AllowDeletions: &allowDeletions,
+				AllowUpdates:   &allowUpdates,
+			})
+		}
+	}
+	return ret, nil
+}
+
+// getJSON decodes the response of a REST API GET request into v.
+// A missing endpoint, e.g. on GitHub Enterprise Server versions
+// without rulesets, or one the token can't access is treated as
+// an empty response: these endpoints only add to what the branch
+// protection rules tell.
+func (handler *branchesHandler) getJSON(ctx context.Context, reqURL string, v interface{}) error {
+	_, err := handler.getJSONPage(ctx, reqURL, 0, v)
+	return err
+}
+
+// getJSONPage is getJSON for a page of a list endpoint, starting at 1.
+// It returns the number of the next page, 0 after the last one.
+func (handler *branchesHandler) getJSONPage(ctx context.Context, reqURL string, page int, v interface{}) (int, error) {
+	if page > 0 {
+		sep := "?"
+		if strings.Contains(reqURL, "?") {
+			sep = "&"
+		}
+		reqURL = fmt.Sprintf("%s%sper_page=%d&page=%d", reqURL, sep, rulesetsPageSize, page)
+	}
+	req, err := handler.ghClient.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("request for %s failed with %w", reqURL, err)
+	}
+	resp, err := handler.ghClient.Do(ctx, req, v)
+	if err != nil {
+		if isMissingOrForbidden(resp, err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("response for %s failed with %w", reqURL, err)
+	}
+	return resp.NextPage, nil
+}
+
+// isMissingOrForbidden returns whether a failed request was answered with a 404, or with a 403 for
+// lack of permission. The 403s of rate limits still failing after the retries of the transport aren't:
+// treating them as empty responses would drop protections.
+func isMissingOrForbidden(resp *github.Response, err error) bool {
+	if resp == nil {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return true
+	case http.StatusForbidden:
+		var rateLimitErr *github.RateLimitError
+		var abuseRateLimitErr *github.AbuseRateLimitError
+		if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) {
+			return false
+		}
+		return resp.Header.Get("Retry-After") == "" && resp.Header.Get("X-RateLimit-Remaining") != "0"
+	default:
+		return false
+	}
+}
+
+func setBoolPtr(dst **bool, v bool) {
+	*dst = &v
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
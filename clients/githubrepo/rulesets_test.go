@@ -0,0 +1,360 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubrepo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v38/github"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/ossf/scorecard/v4/clients"
+)
+
+// newTestBranchesHandler returns a branchesHandler whose REST and GraphQL
+// clients talk to a local stand-in of the GitHub API serving routes, by
+// escaped path. The pages after the first are routed as `<path>?page=<n>`.
+func newTestBranchesHandler(t *testing.T, routes map[string]string) *branchesHandler {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.URL.EscapedPath()
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			page = 1
+		}
+		if page > 1 {
+			route = fmt.Sprintf("%s?page=%d", route, page)
+		}
+		nextRoute := fmt.Sprintf("%s?page=%d", r.URL.EscapedPath(), page+1)
+		if _, ok := routes[nextRoute]; ok {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, srv.URL, r.URL.EscapedPath(), page+1))
+		}
+		body, ok := routes[route]
+		switch {
+		case !ok:
+			http.NotFound(w, r)
+		case body == "forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			//nolint:errcheck
+			w.Write([]byte(`{"message":"Must have admin rights to Repository."}`))
+		case body == "rate limited":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			//nolint:errcheck
+			w.Write([]byte(`{"message":"API rate limit exceeded for user."}`))
+		case body == "secondary rate limited":
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusForbidden)
+			//nolint:errcheck
+			w.Write([]byte(`{"message":"You have exceeded a secondary rate limit.",` +
+				`"documentation_url":"https://docs.github.com/rest/overview/resources-in-the-rest-api#abuse-rate-limits"}`))
+		case body == "error":
+			w.WriteHeader(http.StatusInternalServerError)
+			//nolint:errcheck
+			w.Write([]byte(`{"message":"Server Error"}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			//nolint:errcheck
+			w.Write([]byte(body))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	ghClient := github.NewClient(srv.Client())
+	baseURL, err := url.Parse(srv.URL + "/")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+	ghClient.BaseURL = baseURL
+	handler := &branchesHandler{
+		ghClient:    ghClient,
+		graphClient: githubv4.NewEnterpriseClient(srv.URL+"/graphql", srv.Client()),
+	}
+	handler.init(&repoURL{
+		owner:     "owner",
+		repo:      "repo",
+		commitSHA: clients.HeadSHA,
+	})
+	return handler
+}
+
+func TestGetBranchWithRulesets(t *testing.T) {
+	t.Parallel()
+	trueVal := true
+	falseVal := false
+	var one int32 = 1
+	var two int32 = 2
+	name := "main"
+
+	//nolint:govet
+	tests := []struct {
+		name   string
+		routes map[string]string
+		want   *clients.BranchRef
+	}{
+		{
+			name: "no rulesets",
+			routes: map[string]string{
+				"/graphql": `{"data":{"repository":{"ref":{"name":"main"}}}}`,
+			},
+			want: &clients.BranchRef{
+				Name:      &name,
+				Protected: &falseVal,
+			},
+		},
+		{
+			name: "rulesets only",
+			routes: map[string]string{
+				"/graphql": `{"data":{"repository":{"ref":{"name":"main"}}}}`,
+				"/repos/owner/repo/rules/branches/main": `[
+					{"type": "deletion"},
+					{"type": "non_fast_forward"},
+					{"type": "required_linear_history"},
+					{"type": "required_signatures"},
+					{"type": "pull_request", "parameters": {
+						"required_approving_review_count": 2,
+						"dismiss_stale_reviews_on_push": true,
+						"require_code_owner_review": false
+					}},
+					{"type": "required_status_checks", "parameters": {
+						"strict_required_status_checks_policy": true,
+						"required_status_checks": [{"context": "build"}, {"context": "test"}]
+					}}
+				]`,
+			},
+			want: &clients.BranchRef{
+				Name:      &name,
+				Protected: &trueVal,
+				BranchProtectionRule: clients.BranchProtectionRule{
+					AllowDeletions:       &falseVal,
+					AllowForcePushes:     &falseVal,
+					RequireLinearHistory: &trueVal,
+					RequireSignedCommits: &trueVal,
+					RequiredPullRequestReviews: clients.PullRequestReviewRule{
+						RequiredApprovingReviewCount: &two,
+						DismissStaleReviews:          &trueVal,
+					},
+					CheckRules: clients.StatusChecksRule{
+						RequiresStatusChecks: &trueVal,
+						UpToDateBeforeMerge:  &trueVal,
+						Contexts:             []string{"build", "test"},
+					},
+				},
+			},
+		},
+		{
+			name: "rulesets merged with branch protection rule",
+			routes: map[string]string{
+				"/graphql": `{"data":{"repository":{"ref":{"name":"main","refUpdateRule":{
+					"allowsDeletions": true,
+					"allowsForcePushes": true,
+					"requiredApprovingReviewCount": 1,
+					"requiresCodeOwnerReviews": true,
+					"requiredStatusCheckContexts": ["build"]
+				}}}}}`,
+				"/repos/owner/repo/rules/branches/main": `[
+					{"type": "deletion"},
+					{"type": "pull_request", "parameters": {"required_approving_review_count": 0}},
+					{"type": "required_status_checks", "parameters": {
+						"required_status_checks": [{"context": "build"}, {"context": "lint"}]
+					}}
+				]`,
+			},
+			want: &clients.BranchRef{
+				Name:      &name,
+				Protected: &trueVal,
+				BranchProtectionRule: clients.BranchProtectionRule{
+					AllowDeletions:   &falseVal,
+					AllowForcePushes: &trueVal,
+					RequiredPullRequestReviews: clients.PullRequestReviewRule{
+						RequiredApprovingReviewCount: &one,
+						RequireCodeOwnerReviews:      &trueVal,
+					},
+					CheckRules: clients.StatusChecksRule{
+						RequiresStatusChecks: &trueVal,
+						Contexts:             []string{"build", "lint"},
+					},
+				},
+			},
+		},
+		{
+			name: "missing branch",
+			routes: map[string]string{
+				"/graphql": `{"data":{"repository":{"ref":null}}}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			handler := newTestBranchesHandler(t, tt.routes)
+			got, err := handler.getBranch(context.Background(), "main")
+			if err != nil {
+				t.Fatalf("getBranch: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("getBranch() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetRulesetRules(t *testing.T) {
+	t.Parallel()
+	handler := newTestBranchesHandler(t, map[string]string{
+		"/repos/owner/repo/rules/branches/release%2F1.0":        `[{"type": "deletion"}]`,
+		"/repos/owner/repo/rules/branches/release%2F1.0?page=2": `[{"type": "non_fast_forward"}]`,
+	})
+	got, err := handler.getRulesetRules(context.Background(), "release/1.0")
+	if err != nil {
+		t.Fatalf("getRulesetRules: %v", err)
+	}
+	want := []rulesetRule{{Type: ruleTypeDeletion}, {Type: ruleTypeNonFastForward}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("getRulesetRules() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestListTagProtectionRules(t *testing.T) {
+	t.Parallel()
+	trueVal := true
+	falseVal := false
+
+	//nolint:govet
+	tests := []struct {
+		name    string
+		routes  map[string]string
+		want    []clients.TagProtectionRule
+		wantErr bool
+	}{
+		{
+			name:   "no tag protection",
+			routes: map[string]string{},
+		},
+		{
+			name: "classic tag protection",
+			routes: map[string]string{
+				"/repos/owner/repo/tags/protection": `[{"id": 1, "pattern": "v*"}]`,
+				"/repos/owner/repo/rulesets":        `[]`,
+			},
+			want: []clients.TagProtectionRule{
+				{
+					Pattern:        "v*",
+					AllowDeletions: &falseVal,
+					AllowUpdates:   &falseVal,
+				},
+			},
+		},
+		{
+			name: "tag rulesets without admin access",
+			routes: map[string]string{
+				"/repos/owner/repo/tags/protection": "forbidden",
+				"/repos/owner/repo/rulesets": `[
+					{"id": 1, "target": "tag", "enforcement": "active"},
+					{"id": 2, "target": "tag", "enforcement": "evaluate"},
+					{"id": 3, "target": "branch", "enforcement": "active"}
+				]`,
+				"/repos/owner/repo/rulesets/1": `{
+					"id": 1, "target": "tag", "enforcement": "active",
+					"conditions": {"ref_name": {"include": ["refs/tags/v*", "refs/tags/release-*"]}},
+					"rules": [{"type": "deletion"}]
+				}`,
+			},
+			want: []clients.TagProtectionRule{
+				{
+					Pattern:        "v*",
+					AllowDeletions: &falseVal,
+					AllowUpdates:   &trueVal,
+				},
+				{
+					Pattern:        "release-*",
+					AllowDeletions: &falseVal,
+					AllowUpdates:   &trueVal,
+				},
+			},
+		},
+		{
+			name: "paginated rulesets",
+			routes: map[string]string{
+				"/repos/owner/repo/rulesets":        `[{"id": 1, "target": "branch", "enforcement": "active"}]`,
+				"/repos/owner/repo/rulesets?page=2": `[{"id": 2, "target": "tag", "enforcement": "active"}]`,
+				"/repos/owner/repo/rulesets/2": `{
+					"id": 2, "target": "tag", "enforcement": "active",
+					"conditions": {"ref_name": {"include": ["refs/tags/v*"]}},
+					"rules": [{"type": "update"}]
+				}`,
+			},
+			want: []clients.TagProtectionRule{
+				{
+					Pattern:        "v*",
+					AllowDeletions: &trueVal,
+					AllowUpdates:   &falseVal,
+				},
+			},
+		},
+		{
+			name: "rulesets without access",
+			routes: map[string]string{
+				"/repos/owner/repo/tags/protection": "forbidden",
+				"/repos/owner/repo/rulesets":        "forbidden",
+			},
+		},
+		{
+			name: "rulesets error",
+			routes: map[string]string{
+				"/repos/owner/repo/rulesets": "error",
+			},
+			wantErr: true,
+		},
+		{
+			// Rate limits don't mean there are no rulesets.
+			name: "rulesets rate limited",
+			routes: map[string]string{
+				"/repos/owner/repo/rulesets": "rate limited",
+			},
+			wantErr: true,
+		},
+		{
+			name: "rulesets secondary rate limited",
+			routes: map[string]string{
+				"/repos/owner/repo/rulesets": "secondary rate limited",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			handler := newTestBranchesHandler(t, tt.routes)
+			got, err := handler.listTagProtectionRules(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("listTagProtectionRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("listTagProtectionRules() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -29,16 +29,16 @@ var errEmptyQuery = errors.New("search query is empty")
 
 type searchHandler struct {
 	ghClient *github.Client
-	ctx      context.Context
 	repourl  *repoURL
 }
 
-func (handler *searchHandler) init(ctx context.Context, repourl *repoURL) {
-	handler.ctx = ctx
+func (handler *searchHandler) init(repourl *repoURL) {
 	handler.repourl = repourl
 }
 
-func (handler *searchHandler) search(request clients.SearchRequest) (clients.SearchResponse, error) {
+func (handler *searchHandler) search(
+	ctx context.Context, request clients.SearchRequest,
+) (clients.SearchResponse, error) {
 	if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
 		return clients.SearchResponse{}, fmt.Errorf(
 			"%w: Search only supported for HEAD queries", clients.ErrUnsupportedFeature)
@@ -48,7 +48,7 @@ func (handler *searchHandler) search(request clients.SearchRequest) (clients.Sea
 		return clients.SearchResponse{}, fmt.Errorf("handler.buildQuery: %w", err)
 	}
 
-	resp, _, err := handler.ghClient.Search.Code(handler.ctx, query, &github.SearchOptions{})
+	resp, _, err := handler.ghClient.Search.Code(ctx, query, &github.SearchOptions{})
 	if err != nil {
 		return clients.SearchResponse{}, fmt.Errorf("Search.Code: %w", err)
 	}
@@ -27,11 +27,14 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/go-github/v38/github"
+	opencensusstats "go.opencensus.io/stats"
 
 	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/stats"
 )
 
 const (
@@ -67,7 +70,6 @@ func extractAndValidateArchivePath(path, dest string) (string, error) {
 type tarballHandler struct {
 	errSetup    error
 	once        *sync.Once
-	ctx         context.Context
 	repo        *github.Repository
 	httpClient  *http.Client
 	commitSHA   string
@@ -76,16 +78,17 @@ type tarballHandler struct {
 	files       []string
 }
 
-func (handler *tarballHandler) init(ctx context.Context, repo *github.Repository, commitSHA string) {
+func (handler *tarballHandler) init(repo *github.Repository, commitSHA string) {
 	handler.errSetup = nil
 	handler.once = new(sync.Once)
-	handler.ctx = ctx
 	handler.repo = repo
 	handler.commitSHA = commitSHA
 }
 
-func (handler *tarballHandler) setup() error {
+func (handler *tarballHandler) setup(ctx context.Context) error {
 	handler.once.Do(func() {
+		// The tarball is shared by the checks: its download isn't charged to the first one reading files.
+		ctx := stats.ForSetup(ctx)
 		// Cleanup any previous state.
 		if err := handler.cleanup(); err != nil {
 			handler.errSetup = sce.WithMessage(sce.ErrScorecardInternal, err.Error())
@@ -93,7 +96,7 @@ func (handler *tarballHandler) setup() error {
 		}
 
 		// Setup temp dir/files and download repo tarball.
-		if err := handler.getTarball(); errors.Is(err, errTarballNotFound) {
+		if err := handler.getTarball(ctx); errors.Is(err, errTarballNotFound) {
 			log.Printf("unable to get tarball %v. Skipping...", err)
 			return
 		} else if err != nil {
@@ -111,7 +114,7 @@ func (handler *tarballHandler) setup() error {
 	return handler.errSetup
 }
 
-func (handler *tarballHandler) getTarball() error {
+func (handler *tarballHandler) getTarball(ctx context.Context) error {
 	url := handler.repo.GetArchiveURL()
 	url = strings.Replace(url, "{archive_format}", "tarball/", 1)
 	if strings.EqualFold(handler.commitSHA, clients.HeadSHA) {
@@ -119,10 +122,11 @@ func (handler *tarballHandler) getTarball() error {
 	} else {
 		url = strings.Replace(url, "{/ref}", handler.commitSHA, 1)
 	}
-	req, err := http.NewRequestWithContext(handler.ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return fmt.Errorf("http.NewRequestWithContext: %w", err)
 	}
+	startTime := time.Now()
 	resp, err := handler.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("handler.httpClient.Do: %w", err)
@@ -145,10 +149,14 @@ func (handler *tarballHandler) getTarball() error {
 		return fmt.Errorf("os.CreateTemp: %w", err)
 	}
 	defer repoFile.Close()
-	if _, err := io.Copy(repoFile, resp.Body); err != nil {
+	size, err := io.Copy(repoFile, resp.Body)
+	if err != nil {
 		// This can happen if the incoming tarball is corrupted/server gateway times out.
 		return fmt.Errorf("%w io.Copy: %v", errTarballNotFound, err)
 	}
+	opencensusstats.Record(ctx,
+		stats.TarballSizeInBytes.M(size),
+		stats.TarballDownloadTimeInSec.M(time.Since(startTime).Seconds()))
 
 	handler.tempDir = tempDir
 	handler.tempTarFile = repoFile.Name()
@@ -226,8 +234,8 @@ func (handler *tarballHandler) extractTarball() error {
 	return nil
 }
 
-func (handler *tarballHandler) listFiles(predicate func(string) (bool, error)) ([]string, error) {
-	if err := handler.setup(); err != nil {
+func (handler *tarballHandler) listFiles(ctx context.Context, predicate func(string) (bool, error)) ([]string, error) {
+	if err := handler.setup(ctx); err != nil {
 		return nil, fmt.Errorf("error during tarballHandler.setup: %w", err)
 	}
 	ret := make([]string, 0)
@@ -243,8 +251,8 @@ func (handler *tarballHandler) listFiles(predicate func(string) (bool, error)) (
 	return ret, nil
 }
 
-func (handler *tarballHandler) getFileContent(filename string) ([]byte, error) {
-	if err := handler.setup(); err != nil {
+func (handler *tarballHandler) getFileContent(ctx context.Context, filename string) ([]byte, error) {
+	if err := handler.setup(ctx); err != nil {
 		return nil, fmt.Errorf("error during tarballHandler.setup: %w", err)
 	}
 	content, err := os.ReadFile(filepath.Join(handler.tempDir, filename))
@@ -15,6 +15,7 @@
 package githubrepo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -139,7 +140,7 @@ func TestExtractTarball(t *testing.T) {
 
 			// Test ListFiles API.
 			for _, listfiletest := range testcase.listfileTests {
-				matchedFiles, err := handler.listFiles(listfiletest.predicate)
+				matchedFiles, err := handler.listFiles(context.Background(), listfiletest.predicate)
 				if !errors.Is(err, listfiletest.err) {
 					t.Errorf("test failed: expected - %v, got - %v", listfiletest.err, err)
 					continue
@@ -153,7 +154,7 @@ func TestExtractTarball(t *testing.T) {
 
 			// Test GetFileContent API.
 			for _, getcontenttest := range testcase.getcontentTests {
-				content, err := handler.getFileContent(getcontenttest.filename)
+				content, err := handler.getFileContent(context.Background(), getcontenttest.filename)
 				if getcontenttest.err != nil && !errors.Is(err, getcontenttest.err) {
 					t.Errorf("test failed: expected - %v, got - %v", getcontenttest.err, err)
 				}
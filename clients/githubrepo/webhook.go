@@ -23,32 +23,32 @@ import (
 	"github.com/google/go-github/v38/github"
 
 	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/stats"
 )
 
 type webhookHandler struct {
 	ghClient *github.Client
 	once     *sync.Once
-	ctx      context.Context
 	errSetup error
 	repourl  *repoURL
 	webhook  []clients.Webhook
 }
 
-func (handler *webhookHandler) init(ctx context.Context, repourl *repoURL) {
-	handler.ctx = ctx
+func (handler *webhookHandler) init(repourl *repoURL) {
 	handler.repourl = repourl
 	handler.errSetup = nil
 	handler.once = new(sync.Once)
 }
 
-func (handler *webhookHandler) setup() error {
+func (handler *webhookHandler) setup(ctx context.Context) error {
 	handler.once.Do(func() {
+		ctx := stats.ForSetup(ctx)
 		if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
 			handler.errSetup = fmt.Errorf("%w: ListWebHooks only supported for HEAD queries", clients.ErrUnsupportedFeature)
 			return
 		}
 		hooks, _, err := handler.ghClient.Repositories.ListHooks(
-			handler.ctx, handler.repourl.owner, handler.repourl.repo, &github.ListOptions{})
+			ctx, handler.repourl.owner, handler.repourl.repo, &github.ListOptions{})
 		if err != nil {
 			handler.errSetup = fmt.Errorf("error during ListHooks: %w", err)
 			return
@@ -76,8 +76,8 @@ func getAuthSecret(config map[string]interface{}) bool {
 	return false
 }
 
-func (handler *webhookHandler) listWebhooks() ([]clients.Webhook, error) {
-	if err := handler.setup(); err != nil {
+func (handler *webhookHandler) listWebhooks(ctx context.Context) ([]clients.Webhook, error) {
+	if err := handler.setup(ctx); err != nil {
 		return nil, fmt.Errorf("error during webhookHandler.setup: %w", err)
 	}
 	return handler.webhook, nil
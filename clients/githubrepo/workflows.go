@@ -27,22 +27,22 @@ import (
 
 type workflowsHandler struct {
 	client  *github.Client
-	ctx     context.Context
 	repourl *repoURL
 }
 
-func (handler *workflowsHandler) init(ctx context.Context, repourl *repoURL) {
-	handler.ctx = ctx
+func (handler *workflowsHandler) init(repourl *repoURL) {
 	handler.repourl = repourl
 }
 
-func (handler *workflowsHandler) listSuccessfulWorkflowRuns(filename string) ([]clients.WorkflowRun, error) {
+func (handler *workflowsHandler) listSuccessfulWorkflowRuns(
+	ctx context.Context, filename string,
+) ([]clients.WorkflowRun, error) {
 	if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
 		return nil, fmt.Errorf(
 			"%w: ListWorkflowRunsByFileName only supported for HEAD queries", clients.ErrUnsupportedFeature)
 	}
 	workflowRuns, _, err := handler.client.Actions.ListWorkflowRunsByFileName(
-		handler.ctx, handler.repourl.owner, handler.repourl.repo, filename, &github.ListWorkflowRunsOptions{
+		ctx, handler.repourl.owner, handler.repourl.repo, filename, &github.ListWorkflowRunsOptions{
 			Status: "success",
 		})
 	if err != nil {
@@ -16,6 +16,7 @@ package gitlabrepo
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 
@@ -38,7 +39,6 @@ func (handler *branchesHandler) init(repourl *repoURL) {
 	handler.once = new(sync.Once)
 }
 
-// nolint: nestif
 func (handler *branchesHandler) setup() error {
 	handler.once.Do(func() {
 		if !strings.EqualFold(handler.repourl.commitSHA, clients.HeadSHA) {
@@ -48,7 +48,7 @@ func (handler *branchesHandler) setup() error {
 
 		proj, _, err := handler.glClient.Projects.GetProject(handler.repourl.projectID, &gitlab.GetProjectOptions{})
 		if err != nil {
-			handler.errSetup = fmt.Errorf("requirest for project failed with error %w", err)
+			handler.errSetup = fmt.Errorf("request for project failed with error %w", err)
 			return
 		}
 
@@ -58,37 +58,10 @@ func (handler *branchesHandler) setup() error {
 			return
 		}
 
-		if branch.Protected {
-			protectedBranch, resp, err := handler.glClient.ProtectedBranches.GetProtectedBranch(
-				handler.repourl.projectID, branch.Name)
-			if err != nil && resp.StatusCode != 403 {
-				handler.errSetup = fmt.Errorf("request for protected branch failed with error %w", err)
-				return
-			} else if resp.StatusCode == 403 {
-				handler.errSetup = fmt.Errorf("incorrect permissions to fully check branch protection %w", err)
-				return
-			}
-
-			projectStatusChecks, resp, err := handler.glClient.ExternalStatusChecks.ListProjectStatusChecks(
-				handler.repourl.projectID, &gitlab.ListOptions{})
-			if err != nil && resp.StatusCode != 404 {
-				handler.errSetup = fmt.Errorf("request for external status checks failed with error %w", err)
-				return
-			}
-
-			projectApprovalRule, resp, err := handler.glClient.Projects.GetApprovalConfiguration(handler.repourl.projectID)
-			if err != nil && resp.StatusCode != 404 {
-				handler.errSetup = fmt.Errorf("request for project approval rule failed with %w", err)
-				return
-			}
-
-			handler.defaultBranchRef = makeBranchRefFrom(branch, protectedBranch,
-				projectStatusChecks, projectApprovalRule)
-		} else {
-			handler.defaultBranchRef = &clients.BranchRef{
-				Name:      &branch.Name,
-				Protected: &branch.Protected,
-			}
+		handler.defaultBranchRef, err = handler.getBranchRef(proj, branch)
+		if err != nil {
+			handler.errSetup = err
+			return
 		}
 		handler.errSetup = nil
 	})
@@ -109,31 +82,70 @@ func (handler *branchesHandler) getBranch(branch string) (*clients.BranchRef, er
 	if err != nil {
 		return nil, fmt.Errorf("error getting branch in branchsHandler.getBranch: %w", err)
 	}
+	if !bran.Protected {
+		return makeUnprotectedBranchRef(bran), nil
+	}
 
-	if bran.Protected {
-		protectedBranch, _, err := handler.glClient.ProtectedBranches.GetProtectedBranch(handler.repourl.projectID, bran.Name)
-		if err != nil {
-			return nil, fmt.Errorf("request for protected branch failed with error %w", err)
-		}
+	proj, _, err := handler.glClient.Projects.GetProject(handler.repourl.projectID, &gitlab.GetProjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("request for project failed with error %w", err)
+	}
+	return handler.getBranchRef(proj, bran)
+}
 
-		projectStatusChecks, resp, err := handler.glClient.ExternalStatusChecks.ListProjectStatusChecks(
-			handler.repourl.projectID, &gitlab.ListOptions{})
-		if err != nil && resp.StatusCode != 404 {
-			return nil, fmt.Errorf("request for external status checks failed with error %w", err)
-		}
+// getBranchRef collects the protected-branch, merge request approval
+// and status check settings applying to the branch.
+func (handler *branchesHandler) getBranchRef(proj *gitlab.Project, branch *gitlab.Branch) (*clients.BranchRef, error) {
+	if !branch.Protected {
+		return makeUnprotectedBranchRef(branch), nil
+	}
 
-		projectApprovalRule, resp, err := handler.glClient.Projects.GetApprovalConfiguration(handler.repourl.projectID)
-		if err != nil && resp.StatusCode != 404 {
-			return nil, fmt.Errorf("request for project approval rule failed with %w", err)
-		}
+	protectedBranch, resp, err := handler.glClient.ProtectedBranches.GetProtectedBranch(
+		handler.repourl.projectID, branch.Name)
+	if hasStatus(resp, http.StatusForbidden) {
+		return nil, fmt.Errorf("incorrect permissions to fully check branch protection %w", err)
+	} else if err != nil {
+		return nil, fmt.Errorf("request for protected branch failed with error %w", err)
+	}
 
-		return makeBranchRefFrom(bran, protectedBranch, projectStatusChecks, projectApprovalRule), nil
-	} else {
-		ret := &clients.BranchRef{
-			Name:      &bran.Name,
-			Protected: &bran.Protected,
+	// External status checks and approval rules are only available
+	// in some GitLab tiers.
+	projectStatusChecks, resp, err := handler.glClient.ExternalStatusChecks.ListProjectStatusChecks(
+		handler.repourl.projectID, &gitlab.ListOptions{})
+	if err != nil && !hasStatus(resp, http.StatusNotFound, http.StatusForbidden) {
+		return nil, fmt.Errorf("request for external status checks failed with error %w", err)
+	}
+
+	projectApprovals, resp, err := handler.glClient.Projects.GetApprovalConfiguration(handler.repourl.projectID)
+	if err != nil && !hasStatus(resp, http.StatusNotFound, http.StatusForbidden) {
+		return nil, fmt.Errorf("request for project approval rule failed with %w", err)
+	}
+
+	approvalRules, resp, err := handler.glClient.Projects.GetProjectApprovalRules(handler.repourl.projectID)
+	if err != nil && !hasStatus(resp, http.StatusNotFound, http.StatusForbidden) {
+		return nil, fmt.Errorf("request for project approval rules failed with %w", err)
+	}
+
+	return makeBranchRefFrom(branch, protectedBranch, proj,
+		projectStatusChecks, projectApprovals, approvalRules), nil
+}
+
+func hasStatus(resp *gitlab.Response, codes ...int) bool {
+	if resp == nil {
+		return false
+	}
+	for _, code := range codes {
+		if resp.StatusCode == code {
+			return true
 		}
-		return ret, nil
+	}
+	return false
+}
+
+func makeUnprotectedBranchRef(branch *gitlab.Branch) *clients.BranchRef {
+	return &clients.BranchRef{
+		Name:      &branch.Name,
+		Protected: &branch.Protected,
 	}
 }
 
@@ -145,29 +157,91 @@ func makeContextsFromResp(checks []*gitlab.ProjectStatusCheck) []string {
 	return ret
 }
 
+// pipelineContext is the status check context reported
+// when merge requests require a successful pipeline.
+const pipelineContext = "pipeline"
+
+// requiredApprovals returns the number of approvals required to merge into
+// the branch: the highest of the approval rules applying to it, or the
+// legacy project-wide setting if no rule applies.
+func requiredApprovals(branch string, projectApprovals *gitlab.ProjectApprovals,
+	approvalRules []*gitlab.ProjectApprovalRule,
+) *int32 {
+	var ret *int32
+	for _, rule := range approvalRules {
+		if !approvalRuleApplies(branch, rule) {
+			continue
+		}
+		n := int32(rule.ApprovalsRequired)
+		if ret == nil || n > *ret {
+			ret = &n
+		}
+	}
+	if ret == nil && projectApprovals != nil {
+		n := int32(projectApprovals.ApprovalsBeforeMerge)
+		ret = &n
+	}
+	return ret
+}
+
+// approvalRuleApplies reports whether the rule applies to the branch.
+// Rules without protected branches apply to all branches.
+func approvalRuleApplies(branch string, rule *gitlab.ProjectApprovalRule) bool {
+	if len(rule.ProtectedBranches) == 0 {
+		return true
+	}
+	for _, pb := range rule.ProtectedBranches {
+		if pb.Name == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// pushRestricted reports whether no one, maintainers included, may push
+// to the branch directly, i.e., all changes go through merge requests.
+// The merge access levels are left out on purpose: they tell who may merge
+// merge requests, and the approval rules and pipelines apply to whoever
+// merges them, so they don't let anyone bypass the protection. Like the push
+// restrictions of GitHub, who may merge has no field in BranchProtectionRule.
+func pushRestricted(protectedBranch *gitlab.ProtectedBranch) bool {
+	for _, level := range protectedBranch.PushAccessLevels {
+		if level.AccessLevel != gitlab.NoPermissions || level.UserID != 0 || level.GroupID != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func makeBranchRefFrom(branch *gitlab.Branch, protectedBranch *gitlab.ProtectedBranch,
+	project *gitlab.Project,
 	projectStatusChecks []*gitlab.ProjectStatusCheck,
-	projectApprovalRule *gitlab.ProjectApprovals,
+	projectApprovals *gitlab.ProjectApprovals,
+	approvalRules []*gitlab.ProjectApprovalRule,
 ) *clients.BranchRef {
-	requiresStatusChecks := newFalse()
-	if len(projectStatusChecks) > 0 {
-		requiresStatusChecks = newTrue()
+	contexts := makeContextsFromResp(projectStatusChecks)
+	if project != nil && project.OnlyAllowMergeIfPipelineSucceeds {
+		contexts = append(contexts, pipelineContext)
 	}
 
+	// Fast-forward and semi-linear merge methods require
+	// the source branch to be rebased on the target branch.
+	upToDate := project != nil && project.MergeMethod != gitlab.NoFastForwardMerge
+	linearHistory := project != nil && project.MergeMethod == gitlab.FastForwardMerge
+
 	statusChecksRule := clients.StatusChecksRule{
-		UpToDateBeforeMerge:  newTrue(),
-		RequiresStatusChecks: requiresStatusChecks,
-		Contexts:             makeContextsFromResp(projectStatusChecks),
+		UpToDateBeforeMerge:  &upToDate,
+		RequiresStatusChecks: newBool(len(contexts) > 0),
+		Contexts:             contexts,
 	}
 
 	pullRequestReviewRule := clients.PullRequestReviewRule{
-		DismissStaleReviews:     newTrue(),
-		RequireCodeOwnerReviews: &protectedBranch.CodeOwnerApprovalRequired,
+		RequireCodeOwnerReviews:      &protectedBranch.CodeOwnerApprovalRequired,
+		RequiredApprovingReviewCount: requiredApprovals(branch.Name, projectApprovals, approvalRules),
 	}
-
-	if projectApprovalRule != nil {
-		requiredApprovalNum := int32(projectApprovalRule.ApprovalsBeforeMerge)
-		pullRequestReviewRule.RequiredApprovingReviewCount = &requiredApprovalNum
+	if projectApprovals != nil {
+		pullRequestReviewRule.DismissStaleReviews = newBool(projectApprovals.ResetApprovalsOnPush)
+		pullRequestReviewRule.AllowAuthorApproval = newBool(projectApprovals.MergeRequestsAuthorApproval)
 	}
 
 	ret := &clients.BranchRef{
@@ -175,22 +249,18 @@ func makeBranchRefFrom(branch *gitlab.Branch, protectedBranch *gitlab.ProtectedB
 		Protected: &branch.Protected,
 		BranchProtectionRule: clients.BranchProtectionRule{
 			RequiredPullRequestReviews: pullRequestReviewRule,
-			AllowDeletions:             newFalse(),
-			AllowForcePushes:           &protectedBranch.AllowForcePush,
-			EnforceAdmins:              newTrue(),
-			CheckRules:                 statusChecksRule,
+			// Protected branches can only be deleted from the UI or API by maintainers.
+			AllowDeletions:       newBool(false),
+			AllowForcePushes:     &protectedBranch.AllowForcePush,
+			RequireLinearHistory: &linearHistory,
+			EnforceAdmins:        newBool(pushRestricted(protectedBranch)),
+			CheckRules:           statusChecksRule,
 		},
 	}
 
 	return ret
 }
 
-func newTrue() *bool {
-	b := true
-	return &b
-}
-
-func newFalse() *bool {
-	b := false
+func newBool(b bool) *bool {
 	return &b
 }
@@ -0,0 +1,185 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlabrepo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/ossf/scorecard/v4/clients"
+)
+
+// newTestGitLabClient returns a GitLab client talking to
+// a local stand-in of the GitLab API serving routes.
+func newTestGitLabClient(t *testing.T, routes map[string]string) *gitlab.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		//nolint:errcheck
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := gitlab.NewClient("", gitlab.WithBaseURL(srv.URL+"/api/v4"))
+	if err != nil {
+		t.Fatalf("gitlab.NewClient: %v", err)
+	}
+	return client
+}
+
+func TestGetBranch(t *testing.T) {
+	t.Parallel()
+	trueVal := true
+	falseVal := false
+	var zero int32
+	var two int32 = 2
+	name := "main"
+
+	//nolint:govet
+	tests := []struct {
+		name   string
+		routes map[string]string
+		want   *clients.BranchRef
+	}{
+		{
+			name: "unprotected branch",
+			routes: map[string]string{
+				"/api/v4/projects/1234/repository/branches/main": `{"name": "main", "protected": false}`,
+			},
+			want: &clients.BranchRef{
+				Name:      &name,
+				Protected: &falseVal,
+			},
+		},
+		{
+			name: "protected branch with approval rules",
+			routes: map[string]string{
+				"/api/v4/projects/1234/repository/branches/main": `{"name": "main", "protected": true}`,
+				"/api/v4/projects/1234": `{
+					"id": 1234,
+					"only_allow_merge_if_pipeline_succeeds": true,
+					"merge_method": "ff"
+				}`,
+				"/api/v4/projects/1234/protected_branches/main": `{
+					"name": "main",
+					"push_access_levels": [{"access_level": 0}],
+					"merge_access_levels": [{"access_level": 40}],
+					"allow_force_push": false,
+					"code_owner_approval_required": true
+				}`,
+				"/api/v4/projects/1234/external_status_checks": `[{"id": 1, "name": "compliance"}]`,
+				"/api/v4/projects/1234/approvals": `{
+					"approvals_before_merge": 1,
+					"reset_approvals_on_push": true,
+					"merge_requests_author_approval": false
+				}`,
+				"/api/v4/projects/1234/approval_rules": `[
+					{"id": 1, "approvals_required": 1, "protected_branches": []},
+					{"id": 2, "approvals_required": 2, "protected_branches": [{"name": "main"}]},
+					{"id": 3, "approvals_required": 5, "protected_branches": [{"name": "release"}]}
+				]`,
+			},
+			want: &clients.BranchRef{
+				Name:      &name,
+				Protected: &trueVal,
+				BranchProtectionRule: clients.BranchProtectionRule{
+					RequiredPullRequestReviews: clients.PullRequestReviewRule{
+						RequiredApprovingReviewCount: &two,
+						DismissStaleReviews:          &trueVal,
+						RequireCodeOwnerReviews:      &trueVal,
+						AllowAuthorApproval:          &falseVal,
+					},
+					AllowDeletions:       &falseVal,
+					AllowForcePushes:     &falseVal,
+					RequireLinearHistory: &trueVal,
+					EnforceAdmins:        &trueVal,
+					CheckRules: clients.StatusChecksRule{
+						UpToDateBeforeMerge:  &trueVal,
+						RequiresStatusChecks: &trueVal,
+						Contexts:             []string{"compliance", "pipeline"},
+					},
+				},
+			},
+		},
+		{
+			name: "protected branch on a free tier",
+			routes: map[string]string{
+				"/api/v4/projects/1234/repository/branches/main": `{"name": "main", "protected": true}`,
+				"/api/v4/projects/1234":                          `{"id": 1234, "merge_method": "merge"}`,
+				"/api/v4/projects/1234/protected_branches/main": `{
+					"name": "main",
+					"push_access_levels": [{"access_level": 40}],
+					"merge_access_levels": [{"access_level": 30}],
+					"allow_force_push": true
+				}`,
+				"/api/v4/projects/1234/approvals": `{
+					"approvals_before_merge": 0,
+					"merge_requests_author_approval": true
+				}`,
+			},
+			want: &clients.BranchRef{
+				Name:      &name,
+				Protected: &trueVal,
+				BranchProtectionRule: clients.BranchProtectionRule{
+					RequiredPullRequestReviews: clients.PullRequestReviewRule{
+						RequiredApprovingReviewCount: &zero,
+						DismissStaleReviews:          &falseVal,
+						RequireCodeOwnerReviews:      &falseVal,
+						AllowAuthorApproval:          &trueVal,
+					},
+					AllowDeletions:       &falseVal,
+					AllowForcePushes:     &trueVal,
+					RequireLinearHistory: &falseVal,
+					EnforceAdmins:        &falseVal,
+					CheckRules: clients.StatusChecksRule{
+						UpToDateBeforeMerge:  &falseVal,
+						RequiresStatusChecks: &falseVal,
+						Contexts:             []string{},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			handler := &branchesHandler{
+				glClient: newTestGitLabClient(t, tt.routes),
+			}
+			handler.init(&repoURL{
+				owner:     "owner",
+				projectID: "1234",
+				commitSHA: clients.HeadSHA,
+			})
+			got, err := handler.getBranch("main")
+			if err != nil {
+				t.Fatalf("getBranch: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("getBranch() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"time"
 
 	"github.com/xanzy/go-gitlab"
@@ -148,6 +149,10 @@ func (client *Client) ListReleases() ([]clients.Release, error) {
 	return client.releases.getReleases()
 }
 
+func (client *Client) ListTagProtectionRules() ([]clients.TagProtectionRule, error) {
+	return nil, fmt.Errorf("ListTagProtectionRules: %w", clients.ErrUnsupportedFeature)
+}
+
 func (client *Client) ListContributors() ([]clients.User, error) {
 	return client.contributors.getContributors()
 }
@@ -205,7 +210,22 @@ func (client *Client) Close() error {
 }
 
 func CreateGitlabClientWithToken(ctx context.Context, token string, repo clients.Repo) (clients.RepoClient, error) {
-	client, err := gitlab.NewClient(token, gitlab.WithBaseURL(repo.URI()))
+	return createGitlabClient(ctx, token, gitlab.WithBaseURL(repo.URI()))
+}
+
+// CreateGitlabClientWithTransport returns a RepoClient making its requests through rt,
+// e.g. one caching the responses of the API, see roundtripper.NewCacheTransport.
+func CreateGitlabClientWithTransport(ctx context.Context, token string, repo clients.Repo,
+	rt http.RoundTripper,
+) (clients.RepoClient, error) {
+	return createGitlabClient(ctx, token,
+		gitlab.WithBaseURL(repo.URI()), gitlab.WithHTTPClient(&http.Client{Transport: rt}))
+}
+
+func createGitlabClient(ctx context.Context, token string,
+	options ...gitlab.ClientOptionFunc,
+) (clients.RepoClient, error) {
+	client, err := gitlab.NewClient(token, options...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create gitlab client with error: %w", err)
 	}
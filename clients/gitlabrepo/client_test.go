@@ -0,0 +1,65 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlabrepo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ossf/scorecard/v4/clients"
+)
+
+// recordingTransport answers all the requests with a 404, recording their URLs and tokens.
+type recordingTransport struct {
+	mu     sync.Mutex
+	urls   []string
+	tokens []string
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.urls = append(rt.urls, r.URL.String())
+	rt.tokens = append(rt.tokens, r.Header.Get("PRIVATE-TOKEN"))
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"message": "404 Project Not Found"}`)),
+		Request:    r,
+	}, nil
+}
+
+func TestCreateGitlabClientWithTransport(t *testing.T) {
+	t.Parallel()
+	rt := &recordingTransport{}
+	repo := &repoURL{hostname: "gitlab.example.com", owner: "foo", projectID: "1234"}
+	client, err := CreateGitlabClientWithTransport(context.Background(), "token", repo, rt)
+	if err != nil {
+		t.Fatalf("CreateGitlabClientWithTransport: %v", err)
+	}
+	if err := client.InitRepo(repo, clients.HeadSHA); err == nil {
+		t.Fatalf("InitRepo: got no error for a missing project")
+	}
+	// The client probes the rate limits of the API first.
+	want := "https://gitlab.example.com/api/v4/projects/1234"
+	last := len(rt.urls) - 1
+	if last < 0 || rt.urls[last] != want || rt.tokens[last] != "token" {
+		t.Errorf("requests = %v with tokens %v, want %s with the token", rt.urls, rt.tokens, want)
+	}
+}
@@ -16,6 +16,7 @@ package gitlabrepo
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 
@@ -107,15 +108,13 @@ func (handler *commitsHandler) setup() error {
 					Message:       commit.Message,
 					SHA:           commit.ID,
 				})
+				continue
 			}
 
-			// Casting the Reviewers into clients.Review.
-			var reviews []clients.Review
-			for _, reviewer := range mergeRequest.Reviewers {
-				reviews = append(reviews, clients.Review{
-					Author: &clients.User{ID: int64(reviewer.ID)},
-					State:  "",
-				})
+			reviews, err := handler.listReviews(mergeRequest)
+			if err != nil {
+				handler.errSetup = err
+				return
 			}
 
 			// Casting the Labels into []clients.Label.
@@ -133,15 +132,15 @@ func (handler *commitsHandler) setup() error {
 					Message:       commit.Message,
 					SHA:           commit.ID,
 					AssociatedMergeRequest: clients.PullRequest{
-						Number:   mergeRequest.ID,
+						Number:   mergeRequest.IID,
 						MergedAt: *mergeRequest.MergedAt,
 						HeadSHA:  mergeRequest.SHA,
-						Author:   clients.User{ID: int64(mergeRequest.Author.ID)},
+						Author:   makeUser(mergeRequest.Author),
 						Labels:   labels,
 						Reviews:  reviews,
-						MergedBy: clients.User{ID: int64(mergeRequest.MergedBy.ID)},
+						MergedBy: makeUser(mergeRequest.MergedBy),
 					},
-					Committer: clients.User{ID: int64(user.ID)},
+					Committer: clients.User{ID: int64(user.ID), Login: user.Username},
 				})
 		}
 	})
@@ -149,6 +148,57 @@ func (handler *commitsHandler) setup() error {
 	return handler.errSetup
 }
 
+// listReviews returns the approvals of the merge request, followed by
+// its assigned reviewers who did not approve it.
+func (handler *commitsHandler) listReviews(mr *gitlab.MergeRequest) ([]clients.Review, error) {
+	var reviews []clients.Review
+	approved := make(map[int]bool)
+
+	approvals, resp, err := handler.glClient.MergeRequestApprovals.GetConfiguration(handler.repourl.projectID, mr.IID)
+	if err != nil && !hasStatus(resp, http.StatusNotFound, http.StatusForbidden) {
+		return nil, fmt.Errorf("request for merge request approvals failed with %w", err)
+	}
+	if approvals != nil {
+		for _, approver := range approvals.ApprovedBy {
+			if approver == nil || approver.User == nil {
+				continue
+			}
+			user := makeUser(approver.User)
+			approved[approver.User.ID] = true
+			reviews = append(reviews, clients.Review{
+				Author: &user,
+				State:  reviewStateApproved,
+			})
+		}
+	}
+
+	for _, reviewer := range mr.Reviewers {
+		if reviewer == nil || approved[reviewer.ID] {
+			continue
+		}
+		user := makeUser(reviewer)
+		reviews = append(reviews, clients.Review{
+			Author: &user,
+			State:  "",
+		})
+	}
+	return reviews, nil
+}
+
+// reviewStateApproved is the review state of an approval,
+// matching the one of GitHub pull request reviews.
+const reviewStateApproved = "APPROVED"
+
+func makeUser(u *gitlab.BasicUser) clients.User {
+	if u == nil {
+		return clients.User{}
+	}
+	return clients.User{
+		Login: u.Username,
+		ID:    int64(u.ID),
+	}
+}
+
 func (handler *commitsHandler) listCommits() ([]clients.Commit, error) {
 	if err := handler.setup(); err != nil {
 		return nil, fmt.Errorf("error during commitsHandler.setup: %w", err)
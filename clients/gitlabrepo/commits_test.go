@@ -0,0 +1,105 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlabrepo
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/ossf/scorecard/v4/clients"
+)
+
+func TestListReviews(t *testing.T) {
+	t.Parallel()
+
+	//nolint:govet
+	tests := []struct {
+		name   string
+		routes map[string]string
+		mr     *gitlab.MergeRequest
+		want   []clients.Review
+	}{
+		{
+			name: "approvals and pending reviewers",
+			routes: map[string]string{
+				"/api/v4/projects/1234/merge_requests/7/approvals": `{
+					"iid": 7,
+					"approved_by": [
+						{"user": {"id": 2, "username": "alice"}},
+						{"user": {"id": 3, "username": "bob"}}
+					]
+				}`,
+			},
+			mr: &gitlab.MergeRequest{
+				IID: 7,
+				Reviewers: []*gitlab.BasicUser{
+					{ID: 3, Username: "bob"},
+					{ID: 4, Username: "carol"},
+				},
+			},
+			want: []clients.Review{
+				{
+					Author: &clients.User{ID: 2, Login: "alice"},
+					State:  "APPROVED",
+				},
+				{
+					Author: &clients.User{ID: 3, Login: "bob"},
+					State:  "APPROVED",
+				},
+				{
+					Author: &clients.User{ID: 4, Login: "carol"},
+				},
+			},
+		},
+		{
+			name:   "approvals unavailable",
+			routes: map[string]string{},
+			mr: &gitlab.MergeRequest{
+				IID: 7,
+				Reviewers: []*gitlab.BasicUser{
+					{ID: 4, Username: "carol"},
+				},
+			},
+			want: []clients.Review{
+				{
+					Author: &clients.User{ID: 4, Login: "carol"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			handler := &commitsHandler{
+				glClient: newTestGitLabClient(t, tt.routes),
+			}
+			handler.init(&repoURL{
+				owner:     "owner",
+				projectID: "1234",
+				commitSHA: clients.HeadSHA,
+			})
+			got, err := handler.listReviews(tt.mr)
+			if err != nil {
+				t.Fatalf("listReviews: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("listReviews() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -190,6 +190,11 @@ func (client *localDirClient) ListReleases() ([]clients.Release, error) {
 	return nil, fmt.Errorf("ListReleases: %w", clients.ErrUnsupportedFeature)
 }
 
+// ListTagProtectionRules implements RepoClient.ListTagProtectionRules.
+func (client *localDirClient) ListTagProtectionRules() ([]clients.TagProtectionRule, error) {
+	return nil, fmt.Errorf("ListTagProtectionRules: %w", clients.ErrUnsupportedFeature)
+}
+
 // ListContributors implements RepoClient.ListContributors.
 func (client *localDirClient) ListContributors() ([]clients.User, error) {
 	return nil, fmt.Errorf("ListContributors: %w", clients.ErrUnsupportedFeature)
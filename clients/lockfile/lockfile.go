@@ -0,0 +1,377 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lockfile parses the packages pinned by dependency lockfiles.
+package lockfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/ossf/scorecard/v4/clients"
+	sce "github.com/ossf/scorecard/v4/errors"
+)
+
+type parseFn func(content []byte) ([]clients.Package, error)
+
+var parsers = map[string]parseFn{
+	"go.mod":              parseGoMod,
+	"go.sum":              parseGoSum,
+	"package-lock.json":   parseNPMLock,
+	"npm-shrinkwrap.json": parseNPMLock,
+	"Cargo.lock":          parseCargoLock,
+	"poetry.lock":         parsePoetryLock,
+	"Gemfile.lock":        parseGemfileLock,
+	"requirements.txt":    parseRequirements,
+}
+
+var (
+	tomlKeyValue       = regexp.MustCompile(`^(\w+)\s*=\s*"([^"]*)"`)
+	gemSpec            = regexp.MustCompile(`^ {4}([^\s(]+) \(([^)]+)\)$`)
+	pypiNameSeparators = regexp.MustCompile(`[-_.]+`)
+)
+
+// IsLockfile returns whether the file at path is a supported lockfile.
+func IsLockfile(filepath string) bool {
+	_, ok := parsers[path.Base(filepath)]
+	return ok
+}
+
+// Parse returns the packages pinned by the lockfile at path.
+// Packages without an exact version, e.g. local or VCS dependencies, are skipped.
+func Parse(filepath string, content []byte) ([]clients.Package, error) {
+	parse, ok := parsers[path.Base(filepath)]
+	if !ok {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("unsupported lockfile: %s", filepath))
+	}
+	pkgs, err := parse(content)
+	if err != nil {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("%s: %v", filepath, err))
+	}
+	return pkgs, nil
+}
+
+// Dedup removes duplicate packages and sorts them.
+func Dedup(pkgs []clients.Package) []clients.Package {
+	seen := make(map[clients.Package]bool)
+	ret := make([]clients.Package, 0, len(pkgs))
+	for _, p := range pkgs {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		ret = append(ret, p)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Ecosystem != ret[j].Ecosystem {
+			return ret[i].Ecosystem < ret[j].Ecosystem
+		}
+		if ret[i].Name != ret[j].Name {
+			return ret[i].Name < ret[j].Name
+		}
+		return ret[i].Version < ret[j].Version
+	})
+	return ret
+}
+
+// purlTypes maps OSV ecosystems to package URL types.
+// See https://github.com/package-url/purl-spec/blob/master/PURL-TYPES.rst.
+var purlTypes = map[string]string{
+	clients.EcosystemGo:       "golang",
+	clients.EcosystemNPM:      "npm",
+	clients.EcosystemCratesIO: "cargo",
+	clients.EcosystemPyPI:     "pypi",
+	clients.EcosystemRubyGems: "gem",
+}
+
+// PackageURL returns the package URL of a package version, or "" for unsupported ecosystems.
+func PackageURL(p clients.Package) string {
+	purlType, ok := purlTypes[p.Ecosystem]
+	if !ok {
+		return ""
+	}
+	version := p.Version
+	if p.Ecosystem == clients.EcosystemGo {
+		// The Go module versions are canonical semver versions.
+		version = "v" + version
+	}
+	segments := strings.Split(p.Name, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	// The '@' of npm scopes is escaped as it separates the version.
+	return fmt.Sprintf("pkg:%s/%s@%s", purlType,
+		strings.ReplaceAll(strings.Join(segments, "/"), "@", "%40"), url.PathEscape(version))
+}
+
+// goVersion strips the 'v' prefix and +incompatible suffix which OSV omits.
+func goVersion(v string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(v, "v"), "+incompatible")
+}
+
+func parseGoMod(content []byte) ([]clients.Package, error) {
+	f, err := modfile.Parse("go.mod", content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("modfile.Parse: %w", err)
+	}
+	replaced := make(map[string]*modfile.Replace)
+	for _, r := range f.Replace {
+		replaced[r.Old.Path] = r
+	}
+	var ret []clients.Package
+	for _, r := range f.Require {
+		mod := r.Mod
+		if rep, ok := replaced[mod.Path]; ok {
+			// Replacements by a local directory have no version.
+			if rep.New.Version == "" {
+				continue
+			}
+			mod = rep.New
+		}
+		ret = append(ret, clients.Package{
+			Ecosystem: clients.EcosystemGo,
+			Name:      mod.Path,
+			Version:   goVersion(mod.Version),
+		})
+	}
+	return ret, nil
+}
+
+func parseGoSum(content []byte) ([]clients.Package, error) {
+	var ret []clients.Package
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// Entries of go.mod files only are for modules
+		// in the module graph whose code isn't used.
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		ret = append(ret, clients.Package{
+			Ecosystem: clients.EcosystemGo,
+			Name:      fields[0],
+			Version:   goVersion(fields[1]),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanner.Err: %w", err)
+	}
+	return ret, nil
+}
+
+type npmDependency struct {
+	Version      string                   `json:"version"`
+	Dependencies map[string]npmDependency `json:"dependencies"`
+}
+
+type npmLockfile struct {
+	// Packages is set by lockfileVersion 2 and 3.
+	Packages map[string]struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Link    bool   `json:"link"`
+	} `json:"packages"`
+	// Dependencies is set by lockfileVersion 1 and 2.
+	Dependencies map[string]npmDependency `json:"dependencies"`
+}
+
+func parseNPMLock(content []byte) ([]clients.Package, error) {
+	var lock npmLockfile
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	var ret []clients.Package
+	if len(lock.Packages) > 0 {
+		for key, p := range lock.Packages {
+			// The root project has an empty key.
+			i := strings.LastIndex(key, "node_modules/")
+			if i < 0 || p.Link {
+				continue
+			}
+			name := key[i+len("node_modules/"):]
+			if p.Name != "" {
+				name = p.Name
+			}
+			ret = appendNPMPackage(ret, name, p.Version)
+		}
+		return ret, nil
+	}
+	var walk func(deps map[string]npmDependency)
+	walk = func(deps map[string]npmDependency) {
+		for name, dep := range deps {
+			ret = appendNPMPackage(ret, name, dep.Version)
+			walk(dep.Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+	return ret, nil
+}
+
+// appendNPMPackage appends packages installed from the registry,
+// resolving aliases such as "npm:other@1.0.0".
+func appendNPMPackage(pkgs []clients.Package, name, version string) []clients.Package {
+	if alias := strings.TrimPrefix(version, "npm:"); alias != version {
+		i := strings.LastIndex(alias, "@")
+		if i <= 0 {
+			return pkgs
+		}
+		name, version = alias[:i], alias[i+1:]
+	}
+	if version == "" || strings.Contains(version, ":") {
+		return pkgs
+	}
+	return append(pkgs, clients.Package{
+		Ecosystem: clients.EcosystemNPM,
+		Name:      name,
+		Version:   version,
+	})
+}
+
+// parseTOMLPackages returns the name and version of the [[package]]
+// tables of Cargo.lock and poetry.lock files.
+func parseTOMLPackages(content []byte, ecosystem string) ([]clients.Package, error) {
+	var ret []clients.Package
+	var cur *clients.Package
+	flush := func() {
+		if cur != nil && cur.Name != "" && cur.Version != "" {
+			ret = append(ret, *cur)
+		}
+		cur = nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "[[package]]":
+			flush()
+			cur = &clients.Package{Ecosystem: ecosystem}
+		case strings.HasPrefix(line, "["):
+			flush()
+		case cur != nil:
+			m := tomlKeyValue.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			switch m[1] {
+			case "name":
+				cur.Name = m[2]
+			case "version":
+				cur.Version = m[2]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanner.Err: %w", err)
+	}
+	flush()
+	return ret, nil
+}
+
+func parseCargoLock(content []byte) ([]clients.Package, error) {
+	return parseTOMLPackages(content, clients.EcosystemCratesIO)
+}
+
+func parsePoetryLock(content []byte) ([]clients.Package, error) {
+	pkgs, err := parseTOMLPackages(content, clients.EcosystemPyPI)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pkgs {
+		pkgs[i].Name = normalizePyPIName(pkgs[i].Name)
+	}
+	return pkgs, nil
+}
+
+func parseGemfileLock(content []byte) ([]clients.Package, error) {
+	var ret []clients.Package
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" && line[0] != ' ' {
+			section = line
+			continue
+		}
+		// Gems from GIT and PATH sources aren't released on RubyGems.
+		if section != "GEM" {
+			continue
+		}
+		m := gemSpec.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		// Strip the platform, e.g. 1.13.10-x86_64-linux.
+		version, _, _ := strings.Cut(m[2], "-")
+		ret = append(ret, clients.Package{
+			Ecosystem: clients.EcosystemRubyGems,
+			Name:      m[1],
+			Version:   version,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanner.Err: %w", err)
+	}
+	return ret, nil
+}
+
+// parseRequirements returns the requirements pinned with '=='.
+func parseRequirements(content []byte) ([]clients.Package, error) {
+	var ret []clients.Package
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		// Drop environment markers and per-requirement options such as --hash.
+		line, _, _ = strings.Cut(line, ";")
+		line, _, _ = strings.Cut(line, " -")
+		line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), "\\"))
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		name, version, ok := strings.Cut(line, "==")
+		if !ok {
+			continue
+		}
+		version = strings.TrimSpace(strings.TrimPrefix(version, "="))
+		if version == "" || strings.Contains(version, "*") {
+			continue
+		}
+		name, _, _ = strings.Cut(name, "[")
+		ret = append(ret, clients.Package{
+			Ecosystem: clients.EcosystemPyPI,
+			Name:      normalizePyPIName(strings.TrimSpace(name)),
+			Version:   version,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanner.Err: %w", err)
+	}
+	return ret, nil
+}
+
+// normalizePyPIName normalizes names as per PEP 503.
+func normalizePyPIName(name string) string {
+	return pypiNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
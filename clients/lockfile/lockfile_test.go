@@ -0,0 +1,241 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lockfile
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/clients"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	//nolint:govet
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    []clients.Package
+		wantErr bool
+	}{
+		{
+			name: "go.mod",
+			path: "go.mod",
+			content: `module example.com/m
+
+go 1.19
+
+require (
+	github.com/a/b v1.2.3
+	github.com/c/d v2.0.0+incompatible // indirect
+	github.com/e/f v0.1.0
+	github.com/g/h v0.2.0
+)
+
+replace github.com/e/f => github.com/fork/f v0.1.1
+
+replace github.com/g/h => ../h
+`,
+			want: []clients.Package{
+				{Ecosystem: clients.EcosystemGo, Name: "github.com/a/b", Version: "1.2.3"},
+				{Ecosystem: clients.EcosystemGo, Name: "github.com/c/d", Version: "2.0.0"},
+				{Ecosystem: clients.EcosystemGo, Name: "github.com/fork/f", Version: "0.1.1"},
+			},
+		},
+		{
+			name: "go.sum",
+			path: "sub/go.sum",
+			content: `github.com/a/b v1.2.3 h1:abc=
+github.com/a/b v1.2.3/go.mod h1:def=
+github.com/old/x v0.1.0/go.mod h1:ghi=
+`,
+			want: []clients.Package{
+				{Ecosystem: clients.EcosystemGo, Name: "github.com/a/b", Version: "1.2.3"},
+			},
+		},
+		{
+			name: "package-lock.json v3",
+			path: "package-lock.json",
+			content: `{
+				"lockfileVersion": 3,
+				"packages": {
+					"": {"name": "root", "version": "1.0.0"},
+					"node_modules/lodash": {"version": "4.17.20"},
+					"node_modules/a/node_modules/@scope/b": {"version": "2.0.0"},
+					"node_modules/alias": {"name": "real", "version": "1.1.0"},
+					"node_modules/local": {"resolved": "../local", "link": true}
+				}
+			}`,
+			want: []clients.Package{
+				{Ecosystem: clients.EcosystemNPM, Name: "@scope/b", Version: "2.0.0"},
+				{Ecosystem: clients.EcosystemNPM, Name: "lodash", Version: "4.17.20"},
+				{Ecosystem: clients.EcosystemNPM, Name: "real", Version: "1.1.0"},
+			},
+		},
+		{
+			name: "package-lock.json v1",
+			path: "package-lock.json",
+			content: `{
+				"lockfileVersion": 1,
+				"dependencies": {
+					"a": {"version": "1.0.0", "dependencies": {"b": {"version": "0.5.0"}}},
+					"c": {"version": "npm:d@3.0.0"},
+					"e": {"version": "git+https://example.com/e.git#abc"}
+				}
+			}`,
+			want: []clients.Package{
+				{Ecosystem: clients.EcosystemNPM, Name: "a", Version: "1.0.0"},
+				{Ecosystem: clients.EcosystemNPM, Name: "b", Version: "0.5.0"},
+				{Ecosystem: clients.EcosystemNPM, Name: "d", Version: "3.0.0"},
+			},
+		},
+		{
+			name:    "invalid package-lock.json",
+			path:    "package-lock.json",
+			content: `{`,
+			wantErr: true,
+		},
+		{
+			name: "Cargo.lock",
+			path: "Cargo.lock",
+			content: `version = 3
+
+[[package]]
+name = "smallvec"
+version = "1.6.0"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "smallvec",
+]
+`,
+			want: []clients.Package{
+				{Ecosystem: clients.EcosystemCratesIO, Name: "app", Version: "0.1.0"},
+				{Ecosystem: clients.EcosystemCratesIO, Name: "smallvec", Version: "1.6.0"},
+			},
+		},
+		{
+			name: "poetry.lock",
+			path: "poetry.lock",
+			content: `[[package]]
+name = "Django"
+version = "3.2.1"
+description = "A web framework"
+
+[package.dependencies]
+sqlparse = ">=0.2.2"
+
+[[package]]
+name = "typing_extensions"
+version = "4.0.0"
+
+[metadata]
+lock-version = "1.1"
+`,
+			want: []clients.Package{
+				{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"},
+				{Ecosystem: clients.EcosystemPyPI, Name: "typing-extensions", Version: "4.0.0"},
+			},
+		},
+		{
+			name: "Gemfile.lock",
+			path: "Gemfile.lock",
+			content: `GIT
+  remote: https://github.com/rails/rails.git
+  specs:
+    rails (7.1.0.alpha)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.13.10-x86_64-linux)
+      racc (~> 1.4)
+    racc (1.6.1)
+
+PLATFORMS
+  x86_64-linux
+`,
+			want: []clients.Package{
+				{Ecosystem: clients.EcosystemRubyGems, Name: "nokogiri", Version: "1.13.10"},
+				{Ecosystem: clients.EcosystemRubyGems, Name: "racc", Version: "1.6.1"},
+			},
+		},
+		{
+			name: "requirements.txt",
+			path: "requirements.txt",
+			content: `# comment
+-r other.txt
+-e git+https://example.com/pkg.git#egg=pkg
+Django==3.2.1  # pinned
+requests[security] == 2.25.0 ; python_version >= "3.6"
+urllib3==1.26.4 \
+    --hash=sha256:abc
+flask>=2.0
+numpy==1.*
+`,
+			want: []clients.Package{
+				{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"},
+				{Ecosystem: clients.EcosystemPyPI, Name: "requests", Version: "2.25.0"},
+				{Ecosystem: clients.EcosystemPyPI, Name: "urllib3", Version: "1.26.4"},
+			},
+		},
+		{
+			name:    "unsupported file",
+			path:    "pom.xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse(tt.path, []byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, Dedup(got)); diff != "" && !tt.wantErr {
+				t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsLockfile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "go.sum", want: true},
+		{path: "a/b/Cargo.lock", want: true},
+		{path: "requirements.txt", want: true},
+		{path: "requirements-dev.txt", want: false},
+		{path: "package.json", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			if got := IsLockfile(tt.path); got != tt.want {
+				t.Errorf("IsLockfile(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
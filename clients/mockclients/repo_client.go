@@ -303,6 +303,21 @@ func (mr *MockRepoClientMockRecorder) ListSuccessfulWorkflowRuns(filename interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSuccessfulWorkflowRuns", reflect.TypeOf((*MockRepoClient)(nil).ListSuccessfulWorkflowRuns), filename)
 }
 
+// ListTagProtectionRules mocks base method.
+func (m *MockRepoClient) ListTagProtectionRules() ([]clients.TagProtectionRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagProtectionRules")
+	ret0, _ := ret[0].([]clients.TagProtectionRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagProtectionRules indicates an expected call of ListTagProtectionRules.
+func (mr *MockRepoClientMockRecorder) ListTagProtectionRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagProtectionRules", reflect.TypeOf((*MockRepoClient)(nil).ListTagProtectionRules))
+}
+
 // ListWebhooks mocks base method.
 func (m *MockRepoClient) ListWebhooks() ([]clients.Webhook, error) {
 	m.ctrl.T.Helper()
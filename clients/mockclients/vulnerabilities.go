@@ -64,3 +64,18 @@ func (mr *MockVulnerabilitiesClientMockRecorder) HasUnfixedVulnerabilities(conte
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasUnfixedVulnerabilities", reflect.TypeOf((*MockVulnerabilitiesClient)(nil).HasUnfixedVulnerabilities), context, commit)
 }
+
+// ListUnfixedVulnerabilities mocks base method.
+func (m *MockVulnerabilitiesClient) ListUnfixedVulnerabilities(context context.Context, packages []clients.Package) (clients.VulnerabilitiesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnfixedVulnerabilities", context, packages)
+	ret0, _ := ret[0].(clients.VulnerabilitiesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnfixedVulnerabilities indicates an expected call of ListUnfixedVulnerabilities.
+func (mr *MockVulnerabilitiesClientMockRecorder) ListUnfixedVulnerabilities(context, packages interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnfixedVulnerabilities", reflect.TypeOf((*MockVulnerabilitiesClient)(nil).ListUnfixedVulnerabilities), context, packages)
+}
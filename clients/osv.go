@@ -18,59 +18,405 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/ossf/scorecard/v4/errors"
 )
 
 var _ VulnerabilitiesClient = osvClient{}
 
-type osvClient struct{}
+type osvClient struct {
+	apiURL string
+}
+
+const (
+	osvAPIURL = "https://api.osv.dev/v1"
+
+	// osvMaxBatchSize is the maximum number of queries per batch request.
+	osvMaxBatchSize = 1000
+
+	osvRangeSemver    = "SEMVER"
+	osvRangeEcosystem = "ECOSYSTEM"
+	osvSeverityCVSSV3 = "CVSS_V3"
+	// GitHub advisories rate medium severity vulns as moderate.
+	osvSeverityModerate = "MODERATE"
+)
 
-const osvQueryEndpoint = "https://api.osv.dev/v1/query"
+var pypiNameSeparators = regexp.MustCompile(`[-_.]+`)
 
 type osvQuery struct {
-	Commit string `json:"commit"`
+	Commit    string      `json:"commit,omitempty"`
+	Version   string      `json:"version,omitempty"`
+	Package   *osvPackage `json:"package,omitempty"`
+	PageToken string      `json:"page_token,omitempty"`
+}
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
 }
 
 type osvResp struct {
-	Vulns []struct {
-		ID string `json:"id"`
-	} `json:"vulns"`
+	Vulns         []osvEntry `json:"vulns"`
+	NextPageToken string     `json:"next_page_token"`
+}
+
+type osvBatchQuery struct {
+	Queries []osvQuery `json:"queries"`
+}
+
+type osvBatchResp struct {
+	Results []osvResp `json:"results"`
+}
+
+// osvEntry is an OSV record. Batch queries only set its ID.
+// See https://ossf.github.io/osv-schema/.
+type osvEntry struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases"`
+	Withdrawn string   `json:"withdrawn"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package  osvPackage `json:"package"`
+		Versions []string   `json:"versions"`
+		Ranges   []struct {
+			Type   string     `json:"type"`
+			Events []osvEvent `json:"events"`
+		} `json:"ranges"`
+		EcosystemSpecific struct {
+			Severity string `json:"severity"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
 }
 
 // HasUnfixedVulnerabilities implements VulnerabilityClient.HasUnfixedVulnerabilities.
 func (v osvClient) HasUnfixedVulnerabilities(ctx context.Context, commit string) (VulnerabilitiesResponse, error) {
-	query, err := json.Marshal(&osvQuery{
-		Commit: commit,
-	})
+	var osvresp osvResp
+	if err := v.post(ctx, "query", &osvQuery{Commit: commit}, &osvresp); err != nil {
+		return VulnerabilitiesResponse{}, err
+	}
+
+	var ret VulnerabilitiesResponse
+	for i := range osvresp.Vulns {
+		ret.Vulnerabilities = append(ret.Vulnerabilities, osvresp.Vulns[i].vulnerability(nil))
+	}
+	return ret, nil
+}
+
+// ListUnfixedVulnerabilities implements VulnerabilityClient.ListUnfixedVulnerabilities.
+func (v osvClient) ListUnfixedVulnerabilities(ctx context.Context, packages []Package,
+) (VulnerabilitiesResponse, error) {
+	var queries []osvQuery
+	var queried []Package
+	for _, p := range packages {
+		if p.Version == "" {
+			continue
+		}
+		queries = append(queries, osvQuery{
+			Version: p.Version,
+			Package: &osvPackage{
+				Name:      p.Name,
+				Ecosystem: p.Ecosystem,
+			},
+		})
+		queried = append(queried, p)
+	}
+
+	// Batch queries only return IDs, so the vulns found
+	// are fetched afterwards, once per ID.
+	var ids []string
+	affected := make(map[string][]Package)
+	for len(queries) > 0 {
+		n := len(queries)
+		if n > osvMaxBatchSize {
+			n = osvMaxBatchSize
+		}
+		batch, batchPkgs := queries[:n], queried[:n]
+		queries, queried = queries[n:], queried[n:]
+
+		var osvresp osvBatchResp
+		if err := v.post(ctx, "querybatch", &osvBatchQuery{Queries: batch}, &osvresp); err != nil {
+			return VulnerabilitiesResponse{}, err
+		}
+		for i, result := range osvresp.Results {
+			if i >= len(batch) {
+				break
+			}
+			for _, vuln := range result.Vulns {
+				pkgs, ok := affected[vuln.ID]
+				if !ok {
+					ids = append(ids, vuln.ID)
+				}
+				if !containsPackage(pkgs, batchPkgs[i]) {
+					affected[vuln.ID] = append(pkgs, batchPkgs[i])
+				}
+			}
+			// Queries with more results than fit in a response are queried again for the next page.
+			if result.NextPageToken != "" {
+				next := batch[i]
+				next.PageToken = result.NextPageToken
+				queries = append(queries, next)
+				queried = append(queried, batchPkgs[i])
+			}
+		}
+	}
+
+	var ret VulnerabilitiesResponse
+	for _, id := range ids {
+		var entry osvEntry
+		if err := v.get(ctx, "vulns/"+url.PathEscape(id), &entry); err != nil {
+			return VulnerabilitiesResponse{}, err
+		}
+		pkgs := affected[id]
+		vuln := entry.vulnerability(&pkgs[0])
+		if len(pkgs) > 1 {
+			vuln.OtherPackages = pkgs[1:]
+		}
+		ret.Vulnerabilities = append(ret.Vulnerabilities, vuln)
+	}
+	return ret, nil
+}
+
+func containsPackage(pkgs []Package, p Package) bool {
+	for _, pkg := range pkgs {
+		if pkg == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (v osvClient) post(ctx context.Context, endpoint string, query, osvresp interface{}) error {
+	body, err := json.Marshal(query)
 	if err != nil {
-		return VulnerabilitiesResponse{}, errors.WithMessage(err, "failed to marshal query")
+		return errors.WithMessage(err, "failed to marshal query")
 	}
+	return v.do(ctx, http.MethodPost, endpoint, bytes.NewReader(body), osvresp)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, osvQueryEndpoint, bytes.NewReader(query))
+func (v osvClient) get(ctx context.Context, endpoint string, osvresp interface{}) error {
+	return v.do(ctx, http.MethodGet, endpoint, nil, osvresp)
+}
+
+func (v osvClient) do(ctx context.Context, method, endpoint string, body io.Reader, osvresp interface{}) error {
+	apiURL := v.apiURL
+	if apiURL == "" {
+		apiURL = osvAPIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiURL+"/"+endpoint, body)
 	if err != nil {
-		return VulnerabilitiesResponse{}, errors.WithMessage(err, "failed to create request")
+		return errors.WithMessage(err, "failed to create request")
 	}
 
 	httpClient := &http.Client{}
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return VulnerabilitiesResponse{}, errors.WithMessage(err, "failed to send request")
+		return errors.WithMessage(err, "failed to send request")
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.WithMessage(errors.ErrScorecardInternal,
+			fmt.Sprintf("OSV API %s returned %s", endpoint, resp.Status))
+	}
 
-	var osvresp osvResp
 	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&osvresp); err != nil {
-		return VulnerabilitiesResponse{}, errors.WithMessage(err, "failed to decode response")
+	if err := decoder.Decode(osvresp); err != nil {
+		return errors.WithMessage(err, "failed to decode response")
 	}
+	return nil
+}
 
-	var ret VulnerabilitiesResponse
-	for _, vuln := range osvresp.Vulns {
-		ret.Vulnerabilities = append(ret.Vulnerabilities, Vulnerability{
-			ID: vuln.ID,
+// vulnerability converts the OSV record of a vuln of p, or of
+// the queried commit if p is nil, to a Vulnerability.
+func (e *osvEntry) vulnerability(p *Package) Vulnerability {
+	ret := Vulnerability{
+		ID:      e.ID,
+		Aliases: e.Aliases,
+	}
+	for _, s := range e.Severity {
+		if s.Type != osvSeverityCVSSV3 {
+			continue
+		}
+		if score, err := cvss3BaseScore(s.Score); err == nil && score > ret.CVSSScore {
+			ret.CVSSScore = score
+		}
+	}
+	ret.Severity = cvssSeverity(ret.CVSSScore)
+	// Without a CVSS vector, fall back to the severity rated by the database,
+	// e.g. by GitHub advisories or OSS-Fuzz.
+	ratings := []string{e.DatabaseSpecific.Severity}
+	for i := range e.Affected {
+		ratings = append(ratings, e.Affected[i].EcosystemSpecific.Severity)
+	}
+	for _, rating := range ratings {
+		if ret.Severity != "" {
+			break
+		}
+		switch severity := strings.ToUpper(rating); severity {
+		case SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow:
+			ret.Severity = severity
+		case osvSeverityModerate:
+			ret.Severity = SeverityMedium
+		}
+	}
+
+	switch {
+	case p != nil:
+		ret.Package = *p
+		ret.FixedVersion = e.fixedVersion(*p)
+	case len(e.Affected) > 0:
+		// The affected package of a commit is not known
+		// by version, so only its first fix is reported.
+		affected := e.Affected[0]
+		ret.Package = Package{
+			Ecosystem: affected.Package.Ecosystem,
+			Name:      affected.Package.Name,
+		}
+		var fixed []string
+		for _, r := range affected.Ranges {
+			if r.Type != osvRangeSemver && r.Type != osvRangeEcosystem {
+				continue
+			}
+			for _, event := range r.Events {
+				if event.Fixed != "" {
+					fixed = append(fixed, event.Fixed)
+				}
+			}
+		}
+		sort.SliceStable(fixed, func(i, j int) bool {
+			return compareVersions(ret.Package.Ecosystem, fixed[i], fixed[j]) < 0
 		})
+		if len(fixed) > 0 {
+			ret.FixedVersion = fixed[0]
+		}
 	}
-	return ret, nil
+	return ret
+}
+
+// affects returns whether the version of p is affected by the vuln.
+func (e *osvEntry) affects(p Package) bool {
+	key := osvPackageKey(p.Ecosystem, p.Name)
+	for _, affected := range e.Affected {
+		if osvPackageKey(affected.Package.Ecosystem, affected.Package.Name) != key {
+			continue
+		}
+		for _, version := range affected.Versions {
+			if trimVersion(version) == trimVersion(p.Version) {
+				return true
+			}
+		}
+		for _, r := range affected.Ranges {
+			// GIT ranges are made of commits which can't be compared to versions.
+			if r.Type != osvRangeSemver && r.Type != osvRangeEcosystem {
+				continue
+			}
+			if inRange(p.Ecosystem, p.Version, r.Events) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fixedVersion returns the first version of p's package after p's
+// version which fixes the vuln, or "" if there is none.
+func (e *osvEntry) fixedVersion(p Package) string {
+	key := osvPackageKey(p.Ecosystem, p.Name)
+	ret := ""
+	for _, affected := range e.Affected {
+		if osvPackageKey(affected.Package.Ecosystem, affected.Package.Name) != key {
+			continue
+		}
+		for _, r := range affected.Ranges {
+			if r.Type != osvRangeSemver && r.Type != osvRangeEcosystem {
+				continue
+			}
+			for _, event := range r.Events {
+				if event.Fixed == "" || compareVersions(p.Ecosystem, event.Fixed, p.Version) <= 0 {
+					continue
+				}
+				if ret == "" || compareVersions(p.Ecosystem, event.Fixed, ret) < 0 {
+					ret = event.Fixed
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// inRange evaluates the events of an OSV range in version order, following
+// https://ossf.github.io/osv-schema/#evaluation.
+func inRange(ecosystem, version string, events []osvEvent) bool {
+	eventVersion := func(e osvEvent) string {
+		switch {
+		case e.Introduced != "":
+			return e.Introduced
+		case e.Fixed != "":
+			return e.Fixed
+		case e.LastAffected != "":
+			return e.LastAffected
+		default:
+			return e.Limit
+		}
+	}
+	sorted := make([]osvEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		vi, vj := eventVersion(sorted[i]), eventVersion(sorted[j])
+		if vi == "0" || vj == "0" {
+			return vi == "0" && vj != "0"
+		}
+		return compareVersions(ecosystem, vi, vj) < 0
+	})
+
+	affected := false
+	for _, e := range sorted {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || compareVersions(ecosystem, version, e.Introduced) >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if compareVersions(ecosystem, version, e.Fixed) >= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if compareVersions(ecosystem, version, e.LastAffected) > 0 {
+				affected = false
+			}
+		case e.Limit != "":
+			if compareVersions(ecosystem, version, e.Limit) >= 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// osvPackageKey identifies a package of an ecosystem. PyPI names are
+// normalized as per PEP 503 since they are case and separator insensitive.
+func osvPackageKey(ecosystem, name string) string {
+	if ecosystem == EcosystemPyPI {
+		name = pypiNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	}
+	return ecosystem + "/" + name
 }
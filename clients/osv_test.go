@@ -0,0 +1,147 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCVSS3BaseScore(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		vector  string
+		want    float64
+		wantErr bool
+	}{
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", want: 9.8},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", want: 10},
+		{vector: "CVSS:3.0/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", want: 6.4},
+		{vector: "CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", want: 1.8},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", want: 0},
+		{vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P", wantErr: true},
+		{vector: "CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.vector, func(t *testing.T) {
+			t.Parallel()
+			got, err := cvss3BaseScore(tt.vector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cvss3BaseScore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("cvss3BaseScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOSVClient(t *testing.T) {
+	t.Parallel()
+	routes := map[string]string{
+		"/query": `{"vulns": [{
+			"id": "OSV-2021-1",
+			"affected": [{
+				"package": {"ecosystem": "OSS-Fuzz", "name": "proj"},
+				"ranges": [{"type": "GIT", "events": [{"introduced": "abc"}]}],
+				"ecosystem_specific": {"severity": "HIGH"}
+			}]
+		}]}`,
+		"/querybatch": `{"results": [
+			{"vulns": [{"id": "GHSA-1"}, {"id": "PYSEC-1"}]},
+			{},
+			{"vulns": [{"id": "GHSA-1"}]}
+		]}`,
+		"/vulns/GHSA-1": `{
+			"id": "GHSA-1",
+			"aliases": ["CVE-2021-1"],
+			"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+			"affected": [{
+				"package": {"ecosystem": "PyPI", "name": "django"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [
+					{"introduced": "0"}, {"fixed": "2.2.24"},
+					{"introduced": "3.0"}, {"fixed": "3.1.12"},
+					{"introduced": "3.2"}, {"fixed": "3.2.4"}
+				]}]
+			}]
+		}`,
+		"/vulns/PYSEC-1": `{"id": "PYSEC-1", "aliases": ["CVE-2021-1"]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		//nolint:errcheck
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	client := osvClient{apiURL: srv.URL}
+
+	got, err := client.HasUnfixedVulnerabilities(context.Background(), "sha")
+	if err != nil {
+		t.Fatalf("HasUnfixedVulnerabilities: %v", err)
+	}
+	want := []Vulnerability{
+		{
+			ID:       "OSV-2021-1",
+			Severity: SeverityHigh,
+			Package:  Package{Ecosystem: "OSS-Fuzz", Name: "proj"},
+		},
+	}
+	if diff := cmp.Diff(want, got.Vulnerabilities); diff != "" {
+		t.Errorf("HasUnfixedVulnerabilities() mismatch (-want +got):\n%s", diff)
+	}
+
+	django := Package{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.1.5"}
+	otherDjango := Package{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.2.1"}
+	got, err = client.ListUnfixedVulnerabilities(context.Background(), []Package{
+		django,
+		{Ecosystem: EcosystemNPM, Name: "lodash", Version: "4.17.21"},
+		otherDjango,
+	})
+	if err != nil {
+		t.Fatalf("ListUnfixedVulnerabilities: %v", err)
+	}
+	want = []Vulnerability{
+		{
+			ID:            "GHSA-1",
+			Aliases:       []string{"CVE-2021-1"},
+			Severity:      SeverityCritical,
+			CVSSScore:     9.8,
+			Package:       django,
+			OtherPackages: []Package{otherDjango},
+			FixedVersion:  "3.1.12",
+		},
+		{
+			ID:      "PYSEC-1",
+			Aliases: []string{"CVE-2021-1"},
+			Package: django,
+		},
+	}
+	if diff := cmp.Diff(want, got.Vulnerabilities); diff != "" {
+		t.Errorf("ListUnfixedVulnerabilities() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := client.get(context.Background(), "vulns/missing", &osvEntry{}); err == nil {
+		t.Errorf("get() of a missing vuln should fail")
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"strings"
+	"unicode"
+)
+
+// compareVersions compares two versions of a package of the ecosystem
+// and returns -1, 0 or 1. Ecosystems following semantic versioning
+// treat anything after a '-' as a pre-release. Other ecosystems compare
+// dot- or letter-separated components, where a trailing alphabetic
+// component marks a pre-release unless it is a post-release.
+// It is an approximation of each ecosystem's ordering which is good
+// enough to evaluate OSV affected ranges offline.
+func compareVersions(ecosystem, a, b string) int {
+	a, b = trimVersion(a), trimVersion(b)
+	switch ecosystem {
+	case EcosystemGo, EcosystemNPM, EcosystemCratesIO:
+		return compareSemver(a, b)
+	default:
+		return compareTokens(versionTokens(a), versionTokens(b))
+	}
+}
+
+// trimVersion strips the 'v' prefix and build metadata.
+func trimVersion(v string) string {
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "v"), "V")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	return v
+}
+
+func compareSemver(a, b string) int {
+	coreA, preA, hasPreA := strings.Cut(a, "-")
+	coreB, preB, hasPreB := strings.Cut(b, "-")
+	if c := compareTokens(versionTokens(coreA), versionTokens(coreB)); c != 0 {
+		return c
+	}
+	switch {
+	case !hasPreA && !hasPreB:
+		return 0
+	case !hasPreA:
+		return 1
+	case !hasPreB:
+		return -1
+	}
+
+	// Pre-release identifiers: numeric ones compare numerically
+	// and have lower precedence than alphanumeric ones.
+	idsA, idsB := strings.Split(preA, "."), strings.Split(preB, ".")
+	for i := 0; i < len(idsA) && i < len(idsB); i++ {
+		numA, numB := isNumeric(idsA[i]), isNumeric(idsB[i])
+		var c int
+		switch {
+		case numA && numB:
+			c = compareNumeric(idsA[i], idsB[i])
+		case numA:
+			c = -1
+		case numB:
+			c = 1
+		default:
+			c = strings.Compare(idsA[i], idsB[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(idsA), len(idsB))
+}
+
+type versionToken struct {
+	value   string
+	numeric bool
+}
+
+// rank orders alphabetic components: development releases
+// come first and post-releases last.
+func (t versionToken) rank() int {
+	switch t.value {
+	case "dev":
+		return 0
+	case "post", "p", "patch", "r", "rev":
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (t versionToken) compare(o versionToken) int {
+	switch {
+	case t.numeric && o.numeric:
+		return compareNumeric(t.value, o.value)
+	case t.numeric:
+		return 1
+	case o.numeric:
+		return -1
+	}
+	if c := compareInts(t.rank(), o.rank()); c != 0 {
+		return c
+	}
+	return strings.Compare(t.value, o.value)
+}
+
+// versionTokens splits v into runs of digits and runs of letters.
+func versionTokens(v string) []versionToken {
+	var ret []versionToken
+	var cur strings.Builder
+	var curNumeric bool
+	flush := func() {
+		if cur.Len() > 0 {
+			ret = append(ret, versionToken{value: cur.String(), numeric: curNumeric})
+			cur.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(v) {
+		switch {
+		case unicode.IsDigit(r):
+			if !curNumeric {
+				flush()
+			}
+			curNumeric = true
+			cur.WriteRune(r)
+		case unicode.IsLetter(r):
+			if curNumeric {
+				flush()
+			}
+			curNumeric = false
+			cur.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return ret
+}
+
+func compareTokens(a, b []versionToken) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var c int
+		switch {
+		case i >= len(a):
+			c = -compareTokenToEnd(b[i])
+		case i >= len(b):
+			c = compareTokenToEnd(a[i])
+		default:
+			c = a[i].compare(b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareTokenToEnd compares a version having an extra component t with
+// the same version without it: 1.0.0 equals 1.0, 1.0.1 and 1.0.post1
+// come after 1.0, and 1.0rc1 comes before 1.0.
+func compareTokenToEnd(t versionToken) int {
+	switch {
+	case t.numeric:
+		return compareNumeric(t.value, "0")
+	case t.rank() == 2:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareNumeric compares two strings of digits of any length.
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if c := compareInts(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ossf/scorecard/v4/errors"
+)
+
+var _ VulnerabilitiesClient = &osvZipClient{}
+
+// osvZipClient matches packages against an OSV database export,
+// e.g. https://osv-vulnerabilities.storage.googleapis.com/<ecosystem>/all.zip,
+// without any network access.
+type osvZipClient struct {
+	// entries indexes the database by ecosystem and package name.
+	entries map[string][]*osvEntry
+}
+
+// NewOSVZipVulnerabilitiesClient returns a Vulnerabilities client reading the
+// OSV database from path, which is either a zip export or a directory of them.
+func NewOSVZipVulnerabilitiesClient(path string) (VulnerabilitiesClient, error) {
+	client := &osvZipClient{
+		entries: make(map[string][]*osvEntry),
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.Stat: %w", err)
+	}
+	if !info.IsDir() {
+		if err := client.load(path); err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".zip") {
+			return nil
+		}
+		return client.load(p)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("filepath.WalkDir: %w", err)
+	}
+	return client, nil
+}
+
+func (v *osvZipClient) load(path string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("zip.OpenReader: %w", err)
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		entry := &osvEntry{}
+		err = json.NewDecoder(rc).Decode(entry)
+		rc.Close()
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to decode %s", f.Name))
+		}
+		if entry.Withdrawn != "" {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, affected := range entry.Affected {
+			key := osvPackageKey(affected.Package.Ecosystem, affected.Package.Name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			v.entries[key] = append(v.entries[key], entry)
+		}
+	}
+	return nil
+}
+
+// HasUnfixedVulnerabilities implements VulnerabilityClient.HasUnfixedVulnerabilities.
+// Commit queries rely on the OSV API's analysis of git histories,
+// so they are not supported offline.
+func (v *osvZipClient) HasUnfixedVulnerabilities(ctx context.Context, commit string,
+) (VulnerabilitiesResponse, error) {
+	return VulnerabilitiesResponse{}, fmt.Errorf("%w: commit queries need the OSV API", ErrUnsupportedFeature)
+}
+
+// ListUnfixedVulnerabilities implements VulnerabilityClient.ListUnfixedVulnerabilities.
+func (v *osvZipClient) ListUnfixedVulnerabilities(ctx context.Context, packages []Package,
+) (VulnerabilitiesResponse, error) {
+	var ret VulnerabilitiesResponse
+	// Index of the vuln of each ID in ret.
+	seen := make(map[string]int)
+	for i := range packages {
+		p := packages[i]
+		if p.Version == "" {
+			continue
+		}
+		for _, entry := range v.entries[osvPackageKey(p.Ecosystem, p.Name)] {
+			if !entry.affects(p) {
+				continue
+			}
+			if j, ok := seen[entry.ID]; ok {
+				vuln := &ret.Vulnerabilities[j]
+				if !containsPackage(vuln.Packages(), p) {
+					vuln.OtherPackages = append(vuln.OtherPackages, p)
+				}
+				continue
+			}
+			seen[entry.ID] = len(ret.Vulnerabilities)
+			ret.Vulnerabilities = append(ret.Vulnerabilities, entry.vulnerability(&p))
+		}
+	}
+	return ret, nil
+}
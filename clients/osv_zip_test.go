@@ -0,0 +1,220 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		ecosystem string
+		a, b      string
+		want      int
+	}{
+		{ecosystem: EcosystemGo, a: "1.2.3", b: "v1.2.3", want: 0},
+		{ecosystem: EcosystemGo, a: "1.10.0", b: "1.9.0", want: 1},
+		{ecosystem: EcosystemGo, a: "0.0.0-20200101000000-abcdef012345", b: "0.0.0", want: -1},
+		{ecosystem: EcosystemNPM, a: "1.0.0-rc.1", b: "1.0.0-rc.2", want: -1},
+		{ecosystem: EcosystemNPM, a: "1.0.0-rc.10", b: "1.0.0-rc.2", want: 1},
+		{ecosystem: EcosystemNPM, a: "1.0.0-alpha", b: "1.0.0-1", want: 1},
+		{ecosystem: EcosystemCratesIO, a: "1.0.0+build", b: "1.0", want: 0},
+		{ecosystem: EcosystemPyPI, a: "1.0rc1", b: "1.0", want: -1},
+		{ecosystem: EcosystemPyPI, a: "1.0.post1", b: "1.0", want: 1},
+		{ecosystem: EcosystemPyPI, a: "1.0.post1", b: "1.0.1", want: -1},
+		{ecosystem: EcosystemPyPI, a: "1.0.dev1", b: "1.0a1", want: -1},
+		{ecosystem: EcosystemRubyGems, a: "1.0.0.pre", b: "1.0.0", want: -1},
+		{ecosystem: EcosystemRubyGems, a: "6.1.10", b: "6.1.9.1", want: 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			t.Parallel()
+			if got := compareVersions(tt.ecosystem, tt.a, tt.b); got != tt.want {
+				t.Errorf("compareVersions(%q, %q, %q) = %d, want %d", tt.ecosystem, tt.a, tt.b, got, tt.want)
+			}
+			if got := compareVersions(tt.ecosystem, tt.b, tt.a); got != -tt.want {
+				t.Errorf("compareVersions(%q, %q, %q) = %d, want %d", tt.ecosystem, tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
+
+func writeOSVZip(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("os.Create: %v", err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for name, content := range entries {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("zip.Create: %v", err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatalf("zip.Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("zip.Close: %v", err)
+	}
+}
+
+func TestOSVZipClient(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "PyPI"), 0o755); err != nil {
+		t.Fatalf("os.Mkdir: %v", err)
+	}
+	writeOSVZip(t, filepath.Join(dir, "PyPI", "all.zip"), map[string]string{
+		"PYSEC-1.json": `{
+			"id": "PYSEC-1",
+			"aliases": ["CVE-2021-1", "GHSA-1"],
+			"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N"}],
+			"affected": [{
+				"package": {"ecosystem": "PyPI", "name": "Django"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [
+					{"introduced": "3.0"}, {"fixed": "3.1.13"},
+					{"introduced": "3.2"}, {"fixed": "3.2.5"}
+				]}]
+			}]
+		}`,
+		"PYSEC-2.json": `{
+			"id": "PYSEC-2",
+			"withdrawn": "2022-01-01T00:00:00Z",
+			"affected": [{"package": {"ecosystem": "PyPI", "name": "django"}, "versions": ["3.2.1"]}]
+		}`,
+	})
+	if err := os.Mkdir(filepath.Join(dir, "Go"), 0o755); err != nil {
+		t.Fatalf("os.Mkdir: %v", err)
+	}
+	writeOSVZip(t, filepath.Join(dir, "Go", "all.zip"), map[string]string{
+		"GO-1.json": `{
+			"id": "GO-1",
+			"database_specific": {"severity": "MODERATE"},
+			"affected": [{
+				"package": {"ecosystem": "Go", "name": "golang.org/x/text"},
+				"ranges": [
+					{"type": "SEMVER", "events": [{"introduced": "0"}, {"last_affected": "0.3.6"}]},
+					{"type": "GIT", "repo": "https://go.googlesource.com/text", "events": [{"introduced": "0"}]}
+				]
+			}]
+		}`,
+		"GO-2.json": `{
+			"id": "GO-2",
+			"affected": [{"package": {"ecosystem": "Go", "name": "example.com/m"}, "versions": ["v1.0.0"]}]
+		}`,
+	})
+
+	tests := []struct {
+		name     string
+		packages []Package
+		want     []Vulnerability
+	}{
+		{
+			name: "affected by ranges",
+			packages: []Package{
+				{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.2.1"},
+				{Ecosystem: EcosystemGo, Name: "golang.org/x/text", Version: "0.3.6"},
+			},
+			want: []Vulnerability{
+				{
+					ID:           "PYSEC-1",
+					Aliases:      []string{"CVE-2021-1", "GHSA-1"},
+					Severity:     SeverityHigh,
+					CVSSScore:    7.5,
+					Package:      Package{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.2.1"},
+					FixedVersion: "3.2.5",
+				},
+				{
+					ID:       "GO-1",
+					Severity: SeverityMedium,
+					Package:  Package{Ecosystem: EcosystemGo, Name: "golang.org/x/text", Version: "0.3.6"},
+				},
+			},
+		},
+		{
+			name: "affected in several versions",
+			packages: []Package{
+				{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.2.1"},
+				{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.1.5"},
+				{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.2.1"},
+			},
+			want: []Vulnerability{
+				{
+					ID:            "PYSEC-1",
+					Aliases:       []string{"CVE-2021-1", "GHSA-1"},
+					Severity:      SeverityHigh,
+					CVSSScore:     7.5,
+					Package:       Package{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.2.1"},
+					OtherPackages: []Package{{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.1.5"}},
+					FixedVersion:  "3.2.5",
+				},
+			},
+		},
+		{
+			name: "fixed versions",
+			packages: []Package{
+				{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.1.13"},
+				{Ecosystem: EcosystemPyPI, Name: "django", Version: "3.2.5"},
+				{Ecosystem: EcosystemGo, Name: "golang.org/x/text", Version: "0.3.7"},
+			},
+		},
+		{
+			name: "affected by versions",
+			packages: []Package{
+				{Ecosystem: EcosystemGo, Name: "example.com/m", Version: "1.0.0"},
+				{Ecosystem: EcosystemNPM, Name: "example.com/m", Version: "1.0.0"},
+			},
+			want: []Vulnerability{
+				{
+					ID:      "GO-2",
+					Package: Package{Ecosystem: EcosystemGo, Name: "example.com/m", Version: "1.0.0"},
+				},
+			},
+		},
+	}
+
+	client, err := NewOSVZipVulnerabilitiesClient(dir)
+	if err != nil {
+		t.Fatalf("NewOSVZipVulnerabilitiesClient: %v", err)
+	}
+	if _, err := client.HasUnfixedVulnerabilities(context.Background(), "sha"); !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("HasUnfixedVulnerabilities() error = %v, want %v", err, ErrUnsupportedFeature)
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := client.ListUnfixedVulnerabilities(context.Background(), tt.packages)
+			if err != nil {
+				t.Fatalf("ListUnfixedVulnerabilities: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got.Vulnerabilities); diff != "" {
+				t.Errorf("ListUnfixedVulnerabilities() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
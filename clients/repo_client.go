@@ -16,6 +16,7 @@
 package clients
 
 import (
+	"context"
 	"errors"
 	"time"
 )
@@ -40,6 +41,7 @@ type RepoClient interface {
 	ListCommits() ([]Commit, error)
 	ListIssues() ([]Issue, error)
 	ListReleases() ([]Release, error)
+	ListTagProtectionRules() ([]TagProtectionRule, error)
 	ListContributors() ([]User, error)
 	ListSuccessfulWorkflowRuns(filename string) ([]WorkflowRun, error)
 	ListCheckRunsForRef(ref string) ([]CheckRun, error)
@@ -50,3 +52,14 @@ type RepoClient interface {
 	SearchCommits(request SearchCommitsOptions) ([]Commit, error)
 	Close() error
 }
+
+// ContextualRepoClient is a RepoClient able to make its requests with the context of a caller,
+// e.g. one tagged with the name of the check making them.
+type ContextualRepoClient interface {
+	RepoClient
+	// WithContext returns a view of the RepoClient making its requests with ctx.
+	// The view shares the state of the RepoClient. InitRepo may be called on the view, to set up
+	// the repo with ctx too, in which case the view is to be used in place of the RepoClient,
+	// e.g. to derive the other views from.
+	WithContext(ctx context.Context) RepoClient
+}
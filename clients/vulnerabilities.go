@@ -21,6 +21,24 @@ import (
 // VulnerabilitiesClient checks for vulnerabilities in vuln DB.
 type VulnerabilitiesClient interface {
 	HasUnfixedVulnerabilities(context context.Context, commit string) (VulnerabilitiesResponse, error)
+	ListUnfixedVulnerabilities(context context.Context, packages []Package) (VulnerabilitiesResponse, error)
+}
+
+// OSV ecosystems of the packages found in lockfiles.
+// See https://ossf.github.io/osv-schema/#affectedpackage-field.
+const (
+	EcosystemGo       = "Go"
+	EcosystemNPM      = "npm"
+	EcosystemCratesIO = "crates.io"
+	EcosystemPyPI     = "PyPI"
+	EcosystemRubyGems = "RubyGems"
+)
+
+// Package identifies a version of a package in an ecosystem.
+type Package struct {
+	Ecosystem string
+	Name      string
+	Version   string
 }
 
 // VulnerabilitiesResponse is the response from the vuln DB.
@@ -28,9 +46,36 @@ type VulnerabilitiesResponse struct {
 	Vulnerabilities []Vulnerability
 }
 
+// Severities of vulnerabilities, as per the CVSS v3 qualitative severity rating scale.
+const (
+	SeverityCritical = "CRITICAL"
+	SeverityHigh     = "HIGH"
+	SeverityMedium   = "MEDIUM"
+	SeverityLow      = "LOW"
+)
+
 // Vulnerability uniquely identifies a reported security vuln.
 type Vulnerability struct {
 	ID string
+	// Aliases are other IDs of the vuln, e.g. its CVE and GHSA IDs.
+	Aliases []string
+	// Severity is one of the Severity constants, or empty if unknown.
+	Severity string
+	// CVSSScore is the CVSS v3 base score, or 0 if unknown.
+	CVSSScore float64
+	// Package is the affected package. Its version is set
+	// for vulns found in the dependencies of the repo.
+	Package Package
+	// OtherPackages are the other dependencies of the repo affected
+	// by the vuln, e.g. other versions of Package.
+	OtherPackages []Package
+	// FixedVersion is the first version of the package fixing the vuln.
+	FixedVersion string
+}
+
+// Packages returns all the packages affected by the vuln.
+func (v *Vulnerability) Packages() []Package {
+	return append([]Package{v.Package}, v.OtherPackages...)
 }
 
 // DefaultVulnerabilitiesClient returns a new OSV Vulnerabilities client.
@@ -0,0 +1,233 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/dependencydiff"
+	docs "github.com/ossf/scorecard/v4/docs/checks"
+	sclog "github.com/ossf/scorecard/v4/log"
+	"github.com/ossf/scorecard/v4/options"
+	"github.com/ossf/scorecard/v4/pkg"
+	scpolicy "github.com/ossf/scorecard/v4/policy"
+)
+
+const (
+	depdiffFormatMarkdown = "markdown"
+	depdiffFormatJSON     = "json"
+)
+
+var (
+	errDependencyPolicy = errors.New("dependencies violate the policy")
+	errDepdiffArgs      = errors.New("invalid depdiff arguments")
+)
+
+type depdiffOptions struct {
+	repo       string
+	base       string
+	head       string
+	policyFile string
+	// scorecardPolicyFile is the Scorecard policy whose check settings apply to the dependencies.
+	scorecardPolicyFile string
+	scores              string
+	scoresCache         string
+	format              string
+	checks              []string
+	changeTypes         []string
+}
+
+func depdiffCmd(o *options.Options) *cobra.Command {
+	do := &depdiffOptions{}
+	cmd := &cobra.Command{
+		Use: `depdiff --base=<ref> --head=<ref> [--repo=<repo>] [--policy=<file>]
+	 [--checks=check1,...] [--change-types=added,...] [--scores=<dir or bucket>]
+	 [--scores-cache=<dir or bucket>] [--format=markdown|json]`,
+		Short: "Review the dependency changes between two revisions",
+		Long: `Review the dependencies added, updated and removed between two revisions, and their Scorecard results.
+The revisions are commits of --repo, or local checkouts without --repo. The dependency changes are read from
+the lockfiles of both revisions. The command exits with an error when dependencies violate the --policy.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if do.base == "" || do.head == "" {
+				return fmt.Errorf("%w: --base and --head are required", errDepdiffArgs)
+			}
+			if do.format != depdiffFormatMarkdown && do.format != depdiffFormatJSON {
+				return fmt.Errorf("%w: unsupported format %s", errDepdiffArgs, do.format)
+			}
+			cmd.SilenceUsage = true
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDepdiff(cmd.Context(), o, do, os.Stdout)
+		},
+	}
+	cmd.Flags().StringVar(&do.repo, "repo", "",
+		"repository of the revisions, e.g. github.com/ossf/scorecard. Without it, --base and --head are local directories")
+	cmd.Flags().StringVar(&do.base, "base", "", "base revision: a commit of --repo or a local directory")
+	cmd.Flags().StringVar(&do.head, "head", "", "head revision: a commit of --repo or a local directory")
+	cmd.Flags().StringVar(&do.policyFile, "policy", "", "dependency policy to enforce")
+	cmd.Flags().StringVar(&do.scorecardPolicyFile, "scorecard-policy", "",
+		"Scorecard policy whose check settings apply to the dependencies, e.g. the allowed licenses")
+	cmd.Flags().StringVar(&o.ContributorsMapping, options.FlagContributorsMapping, o.ContributorsMapping,
+		"YAML file mapping alternate company names and user identities for the Contributors check")
+	cmd.Flags().StringVar(&do.scores, "scores", "",
+		"directory or bucket of Scorecard results laid out like the cron job's API results bucket, "+
+			"to use before running the checks. It's only read")
+	cmd.Flags().StringVar(&do.scoresCache, "scores-cache", "",
+		"directory or bucket of its own where the results of the checks run are stored, "+
+			"merged with those already there, and read back by later runs")
+	cmd.Flags().StringVar(&do.format, "format", depdiffFormatMarkdown, "output format: markdown or json")
+	cmd.Flags().StringSliceVar(&do.checks, "checks", nil,
+		"checks to run on the dependencies, all by default. The checks of --policy are always run")
+	cmd.Flags().StringSliceVar(&do.changeTypes, "change-types", nil,
+		"change types of the dependencies to score: added, updated, removed. All by default")
+	return cmd
+}
+
+func runDepdiff(ctx context.Context, o *options.Options, do *depdiffOptions, w io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := sclog.NewLogger(sclog.ParseLevel(o.LogLevel))
+
+	policy := &dependencydiff.Policy{}
+	if do.policyFile != "" {
+		var err error
+		policy, err = dependencydiff.ParsePolicyFromFile(do.policyFile)
+		if err != nil {
+			return fmt.Errorf("ParsePolicyFromFile: %w", err)
+		}
+	}
+
+	// The checks the policy gates on run even when --checks leaves them out.
+	if len(do.checks) > 0 {
+		do.checks = withChecks(do.checks, policy.Checks())
+	}
+
+	pol, err := scpolicy.ParseFromFile(do.scorecardPolicyFile)
+	if err != nil {
+		return fmt.Errorf("readPolicy: %w", err)
+	}
+	settings, err := policySettings(o, pol)
+	if err != nil {
+		return err
+	}
+	var sources []dependencydiff.ScoreSource
+	if do.scores != "" {
+		stored, err := dependencydiff.NewStoredScoreSource(do.scores)
+		if err != nil {
+			return fmt.Errorf("NewStoredScoreSource: %w", err)
+		}
+		sources = append(sources, stored)
+	}
+	if do.scoresCache != "" {
+		cache, err := dependencydiff.NewScoreCache(do.scoresCache)
+		if err != nil {
+			return fmt.Errorf("NewScoreCache: %w", err)
+		}
+		sources = append(sources, cache)
+	}
+	sources = append(sources, dependencydiff.NewLiveScoreSourceWithSettings(settings))
+	scores := dependencydiff.NewCachingScoreSource(sources...)
+
+	var results []pkg.DependencyCheckResult
+	if do.repo == "" {
+		results, err = dependencydiff.GetDependencyDiffResultsFromDirs(
+			ctx, do.base, do.head, do.checks, do.changeTypes, scores)
+	} else {
+		results, err = depdiffFromRepo(ctx, logger, do, scores)
+	}
+	if err != nil {
+		return fmt.Errorf("dependency-diff: %w", err)
+	}
+
+	checkDocs, err := docs.Read()
+	if err != nil {
+		return fmt.Errorf("cannot read yaml file: %w", err)
+	}
+	evaluations, err := policy.Evaluate(results, checkDocs)
+	if err != nil {
+		return fmt.Errorf("Evaluate: %w", err)
+	}
+
+	switch do.format {
+	case depdiffFormatJSON:
+		for i := range results {
+			if err := results[i].AsJSON(w); err != nil {
+				return fmt.Errorf("AsJSON: %w", err)
+			}
+		}
+	default:
+		if err := dependencydiff.AsMarkdown(evaluations, w); err != nil {
+			return fmt.Errorf("AsMarkdown: %w", err)
+		}
+	}
+
+	if n := dependencydiff.Violations(evaluations); n > 0 {
+		return fmt.Errorf("%w: %d", errDependencyPolicy, n)
+	}
+	return nil
+}
+
+// withChecks returns the check names with the missing ones of extra appended.
+func withChecks(checkNames, extra []string) []string {
+	ret := append([]string(nil), checkNames...)
+	for _, name := range extra {
+		found := false
+		for _, n := range checkNames {
+			if strings.EqualFold(n, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+// depdiffFromRepo computes the dependency-diff between two commits of a repo.
+func depdiffFromRepo(ctx context.Context, logger *sclog.Logger, do *depdiffOptions,
+	scores dependencydiff.ScoreSource,
+) ([]pkg.DependencyCheckResult, error) {
+	repoClients := make([]clients.RepoClient, 0, 2)
+	for _, commit := range []string{do.base, do.head} {
+		repo, repoClient, ossFuzzClient, _, _, err := checker.GetClients(ctx, do.repo, "", logger)
+		if err != nil {
+			return nil, fmt.Errorf("GetClients: %w", err)
+		}
+		if ossFuzzClient != nil {
+			ossFuzzClient.Close()
+		}
+		if err := repoClient.InitRepo(repo, commit); err != nil {
+			return nil, fmt.Errorf("InitRepo: %w", err)
+		}
+		defer repoClient.Close()
+		repoClients = append(repoClients, repoClient)
+	}
+	//nolint:wrapcheck
+	return dependencydiff.GetDependencyDiffResultsFromClients(
+		ctx, repoClients[0], repoClients[1], do.checks, do.changeTypes, scores)
+}
@@ -66,6 +66,7 @@ func New(o *options.Options) *cobra.Command {
 
 	// Add sub-commands.
 	cmd.AddCommand(serveCmd(o))
+	cmd.AddCommand(depdiffCmd(o))
 	cmd.AddCommand(version.Version())
 	return cmd
 }
@@ -86,18 +87,28 @@ func rootCmd(o *options.Options) error {
 	if err != nil {
 		return fmt.Errorf("readPolicy: %w", err)
 	}
+	settings, err := policySettings(o, pol)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 	logger := sclog.NewLogger(sclog.ParseLevel(o.LogLevel))
-	repoURI, repoClient, ossFuzzRepoClient, ciiClient, vulnsClient, err := checker.GetClients(
-		ctx, o.Repo, o.Local, logger)
+	repoURI, repoClient, ossFuzzRepoClient, ciiClient, vulnsClient, err := checker.GetClientsWithCache(
+		ctx, o.Repo, o.Local, o.CacheDir, logger)
 	if err != nil {
-		return fmt.Errorf("GetClients: %w", err)
+		return fmt.Errorf("GetClientsWithCache: %w", err)
 	}
 	defer repoClient.Close()
 	if ossFuzzRepoClient != nil {
 		defer ossFuzzRepoClient.Close()
 	}
+	if o.OSVDatabase != "" {
+		vulnsClient, err = clients.NewOSVZipVulnerabilitiesClient(o.OSVDatabase)
+		if err != nil {
+			return fmt.Errorf("NewOSVZipVulnerabilitiesClient: %w", err)
+		}
+	}
 
 	// Read docs.
 	checkDocs, err := docs.Read()
@@ -123,7 +134,7 @@ func rootCmd(o *options.Options) error {
 		}
 	}
 
-	repoResult, err := pkg.RunScorecards(
+	repoResult, err := pkg.RunScorecardsWithOptions(
 		ctx,
 		repoURI,
 		o.Commit,
@@ -132,9 +143,10 @@ func rootCmd(o *options.Options) error {
 		ossFuzzRepoClient,
 		ciiClient,
 		vulnsClient,
+		pkg.RunOptions{Settings: settings, RecordUsage: o.ShowUsage},
 	)
 	if err != nil {
-		return fmt.Errorf("RunScorecards: %w", err)
+		return fmt.Errorf("RunScorecardsWithOptions: %w", err)
 	}
 	repoResult.Metadata = append(repoResult.Metadata, o.Metadata...)
 
@@ -168,3 +180,15 @@ func rootCmd(o *options.Options) error {
 	}
 	return nil
 }
+
+// policySettings returns the check-specific settings of the policy, along
+// with the contributors mapping of the options.
+func policySettings(o *options.Options, pol *policy.ScorecardPolicy) (*checker.PolicySettings, error) {
+	settings := policy.GetSettings(pol)
+	mapping, err := policy.ParseContributorsMappingFromFile(o.ContributorsMapping)
+	if err != nil {
+		return nil, fmt.Errorf("readContributorsMapping: %w", err)
+	}
+	settings.ContributorsMapping = mapping
+	return settings, nil
+}
@@ -29,11 +29,14 @@ import (
 	"github.com/ossf/scorecard/v4/log"
 	"github.com/ossf/scorecard/v4/options"
 	"github.com/ossf/scorecard/v4/pkg"
+	"github.com/ossf/scorecard/v4/policy"
+	"github.com/ossf/scorecard/v4/stats"
+	"github.com/ossf/scorecard/v4/stats/prometheus"
 )
 
 // TODO(cmd): Determine if this should be exported.
 func serveCmd(o *options.Options) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "serve",
 		Short: "Serve the scorecard program over http",
 		Long:  ``,
@@ -47,6 +50,17 @@ func serveCmd(o *options.Options) *cobra.Command {
 				panic(err)
 			}
 
+			pol, err := policy.ParseFromFile(o.PolicyFile)
+			if err != nil {
+				logger.Error(err, "reading the policy")
+				panic(err)
+			}
+			settings, err := policySettings(o, pol)
+			if err != nil {
+				logger.Error(err, "reading the policy settings")
+				panic(err)
+			}
+
 			http.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
 				repoParam := r.URL.Query().Get("repo")
 				const length = 3
@@ -69,9 +83,9 @@ func serveCmd(o *options.Options) *cobra.Command {
 				defer ossFuzzRepoClient.Close()
 				ciiClient := clients.DefaultCIIBestPracticesClient()
 				checksToRun := checks.GetAll()
-				repoResult, err := pkg.RunScorecards(
+				repoResult, err := pkg.RunScorecardsWithOptions(
 					ctx, repo, clients.HeadSHA /*commitSHA*/, checksToRun, repoClient,
-					ossFuzzRepoClient, ciiClient, vulnsClient)
+					ossFuzzRepoClient, ciiClient, vulnsClient, pkg.RunOptions{Settings: settings})
 				if err != nil {
 					logger.Error(err, "running enabled scorecard checks on repo")
 					rw.WriteHeader(http.StatusInternalServerError)
@@ -90,6 +104,20 @@ func serveCmd(o *options.Options) *cobra.Command {
 					logger.Error(err, "")
 				}
 			})
+			if o.ServeMetrics {
+				// Exposes the metrics for Prometheus on /metrics.
+				exporter, err := prometheus.NewExporter()
+				if err != nil {
+					logger.Error(err, "creating the metrics exporter")
+					panic(err)
+				}
+				http.Handle(prometheus.MetricsPath, exporter)
+				if err := stats.RegisterViews(); err != nil {
+					logger.Error(err, "registering the metric views")
+					panic(err)
+				}
+			}
+
 			port := os.Getenv("PORT")
 			if port == "" {
 				port = "8080"
@@ -104,6 +132,13 @@ func serveCmd(o *options.Options) *cobra.Command {
 			}
 		},
 	}
+	cmd.Flags().StringVar(&o.PolicyFile, options.FlagPolicyFile, o.PolicyFile,
+		"policy whose check settings apply, e.g. the allowed licenses")
+	cmd.Flags().StringVar(&o.ContributorsMapping, options.FlagContributorsMapping, o.ContributorsMapping,
+		"YAML file mapping alternate company names and user identities for the Contributors check")
+	cmd.Flags().BoolVar(&o.ServeMetrics, options.FlagServeMetrics, o.ServeMetrics,
+		"serve the metrics for Prometheus on "+prometheus.MetricsPath)
+	return cmd
 }
 
 const tpl = `
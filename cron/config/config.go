@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -36,6 +37,12 @@ const (
 	ShardNumFilename string = ".shard_num"
 	// TransferStatusFilename file identifies if shard transfer to BigQuery is completed.
 	TransferStatusFilename string = ".transfer_complete"
+	// FailureLedgerPrefix is the prefix of the files listing the repos of a shard which failed, and why.
+	FailureLedgerPrefix string = "failures-"
+	// DeadLetterPrefix is the prefix of the files listing the repos of a shard which failed permanently.
+	DeadLetterPrefix string = "dead-letter-"
+	// UsageReportPrefix is the prefix of the files reporting the resources used by the checks of a shard.
+	UsageReportPrefix string = "usage-"
 
 	configFlag        string = "config"
 	configDefault     string = ""
@@ -56,6 +63,7 @@ const (
 	apiResultsBucketURL     string = "SCORECARD_API_RESULTS_BUCKET_URL"
 	inputBucketURL          string = "SCORECARD_INPUT_BUCKET_URL"
 	inputBucketPrefix       string = "SCORECARD_INPUT_BUCKET_PREFIX"
+	resultsSinkURL          string = "SCORECARD_RESULTS_SINK_URL"
 )
 
 var (
@@ -85,6 +93,7 @@ type config struct {
 	ShardSize               int                          `yaml:"shard-size"`
 	InputBucketURL          string                       `yaml:"input-bucket-url"`
 	InputBucketPrefix       string                       `yaml:"input-bucket-prefix"`
+	ResultsSinkURL          string                       `yaml:"results-sink-url"`
 	AdditionalParams        map[string]map[string]string `yaml:"additional-params"`
 }
 
@@ -251,6 +260,12 @@ func GetBigQueryTable() (string, error) {
 	return getStringConfigValue(bigqueryTable, configYAML, "BigQueryTable", "bigquery-table")
 }
 
+// GetResultsSinkURL returns the URL of the SQL database to transfer cron job results to,
+// e.g. sqlite:///var/lib/scorecard/results.db or postgres://user@host/db.
+func GetResultsSinkURL() (string, error) {
+	return getStringConfigValue(resultsSinkURL, configYAML, "ResultsSinkURL", "results-sink-url")
+}
+
 // GetCompletionThreshold returns fraction of shards to be populated before transferring cron job results.
 func GetCompletionThreshold() (float64, error) {
 	return getFloat64ConfigValue(completionThreshold, configYAML, "CompletionThreshold", "completion-threshold")
@@ -291,6 +306,74 @@ func GetBlacklistedChecks() ([]string, error) {
 	return strings.Split(checks, ","), err
 }
 
+// GetRepoConcurrency returns the number of repos of a shard the worker scores concurrently, 1 if unset.
+func GetRepoConcurrency() (int, error) {
+	value, err := getScorecardParam("repo-concurrency")
+	if err != nil || value == "" {
+		return 1, err
+	}
+	concurrency, err := strconv.Atoi(value)
+	if err != nil || concurrency < 1 {
+		return 0, fmt.Errorf("%w: repo-concurrency %q", ErrorValueConversion, value)
+	}
+	return concurrency, nil
+}
+
+// GetRepoTimeout returns the maximum duration of the scoring of a repo by the worker, 0 if unlimited.
+func GetRepoTimeout() (time.Duration, error) {
+	value, err := getScorecardParam("repo-timeout")
+	if err != nil || value == "" {
+		return 0, err
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("%w: repo-timeout %q", ErrorValueConversion, value)
+	}
+	return timeout, nil
+}
+
+// GetRepoMaxAttempts returns the number of times the worker scores a repo failing with
+// transient errors before dead-lettering it, 3 if unset.
+func GetRepoMaxAttempts() (int, error) {
+	value, err := getScorecardParam("repo-max-attempts")
+	if err != nil || value == "" {
+		return 3, err
+	}
+	attempts, err := strconv.Atoi(value)
+	if err != nil || attempts < 1 {
+		return 0, fmt.Errorf("%w: repo-max-attempts %q", ErrorValueConversion, value)
+	}
+	return attempts, nil
+}
+
+// GetIncrementalScans returns whether the worker copies forward the results of the checks
+// of the repos whose HEAD didn't change since their last scan, false if unset.
+func GetIncrementalScans() (bool, error) {
+	value, err := getScorecardParam("incremental-scans")
+	if err != nil || value == "" {
+		return false, err
+	}
+	incremental, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: incremental-scans %q", ErrorValueConversion, value)
+	}
+	return incremental, nil
+}
+
+// GetResourceUsage returns whether the worker records the usage of resources by the checks
+// and reports it per shard, false if unset.
+func GetResourceUsage() (bool, error) {
+	value, err := getScorecardParam("resource-usage")
+	if err != nil || value == "" {
+		return false, err
+	}
+	resourceUsage, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: resource-usage %q", ErrorValueConversion, value)
+	}
+	return resourceUsage, nil
+}
+
 // GetMetricExporter returns the opencensus exporter type.
 func GetMetricExporter() (string, error) {
 	return getStringConfigValue(metricExporter, configYAML, "MetricExporter", "metric-exporter")
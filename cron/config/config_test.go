@@ -19,6 +19,7 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -45,6 +46,11 @@ const (
 	prodInputBucketURL        = "gs://ossf-scorecard-input-projects"
 	prodInputBucketPrefix     = ""
 	prodInputBucketPrefixFile = ""
+	prodRepoConcurrency       = "4"
+	prodIncrementalScans      = "true"
+	prodRepoMaxAttempts       = "3"
+	prodRepoTimeout           = "30m"
+	prodResourceUsage         = "false"
 )
 
 var (
@@ -57,8 +63,13 @@ var (
 		"api-results-bucket-url":     prodAPIBucketURL,
 		"blacklisted-checks":         prodBlacklistedChecks,
 		"cii-data-bucket-url":        prodCIIDataBucket,
+		"incremental-scans":          prodIncrementalScans,
 		"raw-bigquery-table":         prodRawBigQueryTable,
 		"raw-result-data-bucket-url": prodRawBucket,
+		"repo-concurrency":           prodRepoConcurrency,
+		"repo-max-attempts":          prodRepoMaxAttempts,
+		"repo-timeout":               prodRepoTimeout,
+		"resource-usage":             prodResourceUsage,
 	}
 	prodAdditionalParams = map[string]map[string]string{
 		"input-bucket": prodInputBucketParams,
@@ -111,6 +122,38 @@ func TestYAMLParsing(t *testing.T) {
 				AdditionalParams:        prodAdditionalParams,
 			},
 		},
+		{
+			name:     "local profile",
+			filename: "local.yaml",
+			expectedConfig: config{
+				ProjectID:              "local",
+				ResultDataBucketURL:    "file:///var/lib/scorecard/data",
+				RequestTopicURL:        "mem://scorecard-batch-requests",
+				RequestSubscriptionURL: "mem://scorecard-batch-requests",
+				CompletionThreshold:    1,
+				ShardSize:              10,
+				MetricExporter:         "printer",
+				ResultsSinkURL:         "sqlite:///var/lib/scorecard/results.db",
+				AdditionalParams: map[string]map[string]string{
+					"input-bucket": {
+						"url":         "file:///var/lib/scorecard/input",
+						"prefix":      "",
+						"prefix-file": "",
+					},
+					"scorecard": {
+						"api-results-bucket-url":     "file:///var/lib/scorecard/api",
+						"blacklisted-checks":         prodBlacklistedChecks,
+						"cii-data-bucket-url":        "file:///var/lib/scorecard/cii",
+						"incremental-scans":          prodIncrementalScans,
+						"raw-result-data-bucket-url": "file:///var/lib/scorecard/raw",
+						"repo-concurrency":           prodRepoConcurrency,
+						"repo-max-attempts":          prodRepoMaxAttempts,
+						"repo-timeout":               prodRepoTimeout,
+						"resource-usage":             prodResourceUsage,
+					},
+				},
+			},
+		},
 		{
 			name:     "basic",
 			filename: "testdata/basic.yaml",
@@ -532,3 +575,208 @@ func TestGetAdditionalParams(t *testing.T) {
 		})
 	}
 }
+
+//nolint:paralleltest // Since t.Setenv is used.
+func TestGetRepoConcurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVal  string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "config value",
+			want: 4,
+		},
+		{
+			name:   "env value",
+			envVal: "8",
+			want:   8,
+		},
+		{
+			name:    "zero",
+			envVal:  "0",
+			wantErr: true,
+		},
+		{
+			name:    "not a number",
+			envVal:  "many",
+			wantErr: true,
+		},
+	}
+	for _, testcase := range tests {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			if testcase.envVal != "" {
+				t.Setenv("SCORECARD_REPO_CONCURRENCY", testcase.envVal)
+			}
+			got, err := GetRepoConcurrency()
+			if (err != nil) != testcase.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != testcase.want {
+				t.Errorf("test failed: expected - %d, got = %d", testcase.want, got)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // Since t.Setenv is used.
+func TestGetRepoMaxAttempts(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVal  string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "config value",
+			want: 3,
+		},
+		{
+			name:   "env value",
+			envVal: "5",
+			want:   5,
+		},
+		{
+			name:    "no attempt",
+			envVal:  "0",
+			wantErr: true,
+		},
+	}
+	for _, testcase := range tests {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			if testcase.envVal != "" {
+				t.Setenv("SCORECARD_REPO_MAX_ATTEMPTS", testcase.envVal)
+			}
+			got, err := GetRepoMaxAttempts()
+			if (err != nil) != testcase.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != testcase.want {
+				t.Errorf("test failed: expected - %d, got = %d", testcase.want, got)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // Since t.Setenv is used.
+func TestGetIncrementalScans(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVal  string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "config value",
+			want: true,
+		},
+		{
+			name:   "env value",
+			envVal: "false",
+			want:   false,
+		},
+		{
+			name:    "not a bool",
+			envVal:  "sometimes",
+			wantErr: true,
+		},
+	}
+	for _, testcase := range tests {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			if testcase.envVal != "" {
+				t.Setenv("SCORECARD_INCREMENTAL_SCANS", testcase.envVal)
+			}
+			got, err := GetIncrementalScans()
+			if (err != nil) != testcase.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != testcase.want {
+				t.Errorf("test failed: expected - %t, got = %t", testcase.want, got)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // Since t.Setenv is used.
+func TestGetResourceUsage(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVal  string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "config value",
+			want: false,
+		},
+		{
+			name:   "env value",
+			envVal: "true",
+			want:   true,
+		},
+		{
+			name:    "not a bool",
+			envVal:  "sometimes",
+			wantErr: true,
+		},
+	}
+	for _, testcase := range tests {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			if testcase.envVal != "" {
+				t.Setenv("SCORECARD_RESOURCE_USAGE", testcase.envVal)
+			}
+			got, err := GetResourceUsage()
+			if (err != nil) != testcase.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != testcase.want {
+				t.Errorf("test failed: expected - %t, got = %t", testcase.want, got)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // Since t.Setenv is used.
+func TestGetRepoTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVal  string
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "config value",
+			want: 30 * time.Minute,
+		},
+		{
+			name:   "env value",
+			envVal: "90s",
+			want:   90 * time.Second,
+		},
+		{
+			name:    "invalid duration",
+			envVal:  "long",
+			wantErr: true,
+		},
+	}
+	for _, testcase := range tests {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			if testcase.envVal != "" {
+				t.Setenv("SCORECARD_REPO_TIMEOUT", testcase.envVal)
+			}
+			got, err := GetRepoTimeout()
+			if (err != nil) != testcase.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != testcase.want {
+				t.Errorf("test failed: expected - %s, got = %s", testcase.want, got)
+			}
+		})
+	}
+}
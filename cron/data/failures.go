@@ -0,0 +1,61 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Failure is an entry of the failure ledger of a shard, about a repo which failed to be scored at least once.
+type Failure struct {
+	Repo string `json:"repo"`
+	// Class is the name of the sentinel error of the last failure, see errors.GetName.
+	Class    string `json:"class"`
+	Error    string `json:"error"`
+	Attempts int    `json:"attempts"`
+	// Dead is true for the repos which failed permanently, which are also listed in the dead-letter file.
+	Dead bool `json:"dead"`
+}
+
+// WriteFailures writes the failures to `out` as JSON lines.
+func WriteFailures(out io.Writer, failures []Failure) error {
+	encoder := json.NewEncoder(out)
+	for i := range failures {
+		if err := encoder.Encode(&failures[i]); err != nil {
+			return fmt.Errorf("error during Encode: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadFailures reads the failures written by WriteFailures.
+func ReadFailures(in io.Reader) ([]Failure, error) {
+	var failures []Failure
+	decoder := json.NewDecoder(in)
+	for {
+		var failure Failure
+		err := decoder.Decode(&failure)
+		if errors.Is(err, io.EOF) {
+			return failures, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error during Decode: %w", err)
+		}
+		failures = append(failures, failure)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFailures(t *testing.T) {
+	t.Parallel()
+	//nolint:govet
+	tests := []struct {
+		name     string
+		failures []Failure
+	}{
+		{
+			name: "no failure",
+		},
+		{
+			name: "failures",
+			failures: []Failure{
+				{Repo: "github.com/owner/gone", Class: "ErrRepoUnreachable", Error: "repo unreachable", Attempts: 1, Dead: true},
+				{Repo: "github.com/owner/flaky", Class: "ErrScorecardInternal", Error: "internal error", Attempts: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			if err := WriteFailures(&buf, tt.failures); err != nil {
+				t.Fatalf("WriteFailures: %v", err)
+			}
+			if got := strings.Count(buf.String(), "\n"); got != len(tt.failures) {
+				t.Errorf("wrote %d lines, want %d", got, len(tt.failures))
+			}
+			got, err := ReadFailures(&buf)
+			if err != nil {
+				t.Fatalf("ReadFailures: %v", err)
+			}
+			if diff := cmp.Diff(tt.failures, got); diff != "" {
+				t.Errorf("failures (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestReadFailures_invalid(t *testing.T) {
+	t.Parallel()
+	if _, err := ReadFailures(strings.NewReader(`{"repo": `)); err == nil {
+		t.Error("ReadFailures of a truncated ledger succeeded")
+	}
+}
@@ -15,6 +15,7 @@
 package data
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
@@ -32,6 +33,7 @@ type ShardSummary struct {
 	shardMetadata  []byte
 	shardsExpected int
 	shardsCreated  int
+	deadRepos      int
 	isTransferred  bool
 }
 
@@ -50,6 +52,18 @@ func (s *ShardSummary) IsTransferred() bool {
 	return s.isTransferred
 }
 
+// DeadRepos returns the number of repos listed in the dead-letter files of the shards. These repos
+// failed permanently: they have no results, but don't keep their shards from being completed.
+func (s *ShardSummary) DeadRepos() int {
+	return s.deadRepos
+}
+
+// Completion describes the progress of the shards, for logging.
+func (s *ShardSummary) Completion() string {
+	return fmt.Sprintf("%d/%d shards completed, %d dead-lettered repos excluded",
+		s.shardsCreated, s.shardsExpected, s.deadRepos)
+}
+
 // Metadata returns the raw metadata about the bucket.
 func (s *ShardSummary) Metadata() []byte {
 	return s.shardMetadata
@@ -112,6 +126,21 @@ func GetBucketSummary(ctx context.Context, bucketURL string) (*BucketSummary, er
 		switch {
 		case strings.HasPrefix(filename, "shard-"):
 			summary.getOrCreate(creationTime).shardsCreated++
+		case strings.HasPrefix(filename, config.FailureLedgerPrefix):
+			// The failures of dead-lettered repos are counted from the dead-letter files.
+			summary.getOrCreate(creationTime)
+		case strings.HasPrefix(filename, config.UsageReportPrefix):
+			summary.getOrCreate(creationTime)
+		case strings.HasPrefix(filename, config.DeadLetterPrefix):
+			keyData, err := GetBlobContent(ctx, bucketURL, key)
+			if err != nil {
+				return nil, fmt.Errorf("error during GetBlobContent: %w", err)
+			}
+			deadRepos, err := countRepos(keyData)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing dead-letter file %s: %w", key, err)
+			}
+			summary.getOrCreate(creationTime).deadRepos += deadRepos
 		case filename == config.TransferStatusFilename:
 			summary.getOrCreate(creationTime).isTransferred = true
 		case filename == config.ShardMetadataFilename:
@@ -132,3 +161,18 @@ func GetBucketSummary(ctx context.Context, bucketURL string) (*BucketSummary, er
 	}
 	return &summary, nil
 }
+
+func countRepos(content []byte) (int, error) {
+	iter, err := MakeIteratorFrom(bytes.NewReader(content))
+	if err != nil {
+		return 0, fmt.Errorf("error during MakeIteratorFrom: %w", err)
+	}
+	var n int
+	for iter.HasNext() {
+		if _, err := iter.Next(); err != nil {
+			return 0, fmt.Errorf("error during iter.Next: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
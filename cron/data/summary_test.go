@@ -98,6 +98,7 @@ func TestGetBucketSummary(t *testing.T) {
 						shardMetadata:  []byte(`{"shardLoc":"test","numShard":5,"commitSha":"2231d1f722454c6c9aa6ad77377d2936803216ff"}`),
 						shardsExpected: 5,
 						shardsCreated:  3,
+						deadRepos:      2,
 						isTransferred:  false,
 					},
 				},
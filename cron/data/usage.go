@@ -0,0 +1,61 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CheckUsage is an entry of the usage report of a shard, about the resources used by a check
+// over all the runs of the check on the repos of the shard.
+type CheckUsage struct {
+	Requests        map[string]int64 `json:"requests"`
+	Check           string           `json:"check"`
+	Runs            int              `json:"runs"`
+	WallTimeSeconds float64          `json:"wallTimeSeconds"`
+	BytesDownloaded int64            `json:"bytesDownloaded"`
+	CacheHits       int64            `json:"cacheHits"`
+}
+
+// WriteUsage writes the usage of the checks to `out` as JSON lines.
+func WriteUsage(out io.Writer, usage []CheckUsage) error {
+	encoder := json.NewEncoder(out)
+	for i := range usage {
+		if err := encoder.Encode(&usage[i]); err != nil {
+			return fmt.Errorf("error during Encode: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadUsage reads the usage of the checks written by WriteUsage.
+func ReadUsage(in io.Reader) ([]CheckUsage, error) {
+	var usage []CheckUsage
+	decoder := json.NewDecoder(in)
+	for {
+		var checkUsage CheckUsage
+		err := decoder.Decode(&checkUsage)
+		if errors.Is(err, io.EOF) {
+			return usage, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error during Decode: %w", err)
+		}
+		usage = append(usage, checkUsage)
+	}
+}
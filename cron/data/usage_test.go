@@ -0,0 +1,81 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		usage []CheckUsage
+	}{
+		{
+			name: "no usage",
+		},
+		{
+			name: "usage",
+			usage: []CheckUsage{
+				{
+					Check:           "Code-Review",
+					Runs:            2,
+					WallTimeSeconds: 3.5,
+					Requests:        map[string]int64{"graphql": 2, "rest": 40},
+					CacheHits:       10,
+				},
+				{
+					Check:           "Pinned-Dependencies",
+					Runs:            2,
+					WallTimeSeconds: 12.25,
+					Requests:        map[string]int64{"tarball": 2},
+					BytesDownloaded: 1 << 20,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			if err := WriteUsage(&buf, tt.usage); err != nil {
+				t.Fatalf("WriteUsage: %v", err)
+			}
+			if got := strings.Count(buf.String(), "\n"); got != len(tt.usage) {
+				t.Errorf("wrote %d lines, want %d", got, len(tt.usage))
+			}
+			got, err := ReadUsage(&buf)
+			if err != nil {
+				t.Fatalf("ReadUsage: %v", err)
+			}
+			if diff := cmp.Diff(tt.usage, got); diff != "" {
+				t.Errorf("usage (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestReadUsage_invalid(t *testing.T) {
+	t.Parallel()
+	if _, err := ReadUsage(strings.NewReader(`{"check": `)); err == nil {
+		t.Error("ReadUsage of a truncated report succeeded")
+	}
+}
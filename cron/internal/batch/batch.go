@@ -0,0 +1,149 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package batch splits the repos to scan in shards, publishes them to
+// the workers and writes the metadata of the shards.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/cron/internal/pubsub"
+)
+
+var headSHA = clients.HeadSHA
+
+// PublishRequests publishes the repos of iter in batch requests of shardSize repos
+// and closes the publisher. It returns the number of shards published.
+func PublishRequests(iter data.Iterator, topicPublisher pubsub.Publisher,
+	shardSize int, datetime time.Time,
+) (int32, error) {
+	var shardNum int32
+	request := data.ScorecardBatchRequest{
+		JobTime:  timestamppb.New(datetime),
+		ShardNum: &shardNum,
+	}
+
+	// Create and send batch requests of repoURLs of size `ShardSize`:
+	// * Iterate through incoming repoURLs until `request` has len(Repos) of size `ShardSize`.
+	// * Publish request to PubSub topic.
+	// * Clear request.Repos and increment shardNum.
+	for iter.HasNext() {
+		repoURL, err := iter.Next()
+		if err != nil {
+			return shardNum, fmt.Errorf("error reading repoURL: %w", err)
+		}
+		request.Repos = append(request.GetRepos(), &data.Repo{
+			Url: &repoURL.Repo,
+			// TODO(controller): pass in non-HEAD commitSHA here.
+			Commit:   &headSHA,
+			Metadata: repoURL.Metadata.ToString(),
+		})
+		if len(request.GetRepos()) < shardSize {
+			continue
+		}
+		if err := topicPublisher.Publish(&request); err != nil {
+			return shardNum, fmt.Errorf("error running topicPublisher.Publish: %w", err)
+		}
+		request.Repos = nil
+		shardNum++
+	}
+	// Check if more repoURLs are pending to be sent in `request`.
+	if len(request.GetRepos()) > 0 {
+		if err := topicPublisher.Publish(&request); err != nil {
+			return shardNum, fmt.Errorf("error running topicPublisher.Publish: %w", err)
+		}
+		shardNum++
+	}
+
+	if err := topicPublisher.Close(); err != nil {
+		return shardNum, fmt.Errorf("error running topicPublisher.Close: %w", err)
+	}
+	return shardNum, nil
+}
+
+// WriteShardMetadata writes the `.shard_metadata` file of the job to each of the buckets.
+// The workers' results are complete once all numShard shards are in a bucket.
+func WriteShardMetadata(ctx context.Context, numShard int32, datetime time.Time,
+	commitSHA string, buckets ...string,
+) error {
+	metadata := data.ShardMetadata{
+		NumShard:  &numShard,
+		ShardLoc:  new(string),
+		CommitSha: &commitSHA,
+	}
+	for _, bucket := range buckets {
+		*metadata.ShardLoc = bucket + "/" + data.GetBlobFilename("", datetime)
+		metadataJSON, err := protojson.Marshal(&metadata)
+		if err != nil {
+			return fmt.Errorf("error during protojson.Marshal: %w", err)
+		}
+		err = data.WriteToBlobStore(ctx, bucket, data.GetShardMetadataFilename(datetime), metadataJSON)
+		if err != nil {
+			return fmt.Errorf("error writing to BlobStore: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReportPendingJobs logs the completion of the jobs of the bucket which weren't transferred yet,
+// e.g. of the previous job when its shards are still being processed.
+func ReportPendingJobs(ctx context.Context, bucketURL string) error {
+	summary, err := data.GetBucketSummary(ctx, bucketURL)
+	if err != nil {
+		return fmt.Errorf("data.GetBucketSummary: %w", err)
+	}
+	shards := summary.Shards()
+	sort.Slice(shards, func(i, j int) bool {
+		return shards[i].CreationTime().Before(shards[j].CreationTime())
+	})
+	for _, s := range shards {
+		if s.IsTransferred() {
+			continue
+		}
+		log.Printf("Job %s not transferred yet: %s", s.CreationTime().Format(time.RFC3339), s.Completion())
+	}
+	return nil
+}
+
+// LocalFiles returns an iterator over the repos of the input files.
+func LocalFiles(filenames []string) (data.Iterator, error) {
+	var iters []data.Iterator
+	for _, filename := range filenames {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open input file: %w", err)
+		}
+		i, err := data.MakeIteratorFrom(f)
+		if err != nil {
+			return nil, fmt.Errorf("data.MakeIteratorFrom: %w", err)
+		}
+		iters = append(iters, i)
+	}
+	iter, err := data.MakeNestedIterator(iters)
+	if err != nil {
+		return nil, fmt.Errorf("data.MakeNestedIterator: %w", err)
+	}
+	return iter, nil
+}
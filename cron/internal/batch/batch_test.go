@@ -0,0 +1,99 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossf/scorecard/v4/cron/data"
+)
+
+type fakePublisher struct {
+	shards []int
+	closed bool
+}
+
+func (p *fakePublisher) Publish(request *data.ScorecardBatchRequest) error {
+	p.shards = append(p.shards, len(request.GetRepos()))
+	return nil
+}
+
+func (p *fakePublisher) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestPublishRequests(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		input        string
+		shardSize    int
+		wantNumShard int32
+		wantShards   []int
+	}{
+		{
+			name:         "no repos",
+			input:        "repo,metadata\n",
+			shardSize:    2,
+			wantNumShard: 0,
+		},
+		{
+			name:         "full shards",
+			input:        "repo,metadata\ngithub.com/o/r1,\ngithub.com/o/r2,\ngithub.com/o/r3,\ngithub.com/o/r4,\n",
+			shardSize:    2,
+			wantNumShard: 2,
+			wantShards:   []int{2, 2},
+		},
+		{
+			name:         "partial last shard",
+			input:        "repo,metadata\ngithub.com/o/r1,\ngithub.com/o/r2,\ngithub.com/o/r3,\n",
+			shardSize:    2,
+			wantNumShard: 2,
+			wantShards:   []int{2, 1},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			iter, err := data.MakeIteratorFrom(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("data.MakeIteratorFrom: %v", err)
+			}
+			publisher := &fakePublisher{}
+			numShard, err := PublishRequests(iter, publisher, tt.shardSize, time.Now())
+			if err != nil {
+				t.Fatalf("PublishRequests: %v", err)
+			}
+			if numShard != tt.wantNumShard {
+				t.Errorf("numShard = %d, want %d", numShard, tt.wantNumShard)
+			}
+			if len(publisher.shards) != len(tt.wantShards) {
+				t.Fatalf("published shards %v, want %v", publisher.shards, tt.wantShards)
+			}
+			for i := range tt.wantShards {
+				if publisher.shards[i] != tt.wantShards[i] {
+					t.Errorf("published shards %v, want %v", publisher.shards, tt.wantShards)
+				}
+			}
+			if !publisher.closed {
+				t.Error("publisher wasn't closed")
+			}
+		})
+	}
+}
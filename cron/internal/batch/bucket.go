@@ -12,8 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package main implements the PubSub controller.
-package main
+package batch
 
 import (
 	"bytes"
@@ -55,7 +54,8 @@ func getPrefix(ctx context.Context, bucket string) (string, error) {
 	return strings.TrimSpace(s), nil
 }
 
-func bucketFiles(ctx context.Context) (data.Iterator, error) {
+// BucketFiles returns an iterator over the repos of the input files of the input bucket.
+func BucketFiles(ctx context.Context) (data.Iterator, error) {
 	var iters []data.Iterator
 
 	bucket, err := config.GetInputBucketURL()
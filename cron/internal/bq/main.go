@@ -33,9 +33,14 @@ func transferDataToBq(ctx context.Context,
 	summary *data.BucketSummary,
 ) error {
 	for _, shards := range summary.Shards() {
-		if shards.IsTransferred() || !shards.IsCompleted(completionThreshold) {
+		if shards.IsTransferred() {
 			continue
 		}
+		if !shards.IsCompleted(completionThreshold) {
+			log.Printf("Skipping incomplete shards of %s: %s", shards.CreationTime(), shards.Completion())
+			continue
+		}
+		log.Printf("Transferring shards of %s: %s", shards.CreationTime(), shards.Completion())
 
 		shardFileURI := data.GetBlobFilename("shard-*", shards.CreationTime())
 		if err := startDataTransferJob(ctx,
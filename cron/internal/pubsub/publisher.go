@@ -26,6 +26,8 @@ import (
 	"gocloud.dev/pubsub"
 	// Needed to link in GCP drivers.
 	_ "gocloud.dev/pubsub/gcppubsub"
+	// Needed to link in the in-process driver of the local profile.
+	_ "gocloud.dev/pubsub/mempubsub"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/ossf/scorecard/v4/cron/data"
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"google.golang.org/protobuf/encoding/protojson"
 
@@ -36,9 +37,13 @@ type Subscriber interface {
 }
 
 // CreateSubscriber returns an implementation of Subscriber interface.
-// Currently returns an instance of gcsSubscriber.
+// Returns an instance of gcsSubscriber for GCP subscriptions, and of gocloudSubscriber
+// for the other gocloud URLs, e.g. mem:// subscriptions.
 func CreateSubscriber(ctx context.Context, subscriptionURL string) (Subscriber, error) {
-	return createGCSSubscriber(ctx, subscriptionURL)
+	if strings.HasPrefix(subscriptionURL, gcpPubsubPrefix) {
+		return createGCSSubscriber(ctx, subscriptionURL)
+	}
+	return createGocloudSubscriber(ctx, subscriptionURL)
 }
 
 func parseJSONToRequest(jsonData []byte) (*data.ScorecardBatchRequest, error) {
@@ -22,6 +22,8 @@ import (
 	"gocloud.dev/pubsub"
 	// Needed to link in GCP drivers.
 	_ "gocloud.dev/pubsub/gcppubsub"
+	// Needed to link in the in-process driver of the local profile.
+	_ "gocloud.dev/pubsub/mempubsub"
 
 	"github.com/ossf/scorecard/v4/cron/data"
 )
@@ -37,7 +39,6 @@ type gocloudSubscriber struct {
 	msg          *pubsub.Message
 }
 
-//nolint:unused,deadcode
 func createGocloudSubscriber(ctx context.Context, subscriptionURL string) (*gocloudSubscriber, error) {
 	subscription, err := pubsub.OpenSubscription(ctx, subscriptionURL)
 	if err != nil {
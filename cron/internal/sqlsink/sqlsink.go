@@ -0,0 +1,276 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqlsink transfers the results of the cron jobs to a PostgreSQL or SQLite database,
+// in place of BigQuery for self-hosted deployments.
+package sqlsink
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	// Needed to link in the PostgreSQL driver.
+	_ "github.com/lib/pq"
+	// Needed to link in the SQLite driver, which requires cgo.
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/ossf/scorecard/v4/cron/data"
+)
+
+const (
+	sqlitePrefix = "sqlite://"
+	// The drivers of lib/pq and mattn/go-sqlite3.
+	postgresDriver = "postgres"
+	sqliteDriver   = "sqlite3"
+)
+
+var errUnsupportedSink = errors.New("unsupported results sink URL")
+
+// The tables are created if missing. Their types are common to PostgreSQL and SQLite.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS scorecard_results (
+		job_time TIMESTAMP NOT NULL,
+		repo_name TEXT NOT NULL,
+		commit_sha TEXT NOT NULL,
+		scan_date TEXT NOT NULL,
+		score DOUBLE PRECISION NOT NULL,
+		scorecard_version TEXT NOT NULL,
+		scorecard_commit TEXT NOT NULL,
+		metadata TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS scorecard_checks (
+		job_time TIMESTAMP NOT NULL,
+		repo_name TEXT NOT NULL,
+		name TEXT NOT NULL,
+		score INTEGER NOT NULL,
+		reason TEXT NOT NULL,
+		details TEXT NOT NULL,
+		documentation_url TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS scorecard_results_repo ON scorecard_results (repo_name, job_time)`,
+	`CREATE INDEX IF NOT EXISTS scorecard_checks_repo ON scorecard_checks (repo_name, job_time)`,
+}
+
+// result is a line of the JSON shards written by the worker, see cron/internal/format.
+type result struct {
+	Date string `json:"date"`
+	Repo struct {
+		Name   string `json:"name"`
+		Commit string `json:"commit"`
+	} `json:"repo"`
+	Scorecard struct {
+		Version string `json:"version"`
+		Commit  string `json:"commit"`
+	} `json:"scorecard"`
+	Score    float64  `json:"score"`
+	Checks   []check  `json:"checks"`
+	Metadata []string `json:"metadata"`
+}
+
+type check struct {
+	Details       []string `json:"details"`
+	Score         int      `json:"score"`
+	Reason        string   `json:"reason"`
+	Name          string   `json:"name"`
+	Documentation struct {
+		URL string `json:"url"`
+	} `json:"documentation"`
+}
+
+// Sink is a database storing the results of the cron jobs.
+type Sink struct {
+	db *sql.DB
+}
+
+// Open connects to the database of sinkURL and creates its tables if needed.
+// sinkURL is either a postgres:// URL or a sqlite:// URL followed by the path of the database.
+func Open(ctx context.Context, sinkURL string) (*Sink, error) {
+	var driver, dsn string
+	switch {
+	case strings.HasPrefix(sinkURL, sqlitePrefix):
+		driver, dsn = sqliteDriver, strings.TrimPrefix(sinkURL, sqlitePrefix)
+	case strings.HasPrefix(sinkURL, "postgres://"), strings.HasPrefix(sinkURL, "postgresql://"):
+		driver, dsn = postgresDriver, sinkURL
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedSink, sinkURL)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error during sql.Open: %w", err)
+	}
+	for _, stmt := range schema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("error creating the tables: %w", err)
+		}
+	}
+	return &Sink{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Sink) Close() error {
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("error during db.Close: %w", err)
+	}
+	return nil
+}
+
+// TransferShards inserts the results of the completed jobs of the bucket which weren't transferred yet,
+// marks them as transferred and posts their metadata to the webhook, if any.
+func (s *Sink) TransferShards(ctx context.Context, bucketURL string, completionThreshold float64,
+	webhookURL string, summary *data.BucketSummary,
+) error {
+	for _, shards := range summary.Shards() {
+		if shards.IsTransferred() {
+			continue
+		}
+		if !shards.IsCompleted(completionThreshold) {
+			log.Printf("Skipping incomplete shards of %s: %s", shards.CreationTime(), shards.Completion())
+			continue
+		}
+		log.Printf("Transferring shards of %s: %s", shards.CreationTime(), shards.Completion())
+
+		if err := s.transferJob(ctx, bucketURL, shards.CreationTime()); err != nil {
+			return fmt.Errorf("error during transferJob: %w", err)
+		}
+
+		if err := shards.MarkTransferred(ctx, bucketURL); err != nil {
+			return fmt.Errorf("error during MarkTransferred: %w", err)
+		}
+		if webhookURL == "" {
+			continue
+		}
+		if err := postMetadata(ctx, webhookURL, shards.Metadata()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transferJob replaces the results of the job in the database by the ones of its shards,
+// so that a transfer interrupted before MarkTransferred can be retried.
+func (s *Sink) transferJob(ctx context.Context, bucketURL string, jobTime time.Time) error {
+	keys, err := data.GetBlobKeysWithPrefix(ctx, bucketURL, data.GetBlobFilename("shard-", jobTime))
+	if err != nil {
+		return fmt.Errorf("error during GetBlobKeysWithPrefix: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error during BeginTx: %w", err)
+	}
+	//nolint:errcheck // Rollback is a no-op once committed.
+	defer tx.Rollback()
+
+	for _, table := range []string{"scorecard_results", "scorecard_checks"} {
+		//nolint:gosec // the table names are constants.
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE job_time = $1", table), jobTime); err != nil {
+			return fmt.Errorf("error deleting from %s: %w", table, err)
+		}
+	}
+	insertResult, err := tx.PrepareContext(ctx, `INSERT INTO scorecard_results
+		(job_time, repo_name, commit_sha, scan_date, score, scorecard_version, scorecard_commit, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)
+	if err != nil {
+		return fmt.Errorf("error during PrepareContext: %w", err)
+	}
+	defer insertResult.Close()
+	insertCheck, err := tx.PrepareContext(ctx, `INSERT INTO scorecard_checks
+		(job_time, repo_name, name, score, reason, details, documentation_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+	if err != nil {
+		return fmt.Errorf("error during PrepareContext: %w", err)
+	}
+	defer insertCheck.Close()
+
+	for _, key := range keys {
+		content, err := data.GetBlobContent(ctx, bucketURL, key)
+		if err != nil {
+			return fmt.Errorf("error during GetBlobContent: %w", err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		for {
+			var r result
+			if err := decoder.Decode(&r); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return fmt.Errorf("error parsing %s: %w", key, err)
+			}
+			metadata, err := jsonList(r.Metadata)
+			if err != nil {
+				return err
+			}
+			if _, err := insertResult.ExecContext(ctx, jobTime, r.Repo.Name, r.Repo.Commit, r.Date, r.Score,
+				r.Scorecard.Version, r.Scorecard.Commit, metadata); err != nil {
+				return fmt.Errorf("error inserting the result of %s: %w", r.Repo.Name, err)
+			}
+			for i := range r.Checks {
+				c := &r.Checks[i]
+				details, err := jsonList(c.Details)
+				if err != nil {
+					return err
+				}
+				if _, err := insertCheck.ExecContext(ctx, jobTime, r.Repo.Name, c.Name, c.Score, c.Reason,
+					details, c.Documentation.URL); err != nil {
+					return fmt.Errorf("error inserting the %s check of %s: %w", c.Name, r.Repo.Name, err)
+				}
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error during Commit: %w", err)
+	}
+	return nil
+}
+
+// jsonList returns the JSON array of the values, empty rather than null when there's none.
+func jsonList(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("error during json.Marshal: %w", err)
+	}
+	return string(b), nil
+}
+
+func postMetadata(ctx context.Context, webhookURL string, metadata []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewBuffer(metadata))
+	if err != nil {
+		return fmt.Errorf("error during http.NewRequestWithContext: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error during http.Post to %s: %w", webhookURL, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading resp.Body: %w", err)
+	}
+	log.Printf("Returned status: %s %s", resp.Status, body)
+	return nil
+}
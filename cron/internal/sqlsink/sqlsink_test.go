@@ -0,0 +1,197 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlsink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/cron/internal/batch"
+)
+
+const shardContent = `{"date":"2022-10-19","repo":{"name":"github.com/owner/repo1","commit":"sha1"},` +
+	`"scorecard":{"version":"v4","commit":"sc"},"score":5.5,"checks":[{"details":["detail"],"score":5,` +
+	`"reason":"reason","name":"Fake-Check","documentation":{"url":"https://example.com"}}],"metadata":["meta"]}
+{"date":"2022-10-19","repo":{"name":"github.com/owner/repo2","commit":"sha2"},` +
+	`"scorecard":{"version":"v4","commit":"sc"},"score":-1,"checks":[{"details":null,"score":-1,` +
+	`"reason":"error","name":"Fake-Check","documentation":{"url":"https://example.com"}}],"metadata":null}
+`
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	if _, err := Open(ctx, "mysql://localhost/db"); !errors.Is(err, errUnsupportedSink) {
+		t.Errorf("Open(mysql://) error = %v, want %v", err, errUnsupportedSink)
+	}
+
+	// The tables are only created when missing.
+	sinkURL := sqlitePrefix + filepath.Join(t.TempDir(), "results.db")
+	for i := 0; i < 2; i++ {
+		sink, err := Open(ctx, sinkURL)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	}
+}
+
+func TestTransferShards(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	bucketURL := "file://" + filepath.ToSlash(t.TempDir())
+	jobTime := time.Date(2022, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	if err := batch.WriteShardMetadata(ctx, 1, jobTime, "commit", bucketURL); err != nil {
+		t.Fatalf("batch.WriteShardMetadata: %v", err)
+	}
+	if err := data.WriteToBlobStore(ctx, bucketURL, data.GetBlobFilename("shard-0000000", jobTime),
+		[]byte(shardContent)); err != nil {
+		t.Fatalf("data.WriteToBlobStore: %v", err)
+	}
+
+	var mu sync.Mutex
+	var posted []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("io.ReadAll: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		posted = append(posted, string(body))
+	}))
+	defer webhook.Close()
+
+	sink, err := Open(ctx, sqlitePrefix+filepath.Join(t.TempDir(), "results.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer sink.Close()
+
+	summary, err := data.GetBucketSummary(ctx, bucketURL)
+	if err != nil {
+		t.Fatalf("data.GetBucketSummary: %v", err)
+	}
+	if err := sink.TransferShards(ctx, bucketURL, 1, webhook.URL, summary); err != nil {
+		t.Fatalf("TransferShards: %v", err)
+	}
+	// A transfer interrupted before the shards were marked transferred is retried without duplicates.
+	if err := sink.transferJob(ctx, bucketURL, jobTime); err != nil {
+		t.Fatalf("transferJob: %v", err)
+	}
+
+	want := []string{
+		`github.com/owner/repo1 sha1 5.5 ["meta"] Fake-Check 5 ["detail"] https://example.com`,
+		`github.com/owner/repo2 sha2 -1.0 [] Fake-Check -1 [] https://example.com`,
+	}
+	if diff := cmp.Diff(want, queryResults(ctx, t, sink)); diff != "" {
+		t.Errorf("results (-want +got): %s", diff)
+	}
+
+	summary, err = data.GetBucketSummary(ctx, bucketURL)
+	if err != nil {
+		t.Fatalf("data.GetBucketSummary: %v", err)
+	}
+	shards := summary.Shards()
+	if len(shards) != 1 || !shards[0].IsTransferred() {
+		t.Fatalf("shards weren't marked transferred")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff([]string{string(shards[0].Metadata())}, posted); diff != "" {
+		t.Errorf("webhook requests (-want +got): %s", diff)
+	}
+
+	// Transferred shards are skipped.
+	if err := sink.TransferShards(ctx, bucketURL, 1, webhook.URL, summary); err != nil {
+		t.Fatalf("TransferShards: %v", err)
+	}
+	if len(posted) != 1 {
+		t.Errorf("webhook got %d requests, want 1", len(posted))
+	}
+}
+
+func TestJSONList(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{
+			name: "nil",
+			want: "[]",
+		},
+		{
+			name:   "values",
+			values: []string{"a", `"b"`},
+			want:   `["a","\"b\""]`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := jsonList(tt.values)
+			if err != nil {
+				t.Fatalf("jsonList: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("jsonList() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func queryResults(ctx context.Context, t *testing.T, sink *Sink) []string {
+	t.Helper()
+	rows, err := sink.db.QueryContext(ctx, `SELECT r.repo_name, r.commit_sha, r.score, r.metadata,
+		c.name, c.score, c.details, c.documentation_url
+		FROM scorecard_results r JOIN scorecard_checks c ON r.repo_name = c.repo_name AND r.job_time = c.job_time
+		ORDER BY r.repo_name`)
+	if err != nil {
+		t.Fatalf("db.Query: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var repo, commit, metadata, check, details, url string
+		var score float64
+		var checkScore int
+		if err := rows.Scan(&repo, &commit, &score, &metadata, &check, &checkScore, &details, &url); err != nil {
+			t.Fatalf("rows.Scan: %v", err)
+		}
+		got = append(got, fmt.Sprintf("%s %s %.1f %s %s %d %s %s",
+			repo, commit, score, metadata, check, checkScore, details, url))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows.Err: %v", err)
+	}
+	return got
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main implements the SQL transfer job, the self-hosted counterpart of the BQ transfer job.
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/ossf/scorecard/v4/cron/config"
+	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/cron/internal/sqlsink"
+)
+
+func main() {
+	ctx := context.Background()
+
+	flag.Parse()
+	if err := config.ReadConfig(); err != nil {
+		panic(err)
+	}
+
+	bucketURL, err := config.GetResultDataBucketURL()
+	if err != nil {
+		panic(err)
+	}
+	webhookURL, err := config.GetWebhookURL()
+	if err != nil {
+		panic(err)
+	}
+	sinkURL, err := config.GetResultsSinkURL()
+	if err != nil {
+		panic(err)
+	}
+	completionThreshold, err := config.GetCompletionThreshold()
+	if err != nil {
+		panic(err)
+	}
+
+	sink, err := sqlsink.Open(ctx, sinkURL)
+	if err != nil {
+		panic(err)
+	}
+	defer sink.Close()
+
+	summary, err := data.GetBucketSummary(ctx, bucketURL)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := sink.TransferShards(ctx, bucketURL, completionThreshold, webhookURL, summary); err != nil {
+		panic(err)
+	}
+}
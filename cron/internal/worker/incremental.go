@@ -0,0 +1,189 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/checks"
+	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// scanIndex holds the results of the last scan of a repo, to copy them forward while its HEAD
+// and the version of Scorecard don't change.
+type scanIndex struct {
+	CommitSHA  string
+	Scorecard  pkg.ScorecardInfo
+	Checks     []indexedCheck
+	RawResults checker.RawResults
+}
+
+// indexedCheck is a checker.CheckResult without error: the checks which failed aren't indexed.
+type indexedCheck struct {
+	Name    string
+	Version int
+	Details []checker.CheckDetail
+	Score   int
+	Reason  string
+}
+
+func newScanIndex(result *pkg.ScorecardResult) *scanIndex {
+	index := scanIndex{
+		CommitSHA:  result.Repo.CommitSHA,
+		Scorecard:  result.Scorecard,
+		RawResults: result.RawResults,
+	}
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		if check.Error != nil {
+			continue
+		}
+		index.Checks = append(index.Checks, indexedCheck{
+			Name:    check.Name,
+			Version: check.Version,
+			Details: check.Details,
+			Score:   check.Score,
+			Reason:  check.Reason,
+		})
+	}
+	return &index
+}
+
+// readScanIndex returns the scan index at path of the API bucket, nil if the repo wasn't indexed yet.
+func readScanIndex(ctx context.Context, apiBucketURL, path string) (*scanIndex, error) {
+	exists, err := data.BlobExists(ctx, apiBucketURL, path)
+	if err != nil {
+		return nil, fmt.Errorf("error during BlobExists: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	content, err := data.GetBlobContent(ctx, apiBucketURL, path)
+	if err != nil {
+		return nil, fmt.Errorf("error during GetBlobContent: %w", err)
+	}
+	var index scanIndex
+	if err := json.Unmarshal(content, &index); err != nil {
+		return nil, fmt.Errorf("error during json.Unmarshal: %w", err)
+	}
+	return &index, nil
+}
+
+// isCopyable returns whether the results of a check at a commit still hold on later scans of the commit:
+// those of the checks which run solely on the files, apart from Vulnerabilities whose results change
+// as vulnerabilities are disclosed.
+func isCopyable(name string, check checker.Check) bool {
+	return name != checks.CheckVulnerabilities &&
+		len(checker.ListUnsupported([]checker.RequestType{checker.FileBased}, check.SupportedRequestTypes)) == 0
+}
+
+// splitChecks returns the checks of checksToRun to run again and the indexed results of the others:
+// the checks which aren't copyable, and those not indexed or indexed with another version, are run again.
+func splitChecks(index *scanIndex, checksToRun checker.CheckNameToFnMap) (checker.CheckNameToFnMap, []indexedCheck) {
+	indexed := map[string]indexedCheck{}
+	for _, check := range index.Checks {
+		if check.Version == checker.CheckResultVersion {
+			indexed[check.Name] = check
+		}
+	}
+	rerun := checker.CheckNameToFnMap{}
+	var copied []indexedCheck
+	for name, fn := range checksToRun {
+		check, ok := indexed[name]
+		if !ok || !isCopyable(name, fn) {
+			rerun[name] = fn
+			continue
+		}
+		copied = append(copied, check)
+	}
+	return rerun, copied
+}
+
+// runIncrementally runs the checks of checksToRun with run, copying forward the results of the index
+// which still hold: those of the copyable checks, if the resolved commit is still the indexed one
+// and the index was written by the running version of Scorecard.
+func runIncrementally(index *scanIndex, scorecard pkg.ScorecardInfo, checksToRun checker.CheckNameToFnMap,
+	run func(pkg.CheckSelector) (pkg.ScorecardResult, error),
+) (pkg.ScorecardResult, int, error) {
+	var copied []indexedCheck
+	result, err := run(func(commitSHA string) checker.CheckNameToFnMap {
+		if index == nil || index.CommitSHA != commitSHA || index.Scorecard != scorecard {
+			return checksToRun
+		}
+		var rerun checker.CheckNameToFnMap
+		rerun, copied = splitChecks(index, checksToRun)
+		return rerun
+	})
+	if err != nil {
+		return result, 0, err
+	}
+	for _, check := range copied {
+		result.Checks = append(result.Checks, checker.CheckResult{
+			Name:    check.Name,
+			Version: check.Version,
+			Details: check.Details,
+			Score:   check.Score,
+			Reason:  check.Reason,
+		})
+		copyRawResults(check.Name, &result.RawResults, &index.RawResults)
+	}
+	return result, len(copied), nil
+}
+
+// copyRawResults copies the raw results of a check from src to dst.
+func copyRawResults(check string, dst, src *checker.RawResults) {
+	switch check {
+	case checks.CheckBinaryArtifacts:
+		dst.BinaryArtifactResults = src.BinaryArtifactResults
+	case checks.CheckBranchProtection:
+		dst.BranchProtectionResults = src.BranchProtectionResults
+	case checks.CheckCITests:
+		dst.CITestResults = src.CITestResults
+	case checks.CheckCIIBestPractices:
+		dst.CIIBestPracticesResults = src.CIIBestPracticesResults
+	case checks.CheckCodeReview:
+		dst.CodeReviewResults = src.CodeReviewResults
+	case checks.CheckContributors:
+		dst.ContributorsResults = src.ContributorsResults
+	case checks.CheckDangerousWorkflow:
+		dst.DangerousWorkflowResults = src.DangerousWorkflowResults
+	case checks.CheckDependencyUpdateTool:
+		dst.DependencyUpdateToolResults = src.DependencyUpdateToolResults
+	case checks.CheckFuzzing:
+		dst.FuzzingResults = src.FuzzingResults
+	case checks.CheckLicense:
+		dst.LicenseResults = src.LicenseResults
+	case checks.CheckMaintained:
+		dst.MaintainedResults = src.MaintainedResults
+	case checks.CheckPackaging:
+		dst.PackagingResults = src.PackagingResults
+	case checks.CheckPinnedDependencies:
+		dst.PinningDependenciesResults = src.PinningDependenciesResults
+	case checks.CheckSecurityPolicy:
+		dst.SecurityPolicyResults = src.SecurityPolicyResults
+	case checks.CheckSignedReleases:
+		dst.SignedReleasesResults = src.SignedReleasesResults
+	case checks.CheckTokenPermissions:
+		dst.TokenPermissionsResults = src.TokenPermissionsResults
+	case checks.CheckVulnerabilities:
+		dst.VulnerabilitiesResults = src.VulnerabilitiesResults
+	case checks.CheckWebHooks:
+		dst.WebhookResults = src.WebhookResults
+	}
+}
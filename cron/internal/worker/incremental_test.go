@@ -0,0 +1,273 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/checks"
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+var errRun = errors.New("run error")
+
+const (
+	indexedSHA = "0123456789abcdef0123456789abcdef01234567"
+	movedSHA   = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+func checkNames(checksToRun checker.CheckNameToFnMap) []string {
+	names := make([]string, 0, len(checksToRun))
+	for name := range checksToRun {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	testScorecard = pkg.ScorecardInfo{Version: "v4.10.0", CommitSHA: "fedcba9876543210fedcba9876543210fedcba98"}
+	fileBased     = checker.Check{SupportedRequestTypes: []checker.RequestType{checker.FileBased}}
+)
+
+func testIndex() *scanIndex {
+	return &scanIndex{
+		CommitSHA: indexedSHA,
+		Scorecard: testScorecard,
+		Checks: []indexedCheck{
+			{Name: checks.CheckLicense, Version: checker.CheckResultVersion, Score: 10, Reason: "license found"},
+			{Name: checks.CheckTokenPermissions, Version: checker.CheckResultVersion, Score: 10},
+			{Name: checks.CheckFuzzing, Version: checker.CheckResultVersion, Score: 0, Reason: "no fuzzer"},
+			{Name: checks.CheckVulnerabilities, Version: checker.CheckResultVersion, Score: 10},
+			{Name: checks.CheckMaintained, Version: checker.CheckResultVersion, Score: 3},
+			// Indexed by a former version of the checks.
+			{Name: checks.CheckPinnedDependencies, Version: checker.CheckResultVersion - 1, Score: 10},
+		},
+		RawResults: checker.RawResults{
+			LicenseResults: checker.LicenseData{
+				LicenseFiles: []checker.LicenseFile{{File: checker.File{Path: "LICENSE"}}},
+			},
+			MaintainedResults: checker.MaintainedData{Issues: []clients.Issue{{}}},
+		},
+	}
+}
+
+// fakeRun returns a run of runIncrementally scanning headSHA, which records the checks it runs.
+func fakeRun(headSHA string, runErr error, runs *[][]string) func(pkg.CheckSelector) (pkg.ScorecardResult, error) {
+	return func(selectChecks pkg.CheckSelector) (pkg.ScorecardResult, error) {
+		checksToRun := selectChecks(headSHA)
+		*runs = append(*runs, checkNames(checksToRun))
+		if runErr != nil {
+			return pkg.ScorecardResult{}, runErr
+		}
+		result := pkg.ScorecardResult{Repo: pkg.RepoInfo{CommitSHA: headSHA}}
+		for name := range checksToRun {
+			result.Checks = append(result.Checks, checker.CheckResult{
+				Name:    name,
+				Version: checker.CheckResultVersion,
+			})
+		}
+		return result, nil
+	}
+}
+
+func TestRunIncrementally(t *testing.T) {
+	t.Parallel()
+	// Only the results of the file-based checks but Vulnerabilities are copied.
+	enabled := checker.CheckNameToFnMap{
+		checks.CheckLicense:            fileBased,
+		checks.CheckTokenPermissions:   fileBased,
+		checks.CheckVulnerabilities:    fileBased,
+		checks.CheckPinnedDependencies: fileBased,
+		checks.CheckFuzzing:            checker.Check{},
+		checks.CheckMaintained:         checker.Check{},
+		checks.CheckCodeReview:         checker.Check{},
+	}
+	rerun := []string{
+		checks.CheckCodeReview, checks.CheckFuzzing, checks.CheckMaintained,
+		checks.CheckPinnedDependencies, checks.CheckVulnerabilities,
+	}
+	//nolint:govet
+	tests := []struct {
+		name      string
+		index     *scanIndex
+		scorecard pkg.ScorecardInfo
+		headSHA   string
+		runErr    error
+		wantRuns  [][]string
+		wantNames []string
+		wantCopy  int
+		wantErr   error
+	}{
+		{
+			name:      "not indexed",
+			scorecard: testScorecard,
+			headSHA:   indexedSHA,
+			wantRuns:  [][]string{checkNames(enabled)},
+			wantNames: checkNames(enabled),
+		},
+		{
+			name:      "HEAD unchanged",
+			index:     testIndex(),
+			scorecard: testScorecard,
+			headSHA:   indexedSHA,
+			wantRuns:  [][]string{rerun},
+			wantNames: checkNames(enabled),
+			wantCopy:  2,
+		},
+		{
+			name:      "HEAD moved",
+			index:     testIndex(),
+			scorecard: testScorecard,
+			headSHA:   movedSHA,
+			wantRuns:  [][]string{checkNames(enabled)},
+			wantNames: checkNames(enabled),
+		},
+		{
+			name:      "Scorecard upgraded",
+			index:     testIndex(),
+			scorecard: pkg.ScorecardInfo{Version: "v4.11.0", CommitSHA: testScorecard.CommitSHA},
+			headSHA:   indexedSHA,
+			wantRuns:  [][]string{checkNames(enabled)},
+			wantNames: checkNames(enabled),
+		},
+		{
+			name:      "run error",
+			index:     testIndex(),
+			scorecard: testScorecard,
+			headSHA:   indexedSHA,
+			runErr:    errRun,
+			wantRuns:  [][]string{rerun},
+			wantErr:   errRun,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var runs [][]string
+			result, copied, err := runIncrementally(tt.index, tt.scorecard, enabled,
+				fakeRun(tt.headSHA, tt.runErr, &runs))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runIncrementally() error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantRuns, runs); diff != "" {
+				t.Errorf("runs (-want +got): %s", diff)
+			}
+			if copied != tt.wantCopy {
+				t.Errorf("copied = %d, want %d", copied, tt.wantCopy)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			var names []string
+			for i := range result.Checks {
+				names = append(names, result.Checks[i].Name)
+			}
+			sort.Strings(names)
+			if diff := cmp.Diff(tt.wantNames, names); diff != "" {
+				t.Errorf("checks (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRunIncrementallyCopiesResults(t *testing.T) {
+	t.Parallel()
+	index := testIndex()
+	enabled := checker.CheckNameToFnMap{
+		checks.CheckLicense:    fileBased,
+		checks.CheckMaintained: checker.Check{},
+	}
+	result, _, err := runIncrementally(index, testScorecard, enabled,
+		func(selectChecks pkg.CheckSelector) (pkg.ScorecardResult, error) {
+			selectChecks(indexedSHA)
+			return pkg.ScorecardResult{
+				Repo: pkg.RepoInfo{CommitSHA: indexedSHA},
+				Checks: []checker.CheckResult{
+					{Name: checks.CheckMaintained, Version: checker.CheckResultVersion, Score: 7},
+				},
+			}, nil
+		})
+	if err != nil {
+		t.Fatalf("runIncrementally: %v", err)
+	}
+	want := []checker.CheckResult{
+		{Name: checks.CheckMaintained, Version: checker.CheckResultVersion, Score: 7},
+		{Name: checks.CheckLicense, Version: checker.CheckResultVersion, Score: 10, Reason: "license found"},
+	}
+	if diff := cmp.Diff(want, result.Checks); diff != "" {
+		t.Errorf("checks (-want +got): %s", diff)
+	}
+	// The raw results of the copied checks come from the index, those of the checks run again don't.
+	if diff := cmp.Diff(index.RawResults.LicenseResults, result.RawResults.LicenseResults); diff != "" {
+		t.Errorf("license raw results (-want +got): %s", diff)
+	}
+	if len(result.RawResults.MaintainedResults.Issues) != 0 {
+		t.Errorf("maintained raw results copied from the index: %v", result.RawResults.MaintainedResults)
+	}
+}
+
+func TestScanIndex(t *testing.T) {
+	t.Parallel()
+	result := pkg.ScorecardResult{
+		Repo:      pkg.RepoInfo{CommitSHA: indexedSHA},
+		Scorecard: testScorecard,
+		Checks: []checker.CheckResult{
+			{Name: checks.CheckLicense, Version: checker.CheckResultVersion, Score: 10, Reason: "license found"},
+			{Name: checks.CheckFuzzing, Version: checker.CheckResultVersion, Error: errRun},
+		},
+		RawResults: testIndex().RawResults,
+	}
+	content, err := json.Marshal(newScanIndex(&result))
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	bucketURL := "file://" + filepath.ToSlash(t.TempDir())
+	ctx := context.Background()
+	index, err := readScanIndex(ctx, bucketURL, "github.com/owner/repo/"+scanIndexFile)
+	if err != nil || index != nil {
+		t.Fatalf("readScanIndex() = %v, %v, want no index", index, err)
+	}
+	if err := data.WriteToBlobStore(ctx, bucketURL, "github.com/owner/repo/"+scanIndexFile, content); err != nil {
+		t.Fatalf("WriteToBlobStore: %v", err)
+	}
+	index, err = readScanIndex(ctx, bucketURL, "github.com/owner/repo/"+scanIndexFile)
+	if err != nil {
+		t.Fatalf("readScanIndex: %v", err)
+	}
+	// The checks which failed aren't indexed.
+	want := &scanIndex{
+		CommitSHA: indexedSHA,
+		Scorecard: testScorecard,
+		Checks: []indexedCheck{
+			{Name: checks.CheckLicense, Version: checker.CheckResultVersion, Score: 10, Reason: "license found"},
+		},
+		RawResults: result.RawResults,
+	}
+	if diff := cmp.Diff(want, index); diff != "" {
+		t.Errorf("readScanIndex (-want +got): %s", diff)
+	}
+}
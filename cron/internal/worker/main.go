@@ -18,21 +18,25 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof" //nolint:gosec
+	"time"
 
-	"go.opencensus.io/stats/view"
+	"sigs.k8s.io/release-utils/version"
 
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/clients"
 	"github.com/ossf/scorecard/v4/clients/githubrepo"
-	githubstats "github.com/ossf/scorecard/v4/clients/githubrepo/stats"
+	"github.com/ossf/scorecard/v4/clients/githubrepo/roundtripper"
 	"github.com/ossf/scorecard/v4/cron/config"
 	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/cron/internal/batch"
 	format "github.com/ossf/scorecard/v4/cron/internal/format"
+	"github.com/ossf/scorecard/v4/cron/local"
 	"github.com/ossf/scorecard/v4/cron/monitoring"
 	"github.com/ossf/scorecard/v4/cron/worker"
 	docs "github.com/ossf/scorecard/v4/docs/checks"
@@ -46,22 +50,34 @@ import (
 const (
 	resultsFile    = "results.json"
 	rawResultsFile = "raw.json"
+	// scanIndexFile is the index of the last scan of a repo in the API bucket, see scanIndex.
+	scanIndexFile = "scan_index.json"
+	// retryDelay is the delay before scoring again a repo which failed with a transient error.
+	retryDelay = 30 * time.Second
 )
 
-var ignoreRuntimeErrors = flag.Bool("ignoreRuntimeErrors", false, "if set to true any runtime errors will be ignored")
+var (
+	ignoreRuntimeErrors = flag.Bool("ignoreRuntimeErrors", false, "if set to true any runtime errors will be ignored")
+	runLocally          = flag.Bool("local", false,
+		"if set to true the repos of the input files, or of the input bucket if none, are published, "+
+			"processed and transferred to the results sink within the process")
+)
 
 type ScorecardWorker struct {
 	ctx               context.Context
 	logger            *log.Logger
 	checkDocs         docs.Doc
 	exporter          monitoring.Exporter
-	repoClient        clients.RepoClient
+	repoClients       []clients.RepoClient
+	scoreOpts         scoreOptions
 	ciiClient         clients.CIIBestPracticesClient
 	ossFuzzRepoClient clients.RepoClient
 	vulnsClient       clients.VulnerabilitiesClient
 	apiBucketURL      string
 	rawBucketURL      string
 	blacklistedChecks []string
+	incremental       bool
+	resourceUsage     bool
 }
 
 func newScorecardWorker() (*ScorecardWorker, error) {
@@ -88,9 +104,36 @@ func newScorecardWorker() (*ScorecardWorker, error) {
 		return nil, fmt.Errorf("config.GetAPIResultsBucketURL: %w", err)
 	}
 
+	var concurrency int
+	if concurrency, err = config.GetRepoConcurrency(); err != nil {
+		return nil, fmt.Errorf("config.GetRepoConcurrency: %w", err)
+	}
+
+	if sw.scoreOpts.timeout, err = config.GetRepoTimeout(); err != nil {
+		return nil, fmt.Errorf("config.GetRepoTimeout: %w", err)
+	}
+
+	if sw.scoreOpts.maxAttempts, err = config.GetRepoMaxAttempts(); err != nil {
+		return nil, fmt.Errorf("config.GetRepoMaxAttempts: %w", err)
+	}
+	sw.scoreOpts.retryDelay = retryDelay
+
+	if sw.incremental, err = config.GetIncrementalScans(); err != nil {
+		return nil, fmt.Errorf("config.GetIncrementalScans: %w", err)
+	}
+
+	if sw.resourceUsage, err = config.GetResourceUsage(); err != nil {
+		return nil, fmt.Errorf("config.GetResourceUsage: %w", err)
+	}
+
 	sw.ctx = context.Background()
 	sw.logger = log.NewLogger(log.InfoLevel)
-	sw.repoClient = githubrepo.CreateGithubRepoClient(sw.ctx, sw.logger)
+	// The clients hold the state of the repo they're initialized for: one per concurrently scored repo.
+	// They share a transport, and so its tokens, rate limits and cache.
+	rt := roundtripper.NewTransport(sw.ctx, sw.logger)
+	for i := 0; i < concurrency; i++ {
+		sw.repoClients = append(sw.repoClients, githubrepo.CreateGithubRepoClientWithTransport(sw.ctx, rt))
+	}
 	sw.ciiClient = clients.BlobCIIBestPracticesClient(ciiDataBucketURL)
 	if sw.ossFuzzRepoClient, err = githubrepo.CreateOssFuzzRepoClient(sw.ctx, sw.logger); err != nil {
 		return nil, fmt.Errorf("githubrepo.CreateOssFuzzRepoClient: %w", err)
@@ -102,7 +145,7 @@ func newScorecardWorker() (*ScorecardWorker, error) {
 		return nil, fmt.Errorf("startMetricsExporter: %w", err)
 	}
 
-	// Exposed for monitoring runtime profiles
+	// Exposed for monitoring runtime profiles, and metrics with the prometheus exporter.
 	go func() {
 		// TODO(log): Previously Fatal. Need to handle the error here.
 		//nolint:gosec // not internet facing.
@@ -118,114 +161,51 @@ func (sw *ScorecardWorker) Close() {
 
 func (sw *ScorecardWorker) Process(ctx context.Context, req *data.ScorecardBatchRequest, bucketURL string) error {
 	return processRequest(ctx, req, sw.blacklistedChecks, bucketURL, sw.rawBucketURL, sw.apiBucketURL,
-		sw.checkDocs, sw.repoClient, sw.ossFuzzRepoClient, sw.ciiClient, sw.vulnsClient, sw.logger)
+		sw.incremental, sw.resourceUsage, sw.checkDocs, sw.repoClients, sw.scoreOpts, sw.ossFuzzRepoClient,
+		sw.ciiClient, sw.vulnsClient, sw.logger)
 }
 
 func (sw *ScorecardWorker) PostProcess() {
 	sw.exporter.Flush()
 }
 
-//nolint:gocognit
 func processRequest(ctx context.Context,
 	batchRequest *data.ScorecardBatchRequest,
 	blacklistedChecks []string, bucketURL, rawBucketURL, apiBucketURL string,
+	incremental, resourceUsage bool,
 	checkDocs docs.Doc,
-	repoClient clients.RepoClient, ossFuzzRepoClient clients.RepoClient,
+	repoClients []clients.RepoClient, opts scoreOptions, ossFuzzRepoClient clients.RepoClient,
 	ciiClient clients.CIIBestPracticesClient,
 	vulnsClient clients.VulnerabilitiesClient,
 	logger *log.Logger,
 ) error {
 	filename := worker.ResultFilename(batchRequest)
 
+	outputs, failures, err := scoreRepos(ctx, batchRequest.GetRepos(), repoClients, opts, logger,
+		func(ctx context.Context, repoClient clients.RepoClient, repoReq *data.Repo) (*repoOutput, error) {
+			return scoreRepo(ctx, batchRequest, repoReq, blacklistedChecks, apiBucketURL, incremental, resourceUsage,
+				checkDocs, repoClient, ossFuzzRepoClient, ciiClient, vulnsClient, logger)
+		})
+	if err != nil {
+		return err
+	}
+
 	var buffer2 bytes.Buffer
 	var rawBuffer bytes.Buffer
-	// TODO: run Scorecard for each repo in a separate thread.
-	for _, repoReq := range batchRequest.GetRepos() {
-		logger.Info(fmt.Sprintf("Running Scorecard for repo: %s", *repoReq.Url))
-		repo, err := githubrepo.MakeGithubRepo(*repoReq.Url)
-		if err != nil {
-			// TODO(log): Previously Warn. Consider logging an error here.
-			logger.Info(fmt.Sprintf("invalid GitHub URL: %v", err))
-			continue
-		}
-		repo.AppendMetadata(repoReq.Metadata...)
-
-		commitSHA := clients.HeadSHA
-		requiredRequestType := []checker.RequestType{}
-		if repoReq.Commit != nil && *repoReq.Commit != clients.HeadSHA {
-			commitSHA = *repoReq.Commit
-			requiredRequestType = append(requiredRequestType, checker.CommitBased)
-		}
-		checksToRun, err := policy.GetEnabled(nil /*policy*/, nil /*checks*/, requiredRequestType)
-		if err != nil {
-			return fmt.Errorf("error during policy.GetEnabled: %w", err)
-		}
-		for _, check := range blacklistedChecks {
-			delete(checksToRun, check)
-		}
-
-		result, err := pkg.RunScorecards(ctx, repo, commitSHA, checksToRun,
-			repoClient, ossFuzzRepoClient, ciiClient, vulnsClient)
-		if errors.Is(err, sce.ErrRepoUnreachable) {
-			// Not accessible repo - continue.
+	for _, output := range outputs {
+		if output == nil {
 			continue
 		}
-		if err != nil {
-			return fmt.Errorf("error during RunScorecards: %w", err)
-		}
-		for checkIndex := range result.Checks {
-			check := &result.Checks[checkIndex]
-			if !errors.Is(check.Error, sce.ErrScorecardInternal) {
-				continue
-			}
-			errorMsg := fmt.Sprintf("check %s has a runtime error: %v", check.Name, check.Error)
-			if !(*ignoreRuntimeErrors) {
-				//nolint: goerr113
-				return errors.New(errorMsg)
-			}
-			// TODO(log): Previously Warn. Consider logging an error here.
-			logger.Info(errorMsg)
-		}
-		result.Date = batchRequest.GetJobTime().AsTime()
-
-		if err := format.AsJSON2(&result, true /*showDetails*/, log.InfoLevel, checkDocs, &buffer2); err != nil {
-			return fmt.Errorf("error during result.AsJSON2: %w", err)
-		}
-		// these are for exporting results to GCS for API consumption
-		var exportBuffer bytes.Buffer
-		var exportRawBuffer bytes.Buffer
+		buffer2.Write(output.result)
+		rawBuffer.Write(output.raw)
+	}
 
-		if err := format.AsJSON2(&result, true /*showDetails*/, log.InfoLevel, checkDocs, &exportBuffer); err != nil {
-			return fmt.Errorf("error during result.AsJSON2 for export: %w", err)
-		}
-		if err := format.AsRawJSON(&result, &exportRawBuffer); err != nil {
-			return fmt.Errorf("error during result.AsRawJSON for export: %w", err)
-		}
-		exportPath := fmt.Sprintf("%s/%s", repo.URI(), resultsFile)
-		exportCommitSHAPath := fmt.Sprintf("%s/%s/%s", repo.URI(), result.Repo.CommitSHA, resultsFile)
-		exportRawPath := fmt.Sprintf("%s/%s", repo.URI(), rawResultsFile)
-		exportRawCommitSHAPath := fmt.Sprintf("%s/%s/%s", repo.URI(), result.Repo.CommitSHA, rawResultsFile)
-
-		// Raw result.
-		if err := format.AsRawJSON(&result, &rawBuffer); err != nil {
-			return fmt.Errorf("error during result.AsRawJSON: %w", err)
-		}
+	if err := writeFailures(ctx, batchRequest, failures, bucketURL); err != nil {
+		return err
+	}
 
-		// These are results without the commit SHA which represents the latest commit.
-		if err := data.WriteToBlobStore(ctx, apiBucketURL, exportPath, exportBuffer.Bytes()); err != nil {
-			return fmt.Errorf("error during writing to exportBucketURL: %w", err)
-		}
-		// Export result based on commitSHA.
-		if err := data.WriteToBlobStore(ctx, apiBucketURL, exportCommitSHAPath, exportBuffer.Bytes()); err != nil {
-			return fmt.Errorf("error during exportBucketURL with commit SHA: %w", err)
-		}
-		// Export raw result.
-		if err := data.WriteToBlobStore(ctx, apiBucketURL, exportRawPath, exportRawBuffer.Bytes()); err != nil {
-			return fmt.Errorf("error during writing to exportBucketURL for raw results: %w", err)
-		}
-		if err := data.WriteToBlobStore(ctx, apiBucketURL, exportRawCommitSHAPath, exportRawBuffer.Bytes()); err != nil {
-			return fmt.Errorf("error during exportBucketURL for raw results with commit SHA: %w", err)
-		}
+	if err := writeUsageReport(ctx, batchRequest, outputs, bucketURL); err != nil {
+		return err
 	}
 
 	// Raw result.
@@ -244,6 +224,173 @@ func processRequest(ctx context.Context,
 	return nil
 }
 
+// writeFailures writes the failure ledger and the dead-letter file of the batch request, if any repo failed.
+func writeFailures(ctx context.Context, batchRequest *data.ScorecardBatchRequest, failures []data.Failure,
+	bucketURL string,
+) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	var ledger bytes.Buffer
+	if err := data.WriteFailures(&ledger, failures); err != nil {
+		return fmt.Errorf("error during data.WriteFailures: %w", err)
+	}
+	if err := data.WriteToBlobStore(ctx, bucketURL, worker.FailureLedgerFilename(batchRequest),
+		ledger.Bytes()); err != nil {
+		return fmt.Errorf("error during WriteToBlobStore for the failure ledger: %w", err)
+	}
+
+	metadata := map[string][]string{}
+	for _, repoReq := range batchRequest.GetRepos() {
+		metadata[repoReq.GetUrl()] = repoReq.GetMetadata()
+	}
+	var deadRepos []data.RepoFormat
+	for i := range failures {
+		if failures[i].Dead {
+			deadRepos = append(deadRepos, data.RepoFormat{
+				Repo:     failures[i].Repo,
+				Metadata: metadata[failures[i].Repo],
+			})
+		}
+	}
+	if len(deadRepos) == 0 {
+		return nil
+	}
+	var deadLetter bytes.Buffer
+	if err := data.WriteTo(&deadLetter, deadRepos); err != nil {
+		return fmt.Errorf("error during data.WriteTo: %w", err)
+	}
+	if err := data.WriteToBlobStore(ctx, bucketURL, worker.DeadLetterFilename(batchRequest),
+		deadLetter.Bytes()); err != nil {
+		return fmt.Errorf("error during WriteToBlobStore for the dead-letter file: %w", err)
+	}
+	return nil
+}
+
+// scoreRepo runs Scorecard on a repo of the batch request, exports its results for the API
+// and returns its lines of the result files of the shard. Errors writing the results fail the
+// shard, the others are retried or dead-letter the repo. Incremental scans of HEAD copy forward
+// the results of the last scan which still hold, see runIncrementally.
+func scoreRepo(ctx context.Context,
+	batchRequest *data.ScorecardBatchRequest, repoReq *data.Repo,
+	blacklistedChecks []string, apiBucketURL string, incremental, resourceUsage bool,
+	checkDocs docs.Doc,
+	repoClient clients.RepoClient, ossFuzzRepoClient clients.RepoClient,
+	ciiClient clients.CIIBestPracticesClient,
+	vulnsClient clients.VulnerabilitiesClient,
+	logger *log.Logger,
+) (*repoOutput, error) {
+	logger.Info(fmt.Sprintf("Running Scorecard for repo: %s", *repoReq.Url))
+	repo, err := githubrepo.MakeGithubRepo(*repoReq.Url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub URL: %w", err)
+	}
+	repo.AppendMetadata(repoReq.Metadata...)
+
+	commitSHA := clients.HeadSHA
+	requiredRequestType := []checker.RequestType{}
+	if repoReq.Commit != nil && *repoReq.Commit != clients.HeadSHA {
+		commitSHA = *repoReq.Commit
+		requiredRequestType = append(requiredRequestType, checker.CommitBased)
+	}
+	checksToRun, err := policy.GetEnabled(nil /*policy*/, nil /*checks*/, requiredRequestType)
+	if err != nil {
+		return nil, shardError{fmt.Errorf("error during policy.GetEnabled: %w", err)}
+	}
+	for _, check := range blacklistedChecks {
+		delete(checksToRun, check)
+	}
+
+	indexPath := fmt.Sprintf("%s/%s", repo.URI(), scanIndexFile)
+	incremental = incremental && commitSHA == clients.HeadSHA
+	var index *scanIndex
+	if incremental {
+		if index, err = readScanIndex(ctx, apiBucketURL, indexPath); err != nil {
+			// TODO(log): Previously Warn. Consider logging an error here.
+			logger.Info(fmt.Sprintf("scanning %s in full, the scan index can't be read: %v", *repoReq.Url, err))
+		}
+	}
+	versionInfo := version.GetVersionInfo()
+	scorecard := pkg.ScorecardInfo{Version: versionInfo.GitVersion, CommitSHA: versionInfo.GitCommit}
+	result, copied, err := runIncrementally(index, scorecard, checksToRun,
+		func(selectChecks pkg.CheckSelector) (pkg.ScorecardResult, error) {
+			return pkg.RunScorecardsWithOptions(ctx, repo, commitSHA, nil, repoClient, ossFuzzRepoClient,
+				ciiClient, vulnsClient, pkg.RunOptions{SelectChecks: selectChecks, RecordUsage: resourceUsage})
+		})
+	if err != nil {
+		return nil, fmt.Errorf("error during RunScorecards: %w", err)
+	}
+	if copied > 0 {
+		logger.Info(fmt.Sprintf("HEAD of %s unchanged, copied forward %d checks", *repoReq.Url, copied))
+	}
+	for checkIndex := range result.Checks {
+		check := &result.Checks[checkIndex]
+		if !errors.Is(check.Error, sce.ErrScorecardInternal) {
+			continue
+		}
+		if !(*ignoreRuntimeErrors) {
+			return nil, fmt.Errorf("check %s has a runtime error: %w", check.Name, check.Error)
+		}
+		// TODO(log): Previously Warn. Consider logging an error here.
+		logger.Info(fmt.Sprintf("check %s has a runtime error: %v", check.Name, check.Error))
+	}
+	result.Date = batchRequest.GetJobTime().AsTime()
+
+	output := repoOutput{usage: checksUsage(&result)}
+	var buffer2 bytes.Buffer
+	if err := format.AsJSON2(&result, true /*showDetails*/, log.InfoLevel, checkDocs, &buffer2); err != nil {
+		return nil, shardError{fmt.Errorf("error during result.AsJSON2: %w", err)}
+	}
+	output.result = buffer2.Bytes()
+	// these are for exporting results to GCS for API consumption
+	var exportBuffer bytes.Buffer
+	var exportRawBuffer bytes.Buffer
+
+	if err := format.AsJSON2(&result, true /*showDetails*/, log.InfoLevel, checkDocs, &exportBuffer); err != nil {
+		return nil, shardError{fmt.Errorf("error during result.AsJSON2 for export: %w", err)}
+	}
+	if err := format.AsRawJSON(&result, &exportRawBuffer); err != nil {
+		return nil, shardError{fmt.Errorf("error during result.AsRawJSON for export: %w", err)}
+	}
+	exportPath := fmt.Sprintf("%s/%s", repo.URI(), resultsFile)
+	exportCommitSHAPath := fmt.Sprintf("%s/%s/%s", repo.URI(), result.Repo.CommitSHA, resultsFile)
+	exportRawPath := fmt.Sprintf("%s/%s", repo.URI(), rawResultsFile)
+	exportRawCommitSHAPath := fmt.Sprintf("%s/%s/%s", repo.URI(), result.Repo.CommitSHA, rawResultsFile)
+
+	// Raw result.
+	var rawBuffer bytes.Buffer
+	if err := format.AsRawJSON(&result, &rawBuffer); err != nil {
+		return nil, shardError{fmt.Errorf("error during result.AsRawJSON: %w", err)}
+	}
+	output.raw = rawBuffer.Bytes()
+
+	// These are results without the commit SHA which represents the latest commit.
+	if err := data.WriteToBlobStore(ctx, apiBucketURL, exportPath, exportBuffer.Bytes()); err != nil {
+		return nil, shardError{fmt.Errorf("error during writing to exportBucketURL: %w", err)}
+	}
+	// Export result based on commitSHA.
+	if err := data.WriteToBlobStore(ctx, apiBucketURL, exportCommitSHAPath, exportBuffer.Bytes()); err != nil {
+		return nil, shardError{fmt.Errorf("error during exportBucketURL with commit SHA: %w", err)}
+	}
+	// Export raw result.
+	if err := data.WriteToBlobStore(ctx, apiBucketURL, exportRawPath, exportRawBuffer.Bytes()); err != nil {
+		return nil, shardError{fmt.Errorf("error during writing to exportBucketURL for raw results: %w", err)}
+	}
+	if err := data.WriteToBlobStore(ctx, apiBucketURL, exportRawCommitSHAPath, exportRawBuffer.Bytes()); err != nil {
+		return nil, shardError{fmt.Errorf("error during exportBucketURL for raw results with commit SHA: %w", err)}
+	}
+	if incremental {
+		indexContent, err := json.Marshal(newScanIndex(&result))
+		if err != nil {
+			return nil, shardError{fmt.Errorf("error during json.Marshal of the scan index: %w", err)}
+		}
+		if err := data.WriteToBlobStore(ctx, apiBucketURL, indexPath, indexContent); err != nil {
+			return nil, shardError{fmt.Errorf("error during writing the scan index to exportBucketURL: %w", err)}
+		}
+	}
+	return &output, nil
+}
+
 func startMetricsExporter() (monitoring.Exporter, error) {
 	exporter, err := monitoring.GetExporter()
 	if err != nil {
@@ -253,23 +400,49 @@ func startMetricsExporter() (monitoring.Exporter, error) {
 		return nil, fmt.Errorf("error in StartMetricsExporter: %w", err)
 	}
 
-	if err := view.Register(
-		&stats.CheckRuntime,
-		&stats.CheckErrorCount,
-		&stats.OutgoingHTTPRequests,
-		&githubstats.GithubTokens); err != nil {
-		return nil, fmt.Errorf("error during view.Register: %w", err)
+	if err := stats.RegisterViews(); err != nil {
+		return nil, fmt.Errorf("error during stats.RegisterViews: %w", err)
 	}
 	return exporter, nil
 }
 
+// runLocal runs the whole cron job within the process, see cron/local.
+func runLocal(sw *ScorecardWorker) error {
+	var iter data.Iterator
+	var err error
+	if len(flag.Args()) > 0 {
+		iter, err = batch.LocalFiles(flag.Args())
+	} else {
+		iter, err = batch.BucketFiles(sw.ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("reading the input: %w", err)
+	}
+	if err := local.Run(sw.ctx, sw, iter); err != nil {
+		return fmt.Errorf("local.Run: %w", err)
+	}
+	return nil
+}
+
 func main() {
 	flag.Parse()
+	if *runLocally {
+		// The worker reads its config at creation.
+		if err := config.ReadConfig(); err != nil {
+			panic(err)
+		}
+	}
 	sw, err := newScorecardWorker()
 	if err != nil {
 		panic(err)
 	}
 	defer sw.Close()
+	if *runLocally {
+		if err := runLocal(sw); err != nil {
+			panic(err)
+		}
+		return
+	}
 	wl := worker.NewWorkLoop(sw)
 	if err := wl.Run(); err != nil {
 		panic(err)
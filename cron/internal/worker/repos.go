@@ -0,0 +1,202 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/cron/data"
+	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/log"
+)
+
+// repoOutput is what a repo contributes to the result files of its shard.
+type repoOutput struct {
+	// usage of resources by the checks, by check name, if recorded.
+	usage  map[string]*checker.ResourceUsage
+	result []byte
+	raw    []byte
+}
+
+// shardError is an error which fails the whole shard, e.g. when the results can't be written,
+// rather than the repo being scored.
+type shardError struct {
+	err error
+}
+
+func (e shardError) Error() string {
+	return e.err.Error()
+}
+
+func (e shardError) Unwrap() error {
+	return e.err
+}
+
+// scoreFunc scores a repo with a client no other goroutine uses meanwhile.
+// It returns no output for the repos to skip.
+type scoreFunc func(ctx context.Context, repoClient clients.RepoClient, repoReq *data.Repo) (*repoOutput, error)
+
+// scoreOptions bounds the scoring of each repo.
+type scoreOptions struct {
+	// timeout of an attempt, none if 0.
+	timeout     time.Duration
+	maxAttempts int
+	// retryDelay is the delay before the second attempt, doubled after each attempt.
+	retryDelay time.Duration
+}
+
+// scoreRepos scores the repos concurrently, with a goroutine per client. The outputs are in the
+// order of the repos, so that the result files of a shard don't depend on the scheduling.
+// The repos failing with transient errors are scored again, up to opts.maxAttempts times.
+// The failures are returned in the order of the repos, the repos without output being the
+// dead ones. Once a repo fails with a shardError, the repos left aren't scored and the error
+// is returned.
+func scoreRepos(ctx context.Context, repos []*data.Repo, repoClients []clients.RepoClient,
+	opts scoreOptions, logger *log.Logger, score scoreFunc,
+) ([]*repoOutput, []data.Failure, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	outputs := make([]*repoOutput, len(repos))
+	failures := make([]*data.Failure, len(repos))
+	idleClients := make(chan clients.RepoClient, len(repoClients))
+	for _, repoClient := range repoClients {
+		idleClients <- repoClient
+	}
+	for i := range repos {
+		repoClient := <-idleClients
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, repoClient clients.RepoClient) {
+			defer wg.Done()
+			defer func() { idleClients <- repoClient }()
+
+			output, failure, err := scoreWithRetries(ctx, repoClient, repos[i], opts, logger, score)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			outputs[i], failures[i] = output, failure
+		}(i, repoClient)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, fmt.Errorf("scoring the repos: %w", err)
+	}
+	var ledger []data.Failure
+	for _, failure := range failures {
+		if failure != nil {
+			ledger = append(ledger, *failure)
+		}
+	}
+	return outputs, ledger, nil
+}
+
+// scoreWithRetries scores the repo until it succeeds or fails permanently. It returns
+// the failure of the repo, if it failed at least once, and the errors failing the shard.
+func scoreWithRetries(ctx context.Context, repoClient clients.RepoClient, repoReq *data.Repo,
+	opts scoreOptions, logger *log.Logger, score scoreFunc,
+) (*repoOutput, *data.Failure, error) {
+	var failure *data.Failure
+	delay := opts.retryDelay
+	for attempt := 1; ; attempt++ {
+		output, err := scoreAttempt(ctx, repoClient, repoReq, opts.timeout, score)
+		if err == nil {
+			return output, failure, nil
+		}
+		var errShard shardError
+		if errors.As(err, &errShard) {
+			return nil, nil, err
+		}
+		if ctx.Err() != nil {
+			// The shard failed meanwhile.
+			return nil, nil, fmt.Errorf("scoring %s: %w", repoReq.GetUrl(), ctx.Err())
+		}
+
+		failure = &data.Failure{
+			Repo:     repoReq.GetUrl(),
+			Class:    errorClass(err),
+			Error:    err.Error(),
+			Attempts: attempt,
+		}
+		if isPermanent(err) || attempt >= opts.maxAttempts {
+			failure.Dead = true
+			// TODO(log): Previously Warn. Consider logging an error here.
+			logger.Info(fmt.Sprintf("dead-lettering repo %s after %d attempts: %v", repoReq.GetUrl(), attempt, err))
+			return nil, failure, nil
+		}
+		logger.Info(fmt.Sprintf("retrying repo %s in %s after attempt %d: %v", repoReq.GetUrl(), delay, attempt, err))
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("scoring %s: %w", repoReq.GetUrl(), ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func scoreAttempt(ctx context.Context, repoClient clients.RepoClient, repoReq *data.Repo,
+	timeout time.Duration, score scoreFunc,
+) (*repoOutput, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	output, err := score(ctx, repoClient, repoReq)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("not scored within %s: %w", timeout, context.DeadlineExceeded)
+	}
+	return output, err
+}
+
+// isPermanent returns whether scoring the repo again in this run would fail the same way.
+func isPermanent(err error) bool {
+	return errors.Is(err, sce.ErrRepoUnreachable) ||
+		errors.Is(err, sce.ErrorInvalidURL) ||
+		errors.Is(err, sce.ErrorUnsupportedHost) ||
+		// The repos too slow to score would time out again.
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// errorClass returns the name of the sentinel error of err, see errors.GetName.
+func errorClass(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "DeadlineExceeded"
+	}
+	return sce.GetName(err)
+}
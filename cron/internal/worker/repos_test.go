@@ -0,0 +1,216 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/clients"
+	mockrepo "github.com/ossf/scorecard/v4/clients/mockclients"
+	"github.com/ossf/scorecard/v4/cron/data"
+	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/log"
+)
+
+var errShard = errors.New("shard error")
+
+func makeRepos(urls ...string) []*data.Repo {
+	repos := make([]*data.Repo, len(urls))
+	for i := range urls {
+		repos[i] = &data.Repo{Url: &urls[i]}
+	}
+	return repos
+}
+
+func TestScoreRepos(t *testing.T) {
+	t.Parallel()
+	//nolint:govet
+	tests := []struct {
+		name    string
+		repos   []*data.Repo
+		clients int
+		opts    scoreOptions
+		// delays of the repos, by URL.
+		delays map[string]time.Duration
+		// errors of the first attempts at scoring the repos, by URL.
+		errs         map[string][]error
+		blocking     string
+		want         []string
+		wantFailures []data.Failure
+		wantErr      error
+	}{
+		{
+			name:    "outputs in the order of the repos",
+			repos:   makeRepos("r1", "r2", "r3", "r4", "r5"),
+			clients: 3,
+			opts:    scoreOptions{maxAttempts: 1},
+			delays:  map[string]time.Duration{"r1": 30 * time.Millisecond, "r2": 20 * time.Millisecond},
+			want:    []string{"r1", "r2", "r3", "r4", "r5"},
+		},
+		{
+			name:    "single client",
+			repos:   makeRepos("r1", "r2", "r3"),
+			clients: 1,
+			opts:    scoreOptions{maxAttempts: 1},
+			want:    []string{"r1", "r2", "r3"},
+		},
+		{
+			name:     "slow repo dead-lettered",
+			repos:    makeRepos("r1", "slow", "r3"),
+			clients:  2,
+			opts:     scoreOptions{timeout: 50 * time.Millisecond, maxAttempts: 3},
+			blocking: "slow",
+			want:     []string{"r1", "", "r3"},
+			wantFailures: []data.Failure{
+				{
+					Repo:     "slow",
+					Class:    "DeadlineExceeded",
+					Error:    "not scored within 50ms: context deadline exceeded",
+					Attempts: 1,
+					Dead:     true,
+				},
+			},
+		},
+		{
+			name:    "transient error retried",
+			repos:   makeRepos("r1", "flaky"),
+			clients: 2,
+			opts:    scoreOptions{maxAttempts: 3, retryDelay: time.Millisecond},
+			errs: map[string][]error{
+				"flaky": {sce.WithMessage(sce.ErrScorecardInternal, "rate limited")},
+			},
+			want: []string{"r1", "flaky"},
+			wantFailures: []data.Failure{
+				{Repo: "flaky", Class: "ErrScorecardInternal", Error: "internal error: rate limited", Attempts: 1},
+			},
+		},
+		{
+			name:    "transient errors dead-lettered after the last attempt",
+			repos:   makeRepos("r1", "flaky"),
+			clients: 1,
+			opts:    scoreOptions{maxAttempts: 2, retryDelay: time.Millisecond},
+			errs: map[string][]error{
+				"flaky": {
+					sce.WithMessage(sce.ErrScorecardInternal, "rate limited"),
+					sce.WithMessage(sce.ErrScorecardInternal, "rate limited again"),
+				},
+			},
+			want: []string{"r1", ""},
+			wantFailures: []data.Failure{
+				{
+					Repo:     "flaky",
+					Class:    "ErrScorecardInternal",
+					Error:    "internal error: rate limited again",
+					Attempts: 2,
+					Dead:     true,
+				},
+			},
+		},
+		{
+			name:    "permanent error not retried",
+			repos:   makeRepos("gone", "r2"),
+			clients: 2,
+			opts:    scoreOptions{maxAttempts: 3, retryDelay: time.Hour},
+			errs: map[string][]error{
+				"gone": {sce.WithMessage(sce.ErrRepoUnreachable, "not found")},
+			},
+			want: []string{"", "r2"},
+			wantFailures: []data.Failure{
+				{Repo: "gone", Class: "ErrRepoUnreachable", Error: "repo unreachable: not found", Attempts: 1, Dead: true},
+			},
+		},
+		{
+			name:    "shard error",
+			repos:   makeRepos("r1", "fail", "r3", "r4"),
+			clients: 2,
+			opts:    scoreOptions{maxAttempts: 3},
+			errs: map[string][]error{
+				"fail": {shardError{errShard}},
+			},
+			wantErr: errShard,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			var repoClients []clients.RepoClient
+			for i := 0; i < tt.clients; i++ {
+				repoClients = append(repoClients, mockrepo.NewMockRepoClient(ctrl))
+			}
+			var mu sync.Mutex
+			busy := map[clients.RepoClient]bool{}
+			attempts := map[string]int{}
+			score := func(ctx context.Context, repoClient clients.RepoClient, repoReq *data.Repo) (*repoOutput, error) {
+				url := repoReq.GetUrl()
+				mu.Lock()
+				if busy[repoClient] {
+					t.Errorf("client used concurrently for %s", url)
+				}
+				busy[repoClient] = true
+				attempt := attempts[url]
+				attempts[url]++
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					busy[repoClient] = false
+					mu.Unlock()
+				}()
+
+				if url == tt.blocking {
+					<-ctx.Done()
+					return nil, fmt.Errorf("scoring %s: %w", url, ctx.Err())
+				}
+				if attempt < len(tt.errs[url]) {
+					return nil, tt.errs[url][attempt]
+				}
+				time.Sleep(tt.delays[url])
+				return &repoOutput{result: []byte(url), raw: []byte(url)}, nil
+			}
+
+			outputs, failures, err := scoreRepos(context.Background(), tt.repos, repoClients, tt.opts,
+				log.NewLogger(log.InfoLevel), score)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("scoreRepos() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			var got []string
+			for _, output := range outputs {
+				if output == nil {
+					got = append(got, "")
+					continue
+				}
+				got = append(got, string(output.result))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("outputs (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantFailures, failures); diff != "" {
+				t.Errorf("failures (-want +got): %s", diff)
+			}
+		})
+	}
+}
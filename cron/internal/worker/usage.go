@@ -0,0 +1,105 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/cron/worker"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// setupCheck is the name the usage of resources by the setup shared by the checks is reported under.
+const setupCheck = "(setup)"
+
+// checksUsage returns the usage of resources by the checks of the result which recorded it,
+// by check name, and by their shared setup under setupCheck. The checks copied forward by
+// incremental scans didn't run, and have none.
+func checksUsage(result *pkg.ScorecardResult) map[string]*checker.ResourceUsage {
+	var usage map[string]*checker.ResourceUsage
+	if result.SetupUsage != nil {
+		usage = map[string]*checker.ResourceUsage{setupCheck: result.SetupUsage}
+	}
+	for i := range result.Checks {
+		if result.Checks[i].Usage == nil {
+			continue
+		}
+		if usage == nil {
+			usage = map[string]*checker.ResourceUsage{}
+		}
+		usage[result.Checks[i].Name] = result.Checks[i].Usage
+	}
+	return usage
+}
+
+// usageReport sums the usage of resources by each check over the repos of a shard,
+// sorted by check name.
+func usageReport(outputs []*repoOutput) []data.CheckUsage {
+	totals := map[string]*checker.ResourceUsage{}
+	runs := map[string]int{}
+	for _, output := range outputs {
+		if output == nil {
+			continue
+		}
+		for check, usage := range output.usage {
+			total, ok := totals[check]
+			if !ok {
+				total = &checker.ResourceUsage{}
+				totals[check] = total
+			}
+			total.Add(usage)
+			runs[check]++
+		}
+	}
+	report := make([]data.CheckUsage, 0, len(totals))
+	for check, total := range totals {
+		report = append(report, data.CheckUsage{
+			Check:           check,
+			Runs:            runs[check],
+			WallTimeSeconds: total.WallTime.Seconds(),
+			Requests:        total.Requests,
+			BytesDownloaded: total.BytesDownloaded,
+			CacheHits:       total.CacheHits,
+		})
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Check < report[j].Check
+	})
+	return report
+}
+
+// writeUsageReport writes the usage report of the batch request, if any check recorded its usage.
+func writeUsageReport(ctx context.Context, batchRequest *data.ScorecardBatchRequest, outputs []*repoOutput,
+	bucketURL string,
+) error {
+	report := usageReport(outputs)
+	if len(report) == 0 {
+		return nil
+	}
+	var buffer bytes.Buffer
+	if err := data.WriteUsage(&buffer, report); err != nil {
+		return fmt.Errorf("error during data.WriteUsage: %w", err)
+	}
+	if err := data.WriteToBlobStore(ctx, bucketURL, worker.UsageReportFilename(batchRequest),
+		buffer.Bytes()); err != nil {
+		return fmt.Errorf("error during WriteToBlobStore for the usage report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+func TestUsageReport(t *testing.T) {
+	t.Parallel()
+	first := &pkg.ScorecardResult{
+		SetupUsage: &checker.ResourceUsage{
+			Requests:        map[string]int64{"graphql": 1, "tarball": 1},
+			WallTime:        time.Second,
+			BytesDownloaded: 1024,
+		},
+		Checks: []checker.CheckResult{
+			{
+				Name: "Pinned-Dependencies",
+				Usage: &checker.ResourceUsage{
+					WallTime: 2 * time.Second,
+				},
+			},
+			{
+				Name: "Code-Review",
+				Usage: &checker.ResourceUsage{
+					Requests:  map[string]int64{"graphql": 1, "rest": 10},
+					WallTime:  time.Second,
+					CacheHits: 4,
+				},
+			},
+		},
+	}
+	second := &pkg.ScorecardResult{
+		Checks: []checker.CheckResult{
+			{
+				Name: "Code-Review",
+				Usage: &checker.ResourceUsage{
+					Requests: map[string]int64{"rest": 5},
+					WallTime: 500 * time.Millisecond,
+				},
+			},
+			// Copied forward by an incremental scan.
+			{Name: "Pinned-Dependencies"},
+		},
+	}
+	outputs := []*repoOutput{
+		{usage: checksUsage(first)},
+		nil,
+		{usage: checksUsage(second)},
+		{usage: checksUsage(&pkg.ScorecardResult{})},
+	}
+	want := []data.CheckUsage{
+		{
+			Check:           setupCheck,
+			Runs:            1,
+			WallTimeSeconds: 1,
+			Requests:        map[string]int64{"graphql": 1, "tarball": 1},
+			BytesDownloaded: 1024,
+		},
+		{
+			Check:           "Code-Review",
+			Runs:            2,
+			WallTimeSeconds: 1.5,
+			Requests:        map[string]int64{"graphql": 1, "rest": 15},
+			CacheHits:       4,
+		},
+		{
+			Check:           "Pinned-Dependencies",
+			Runs:            1,
+			WallTimeSeconds: 2,
+		},
+	}
+	if diff := cmp.Diff(want, usageReport(outputs)); diff != "" {
+		t.Errorf("usageReport (-want +got): %s", diff)
+	}
+	if got := usageReport([]*repoOutput{{}}); len(got) != 0 {
+		t.Errorf("usageReport without usage = %v, want none", got)
+	}
+}
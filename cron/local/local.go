@@ -0,0 +1,167 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package local runs the controller, a worker and the SQL transfer of the cron job in a single process,
+// for self-hosted deployments without a message broker, e.g. with the local profile of cron/config.
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"sigs.k8s.io/release-utils/version"
+
+	"github.com/ossf/scorecard/v4/cron/config"
+	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/cron/internal/batch"
+	"github.com/ossf/scorecard/v4/cron/internal/pubsub"
+	"github.com/ossf/scorecard/v4/cron/internal/sqlsink"
+	"github.com/ossf/scorecard/v4/cron/worker"
+)
+
+// pollInterval is the interval between the checks for the results of the shards.
+var pollInterval = time.Second
+
+var errWorkLoopStopped = errors.New("the work loop stopped before the shards were processed")
+
+// Run publishes the repos of iter, processes them with w and transfers their results
+// to the results sink of the config once the results of all the shards are written.
+// The topic must be readable from the subscription of the config within the process,
+// e.g. mem:// URLs, as the subscription is opened before the requests are published.
+func Run(ctx context.Context, w worker.Worker, iter data.Iterator) error {
+	topicURL, err := config.GetRequestTopicURL()
+	if err != nil {
+		return fmt.Errorf("config.GetRequestTopicURL: %w", err)
+	}
+	subscriptionURL, err := config.GetRequestSubscriptionURL()
+	if err != nil {
+		return fmt.Errorf("config.GetRequestSubscriptionURL: %w", err)
+	}
+	shardSize, err := config.GetShardSize()
+	if err != nil {
+		return fmt.Errorf("config.GetShardSize: %w", err)
+	}
+	bucketURL, err := config.GetResultDataBucketURL()
+	if err != nil {
+		return fmt.Errorf("config.GetResultDataBucketURL: %w", err)
+	}
+	rawBucketURL, err := config.GetRawResultDataBucketURL()
+	if err != nil {
+		return fmt.Errorf("config.GetRawResultDataBucketURL: %w", err)
+	}
+	sinkURL, err := config.GetResultsSinkURL()
+	if err != nil {
+		return fmt.Errorf("config.GetResultsSinkURL: %w", err)
+	}
+	completionThreshold, err := config.GetCompletionThreshold()
+	if err != nil {
+		return fmt.Errorf("config.GetCompletionThreshold: %w", err)
+	}
+	webhookURL, err := config.GetWebhookURL()
+	if err != nil {
+		return fmt.Errorf("config.GetWebhookURL: %w", err)
+	}
+
+	// The in-process topics drop the messages sent before a subscription exists.
+	publisher, err := pubsub.CreatePublisher(ctx, topicURL)
+	if err != nil {
+		return fmt.Errorf("pubsub.CreatePublisher: %w", err)
+	}
+	workCtx, stopWork := context.WithCancel(ctx)
+	defer stopWork()
+	subscriber, err := pubsub.CreateSubscriber(workCtx, subscriptionURL)
+	if err != nil {
+		return fmt.Errorf("pubsub.CreateSubscriber: %w", err)
+	}
+
+	jobTime := time.Now()
+	numShard, err := batch.PublishRequests(iter, publisher, shardSize, jobTime)
+	if err != nil {
+		return fmt.Errorf("batch.PublishRequests: %w", err)
+	}
+	buckets := []string{bucketURL}
+	if rawBucketURL != "" {
+		buckets = append(buckets, rawBucketURL)
+	}
+	commitSHA := version.GetVersionInfo().GitCommit
+	if err := batch.WriteShardMetadata(ctx, numShard, jobTime, commitSHA, buckets...); err != nil {
+		return fmt.Errorf("batch.WriteShardMetadata: %w", err)
+	}
+
+	wl := worker.NewWorkLoop(w)
+	served := make(chan error, 1)
+	go func() {
+		served <- wl.Serve(workCtx, subscriber, bucketURL)
+	}()
+	if err := waitForShards(ctx, bucketURL, numShard, jobTime, served); err != nil {
+		return err
+	}
+	stopWork()
+	// The subscriber is shut down with the canceled context.
+	if err := <-served; err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("WorkLoop.Serve: %w", err)
+	}
+
+	sink, err := sqlsink.Open(ctx, sinkURL)
+	if err != nil {
+		return fmt.Errorf("sqlsink.Open: %w", err)
+	}
+	defer sink.Close()
+	summary, err := data.GetBucketSummary(ctx, bucketURL)
+	if err != nil {
+		return fmt.Errorf("data.GetBucketSummary: %w", err)
+	}
+	if err := sink.TransferShards(ctx, bucketURL, completionThreshold, webhookURL, summary); err != nil {
+		return fmt.Errorf("sink.TransferShards: %w", err)
+	}
+	return nil
+}
+
+// waitForShards returns once the results of the numShard shards of the job are in the bucket,
+// or with the error of the work loop if it stops before.
+func waitForShards(ctx context.Context, bucketURL string, numShard int32, jobTime time.Time,
+	served <-chan error,
+) error {
+	var shardNum int32
+	req := data.ScorecardBatchRequest{
+		JobTime:  timestamppb.New(jobTime),
+		ShardNum: &shardNum,
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for shardNum < numShard {
+		exists, err := data.BlobExists(ctx, bucketURL, worker.ResultFilename(&req))
+		if err != nil {
+			return fmt.Errorf("data.BlobExists: %w", err)
+		}
+		if exists {
+			shardNum++
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for the shards: %w", ctx.Err())
+		case err := <-served:
+			if err != nil {
+				return fmt.Errorf("WorkLoop.Serve: %w", err)
+			}
+			return errWorkLoopStopped
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
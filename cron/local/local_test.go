@@ -0,0 +1,174 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/cron/config"
+	"github.com/ossf/scorecard/v4/cron/data"
+	"github.com/ossf/scorecard/v4/cron/worker"
+)
+
+// fakeWorker writes a result per repo, with a single check scoring the shard number.
+type fakeWorker struct {
+	mu        sync.Mutex
+	processed int
+}
+
+func (w *fakeWorker) Process(ctx context.Context, req *data.ScorecardBatchRequest, bucketURL string) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, repo := range req.GetRepos() {
+		result := map[string]interface{}{
+			"date":      "2022-10-19",
+			"repo":      map[string]string{"name": repo.GetUrl(), "commit": "sha"},
+			"scorecard": map[string]string{"version": "v4", "commit": "sc"},
+			"score":     float64(req.GetShardNum()),
+			"checks": []map[string]interface{}{{
+				"details":       []string{"detail"},
+				"score":         req.GetShardNum(),
+				"reason":        "reason",
+				"name":          "Fake-Check",
+				"documentation": map[string]string{"url": "https://example.com", "short": "short"},
+			}},
+			"metadata": repo.GetMetadata(),
+		}
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("encoder.Encode: %w", err)
+		}
+	}
+	if err := data.WriteToBlobStore(ctx, bucketURL, worker.ResultFilename(req), buf.Bytes()); err != nil {
+		return fmt.Errorf("data.WriteToBlobStore: %w", err)
+	}
+	return nil
+}
+
+func (w *fakeWorker) PostProcess() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.processed++
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "data")
+	rawDir := filepath.Join(dir, "raw")
+	for _, d := range []string{dataDir, rawDir} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			t.Fatalf("os.Mkdir: %v", err)
+		}
+	}
+	configFile := filepath.Join(dir, "config.yaml")
+	configYAML := fmt.Sprintf(`request-topic-url: mem://local-test
+request-subscription-url: mem://local-test
+completion-threshold: 1
+shard-size: 2
+webhook-url:
+result-data-bucket-url: file://%s
+results-sink-url: sqlite://%s
+additional-params:
+  scorecard:
+    raw-result-data-bucket-url: file://%s
+`, filepath.ToSlash(dataDir), filepath.Join(dir, "results.db"), filepath.ToSlash(rawDir))
+	if err := os.WriteFile(configFile, []byte(configYAML), 0o600); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+	if err := flag.Set("config", configFile); err != nil {
+		t.Fatalf("flag.Set: %v", err)
+	}
+	t.Cleanup(func() {
+		flag.Set("config", "") //nolint:errcheck
+	})
+	if err := config.ReadConfig(); err != nil {
+		t.Fatalf("config.ReadConfig: %v", err)
+	}
+	pollInterval = 10 * time.Millisecond
+
+	iter, err := data.MakeIteratorFrom(strings.NewReader(`repo,metadata
+github.com/owner/repo1,
+github.com/owner/repo2,meta
+github.com/owner/repo3,
+`))
+	if err != nil {
+		t.Fatalf("data.MakeIteratorFrom: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	w := &fakeWorker{}
+	if err := Run(ctx, w, iter); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if w.processed != 2 {
+		t.Errorf("processed %d shards, want 2", w.processed)
+	}
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "results.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, `SELECT r.repo_name, r.score, r.metadata, c.name, c.score, c.details
+		FROM scorecard_results r JOIN scorecard_checks c ON r.repo_name = c.repo_name AND r.job_time = c.job_time
+		ORDER BY r.repo_name`)
+	if err != nil {
+		t.Fatalf("db.Query: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var repo, metadata, check, details string
+		var score float64
+		var checkScore int
+		if err := rows.Scan(&repo, &score, &metadata, &check, &checkScore, &details); err != nil {
+			t.Fatalf("rows.Scan: %v", err)
+		}
+		got = append(got, fmt.Sprintf("%s %.1f %s %s %d %s", repo, score, metadata, check, checkScore, details))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows.Err: %v", err)
+	}
+	want := []string{
+		`github.com/owner/repo1 0.0 [] Fake-Check 0 ["detail"]`,
+		`github.com/owner/repo2 0.0 ["meta"] Fake-Check 0 ["detail"]`,
+		`github.com/owner/repo3 1.0 [] Fake-Check 1 ["detail"]`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("results (-want +got): %s", diff)
+	}
+
+	summary, err := data.GetBucketSummary(ctx, "file://"+filepath.ToSlash(dataDir))
+	if err != nil {
+		t.Fatalf("data.GetBucketSummary: %v", err)
+	}
+	for _, shards := range summary.Shards() {
+		if !shards.IsTransferred() {
+			t.Errorf("shards of %s weren't marked transferred", shards.CreationTime())
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"go.opencensus.io/stats/view"
 
 	"github.com/ossf/scorecard/v4/cron/config"
+	"github.com/ossf/scorecard/v4/stats/prometheus"
 )
 
 var errorUndefinedExporter = errors.New("unsupported exporterType")
@@ -36,6 +37,7 @@ const (
 	stackdriverTimeoutMinutes               = 10
 	stackDriver                exporterType = "stackdriver"
 	printer                    exporterType = "printer"
+	prometheusExporterType     exporterType = "prometheus"
 )
 
 // Exporter interface is a custom wrapper to represent an opencensus exporter.
@@ -58,6 +60,12 @@ func GetExporter() (Exporter, error) {
 		return newStackDriverExporter()
 	case printer:
 		return new(printerExporter), nil
+	case prometheusExporterType:
+		exporter, err := prometheus.NewExporter()
+		if err != nil {
+			return nil, fmt.Errorf("error during prometheus.NewExporter: %w", err)
+		}
+		return exporter, nil
 	default:
 		return nil, fmt.Errorf("%w: %s", errorUndefinedExporter, exporter)
 	}
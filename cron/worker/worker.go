@@ -74,6 +74,12 @@ func (wl *WorkLoop) Run() error {
 		return fmt.Errorf("config.GetResultDataBucketURL: %w", err)
 	}
 
+	return wl.Serve(ctx, subscriber, bucketURL)
+}
+
+// Serve processes the requests pulled from the subscriber, writing their results to bucketURL,
+// until the subscriber returns no message, e.g. once ctx is done. It closes the subscriber.
+func (wl *WorkLoop) Serve(ctx context.Context, subscriber pubsub.Subscriber, bucketURL string) error {
 	logger := log.NewLogger(log.InfoLevel)
 
 	for {
@@ -150,6 +156,25 @@ func ResultFilename(sbr *data.ScorecardBatchRequest) string {
 	return data.GetBlobFilename(shardname, sbr.GetJobTime().AsTime())
 }
 
+// FailureLedgerFilename returns the filename of the failure ledger of a batch request, see data.Failure.
+func FailureLedgerFilename(sbr *data.ScorecardBatchRequest) string {
+	filename := fmt.Sprintf("%s%07d", config.FailureLedgerPrefix, sbr.GetShardNum())
+	return data.GetBlobFilename(filename, sbr.GetJobTime().AsTime())
+}
+
+// DeadLetterFilename returns the filename listing the repos of a batch request which failed permanently.
+// It's in the format of the input files, so that the repos can be submitted again once fixed.
+func DeadLetterFilename(sbr *data.ScorecardBatchRequest) string {
+	filename := fmt.Sprintf("%s%07d", config.DeadLetterPrefix, sbr.GetShardNum())
+	return data.GetBlobFilename(filename, sbr.GetJobTime().AsTime())
+}
+
+// UsageReportFilename returns the filename of the usage report of a batch request, see data.CheckUsage.
+func UsageReportFilename(sbr *data.ScorecardBatchRequest) string {
+	filename := fmt.Sprintf("%s%07d", config.UsageReportPrefix, sbr.GetShardNum())
+	return data.GetBlobFilename(filename, sbr.GetJobTime().AsTime())
+}
+
 func hasMetadataFile(ctx context.Context, req *data.ScorecardBatchRequest, bucketURL string) (bool, error) {
 	filename := data.GetShardMetadataFilename(req.GetJobTime().AsTime())
 	exists, err := data.BlobExists(ctx, bucketURL, filename)
@@ -55,3 +55,28 @@ func TestResultFilename(t *testing.T) {
 		})
 	}
 }
+
+func TestFailureFilenames(t *testing.T) {
+	t.Parallel()
+	req := &data.ScorecardBatchRequest{
+		JobTime:  timestamppb.New(time.Date(1979, time.October, 12, 1, 2, 3, 0, time.UTC)),
+		ShardNum: asPointer(42),
+	}
+	if got, want := FailureLedgerFilename(req), "1979.10.12/010203/failures-0000042"; got != want {
+		t.Errorf("FailureLedgerFilename() = %s, want %s", got, want)
+	}
+	if got, want := DeadLetterFilename(req), "1979.10.12/010203/dead-letter-0000042"; got != want {
+		t.Errorf("DeadLetterFilename() = %s, want %s", got, want)
+	}
+}
+
+func TestUsageReportFilename(t *testing.T) {
+	t.Parallel()
+	req := &data.ScorecardBatchRequest{
+		JobTime:  timestamppb.New(time.Date(1979, time.October, 12, 1, 2, 3, 0, time.UTC)),
+		ShardNum: asPointer(42),
+	}
+	if got, want := UsageReportFilename(req), "1979.10.12/010203/usage-0000042"; got != want {
+		t.Errorf("UsageReportFilename() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// pseudoVersion matches the timestamp and revision of Go pseudo-versions, e.g.
+// 0.0.0-20220101000000-abcdef012345 or 1.2.4-0.20220101000000-abcdef012345.
+// See https://go.dev/ref/mod#pseudo-versions.
+var pseudoVersion = regexp.MustCompile(`(?:^|[-.])\d{14}-([0-9a-f]{12})$`)
+
+// majorVersionSuffix matches the major version suffix of Go module paths, e.g. /v2.
+var majorVersionSuffix = regexp.MustCompile(`/v\d+$`)
+
+// versionCommit resolves the commit of the source repository a package version was built from.
+// Versions which can't be resolved return clients.HeadSHA and pkg.NotResolved.
+func (r *registryClient) versionCommit(ctx context.Context, p clients.Package,
+	sourceRepo string,
+) (string, pkg.CommitResolution) {
+	switch p.Ecosystem {
+	case clients.EcosystemGo:
+		if m := pseudoVersion.FindStringSubmatch(p.Version); m != nil {
+			if sha, err := r.commitSHA(ctx, sourceRepo, m[1]); err == nil {
+				return sha, pkg.ResolvedByPseudoVersion
+			}
+			return clients.HeadSHA, pkg.NotResolved
+		}
+	case clients.EcosystemNPM:
+		if sha, err := r.npmGitHead(ctx, p); err == nil && sha != "" {
+			return sha, pkg.ResolvedByGitHead
+		}
+	case clients.EcosystemCratesIO:
+		if sha, err := r.crateVCSInfo(ctx, p); err == nil && sha != "" {
+			return sha, pkg.ResolvedByVCSInfo
+		}
+	}
+	for _, tag := range versionTags(p, sourceRepo) {
+		if sha, err := r.commitSHA(ctx, sourceRepo, tag); err == nil {
+			return sha, pkg.ResolvedByTag
+		}
+	}
+	return clients.HeadSHA, pkg.NotResolved
+}
+
+// versionTags returns the tags a package version is commonly released with, in order of preference.
+func versionTags(p clients.Package, sourceRepo string) []string {
+	if p.Ecosystem == clients.EcosystemGo {
+		// Modules in subdirectories are tagged with the subdirectory as prefix,
+		// without the major version suffix, e.g. sub/v2.0.0 for github.com/owner/repo/sub/v2.
+		prefix := ""
+		modulePath := majorVersionSuffix.ReplaceAllString(p.Name, "")
+		if subdir := strings.TrimPrefix(modulePath, repoURI(sourceRepo)); subdir != modulePath {
+			if subdir = strings.Trim(subdir, "/"); subdir != "" {
+				prefix = subdir + "/"
+			}
+		}
+		return []string{prefix + "v" + p.Version}
+	}
+	tags := []string{"v" + p.Version, p.Version}
+	// Repositories of several packages prefix their tags with the package name.
+	name := p.Name[strings.LastIndex(p.Name, "/")+1:]
+	return append(tags, name+"-v"+p.Version, name+"-"+p.Version, name+"@"+p.Version)
+}
+
+// commitSHA returns the full SHA of a commit, short SHA or tag of a GitHub repository.
+func (r *registryClient) commitSHA(ctx context.Context, sourceRepo, ref string) (string, error) {
+	parts := strings.Split(repoURI(sourceRepo), "/")
+	if len(parts) != 3 || parts[0] != githubHost {
+		return "", fmt.Errorf("%w: not a GitHub repository: %s", errInvalid, sourceRepo)
+	}
+	r.githubOnce.Do(func() {
+		r.github = r.newGitHub()
+	})
+	sha, _, err := r.github.Repositories.GetCommitSHA1(ctx, parts[1], parts[2], ref, "")
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s of %s: %w", ref, sourceRepo, err)
+	}
+	return sha, nil
+}
+
+// npmGitHead returns the commit an npm package version was published from, if recorded.
+func (r *registryClient) npmGitHead(ctx context.Context, p clients.Package) (string, error) {
+	var v struct {
+		GitHead string `json:"gitHead"`
+	}
+	if err := r.getJSON(ctx, r.npmVersionURL(p), &v); err != nil {
+		return "", err
+	}
+	return v.GitHead, nil
+}
+
+// crateVCSInfo returns the commit a crate version was packaged from,
+// which cargo records in the .cargo_vcs_info.json of the crate.
+func (r *registryClient) crateVCSInfo(ctx context.Context, p clients.Package) (string, error) {
+	// Crates are downloaded once per version, so they aren't memoized.
+	body, err := r.fetch(ctx, r.cratesURL+"/crates/"+url.PathEscape(p.Name)+"/"+url.PathEscape(p.Version)+"/download")
+	if err != nil {
+		return "", err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("gzip.NewReader: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("tar.Next: %w", err)
+		}
+		if hdr.Name != p.Name+"-"+p.Version+"/.cargo_vcs_info.json" {
+			continue
+		}
+		var info struct {
+			Git struct {
+				SHA1 string `json:"sha1"`
+			} `json:"git"`
+		}
+		if err := json.NewDecoder(tr).Decode(&info); err != nil {
+			return "", fmt.Errorf("error parsing .cargo_vcs_info.json: %w", err)
+		}
+		return info.Git.SHA1, nil
+	}
+}
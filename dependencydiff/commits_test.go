@@ -0,0 +1,212 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v38/github"
+
+	"github.com/ossf/scorecard/v4/checks"
+	"github.com/ossf/scorecard/v4/clients"
+	sclog "github.com/ossf/scorecard/v4/log"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+func crateTarball(t *testing.T, name, version, vcsInfo string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	files := map[string]string{
+		fmt.Sprintf("%s-%s/Cargo.toml", name, version):           "[package]\n",
+		fmt.Sprintf("%s-%s/.cargo_vcs_info.json", name, version): vcsInfo,
+	}
+	for fname, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: fname, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			t.Fatalf("tar.WriteHeader: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("tar.Write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar.Close: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip.Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func Test_registryClient_versionCommit(t *testing.T) {
+	t.Parallel()
+	crate := crateTarball(t, "serde", "1.0.0", `{"git": {"sha1": "5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e"}}`)
+	// Full SHAs of the refs of the GitHub repositories.
+	refs := map[string]string{
+		"/github/repos/owner/repo/commits/abcdef012345": "abcdef0123456789abcdef0123456789abcdef01",
+		"/github/repos/owner/repo/commits/sub/v2.1.0":   "2121212121212121212121212121212121212121",
+		"/github/repos/owner/repo/commits/v1.0.0":       "1010101010101010101010101010101010101010",
+		"/github/repos/owner/mono/commits/pkg-v3.0.0":   "3030303030303030303030303030303030303030",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sha, ok := refs[r.URL.Path]; ok {
+			fmt.Fprint(w, sha)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/npm/left-pad/1.3.0":
+			fmt.Fprint(w, `{"gitHead": "9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f"}`)
+		case "/crates/crates/serde/1.0.0/download":
+			w.Write(crate) //nolint:errcheck
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	ghClient := github.NewClient(nil)
+	baseURL, err := url.Parse(srv.URL + "/github/")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+	ghClient.BaseURL = baseURL
+	registry := newRegistryClient(func() *github.Client { return ghClient })
+	registry.npmURL = srv.URL + "/npm"
+	registry.cratesURL = srv.URL + "/crates"
+
+	tests := []struct {
+		name           string
+		p              clients.Package
+		sourceRepo     string
+		wantCommit     string
+		wantResolution pkg.CommitResolution
+	}{
+		{
+			name:           "npm gitHead",
+			p:              clients.Package{Ecosystem: clients.EcosystemNPM, Name: "left-pad", Version: "1.3.0"},
+			sourceRepo:     "https://github.com/owner/left-pad",
+			wantCommit:     "9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f9f",
+			wantResolution: pkg.ResolvedByGitHead,
+		},
+		{
+			name:           "crate vcs info",
+			p:              clients.Package{Ecosystem: clients.EcosystemCratesIO, Name: "serde", Version: "1.0.0"},
+			sourceRepo:     "https://github.com/serde-rs/serde",
+			wantCommit:     "5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e5e",
+			wantResolution: pkg.ResolvedByVCSInfo,
+		},
+		{
+			name: "go pseudo-version",
+			p: clients.Package{
+				Ecosystem: clients.EcosystemGo,
+				Name:      "github.com/owner/repo",
+				Version:   "1.2.4-0.20220101000000-abcdef012345",
+			},
+			sourceRepo:     "https://github.com/owner/repo",
+			wantCommit:     "abcdef0123456789abcdef0123456789abcdef01",
+			wantResolution: pkg.ResolvedByPseudoVersion,
+		},
+		{
+			name:           "go module in a subdirectory",
+			p:              clients.Package{Ecosystem: clients.EcosystemGo, Name: "github.com/owner/repo/sub/v2", Version: "2.1.0"},
+			sourceRepo:     "https://github.com/owner/repo",
+			wantCommit:     "2121212121212121212121212121212121212121",
+			wantResolution: pkg.ResolvedByTag,
+		},
+		{
+			name:           "pypi tag",
+			p:              clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "repo", Version: "1.0.0"},
+			sourceRepo:     "https://github.com/owner/repo",
+			wantCommit:     "1010101010101010101010101010101010101010",
+			wantResolution: pkg.ResolvedByTag,
+		},
+		{
+			name:           "tag prefixed with the package name",
+			p:              clients.Package{Ecosystem: clients.EcosystemNPM, Name: "@scope/pkg", Version: "3.0.0"},
+			sourceRepo:     "https://github.com/owner/mono",
+			wantCommit:     "3030303030303030303030303030303030303030",
+			wantResolution: pkg.ResolvedByTag,
+		},
+		{
+			name:           "unresolved",
+			p:              clients.Package{Ecosystem: clients.EcosystemRubyGems, Name: "rails", Version: "7.0.0"},
+			sourceRepo:     "https://github.com/rails/rails",
+			wantCommit:     clients.HeadSHA,
+			wantResolution: pkg.NotResolved,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			commit, resolution := registry.versionCommit(context.Background(), tt.p, tt.sourceRepo)
+			if commit != tt.wantCommit || resolution != tt.wantResolution {
+				t.Errorf("versionCommit() = %s, %s, want %s, %s", commit, resolution, tt.wantCommit, tt.wantResolution)
+			}
+		})
+	}
+}
+
+func Test_getScorecardCheckResults_scoredCommit(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/npm/left-pad/1.3.0":
+			fmt.Fprint(w, `{"gitHead": "sha1"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	registry := newRegistryClient(func() *github.Client { return github.NewClient(nil) })
+	registry.npmURL = srv.URL + "/npm"
+	scores := &fakeScoreSource{
+		results: map[string]*pkg.ScorecardResult{
+			"https://github.com/owner/left-pad@sha1": testResult("sha1", map[string]int{checks.CheckLicense: 10}),
+		},
+	}
+	dCtx := dependencydiffContext{
+		logger:          sclog.NewLogger(sclog.InfoLevel),
+		ctx:             context.Background(),
+		registry:        registry,
+		scores:          scores,
+		checkNamesToRun: []string{checks.CheckLicense},
+		dependencydiffs: []dependency{
+			newDependency(packageKey{ecosystem: clients.EcosystemNPM, name: "left-pad"}, "1.3.0", pkg.Added),
+		},
+	}
+	dCtx.dependencydiffs[0].SourceRepository = asPointer("https://github.com/owner/left-pad")
+	if err := getScorecardCheckResults(&dCtx); err != nil {
+		t.Fatalf("getScorecardCheckResults: %v", err)
+	}
+	got := dCtx.results[0]
+	if got.ScoredCommit == nil || *got.ScoredCommit != "sha1" {
+		t.Errorf("ScoredCommit = %v, want sha1", got.ScoredCommit)
+	}
+	if got.CommitResolution == nil || *got.CommitResolution != pkg.ResolvedByGitHead {
+		t.Errorf("CommitResolution = %v, want %s", got.CommitResolution, pkg.ResolvedByGitHead)
+	}
+	if got.ScorecardResultWithError.ScorecardResult == nil {
+		t.Errorf("ScorecardResult is nil, error: %v", got.ScorecardResultWithError.Error)
+	}
+}
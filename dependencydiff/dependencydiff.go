@@ -19,9 +19,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/google/go-github/v38/github"
+
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/checks"
 	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/clients/localdir"
 	sce "github.com/ossf/scorecard/v4/errors"
 	sclog "github.com/ossf/scorecard/v4/log"
 	"github.com/ossf/scorecard/v4/pkg"
@@ -41,6 +44,8 @@ type dependencydiffContext struct {
 	ossFuzzClient                   clients.RepoClient
 	vulnsClient                     clients.VulnerabilitiesClient
 	ciiClient                       clients.CIIBestPracticesClient
+	registry                        *registryClient
+	scores                          ScoreSource
 	changeTypesToCheck              []string
 	checkNamesToRun                 []string
 	dependencydiffs                 []dependency
@@ -56,6 +61,19 @@ func GetDependencyDiffResults(
 	base, head string, /* Two code commits base and head, can use either SHAs or branch names. */
 	checksToRun []string, /* A list of enabled check names to run. */
 	changeTypes []string, /* A list of dependency change types for which we surface scorecard results. */
+) ([]pkg.DependencyCheckResult, error) {
+	return GetDependencyDiffResultsWithScoreSource(ctx, repoURI, base, head, checksToRun, changeTypes, nil)
+}
+
+// GetDependencyDiffResultsWithScoreSource is GetDependencyDiffResults getting the Scorecard check results
+// of the dependencies from the given ScoreSource. A nil ScoreSource runs Scorecard live on the dependencies.
+func GetDependencyDiffResultsWithScoreSource(
+	ctx context.Context,
+	repoURI string,
+	base, head string,
+	checksToRun []string,
+	changeTypes []string,
+	scores ScoreSource,
 ) ([]pkg.DependencyCheckResult, error) {
 	logger := sclog.NewLogger(sclog.DefaultLevel)
 	ownerAndRepo := strings.Split(repoURI, "/")
@@ -63,6 +81,9 @@ func GetDependencyDiffResults(
 		return nil, fmt.Errorf("%w: repo uri input", errInvalid)
 	}
 	owner, repo := ownerAndRepo[0], ownerAndRepo[1]
+	newGitHub := func() *github.Client {
+		return newGitHubClient(ctx, logger)
+	}
 	dCtx := dependencydiffContext{
 		logger:             logger,
 		ownerName:          owner,
@@ -70,6 +91,8 @@ func GetDependencyDiffResults(
 		base:               base,
 		head:               head,
 		ctx:                ctx,
+		registry:           newRegistryClient(newGitHub),
+		scores:             scoreSourceOrDefault(scores),
 		changeTypesToCheck: changeTypes,
 		checkNamesToRun:    checksToRun,
 	}
@@ -90,6 +113,78 @@ func GetDependencyDiffResults(
 	return dCtx.results, nil
 }
 
+// GetDependencyDiffResultsFromClients gets dependency changes between the two revisions the given
+// repo clients were initialized at, along with the Scorecard check results of the dependencies.
+// Unlike GetDependencyDiffResults, the changes are computed from the manifests and lockfiles of both
+// revisions instead of the GitHub Dependency Review API, so the clients can be GitLab, GitHub Enterprise
+// or local directory clients. The source repositories of the dependencies are looked up in the metadata
+// of their package registries. A nil ScoreSource runs Scorecard live on the dependencies.
+func GetDependencyDiffResultsFromClients(
+	ctx context.Context,
+	baseClient, headClient clients.RepoClient, /* Repo clients initialized at the base and head revisions. */
+	checksToRun []string, /* A list of enabled check names to run. */
+	changeTypes []string, /* A list of dependency change types for which we surface scorecard results. */
+	scores ScoreSource, /* Where to get the Scorecard check results of the dependencies from. */
+) ([]pkg.DependencyCheckResult, error) {
+	logger := sclog.NewLogger(sclog.DefaultLevel)
+	newGitHub := func() *github.Client {
+		return newGitHubClient(ctx, logger)
+	}
+	dCtx := dependencydiffContext{
+		logger:             logger,
+		ctx:                ctx,
+		registry:           newRegistryClient(newGitHub),
+		scores:             scoreSourceOrDefault(scores),
+		changeTypesToCheck: changeTypes,
+		checkNamesToRun:    checksToRun,
+	}
+	// The ecosystems of the lockfiles already follow the OSV naming.
+	err := fetchLocalDependencyDiffData(&dCtx, baseClient, headClient)
+	if err != nil {
+		return nil, fmt.Errorf("error in fetchLocalDependencyDiffData: %w", err)
+	}
+	err = getScorecardCheckResults(&dCtx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scorecard check results: %w", err)
+	}
+	return dCtx.results, nil
+}
+
+// GetDependencyDiffResultsFromDirs is GetDependencyDiffResultsFromClients
+// for two local checkouts of the base and head revisions.
+func GetDependencyDiffResultsFromDirs(
+	ctx context.Context,
+	baseDir, headDir string,
+	checksToRun []string,
+	changeTypes []string,
+	scores ScoreSource,
+) ([]pkg.DependencyCheckResult, error) {
+	logger := sclog.NewLogger(sclog.DefaultLevel)
+	repoClients := make([]clients.RepoClient, 0, 2)
+	for _, dir := range []string{baseDir, headDir} {
+		repo, err := localdir.MakeLocalDirRepo(dir)
+		if err != nil {
+			return nil, fmt.Errorf("error in MakeLocalDirRepo: %w", err)
+		}
+		repoClient := localdir.CreateLocalDirClient(ctx, logger)
+		if err := repoClient.InitRepo(repo, clients.HeadSHA); err != nil {
+			return nil, fmt.Errorf("error in InitRepo: %w", err)
+		}
+		defer repoClient.Close()
+		repoClients = append(repoClients, repoClient)
+	}
+	return GetDependencyDiffResultsFromClients(ctx, repoClients[0], repoClients[1], checksToRun, changeTypes, scores)
+}
+
+// scoreSourceOrDefault returns the ScoreSource, or one running Scorecard live
+// and memoizing the results of the dependencies for a nil ScoreSource.
+func scoreSourceOrDefault(scores ScoreSource) ScoreSource {
+	if scores != nil {
+		return scores
+	}
+	return NewCachingScoreSource(NewLiveScoreSource())
+}
+
 func initRepoAndClientByChecks(dCtx *dependencydiffContext, dSrcRepo string) error {
 	repo, repoClient, ossFuzzClient, ciiClient, vulnsClient, err := checker.GetClients(
 		dCtx.ctx, dSrcRepo, "", dCtx.logger,
@@ -123,6 +218,10 @@ func getScorecardCheckResults(dCtx *dependencydiffContext) error {
 	if err != nil {
 		return fmt.Errorf("error init scorecard checks: %w", err)
 	}
+	commitChecksToRun, err := commitBasedChecks(dCtx.checkNamesToRun, checksToRun)
+	if err != nil {
+		return fmt.Errorf("error init scorecard checks: %w", err)
+	}
 	for _, d := range dCtx.dependencydiffs {
 		depCheckResult := pkg.DependencyCheckResult{
 			PackageURL:       d.PackageURL,
@@ -146,28 +245,26 @@ func getScorecardCheckResults(dCtx *dependencydiffContext) error {
 			isSpecifiedByUser(*d.ChangeType, dCtx.changeTypesToCheck) /* Specified by the user.*/
 		// For now we skip those without source repo urls.
 		// TODO (#2063): use the BigQuery dataset to supplement null source repo URLs to fetch the Scorecard results for them.
+		if d.SourceRepository != nil && noneGivenOrIsSpecified && !isGitHubRepo(*d.SourceRepository) {
+			// Such dependencies are reported with the reason they have no Scorecard results.
+			depCheckResult.ScorecardResultWithError.Error = fmt.Errorf(
+				"%w: %s isn't hosted on GitHub, the only host Scorecard can score", errUnsupportedRepo, *d.SourceRepository)
+			dCtx.results = append(dCtx.results, depCheckResult)
+			continue
+		}
 		if d.SourceRepository != nil && noneGivenOrIsSpecified {
-			// Initialize the repo and client(s) corresponding to the checks to run.
-			err = initRepoAndClientByChecks(dCtx, *d.SourceRepository)
-			if err != nil {
-				return fmt.Errorf("error init repo and clients: %w", err)
+			// Score the source repository at the commit of the dependency version.
+			commitSHA, resolution := clients.HeadSHA, pkg.NotResolved
+			if d.Ecosystem != nil && d.Version != nil && dCtx.registry != nil {
+				commitSHA, resolution = dCtx.registry.versionCommit(dCtx.ctx,
+					clients.Package{Ecosystem: *d.Ecosystem, Name: d.Name, Version: *d.Version}, *d.SourceRepository)
 			}
-
-			// Run scorecard on those types of dependencies that the caller would like to check.
-			// If the input map changeTypesToCheck is empty, by default, we run the checks for all valid types.
-			// TODO (#2064): use the Scorecare REST API to retrieve the Scorecard result statelessly.
-			scorecardResult, err := pkg.RunScorecards(
-				dCtx.ctx,
-				dCtx.ghRepo,
-				// TODO (#2065): In future versions, ideally, this should be
-				// the commitSHA corresponding to d.Version instead of HEAD.
-				clients.HeadSHA,
-				checksToRun,
-				dCtx.ghRepoClient,
-				dCtx.ossFuzzClient,
-				dCtx.ciiClient,
-				dCtx.vulnsClient,
-			)
+			depCheckResult.CommitResolution = &resolution
+			if commitSHA != clients.HeadSHA {
+				depCheckResult.ScoredCommit = asPointer(commitSHA)
+			}
+			scorecardResult, err := scoreDependency(dCtx, *d.SourceRepository, commitSHA,
+				checksToRun, commitChecksToRun)
 			// If the run fails, we leave the current dependency scorecard result empty and record the error
 			// rather than letting the entire API return nil since we still expect results for other dependencies.
 			if err != nil {
@@ -176,7 +273,10 @@ func getScorecardCheckResults(dCtx *dependencydiffContext) error {
 				dCtx.logger.Error(wrappedErr, "")
 				depCheckResult.ScorecardResultWithError.Error = wrappedErr
 			} else { // Otherwise, we record the scorecard check results for this dependency.
-				depCheckResult.ScorecardResultWithError.ScorecardResult = &scorecardResult
+				depCheckResult.ScorecardResultWithError.ScorecardResult = scorecardResult
+				if scorecardResult.Repo.CommitSHA != "" {
+					depCheckResult.ScoredCommit = asPointer(scorecardResult.Repo.CommitSHA)
+				}
 			}
 		}
 		dCtx.results = append(dCtx.results, depCheckResult)
@@ -184,6 +284,61 @@ func getScorecardCheckResults(dCtx *dependencydiffContext) error {
 	return nil
 }
 
+// scoreDependency returns the results of checksToRun for the source repo of a dependency at commitSHA.
+// At a resolved commit, the checks supporting commits run on it, and the others, e.g. Maintained,
+// which are about the project rather than its code, run at HEAD and their results are merged in.
+func scoreDependency(dCtx *dependencydiffContext, repoURL, commitSHA string,
+	checksToRun, commitChecksToRun checker.CheckNameToFnMap,
+) (*pkg.ScorecardResult, error) {
+	if commitSHA == clients.HeadSHA {
+		//nolint:wrapcheck
+		return dCtx.scores.GetScore(dCtx.ctx, repoURL, commitSHA, checksToRun)
+	}
+	result, err := dCtx.scores.GetScore(dCtx.ctx, repoURL, commitSHA, commitChecksToRun)
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+	headChecksToRun := checker.CheckNameToFnMap{}
+	for name, check := range checksToRun {
+		if _, ok := commitChecksToRun[name]; !ok {
+			headChecksToRun[name] = check
+		}
+	}
+	if len(headChecksToRun) == 0 {
+		return result, nil
+	}
+	headResult, err := dCtx.scores.GetScore(dCtx.ctx, repoURL, clients.HeadSHA, headChecksToRun)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the results at HEAD: %w", err)
+	}
+	headResult, _ = withChecks(headResult, headChecksToRun)
+	merged := *result
+	merged.Checks = make([]checker.CheckResult, 0, len(result.Checks)+len(headResult.Checks))
+	merged.Checks = append(append(merged.Checks, result.Checks...), headResult.Checks...)
+	return &merged, nil
+}
+
+// commitBasedChecks returns the checks to run on the resolved commits of the dependencies:
+// those of checksToRun which support commits, or all the checks supporting them by default.
+func commitBasedChecks(checkNames []string, checksToRun checker.CheckNameToFnMap) (checker.CheckNameToFnMap, error) {
+	commitBased := []checker.RequestType{checker.CommitBased}
+	if len(checkNames) == 0 {
+		ret, err := policy.GetEnabled(nil, nil, commitBased)
+		if err != nil {
+			return nil, fmt.Errorf("policy.GetEnabled: %w", err)
+		}
+		return ret, nil
+	}
+	ret := checker.CheckNameToFnMap{}
+	for name, check := range checksToRun {
+		if len(checker.ListUnsupported(commitBased, check.SupportedRequestTypes)) == 0 {
+			ret[name] = check
+		}
+	}
+	return ret, nil
+}
+
 func isSpecifiedByUser(ct pkg.ChangeType, changeTypes []string) bool {
 	if len(changeTypes) == 0 {
 		return false
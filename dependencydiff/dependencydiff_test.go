@@ -17,9 +17,16 @@ package dependencydiff
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"path"
+	"sort"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/checks"
 	"github.com/ossf/scorecard/v4/clients"
 	sclog "github.com/ossf/scorecard/v4/log"
 	"github.com/ossf/scorecard/v4/pkg"
@@ -160,6 +167,137 @@ func Test_getScorecardCheckResults(t *testing.T) {
 	}
 }
 
+func Test_getScorecardCheckResultsUnsupportedRepo(t *testing.T) {
+	t.Parallel()
+	scores := &fakeScoreSource{}
+	added := pkg.Added
+	dCtx := dependencydiffContext{
+		ctx:    context.Background(),
+		logger: sclog.NewLogger(sclog.InfoLevel),
+		scores: scores,
+		dependencydiffs: []dependency{
+			{
+				Name:             "project",
+				ChangeType:       &added,
+				SourceRepository: asPointer("https://gitlab.com/group/project"),
+			},
+		},
+	}
+	if err := getScorecardCheckResults(&dCtx); err != nil {
+		t.Fatalf("getScorecardCheckResults: %v", err)
+	}
+	if scores.calls != 0 {
+		t.Errorf("getScorecardCheckResults() scored a GitLab repository")
+	}
+	if len(dCtx.results) != 1 || !errors.Is(dCtx.results[0].ScorecardResultWithError.Error, errUnsupportedRepo) {
+		t.Errorf("getScorecardCheckResults() = %+v, want a result with error %v", dCtx.results, errUnsupportedRepo)
+	}
+}
+
+func Test_getScorecardCheckResultsAtCommit(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/npm/resolved/1.0.0":
+			fmt.Fprint(w, `{"gitHead": "sha1"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	registry := newRegistryClient(nil)
+	registry.npmURL = srv.URL + "/npm"
+
+	tests := []struct {
+		name       string
+		registry   *registryClient
+		want       map[string][]string
+		wantChecks []string
+	}{
+		{
+			name: "HEAD",
+			want: map[string][]string{
+				"https://github.com/owner/repo@" + clients.HeadSHA: {checks.CheckLicense, checks.CheckMaintained},
+			},
+			wantChecks: []string{checks.CheckLicense, checks.CheckMaintained},
+		},
+		{
+			// Maintained only runs at HEAD, its result is merged in.
+			name:     "resolved commit",
+			registry: registry,
+			want: map[string][]string{
+				"https://github.com/owner/repo@sha1":               {checks.CheckLicense},
+				"https://github.com/owner/repo@" + clients.HeadSHA: {checks.CheckMaintained},
+			},
+			wantChecks: []string{checks.CheckLicense, checks.CheckMaintained},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			scores := &fakeScoreSource{
+				checksRun: make(map[string][]string),
+				results: map[string]*pkg.ScorecardResult{
+					"https://github.com/owner/repo@sha1": testResult("sha1",
+						map[string]int{checks.CheckLicense: 10, checks.CheckMaintained: 0}),
+					"https://github.com/owner/repo@" + clients.HeadSHA: testResult("sha2",
+						map[string]int{checks.CheckLicense: 10, checks.CheckMaintained: 10}),
+				},
+			}
+			added := pkg.Added
+			dCtx := dependencydiffContext{
+				ctx:             context.Background(),
+				logger:          sclog.NewLogger(sclog.InfoLevel),
+				registry:        tt.registry,
+				scores:          scores,
+				checkNamesToRun: []string{checks.CheckMaintained, checks.CheckLicense},
+				dependencydiffs: []dependency{
+					{
+						Name:             "resolved",
+						Ecosystem:        asPointer(clients.EcosystemNPM),
+						Version:          asPointer("1.0.0"),
+						ChangeType:       &added,
+						SourceRepository: asPointer("https://github.com/owner/repo"),
+					},
+				},
+			}
+			if err := getScorecardCheckResults(&dCtx); err != nil {
+				t.Fatalf("getScorecardCheckResults: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, scores.checksRun); diff != "" {
+				t.Errorf("checks run mismatch (-want +got):\n%s", diff)
+			}
+			result := dCtx.results[0].ScorecardResultWithError.ScorecardResult
+			if result == nil {
+				t.Fatalf("no result, error: %v", dCtx.results[0].ScorecardResultWithError.Error)
+			}
+			var gotChecks []string
+			for i := range result.Checks {
+				gotChecks = append(gotChecks, result.Checks[i].Name)
+			}
+			sort.Strings(gotChecks)
+			if diff := cmp.Diff(tt.wantChecks, gotChecks); diff != "" {
+				t.Errorf("checks mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_commitBasedChecks(t *testing.T) {
+	t.Parallel()
+	got, err := commitBasedChecks(nil, nil)
+	if err != nil {
+		t.Fatalf("commitBasedChecks: %v", err)
+	}
+	if _, ok := got[checks.CheckMaintained]; ok {
+		t.Errorf("commitBasedChecks() enabled %s, which only runs at HEAD", checks.CheckMaintained)
+	}
+	if _, ok := got[checks.CheckLicense]; !ok {
+		t.Errorf("commitBasedChecks() didn't enable %s", checks.CheckLicense)
+	}
+}
+
 func Test_mapDependencyEcosystemNaming(t *testing.T) {
 	t.Parallel()
 	//nolint
@@ -20,4 +20,6 @@ import "errors"
 var (
 	errMappingNotFound = errors.New("ecosystem mapping not found")
 	errInvalid         = errors.New("invalid")
+	errRegistry        = errors.New("package registry request failed")
+	errUnsupportedRepo = errors.New("unsupported source repository")
 )
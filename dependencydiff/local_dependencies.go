@@ -0,0 +1,188 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/clients/lockfile"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// packageKey identifies a dependency of a manifest regardless of its version.
+type packageKey struct {
+	manifest, ecosystem, name string
+}
+
+// lockfileDependencies returns the versions of the packages pinned by the lockfiles
+// of the revision the repo client was initialized at.
+func lockfileDependencies(repoClient clients.RepoClient) (map[packageKey][]string, error) {
+	files, err := repoClient.ListFiles(func(p string) (bool, error) {
+		return lockfile.IsLockfile(p) && !strings.Contains(p, "testdata/"), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing lockfiles: %w", err)
+	}
+	// go.sum also lists the modules needed to resolve the build list,
+	// so it's only used where there's no go.mod.
+	goMods := make(map[string]bool)
+	for _, f := range files {
+		if path.Base(f) == "go.mod" {
+			goMods[path.Dir(f)] = true
+		}
+	}
+
+	versions := make(map[packageKey]map[string]bool)
+	for _, f := range files {
+		if path.Base(f) == "go.sum" && goMods[path.Dir(f)] {
+			continue
+		}
+		content, err := repoClient.GetFileContent(f)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", f, err)
+		}
+		pkgs, err := lockfile.Parse(f, content)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", f, err)
+		}
+		for _, p := range pkgs {
+			k := packageKey{manifest: f, ecosystem: p.Ecosystem, name: p.Name}
+			if versions[k] == nil {
+				versions[k] = make(map[string]bool)
+			}
+			versions[k][p.Version] = true
+		}
+	}
+
+	ret := make(map[packageKey][]string, len(versions))
+	for k, vs := range versions {
+		for v := range vs {
+			ret[k] = append(ret[k], v)
+		}
+		sort.Strings(ret[k])
+	}
+	return ret, nil
+}
+
+// diffDependencies returns the dependencies added, updated and removed between
+// the base and head revisions. A package whose single version changed is updated,
+// otherwise each version is either added or removed.
+func diffDependencies(base, head map[packageKey][]string) []dependency {
+	keys := make(map[packageKey]bool)
+	for k := range base {
+		keys[k] = true
+	}
+	for k := range head {
+		keys[k] = true
+	}
+	sorted := make([]packageKey, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].manifest != sorted[j].manifest {
+			return sorted[i].manifest < sorted[j].manifest
+		}
+		if sorted[i].ecosystem != sorted[j].ecosystem {
+			return sorted[i].ecosystem < sorted[j].ecosystem
+		}
+		return sorted[i].name < sorted[j].name
+	})
+
+	var ret []dependency
+	for _, k := range sorted {
+		removed := difference(base[k], head[k])
+		added := difference(head[k], base[k])
+		if len(removed) == 1 && len(added) == 1 {
+			ret = append(ret, newDependency(k, added[0], pkg.Updated))
+			continue
+		}
+		for _, v := range added {
+			ret = append(ret, newDependency(k, v, pkg.Added))
+		}
+		for _, v := range removed {
+			ret = append(ret, newDependency(k, v, pkg.Removed))
+		}
+	}
+	return ret
+}
+
+// difference returns the versions of a which are not in b.
+func difference(a, b []string) []string {
+	var ret []string
+	for _, v := range a {
+		found := false
+		for _, w := range b {
+			if v == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func newDependency(k packageKey, version string, changeType pkg.ChangeType) dependency {
+	ct := changeType
+	d := dependency{
+		ChangeType:   &ct,
+		ManifestPath: asPointer(k.manifest),
+		Ecosystem:    asPointer(k.ecosystem),
+		Version:      asPointer(version),
+		Name:         k.name,
+	}
+	p := clients.Package{Ecosystem: k.ecosystem, Name: k.name, Version: version}
+	if purl := lockfile.PackageURL(p); purl != "" {
+		d.PackageURL = asPointer(purl)
+	}
+	return d
+}
+
+// fetchLocalDependencyDiffData computes the dependency-diffs from the lockfiles at the
+// revisions the base and head clients were initialized at, and resolves the source
+// repositories of the dependencies from the metadata of their package registries.
+func fetchLocalDependencyDiffData(dCtx *dependencydiffContext, baseClient, headClient clients.RepoClient) error {
+	base, err := lockfileDependencies(baseClient)
+	if err != nil {
+		return fmt.Errorf("error reading the base dependencies: %w", err)
+	}
+	head, err := lockfileDependencies(headClient)
+	if err != nil {
+		return fmt.Errorf("error reading the head dependencies: %w", err)
+	}
+	dCtx.dependencydiffs = diffDependencies(base, head)
+	for i := range dCtx.dependencydiffs {
+		d := &dCtx.dependencydiffs[i]
+		p := clients.Package{Ecosystem: *d.Ecosystem, Name: d.Name, Version: *d.Version}
+		source, err := dCtx.registry.sourceRepository(dCtx.ctx, p)
+		if err != nil {
+			// Such dependencies are reported without Scorecard results like
+			// those without a source repository rather than failing the diff.
+			dCtx.logger.Info(fmt.Sprintf("error resolving the source repository of %s: %v", d.Name, err))
+			continue
+		}
+		if source != "" {
+			d.SourceRepository = asPointer(source)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/clients/githubrepo/roundtripper"
+	mockrepo "github.com/ossf/scorecard/v4/clients/mockclients"
+	sclog "github.com/ossf/scorecard/v4/log"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+func mockRepoClient(ctrl *gomock.Controller, files map[string]string) clients.RepoClient {
+	repoClient := mockrepo.NewMockRepoClient(ctrl)
+	repoClient.EXPECT().ListFiles(gomock.Any()).DoAndReturn(
+		func(predicate func(string) (bool, error)) ([]string, error) {
+			var ret []string
+			for f := range files {
+				if ok, _ := predicate(f); ok {
+					ret = append(ret, f)
+				}
+			}
+			return ret, nil
+		}).AnyTimes()
+	repoClient.EXPECT().GetFileContent(gomock.Any()).DoAndReturn(
+		func(f string) ([]byte, error) {
+			return []byte(files[f]), nil
+		}).AnyTimes()
+	return repoClient
+}
+
+func Test_fetchLocalDependencyDiffData(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/npm/@scope%2Fadded/1.0.0":
+			fmt.Fprint(w, `{"repository": {"type": "git", "url": "git+https://github.com/scope/added.git"}}`)
+		case "/npm/left-pad/1.3.0":
+			fmt.Fprint(w, `{"repository": "https://gitlab.com/left/pad"}`)
+		case "/go/example.com/vanity":
+			fmt.Fprint(w, `<html><head>
+				<meta name="go-import" content="example.com/vanity git https://github.com/example/vanity">
+			</head></html>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	ctrl := gomock.NewController(t)
+	base := mockRepoClient(ctrl, map[string]string{
+		"go.mod": "module m\n\nrequire (\n\tgithub.com/owner/repo v1.0.0\n\texample.com/vanity v0.1.0\n)\n",
+		"go.sum": "golang.org/x/text v0.3.0 h1:abc=\n",
+		"web/package-lock.json": `{"lockfileVersion": 2, "packages": {
+			"node_modules/left-pad": {"version": "1.3.0"},
+			"node_modules/unknown": {"version": "1.0.0"}
+		}}`,
+		"testdata/Cargo.lock": "[[package]]\nname = \"serde\"\nversion = \"1.0.0\"\n",
+	})
+	head := mockRepoClient(ctrl, map[string]string{
+		"go.mod": "module m\n\nrequire (\n\tgithub.com/owner/repo v1.1.0\n\texample.com/vanity v0.1.0\n)\n",
+		"web/package-lock.json": `{"lockfileVersion": 2, "packages": {
+			"node_modules/@scope/added": {"version": "1.0.0"},
+			"node_modules/unknown": {"version": "2.0.0"},
+			"node_modules/a/node_modules/unknown": {"version": "3.0.0"}
+		}}`,
+	})
+
+	registry := newRegistryClient(nil)
+	registry.npmURL = srv.URL + "/npm"
+	registry.goImportURL = srv.URL + "/go/%s"
+	dCtx := dependencydiffContext{
+		logger:   sclog.NewLogger(sclog.InfoLevel),
+		ctx:      context.Background(),
+		registry: registry,
+	}
+	if err := fetchLocalDependencyDiffData(&dCtx, base, head); err != nil {
+		t.Fatalf("fetchLocalDependencyDiffData: %v", err)
+	}
+
+	newDep := func(manifest, ecosystem, name, version, purl, source string, ct pkg.ChangeType) dependency {
+		d := newDependency(packageKey{manifest: manifest, ecosystem: ecosystem, name: name}, version, ct)
+		if d.PackageURL == nil || *d.PackageURL != purl {
+			t.Errorf("package URL of %s = %v, want %s", name, d.PackageURL, purl)
+		}
+		if source != "" {
+			d.SourceRepository = asPointer(source)
+		}
+		return d
+	}
+	want := []dependency{
+		newDep("go.mod", clients.EcosystemGo, "github.com/owner/repo", "1.1.0",
+			"pkg:golang/github.com/owner/repo@v1.1.0", "https://github.com/owner/repo", pkg.Updated),
+		newDep("web/package-lock.json", clients.EcosystemNPM, "@scope/added", "1.0.0",
+			"pkg:npm/%40scope/added@1.0.0", "https://github.com/scope/added", pkg.Added),
+		newDep("web/package-lock.json", clients.EcosystemNPM, "left-pad", "1.3.0",
+			"pkg:npm/left-pad@1.3.0", "https://gitlab.com/left/pad", pkg.Removed),
+		newDep("web/package-lock.json", clients.EcosystemNPM, "unknown", "2.0.0",
+			"pkg:npm/unknown@2.0.0", "", pkg.Added),
+		newDep("web/package-lock.json", clients.EcosystemNPM, "unknown", "3.0.0",
+			"pkg:npm/unknown@3.0.0", "", pkg.Added),
+		newDep("web/package-lock.json", clients.EcosystemNPM, "unknown", "1.0.0",
+			"pkg:npm/unknown@1.0.0", "", pkg.Removed),
+	}
+	if diff := cmp.Diff(want, dCtx.dependencydiffs); diff != "" {
+		t.Errorf("dependencydiffs mismatch (-want +got):\n%s", diff)
+	}
+
+	// Vanity import paths resolve to the repositories of their go-import meta tags.
+	source, err := registry.sourceRepository(context.Background(),
+		clients.Package{Ecosystem: clients.EcosystemGo, Name: "example.com/vanity", Version: "0.1.0"})
+	if err != nil || source != "https://github.com/example/vanity" {
+		t.Errorf("sourceRepository() = %q, %v, want https://github.com/example/vanity", source, err)
+	}
+}
+
+func Test_registryClient_sourceRepository(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/pypi/requests/2.28.0/json":
+			fmt.Fprint(w, `{"info": {"home_page": "https://requests.readthedocs.io",
+				"project_urls": {"Source": "https://github.com/psf/requests/tree/main"}}}`)
+		case "/crates/crates/serde":
+			if r.UserAgent() == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `{"crate": {"repository": "https://github.com/serde-rs/serde"}}`)
+		case "/rubygems/gems/rails.json":
+			fmt.Fprint(w, `{"source_code_uri": "https://github.com/rails/rails/tree/v7.0.0", "homepage_uri": "https://rubyonrails.org"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	registry := newRegistryClient(nil)
+	registry.pypiURL = srv.URL + "/pypi"
+	registry.cratesURL = srv.URL + "/crates"
+	registry.rubyGemsURL = srv.URL + "/rubygems"
+
+	tests := []struct {
+		name    string
+		p       clients.Package
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "pypi",
+			p:    clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "requests", Version: "2.28.0"},
+			want: "https://github.com/psf/requests",
+		},
+		{
+			name: "crates.io",
+			p:    clients.Package{Ecosystem: clients.EcosystemCratesIO, Name: "serde", Version: "1.0.0"},
+			want: "https://github.com/serde-rs/serde",
+		},
+		{
+			name: "rubygems",
+			p:    clients.Package{Ecosystem: clients.EcosystemRubyGems, Name: "rails", Version: "7.0.0"},
+			want: "https://github.com/rails/rails",
+		},
+		{
+			name: "golang.org/x",
+			p:    clients.Package{Ecosystem: clients.EcosystemGo, Name: "golang.org/x/text", Version: "0.3.0"},
+			want: "https://github.com/golang/text",
+		},
+		{
+			name:    "not found",
+			p:       clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "missing", Version: "1.0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := registry.sourceRepository(context.Background(), tt.p)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sourceRepository() error = %v, want error: %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("sourceRepository() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_normalizeRepoURL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "git+https://github.com/owner/repo.git", want: "https://github.com/owner/repo"},
+		{raw: "git+ssh://git@github.com/owner/repo.git", want: "https://github.com/owner/repo"},
+		{raw: "git@github.com:owner/repo.git", want: "https://github.com/owner/repo"},
+		{raw: "github:owner/repo", want: "https://github.com/owner/repo"},
+		{raw: "https://www.github.com/owner/repo/tree/main/packages/x", want: "https://github.com/owner/repo"},
+		{raw: "github.com/owner/repo", want: "https://github.com/owner/repo"},
+		{raw: "https://github.com/owner", want: ""},
+		{raw: "https://gitlab.com/owner/repo", want: "https://gitlab.com/owner/repo"},
+		{raw: "git@gitlab.com:group/subgroup/repo.git", want: "https://gitlab.com/group/subgroup/repo"},
+		{raw: "https://gitlab.com/group/repo/-/tree/main", want: "https://gitlab.com/group/repo"},
+		{raw: "bitbucket:owner/repo", want: "https://bitbucket.org/owner/repo"},
+		{raw: "https://example.com/owner/repo", want: ""},
+		{raw: "", want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.raw, func(t *testing.T) {
+			t.Parallel()
+			if got := normalizeRepoURL(tt.raw); got != tt.want {
+				t.Errorf("normalizeRepoURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // Sets the GitHub Enterprise Server host in the environment.
+func Test_isGitHubRepo(t *testing.T) {
+	t.Setenv(roundtripper.GitHubHostEnvVar, "ghe.example.com")
+	tests := []struct {
+		repoURL string
+		want    bool
+	}{
+		{repoURL: "https://github.com/owner/repo", want: true},
+		{repoURL: "https://ghe.example.com/owner/repo", want: true},
+		{repoURL: "https://gitlab.com/owner/repo", want: false},
+		{repoURL: "https://github.com.example.com/owner/repo", want: false},
+	}
+	for _, tt := range tests {
+		if got := isGitHubRepo(tt.repoURL); got != tt.want {
+			t.Errorf("isGitHubRepo(%q) = %v, want %v", tt.repoURL, got, tt.want)
+		}
+	}
+	// The repositories of the GitHub Enterprise Server are declared like those of github.com.
+	if got, want := normalizeRepoURL("git+https://ghe.example.com/owner/repo.git/tree/main"),
+		"https://ghe.example.com/owner/repo"; got != want {
+		t.Errorf("normalizeRepoURL() = %q, want %q", got, want)
+	}
+}
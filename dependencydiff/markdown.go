@@ -0,0 +1,160 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ossf/scorecard/v4/checker"
+	sce "github.com/ossf/scorecard/v4/errors"
+)
+
+var statusIcons = map[Status]string{
+	StatusPass:     ":white_check_mark:",
+	StatusFail:     ":x:",
+	StatusAllowed:  ":heavy_check_mark:",
+	StatusUnscored: ":grey_question:",
+	StatusRemoved:  ":information_source:",
+}
+
+// Violations returns the number of dependencies failing the policy.
+func Violations(evaluations []DependencyEvaluation) int {
+	n := 0
+	for i := range evaluations {
+		if evaluations[i].Status == StatusFail {
+			n++
+		}
+	}
+	return n
+}
+
+// AsMarkdown writes the policy evaluations as markdown suitable for a pull request comment:
+// a table of the added and updated dependencies, followed by the removed ones.
+func AsMarkdown(evaluations []DependencyEvaluation, writer io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("## Scorecard dependency review\n\n")
+
+	var changed, removed []*DependencyEvaluation
+	for i := range evaluations {
+		if evaluations[i].Status == StatusRemoved {
+			removed = append(removed, &evaluations[i])
+		} else {
+			changed = append(changed, &evaluations[i])
+		}
+	}
+
+	switch n := Violations(evaluations); n {
+	case 0:
+		sb.WriteString(":white_check_mark: No dependency violates the policy.\n\n")
+	case 1:
+		sb.WriteString(":x: 1 dependency violates the policy.\n\n")
+	default:
+		sb.WriteString(fmt.Sprintf(":x: %d dependencies violate the policy.\n\n", n))
+	}
+
+	if len(changed) > 0 {
+		sb.WriteString("| Status | Change | Package | Version | Source | Score | Notes |\n")
+		sb.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, e := range changed {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+				statusIcons[e.Status], changeType(e), markdownEscape(packageName(e)), markdownEscape(deref(e.Result.Version)),
+				source(e), formatScore(e.Score), markdownEscape(notes(e))))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(removed) > 0 {
+		sb.WriteString("### Removed dependencies\n\n")
+		sb.WriteString("These dependencies no longer need to be trusted:\n\n")
+		for _, e := range removed {
+			sb.WriteString(fmt.Sprintf("- %s %s", markdownEscape(packageName(e)), markdownEscape(deref(e.Result.Version))))
+			if e.Score != checker.InconclusiveResultScore {
+				sb.WriteString(fmt.Sprintf(" (score %s)", formatScore(e.Score)))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if _, err := io.WriteString(writer, sb.String()); err != nil {
+		return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("io.WriteString: %v", err))
+	}
+	return nil
+}
+
+func changeType(e *DependencyEvaluation) string {
+	if e.Result.ChangeType == nil {
+		return ""
+	}
+	return string(*e.Result.ChangeType)
+}
+
+func packageName(e *DependencyEvaluation) string {
+	if e.Result.Ecosystem == nil {
+		return e.Result.Name
+	}
+	return fmt.Sprintf("%s (%s)", e.Result.Name, *e.Result.Ecosystem)
+}
+
+func source(e *DependencyEvaluation) string {
+	if e.Result.SourceRepository == nil {
+		return ""
+	}
+	repo := *e.Result.SourceRepository
+	name := strings.TrimPrefix(strings.TrimPrefix(repo, "https://"), "github.com/")
+	if e.Result.ScoredCommit == nil {
+		return fmt.Sprintf("[%s](%s)", name, repo)
+	}
+	commit := *e.Result.ScoredCommit
+	short := commit
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	return fmt.Sprintf("[%s](%s) @ [%s](%s/tree/%s)", name, repo, short, repo, commit)
+}
+
+func notes(e *DependencyEvaluation) string {
+	switch e.Status {
+	case StatusFail:
+		return strings.Join(e.Violations, "; ")
+	case StatusAllowed:
+		return "allowlisted"
+	case StatusUnscored:
+		switch {
+		case errors.Is(e.Result.ScorecardResultWithError.Error, errUnsupportedRepo):
+			return "source repository not hosted on GitHub"
+		case e.Result.ScorecardResultWithError.Error != nil:
+			return "scoring failed"
+		case e.Result.SourceRepository == nil:
+			return "no source repository"
+		}
+		return "not scored"
+	}
+	return ""
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// markdownEscape escapes the characters which would break table cells or be rendered as markdown.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "*", "\\*", "_", "\\_", "`", "\\`", "\n", " ").Replace(s)
+}
@@ -0,0 +1,234 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/checks"
+	docs "github.com/ossf/scorecard/v4/docs/checks"
+	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// Policy gates the dependencies added or updated by a dependency-diff on their Scorecard results.
+// Removed dependencies are never gated.
+type Policy struct {
+	// MinimumScore is the minimum aggregate score of the dependencies.
+	MinimumScore float64 `yaml:"minimum-score"`
+	// RequiredChecks must have a conclusive result for the dependencies, with the maximum score
+	// unless a threshold is set in CheckThresholds.
+	RequiredChecks []string `yaml:"required-checks"`
+	// CheckThresholds are the minimum scores of checks. Inconclusive results of checks which are not
+	// required don't violate them.
+	CheckThresholds map[string]int `yaml:"check-thresholds"`
+	// AllowedPackages are exempted from the policy. Entries are package names or
+	// package URLs, either may be a path.Match pattern, e.g. @myorg/* or pkg:golang/example.com/*.
+	AllowedPackages []string `yaml:"allowed-packages"`
+	// FailUnscored fails dependencies without Scorecard results, e.g. those without source repository.
+	FailUnscored bool `yaml:"fail-unscored"`
+}
+
+// ParsePolicyFromFile reads a dependency-diff policy from a YAML file.
+func ParsePolicyFromFile(policyFile string) (*Policy, error) {
+	data, err := os.ReadFile(policyFile)
+	if err != nil {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("os.ReadFile: %v", err))
+	}
+	p := &Policy{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("yaml.Unmarshal: %v", err))
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Policy) validate() error {
+	allChecks := checks.GetAllWithExperimental()
+	if p.MinimumScore < 0 || p.MinimumScore > checker.MaxResultScore {
+		return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("%v: minimum-score: %v", errInvalid, p.MinimumScore))
+	}
+	for _, name := range p.RequiredChecks {
+		if _, ok := allChecks[name]; !ok {
+			return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("%v check: %s", errInvalid, name))
+		}
+	}
+	for name, threshold := range p.CheckThresholds {
+		if _, ok := allChecks[name]; !ok {
+			return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("%v check: %s", errInvalid, name))
+		}
+		if threshold < checker.MinResultScore || threshold > checker.MaxResultScore {
+			return sce.WithMessage(sce.ErrScorecardInternal,
+				fmt.Sprintf("%v threshold for %s: %d", errInvalid, name, threshold))
+		}
+	}
+	for _, pattern := range p.AllowedPackages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("%v allowed package: %s", errInvalid, pattern))
+		}
+	}
+	return nil
+}
+
+// Checks returns the names of the checks the policy needs the results of, sorted.
+func (p *Policy) Checks() []string {
+	names := make(map[string]bool, len(p.RequiredChecks)+len(p.CheckThresholds))
+	for _, name := range p.RequiredChecks {
+		names[name] = true
+	}
+	for name := range p.CheckThresholds {
+		names[name] = true
+	}
+	ret := make([]string, 0, len(names))
+	for name := range names {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Status is the outcome of a policy evaluation for a dependency.
+type Status string
+
+const (
+	// StatusPass suggests the dependency meets the policy.
+	StatusPass Status = "pass"
+	// StatusFail suggests the dependency violates the policy.
+	StatusFail Status = "fail"
+	// StatusAllowed suggests the dependency is exempted from the policy.
+	StatusAllowed Status = "allowed"
+	// StatusUnscored suggests the dependency has no Scorecard results to evaluate.
+	StatusUnscored Status = "unscored"
+	// StatusRemoved suggests the dependency is removed, which is informational only.
+	StatusRemoved Status = "removed"
+)
+
+// DependencyEvaluation is the policy evaluation of a dependency-diff result.
+type DependencyEvaluation struct {
+	Result *pkg.DependencyCheckResult
+	Status Status
+	// Score is the aggregate score of the dependency, or checker.InconclusiveResultScore.
+	Score float64
+	// Violations describe why the dependency fails the policy.
+	Violations []string
+}
+
+// Evaluate evaluates the dependency-diff results against the policy.
+func (p *Policy) Evaluate(results []pkg.DependencyCheckResult, checkDocs docs.Doc) ([]DependencyEvaluation, error) {
+	ret := make([]DependencyEvaluation, 0, len(results))
+	for i := range results {
+		r := &results[i]
+		e := DependencyEvaluation{Result: r, Score: checker.InconclusiveResultScore}
+		scorecardResult := r.ScorecardResultWithError.ScorecardResult
+		if scorecardResult != nil {
+			score, err := scorecardResult.GetAggregateScore(checkDocs)
+			if err != nil {
+				return nil, fmt.Errorf("error computing the aggregate score of %s: %w", r.Name, err)
+			}
+			e.Score = score
+		}
+
+		switch {
+		case r.ChangeType != nil && *r.ChangeType == pkg.Removed:
+			e.Status = StatusRemoved
+		case p.isAllowed(r):
+			e.Status = StatusAllowed
+		case scorecardResult == nil:
+			e.Status = StatusUnscored
+			if p.FailUnscored {
+				e.Status = StatusFail
+				e.Violations = append(e.Violations, "no Scorecard results")
+			}
+		default:
+			e.Violations = p.violations(scorecardResult, e.Score)
+			e.Status = StatusPass
+			if len(e.Violations) > 0 {
+				e.Status = StatusFail
+			}
+		}
+		ret = append(ret, e)
+	}
+	return ret, nil
+}
+
+func (p *Policy) isAllowed(r *pkg.DependencyCheckResult) bool {
+	for _, pattern := range p.AllowedPackages {
+		if ok, _ := path.Match(pattern, r.Name); ok {
+			return true
+		}
+		if r.PackageURL != nil {
+			if ok, _ := path.Match(pattern, *r.PackageURL); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *Policy) violations(result *pkg.ScorecardResult, score float64) []string {
+	var ret []string
+	if p.MinimumScore > 0 && (score == checker.InconclusiveResultScore || score < p.MinimumScore) {
+		ret = append(ret, fmt.Sprintf("score %s is below %.1f", formatScore(score), p.MinimumScore))
+	}
+
+	scores := make(map[string]int, len(result.Checks))
+	for i := range result.Checks {
+		scores[result.Checks[i].Name] = result.Checks[i].Score
+	}
+	thresholds := make(map[string]int, len(p.CheckThresholds)+len(p.RequiredChecks))
+	required := make(map[string]bool, len(p.RequiredChecks))
+	for name, threshold := range p.CheckThresholds {
+		thresholds[name] = threshold
+	}
+	for _, name := range p.RequiredChecks {
+		required[name] = true
+		if _, ok := thresholds[name]; !ok {
+			thresholds[name] = checker.MaxResultScore
+		}
+	}
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		checkScore, ok := scores[name]
+		switch {
+		case !ok || checkScore == checker.InconclusiveResultScore:
+			if required[name] {
+				ret = append(ret, fmt.Sprintf("required check %s has no result", name))
+			}
+		case checkScore < thresholds[name]:
+			ret = append(ret, fmt.Sprintf("%s scored %d, below %d", name, checkScore, thresholds[name]))
+		}
+	}
+	return ret
+}
+
+func formatScore(score float64) string {
+	if score == checker.InconclusiveResultScore {
+		return "?"
+	}
+	return fmt.Sprintf("%.1f", score)
+}
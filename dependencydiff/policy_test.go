@@ -0,0 +1,272 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/checks"
+	docs "github.com/ossf/scorecard/v4/docs/checks"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+func testDependencyResult(name string, ct pkg.ChangeType, checkScores map[string]int) pkg.DependencyCheckResult {
+	r := pkg.DependencyCheckResult{
+		Name:       name,
+		ChangeType: &ct,
+		Ecosystem:  asPointer("npm"),
+		Version:    asPointer("1.0.0"),
+		PackageURL: asPointer("pkg:npm/" + strings.ReplaceAll(name, "@", "%40") + "@1.0.0"),
+	}
+	if checkScores != nil {
+		r.SourceRepository = asPointer("https://github.com/owner/" + name)
+		r.ScoredCommit = asPointer("0123456789abcdef0123456789abcdef01234567")
+		r.ScorecardResultWithError.ScorecardResult = testResult("sha", checkScores)
+	}
+	return r
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	t.Parallel()
+	checkDocs, err := docs.Read()
+	if err != nil {
+		t.Fatalf("docs.Read: %v", err)
+	}
+	results := []pkg.DependencyCheckResult{
+		testDependencyResult("good", pkg.Added, map[string]int{
+			checks.CheckBinaryArtifacts: 10, checks.CheckLicense: 10, checks.CheckMaintained: 8,
+		}),
+		testDependencyResult("low-score", pkg.Updated, map[string]int{
+			checks.CheckBinaryArtifacts: 10, checks.CheckLicense: 0, checks.CheckMaintained: 2,
+		}),
+		testDependencyResult("inconclusive", pkg.Added, map[string]int{
+			checks.CheckBinaryArtifacts: 10, checks.CheckLicense: 10, checks.CheckMaintained: -1,
+		}),
+		testDependencyResult("@myorg/internal", pkg.Added, map[string]int{checks.CheckLicense: 0}),
+		testDependencyResult("no-source", pkg.Added, nil),
+		testDependencyResult("gone", pkg.Removed, map[string]int{checks.CheckLicense: 0}),
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   map[string][]string
+		status map[string]Status
+	}{
+		{
+			name:   "empty policy",
+			policy: Policy{},
+			status: map[string]Status{
+				"good": StatusPass, "low-score": StatusPass, "inconclusive": StatusPass,
+				"@myorg/internal": StatusPass, "no-source": StatusUnscored, "gone": StatusRemoved,
+			},
+		},
+		{
+			name: "thresholds",
+			policy: Policy{
+				MinimumScore:    5.5,
+				RequiredChecks:  []string{checks.CheckMaintained},
+				CheckThresholds: map[string]int{checks.CheckMaintained: 5, checks.CheckLicense: 5},
+				AllowedPackages: []string{"@myorg/*"},
+				FailUnscored:    true,
+			},
+			want: map[string][]string{
+				"low-score": {
+					"score 5.1 is below 5.5",
+					"License scored 0, below 5",
+					"Maintained scored 2, below 5",
+				},
+				"inconclusive": {"required check Maintained has no result"},
+				"no-source":    {"no Scorecard results"},
+			},
+			status: map[string]Status{
+				"good": StatusPass, "low-score": StatusFail, "inconclusive": StatusFail,
+				"@myorg/internal": StatusAllowed, "no-source": StatusFail, "gone": StatusRemoved,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			evaluations, err := tt.policy.Evaluate(results, checkDocs)
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			gotViolations := map[string][]string{}
+			gotStatus := map[string]Status{}
+			for _, e := range evaluations {
+				gotStatus[e.Result.Name] = e.Status
+				if len(e.Violations) > 0 {
+					gotViolations[e.Result.Name] = e.Violations
+				}
+			}
+			if tt.want == nil {
+				tt.want = map[string][]string{}
+			}
+			if diff := cmp.Diff(tt.want, gotViolations); diff != "" {
+				t.Errorf("violations mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.status, gotStatus); diff != "" {
+				t.Errorf("status mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePolicyFromFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		want    *Policy
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			content: `minimum-score: 6.5
+required-checks: [Maintained]
+check-thresholds:
+  Binary-Artifacts: 8
+allowed-packages: ["@myorg/*", "pkg:golang/example.com/*"]
+fail-unscored: true
+`,
+			want: &Policy{
+				MinimumScore:    6.5,
+				RequiredChecks:  []string{checks.CheckMaintained},
+				CheckThresholds: map[string]int{checks.CheckBinaryArtifacts: 8},
+				AllowedPackages: []string{"@myorg/*", "pkg:golang/example.com/*"},
+				FailUnscored:    true,
+			},
+		},
+		{
+			name:    "unknown check",
+			content: "required-checks: [Not-A-Check]\n",
+			wantErr: true,
+		},
+		{
+			name:    "threshold out of range",
+			content: "check-thresholds:\n  Maintained: 11\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			content: "allowed-packages: ['[']\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			policyFile := filepath.Join(t.TempDir(), "policy.yml")
+			if err := os.WriteFile(policyFile, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("os.WriteFile: %v", err)
+			}
+			got, err := ParsePolicyFromFile(policyFile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicyFromFile() error = %v, want error: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParsePolicyFromFile() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolicy_Checks(t *testing.T) {
+	t.Parallel()
+	p := &Policy{
+		RequiredChecks: []string{checks.CheckMaintained, checks.CheckBinaryArtifacts},
+		CheckThresholds: map[string]int{
+			checks.CheckMaintained: 5,
+			checks.CheckLicense:    8,
+		},
+	}
+	want := []string{checks.CheckBinaryArtifacts, checks.CheckLicense, checks.CheckMaintained}
+	if diff := cmp.Diff(want, p.Checks()); diff != "" {
+		t.Errorf("Checks() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAsMarkdown(t *testing.T) {
+	t.Parallel()
+	added := pkg.Added
+	removed := pkg.Removed
+	evaluations := []DependencyEvaluation{
+		{
+			Result: &pkg.DependencyCheckResult{
+				Name:             "left_pad",
+				ChangeType:       &added,
+				Ecosystem:        asPointer("npm"),
+				Version:          asPointer("1.3.0"),
+				SourceRepository: asPointer("https://github.com/owner/left-pad"),
+				ScoredCommit:     asPointer("0123456789abcdef"),
+			},
+			Status:     StatusFail,
+			Score:      3.5,
+			Violations: []string{"score 3.5 is below 5.0", "License scored 0, below 5"},
+		},
+		{
+			Result: &pkg.DependencyCheckResult{
+				Name:       "no-source",
+				ChangeType: &added,
+				Version:    asPointer("2.0.0"),
+			},
+			Status: StatusUnscored,
+			Score:  checker.InconclusiveResultScore,
+		},
+		{
+			Result: &pkg.DependencyCheckResult{
+				Name:       "gone",
+				ChangeType: &removed,
+				Ecosystem:  asPointer("npm"),
+				Version:    asPointer("0.1.0"),
+			},
+			Status: StatusRemoved,
+			Score:  2,
+		},
+	}
+	var sb strings.Builder
+	if err := AsMarkdown(evaluations, &sb); err != nil {
+		t.Fatalf("AsMarkdown: %v", err)
+	}
+	want := `## Scorecard dependency review
+
+:x: 1 dependency violates the policy.
+
+| Status | Change | Package | Version | Source | Score | Notes |
+| --- | --- | --- | --- | --- | --- | --- |
+| :x: | added | left\_pad (npm) | 1.3.0 | [owner/left-pad](https://github.com/owner/left-pad) @ ` +
+		`[0123456](https://github.com/owner/left-pad/tree/0123456789abcdef) | 3.5 | ` +
+		`score 3.5 is below 5.0; License scored 0, below 5 |
+| :grey_question: | added | no-source | 2.0.0 |  | ? | no source repository |
+
+### Removed dependencies
+
+These dependencies no longer need to be trusted:
+
+- gone (npm) 0.1.0 (score 2.0)
+`
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("AsMarkdown() mismatch (-want +got):\n%s", diff)
+	}
+}
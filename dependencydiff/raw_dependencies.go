@@ -15,6 +15,7 @@
 package dependencydiff
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"path"
@@ -22,6 +23,7 @@ import (
 	"github.com/google/go-github/v38/github"
 
 	"github.com/ossf/scorecard/v4/clients/githubrepo/roundtripper"
+	sclog "github.com/ossf/scorecard/v4/log"
 	"github.com/ossf/scorecard/v4/pkg"
 )
 
@@ -50,11 +52,16 @@ type dependency struct {
 	Name string `json:"name"`
 }
 
+// newGitHubClient returns a GitHub client authenticated with the access token of the environment.
+func newGitHubClient(ctx context.Context, logger *sclog.Logger) *github.Client {
+	ghrt := roundtripper.NewTransport(ctx, logger)
+	return github.NewClient(&http.Client{Transport: ghrt})
+}
+
 // fetchRawDependencyDiffData fetches the dependency-diffs between the two code commits
 // using the GitHub Dependency Review API, and returns a slice of DependencyCheckResult.
 func fetchRawDependencyDiffData(dCtx *dependencydiffContext) error {
-	ghrt := roundtripper.NewTransport(dCtx.ctx, dCtx.logger)
-	ghClient := github.NewClient(&http.Client{Transport: ghrt})
+	ghClient := newGitHubClient(dCtx.ctx, dCtx.logger)
 	req, err := ghClient.NewRequest(
 		"GET",
 		path.Join("repos", dCtx.ownerName, dCtx.repoName,
@@ -0,0 +1,337 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v38/github"
+
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/clients/githubrepo/roundtripper"
+)
+
+const (
+	npmRegistryURL      = "https://registry.npmjs.org"
+	pypiRegistryURL     = "https://pypi.org/pypi"
+	cratesRegistryURL   = "https://crates.io/api/v1"
+	rubyGemsRegistryURL = "https://rubygems.org/api/v1"
+	// goImportURL is the URL of the go-get request resolving a module path.
+	goImportURL = "https://%s?go-get=1"
+
+	githubHost    = "github.com"
+	gitlabHost    = "gitlab.com"
+	bitbucketHost = "bitbucket.org"
+)
+
+var goImportMeta = regexp.MustCompile(`<meta\s+name="go-import"\s+content="([^"]+)"`)
+
+// registryClient looks up the metadata of packages in package registries,
+// and the commits of their versions in their GitHub repositories.
+type registryClient struct {
+	httpClient *http.Client
+	// newGitHub creates the GitHub client on first use, as it needs an access token.
+	newGitHub  func() *github.Client
+	githubOnce sync.Once
+	github     *github.Client
+	// URLs of the registries, overridden in tests.
+	npmURL, pypiURL, cratesURL, rubyGemsURL, goImportURL string
+
+	mu sync.Mutex
+	// sources memoizes the source repositories by package.
+	sources map[clients.Package]string
+	// responses memoizes the registry responses by URL.
+	responses map[string][]byte
+}
+
+func newRegistryClient(newGitHub func() *github.Client) *registryClient {
+	return &registryClient{
+		httpClient:  &http.Client{},
+		newGitHub:   newGitHub,
+		npmURL:      npmRegistryURL,
+		pypiURL:     pypiRegistryURL,
+		cratesURL:   cratesRegistryURL,
+		rubyGemsURL: rubyGemsRegistryURL,
+		goImportURL: goImportURL,
+		sources:     make(map[clients.Package]string),
+		responses:   make(map[string][]byte),
+	}
+}
+
+// sourceRepository returns the URL of the source repository of a package version,
+// as declared in its registry metadata, or "" if it's not hosted on a known forge.
+func (r *registryClient) sourceRepository(ctx context.Context, p clients.Package) (string, error) {
+	r.mu.Lock()
+	source, ok := r.sources[p]
+	r.mu.Unlock()
+	if ok {
+		return source, nil
+	}
+
+	var candidates []string
+	var err error
+	switch p.Ecosystem {
+	case clients.EcosystemGo:
+		candidates, err = r.goSources(ctx, p)
+	case clients.EcosystemNPM:
+		candidates, err = r.npmSources(ctx, p)
+	case clients.EcosystemPyPI:
+		candidates, err = r.pypiSources(ctx, p)
+	case clients.EcosystemCratesIO:
+		candidates, err = r.cratesSources(ctx, p)
+	case clients.EcosystemRubyGems:
+		candidates, err = r.rubyGemsSources(ctx, p)
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, c := range candidates {
+		if source = normalizeRepoURL(c); source != "" {
+			break
+		}
+	}
+
+	r.mu.Lock()
+	r.sources[p] = source
+	r.mu.Unlock()
+	return source, nil
+}
+
+func (r *registryClient) goSources(ctx context.Context, p clients.Package) ([]string, error) {
+	switch {
+	case strings.HasPrefix(p.Name, githubHost+"/"):
+		return []string{"https://" + p.Name}, nil
+	case strings.HasPrefix(p.Name, "golang.org/x/"):
+		// The Go subrepositories are mirrored on GitHub.
+		name := strings.SplitN(strings.TrimPrefix(p.Name, "golang.org/x/"), "/", 2)[0]
+		return []string{"https://github.com/golang/" + name}, nil
+	}
+	// Vanity import paths declare their repository in a go-import meta tag.
+	body, err := r.get(ctx, fmt.Sprintf(r.goImportURL, p.Name))
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, m := range goImportMeta.FindAllStringSubmatch(string(body), -1) {
+		// content is "import-prefix vcs repo-root".
+		if fields := strings.Fields(m[1]); len(fields) == 3 && strings.HasPrefix(p.Name, fields[0]) {
+			ret = append(ret, fields[2])
+		}
+	}
+	return ret, nil
+}
+
+func (r *registryClient) npmSources(ctx context.Context, p clients.Package) ([]string, error) {
+	var v struct {
+		Repository json.RawMessage `json:"repository"`
+		Homepage   string          `json:"homepage"`
+	}
+	if err := r.getJSON(ctx, r.npmVersionURL(p), &v); err != nil {
+		return nil, err
+	}
+	// The repository is either a URL or an object with a URL.
+	var repository struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(v.Repository, &repository); err != nil {
+		//nolint:errcheck
+		json.Unmarshal(v.Repository, &repository.URL)
+	}
+	return []string{repository.URL, v.Homepage}, nil
+}
+
+func (r *registryClient) npmVersionURL(p clients.Package) string {
+	// Scoped package names keep their '@' but escape their '/'.
+	return r.npmURL + "/" + strings.Replace(p.Name, "/", "%2F", 1) + "/" + p.Version
+}
+
+func (r *registryClient) pypiSources(ctx context.Context, p clients.Package) ([]string, error) {
+	var v struct {
+		Info struct {
+			ProjectURLs map[string]string `json:"project_urls"`
+			HomePage    string            `json:"home_page"`
+		} `json:"info"`
+	}
+	if err := r.getJSON(ctx, r.pypiURL+"/"+url.PathEscape(p.Name)+"/"+url.PathEscape(p.Version)+"/json", &v); err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, key := range []string{"Source", "Source Code", "Code", "Repository", "GitHub", "Homepage"} {
+		if u, ok := v.Info.ProjectURLs[key]; ok {
+			ret = append(ret, u)
+		}
+	}
+	return append(ret, v.Info.HomePage), nil
+}
+
+func (r *registryClient) cratesSources(ctx context.Context, p clients.Package) ([]string, error) {
+	var v struct {
+		Crate struct {
+			Repository string `json:"repository"`
+			Homepage   string `json:"homepage"`
+		} `json:"crate"`
+	}
+	if err := r.getJSON(ctx, r.cratesURL+"/crates/"+url.PathEscape(p.Name), &v); err != nil {
+		return nil, err
+	}
+	return []string{v.Crate.Repository, v.Crate.Homepage}, nil
+}
+
+func (r *registryClient) rubyGemsSources(ctx context.Context, p clients.Package) ([]string, error) {
+	var v struct {
+		SourceCodeURI string `json:"source_code_uri"`
+		HomepageURI   string `json:"homepage_uri"`
+	}
+	if err := r.getJSON(ctx, r.rubyGemsURL+"/gems/"+url.PathEscape(p.Name)+".json", &v); err != nil {
+		return nil, err
+	}
+	return []string{v.SourceCodeURI, v.HomepageURI}, nil
+}
+
+func (r *registryClient) getJSON(ctx context.Context, reqURL string, v interface{}) error {
+	body, err := r.get(ctx, reqURL)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error parsing the response of %s: %w", reqURL, err)
+	}
+	return nil
+}
+
+// get returns the memoized response of reqURL, as the metadata of a package
+// version is used both for its source repository and its commit.
+func (r *registryClient) get(ctx context.Context, reqURL string) ([]byte, error) {
+	r.mu.Lock()
+	body, ok := r.responses[reqURL]
+	r.mu.Unlock()
+	if ok {
+		return body, nil
+	}
+	body, err := r.fetch(ctx, reqURL)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.responses[reqURL] = body
+	r.mu.Unlock()
+	return body, nil
+}
+
+func (r *registryClient) fetch(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request for %s failed with %w", reqURL, err)
+	}
+	// crates.io rejects requests without a user agent.
+	req.Header.Set("User-Agent", "scorecard")
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request for %s failed with %w", reqURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned %s", errRegistry, reqURL, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the response of %s: %w", reqURL, err)
+	}
+	return body, nil
+}
+
+// normalizeRepoURL returns the https URL of a repository hosted on GitHub,
+// GitLab or Bitbucket from the many forms repositories are declared with, e.g.
+// git+https://github.com/owner/repo.git or git@github.com:owner/repo,
+// or "" if the URL isn't one of a repository of these forges.
+func normalizeRepoURL(raw string) string {
+	s := strings.TrimSpace(raw)
+	s = strings.TrimPrefix(s, "git+")
+	// npm's shorthands and scp-like git URLs.
+	for shorthand, host := range map[string]string{
+		"github:":    githubHost,
+		"gitlab:":    gitlabHost,
+		"bitbucket:": bitbucketHost,
+	} {
+		for _, prefix := range []string{shorthand, "git@" + host + ":"} {
+			if rest := strings.TrimPrefix(s, prefix); rest != s {
+				s = "https://" + host + "/" + rest
+			}
+		}
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(strings.TrimPrefix(u.Hostname(), "www."))
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	forge := host
+	if isEnterpriseHost(host) {
+		// The repositories of a GitHub Enterprise Server are laid out like those of github.com.
+		forge = githubHost
+	}
+	switch forge {
+	case githubHost, bitbucketHost:
+		if len(parts) > 2 {
+			parts = parts[:2]
+		}
+	case gitlabHost:
+		// GitLab projects may be nested in subgroups, and their pages follow a "-" segment,
+		// e.g. https://gitlab.com/group/subgroup/project/-/tree/main.
+		for i, part := range parts {
+			if part == "-" {
+				parts = parts[:i]
+				break
+			}
+		}
+	default:
+		return ""
+	}
+	if len(parts) < 2 {
+		return ""
+	}
+	for _, part := range parts {
+		if part == "" {
+			return ""
+		}
+	}
+	parts[len(parts)-1] = strings.TrimSuffix(parts[len(parts)-1], ".git")
+	return fmt.Sprintf("https://%s/%s", host, strings.Join(parts, "/"))
+}
+
+// isGitHubRepo returns whether a source repository URL is one of a GitHub repository,
+// the only ones Scorecard can score, including those of the GitHub Enterprise Server
+// configured with roundtripper.GitHubHostEnvVar.
+func isGitHubRepo(repoURL string) bool {
+	host, _, _ := strings.Cut(repoURI(repoURL), "/")
+	return strings.EqualFold(host, githubHost) || isEnterpriseHost(host)
+}
+
+// isEnterpriseHost returns whether host is the one of the configured GitHub Enterprise Server, if any.
+func isEnterpriseHost(host string) bool {
+	enterpriseHost := roundtripper.EnterpriseHost()
+	return enterpriseHost != "" && strings.EqualFold(host, enterpriseHost)
+}
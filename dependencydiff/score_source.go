@@ -0,0 +1,345 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+	// Needed to read file:/// buckets.
+	_ "gocloud.dev/blob/fileblob"
+	// Needed to link in GCP drivers.
+	_ "gocloud.dev/blob/gcsblob"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/clients"
+	docs "github.com/ossf/scorecard/v4/docs/checks"
+	sclog "github.com/ossf/scorecard/v4/log"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// resultsFile is the name of the results the cron job exports for the API, for each repo and commit.
+const resultsFile = "results.json"
+
+// ErrScoreNotFound is returned by a ScoreSource which has no results for a repo and commit.
+var ErrScoreNotFound = errors.New("scorecard result not found")
+
+// ScoreSource provides the Scorecard results of the source repositories of dependencies.
+type ScoreSource interface {
+	// GetScore returns the results of the checks for the repo at the commit,
+	// or ErrScoreNotFound if the source has none.
+	// The repo is a URL such as https://github.com/owner/repo.
+	// The commit is either a SHA or clients.HeadSHA for the latest results.
+	GetScore(ctx context.Context, repoURL, commitSHA string,
+		checksToRun checker.CheckNameToFnMap) (*pkg.ScorecardResult, error)
+}
+
+// scoreStore is implemented by the sources which can store the results found by others,
+// such as those returned by NewScoreCache.
+type scoreStore interface {
+	putScore(ctx context.Context, repoURL string, result *pkg.ScorecardResult) error
+}
+
+// repoURI returns the URI of a repo URL as used by clients.Repo.URI(), e.g. github.com/owner/repo.
+func repoURI(repoURL string) string {
+	uri := repoURL
+	if i := strings.Index(uri, "://"); i >= 0 {
+		uri = uri[i+len("://"):]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(uri, "/"), ".git")
+}
+
+// withChecks returns the results of the given checks only,
+// or false if the results miss one of them.
+func withChecks(result *pkg.ScorecardResult, checksToRun checker.CheckNameToFnMap) (*pkg.ScorecardResult, bool) {
+	ret := *result
+	ret.Checks = nil
+	for i := range result.Checks {
+		if _, ok := checksToRun[result.Checks[i].Name]; ok {
+			ret.Checks = append(ret.Checks, result.Checks[i])
+		}
+	}
+	return &ret, len(ret.Checks) == len(checksToRun)
+}
+
+// storedScoreSource reads the results from a bucket laid out like the API results bucket
+// the cron job exports to (api-results-bucket-url): <host>/<owner>/<repo>/<commit>/results.json,
+// and <host>/<owner>/<repo>/results.json for the latest results. It doesn't read the
+// newline-delimited shards of the cron job's results bucket.
+type storedScoreSource struct {
+	bucketURL string
+}
+
+// NewStoredScoreSource returns a ScoreSource reading results previously exported with
+// the JSON format from a blob bucket, e.g. gs://bucket, or from a local directory.
+func NewStoredScoreSource(bucketURL string) (ScoreSource, error) {
+	return newStoredScoreSource(bucketURL)
+}
+
+func newStoredScoreSource(bucketURL string) (*storedScoreSource, error) {
+	if !strings.Contains(bucketURL, "://") {
+		dir, err := filepath.Abs(bucketURL)
+		if err != nil {
+			return nil, fmt.Errorf("filepath.Abs: %w", err)
+		}
+		bucketURL = "file://" + filepath.ToSlash(dir)
+	}
+	return &storedScoreSource{bucketURL: bucketURL}, nil
+}
+
+func (s *storedScoreSource) key(repoURL, commitSHA string) string {
+	if commitSHA == clients.HeadSHA || commitSHA == "" {
+		return fmt.Sprintf("%s/%s", repoURI(repoURL), resultsFile)
+	}
+	return fmt.Sprintf("%s/%s/%s", repoURI(repoURL), commitSHA, resultsFile)
+}
+
+// GetScore implements ScoreSource.GetScore.
+func (s *storedScoreSource) GetScore(ctx context.Context, repoURL, commitSHA string,
+	checksToRun checker.CheckNameToFnMap,
+) (*pkg.ScorecardResult, error) {
+	bucket, err := blob.OpenBucket(ctx, s.bucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("error from blob.OpenBucket: %w", err)
+	}
+	defer bucket.Close()
+
+	result, err := s.read(ctx, bucket, repoURL, commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	ret, ok := withChecks(result, checksToRun)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s@%s misses some checks", ErrScoreNotFound, repoURL, commitSHA)
+	}
+	return ret, nil
+}
+
+func (s *storedScoreSource) read(ctx context.Context, bucket *blob.Bucket, repoURL, commitSHA string,
+) (*pkg.ScorecardResult, error) {
+	content, err := bucket.ReadAll(ctx, s.key(repoURL, commitSHA))
+	if gcerrors.Code(err) == gcerrors.NotFound {
+		return nil, fmt.Errorf("%w: %s@%s", ErrScoreNotFound, repoURL, commitSHA)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error during bucket.ReadAll: %w", err)
+	}
+	result, _, err := pkg.ExperimentalFromJSON2(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the results of %s@%s: %w", repoURL, commitSHA, err)
+	}
+	return &result, nil
+}
+
+var _ scoreStore = &scoreCache{}
+
+// scoreCache is a storedScoreSource which stores the results found by the other sources.
+type scoreCache struct {
+	storedScoreSource
+}
+
+// NewScoreCache returns a ScoreSource reading results like NewStoredScoreSource, which also
+// stores those found by the other sources of NewCachingScoreSource, e.g. run live, so that later
+// runs find them. It's written to, so it should be a directory or bucket of its own rather than
+// the API results bucket of the Scorecard cron job. The results stored for a commit are merged with
+// those already there, the new ones replacing the old ones of the same checks.
+func NewScoreCache(bucketURL string) (ScoreSource, error) {
+	stored, err := newStoredScoreSource(bucketURL)
+	if err != nil {
+		return nil, err
+	}
+	return &scoreCache{storedScoreSource: *stored}, nil
+}
+
+// putScore stores the results under their commit, merged with those already stored.
+func (s *scoreCache) putScore(ctx context.Context, repoURL string, result *pkg.ScorecardResult) error {
+	if result.Repo.CommitSHA == "" {
+		return nil
+	}
+	checkDocs, err := docs.Read()
+	if err != nil {
+		return fmt.Errorf("error reading the check docs: %w", err)
+	}
+
+	bucket, err := blob.OpenBucket(ctx, s.bucketURL)
+	if err != nil {
+		return fmt.Errorf("error from blob.OpenBucket: %w", err)
+	}
+	defer bucket.Close()
+	existing, err := s.read(ctx, bucket, repoURL, result.Repo.CommitSHA)
+	switch {
+	case errors.Is(err, ErrScoreNotFound):
+	case err != nil:
+		return err
+	default:
+		result = mergeChecks(existing, result)
+	}
+
+	var buf bytes.Buffer
+	if err := result.AsJSON2(true /*showDetails*/, sclog.DefaultLevel, checkDocs, &buf); err != nil {
+		return fmt.Errorf("error during result.AsJSON2: %w", err)
+	}
+	if err := bucket.WriteAll(ctx, s.key(repoURL, result.Repo.CommitSHA), buf.Bytes(), nil); err != nil {
+		return fmt.Errorf("error during bucket.WriteAll: %w", err)
+	}
+	return nil
+}
+
+// mergeChecks returns result with the checks of existing it doesn't have.
+func mergeChecks(existing, result *pkg.ScorecardResult) *pkg.ScorecardResult {
+	ret := *result
+	ret.Checks = append([]checker.CheckResult{}, result.Checks...)
+	names := make(map[string]bool, len(result.Checks))
+	for i := range result.Checks {
+		names[result.Checks[i].Name] = true
+	}
+	for i := range existing.Checks {
+		if !names[existing.Checks[i].Name] {
+			ret.Checks = append(ret.Checks, existing.Checks[i])
+		}
+	}
+	return &ret
+}
+
+// liveScoreSource runs the checks on the repos.
+type liveScoreSource struct {
+	logger   *sclog.Logger
+	settings *checker.PolicySettings
+}
+
+// NewLiveScoreSource returns a ScoreSource running Scorecard on the repos,
+// which needs an access token. See https://github.com/ossf/scorecard#authentication.
+func NewLiveScoreSource() ScoreSource {
+	return NewLiveScoreSourceWithSettings(nil)
+}
+
+// NewLiveScoreSourceWithSettings is NewLiveScoreSource running the checks
+// with the check-specific settings, e.g. of a Scorecard policy.
+func NewLiveScoreSourceWithSettings(settings *checker.PolicySettings) ScoreSource {
+	return &liveScoreSource{
+		logger:   sclog.NewLogger(sclog.DefaultLevel),
+		settings: settings,
+	}
+}
+
+// GetScore implements ScoreSource.GetScore.
+func (s *liveScoreSource) GetScore(ctx context.Context, repoURL, commitSHA string,
+	checksToRun checker.CheckNameToFnMap,
+) (*pkg.ScorecardResult, error) {
+	dCtx := dependencydiffContext{
+		logger: s.logger,
+		ctx:    ctx,
+	}
+	for name := range checksToRun {
+		dCtx.checkNamesToRun = append(dCtx.checkNamesToRun, name)
+	}
+	// Initialize the repo and client(s) corresponding to the checks to run.
+	if err := initRepoAndClientByChecks(&dCtx, repoURL); err != nil {
+		return nil, fmt.Errorf("error init repo and clients: %w", err)
+	}
+	result, err := pkg.RunScorecardsWithOptions(
+		ctx,
+		dCtx.ghRepo,
+		commitSHA,
+		checksToRun,
+		dCtx.ghRepoClient,
+		dCtx.ossFuzzClient,
+		dCtx.ciiClient,
+		dCtx.vulnsClient,
+		pkg.RunOptions{Settings: s.settings},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error running scorecard: %w", err)
+	}
+	return &result, nil
+}
+
+// scoreKey identifies the results of a repo at a commit.
+type scoreKey struct {
+	repo, commit string
+}
+
+// cachingScoreSource tries its sources in order and memoizes their results.
+type cachingScoreSource struct {
+	logger  *sclog.Logger
+	sources []ScoreSource
+
+	mu      sync.Mutex
+	results map[scoreKey]*pkg.ScorecardResult
+}
+
+// NewCachingScoreSource returns a ScoreSource trying the sources in order, e.g. stored results
+// first and running Scorecard live as a fallback. The results are memoized by repo and commit,
+// so reusing the ScoreSource across dependency-diffs, e.g. of several pull requests, scores
+// each repo and commit once. Results at clients.HeadSHA are only memoized by the commit HEAD
+// was at, as HEAD moves. The results found by a source are also stored into the previous
+// sources which can store them, i.e. those returned by NewScoreCache.
+func NewCachingScoreSource(sources ...ScoreSource) ScoreSource {
+	return &cachingScoreSource{
+		logger:  sclog.NewLogger(sclog.DefaultLevel),
+		sources: sources,
+		results: make(map[scoreKey]*pkg.ScorecardResult),
+	}
+}
+
+// GetScore implements ScoreSource.GetScore.
+func (s *cachingScoreSource) GetScore(ctx context.Context, repoURL, commitSHA string,
+	checksToRun checker.CheckNameToFnMap,
+) (*pkg.ScorecardResult, error) {
+	key := scoreKey{repo: repoURI(repoURL), commit: commitSHA}
+	s.mu.Lock()
+	cached, ok := s.results[key]
+	s.mu.Unlock()
+	if ok && commitSHA != clients.HeadSHA {
+		if ret, ok := withChecks(cached, checksToRun); ok {
+			return ret, nil
+		}
+	}
+
+	errs := []string{}
+	for i, source := range s.sources {
+		result, err := source.GetScore(ctx, repoURL, commitSHA, checksToRun)
+		if err != nil {
+			// Fall back to the next source.
+			errs = append(errs, err.Error())
+			continue
+		}
+		for _, previous := range s.sources[:i] {
+			if store, ok := previous.(scoreStore); ok {
+				// Storing is best effort: the results are memoized anyway.
+				if err := store.putScore(ctx, repoURL, result); err != nil {
+					s.logger.Error(err, fmt.Sprintf("storing the results of %s@%s", repoURL, commitSHA))
+				}
+			}
+		}
+		s.mu.Lock()
+		if commitSHA != clients.HeadSHA {
+			s.results[key] = result
+		}
+		if result.Repo.CommitSHA != "" {
+			s.results[scoreKey{repo: key.repo, commit: result.Repo.CommitSHA}] = result
+		}
+		s.mu.Unlock()
+		return result, nil
+	}
+	return nil, fmt.Errorf("%w: %s@%s: %s", ErrScoreNotFound, repoURL, commitSHA, strings.Join(errs, "; "))
+}
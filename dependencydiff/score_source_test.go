@@ -0,0 +1,242 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencydiff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/checks"
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/pkg"
+)
+
+// fakeScoreSource returns a result per call, or ErrScoreNotFound if it has none.
+type fakeScoreSource struct {
+	results map[string]*pkg.ScorecardResult
+	calls   int
+	// checksRun records the checks asked for by repo and commit.
+	checksRun map[string][]string
+}
+
+func (s *fakeScoreSource) GetScore(ctx context.Context, repoURL, commitSHA string,
+	checksToRun checker.CheckNameToFnMap,
+) (*pkg.ScorecardResult, error) {
+	s.calls++
+	if s.checksRun != nil {
+		for name := range checksToRun {
+			s.checksRun[repoURL+"@"+commitSHA] = append(s.checksRun[repoURL+"@"+commitSHA], name)
+		}
+		sort.Strings(s.checksRun[repoURL+"@"+commitSHA])
+	}
+	result, ok := s.results[repoURL+"@"+commitSHA]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrScoreNotFound, repoURL)
+	}
+	ret, _ := withChecks(result, checksToRun)
+	return ret, nil
+}
+
+func testResult(commitSHA string, checkScores map[string]int) *pkg.ScorecardResult {
+	date, _ := time.Parse("2006-01-02", "2022-10-01")
+	result := &pkg.ScorecardResult{
+		Repo: pkg.RepoInfo{Name: "github.com/owner/repo", CommitSHA: commitSHA},
+		Date: date,
+	}
+	for _, name := range []string{checks.CheckBinaryArtifacts, checks.CheckLicense, checks.CheckMaintained} {
+		if score, ok := checkScores[name]; ok {
+			result.Checks = append(result.Checks, checker.CheckResult{
+				Name:   name,
+				Score:  score,
+				Reason: "reason",
+				Details: []checker.CheckDetail{
+					{Type: checker.DetailWarn, Msg: checker.LogMessage{Text: "warning"}},
+				},
+			})
+		}
+	}
+	return result
+}
+
+func TestStoredScoreSource(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "github.com", "owner", "repo", "sha1"), 0o755); err != nil {
+		t.Fatalf("os.MkdirAll: %v", err)
+	}
+	const stored = `{"date": "2022-10-01", "repo": {"name": "github.com/owner/repo", "commit": "sha1"},
+		"scorecard": {"version": "v4.8.0", "commit": "abc"}, "score": 6.5,
+		"checks": [
+			{"name": "Binary-Artifacts", "score": 10, "reason": "no binaries found", "details": null},
+			{"name": "License", "score": 0, "reason": "license file not detected",
+				"details": ["Warn: project does not have a license file"]}
+		]}`
+	if err := os.WriteFile(filepath.Join(dir, "github.com", "owner", "repo", "sha1", "results.json"),
+		[]byte(stored), 0o600); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+	source, err := NewStoredScoreSource(dir)
+	if err != nil {
+		t.Fatalf("NewStoredScoreSource: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		repoURL   string
+		commitSHA string
+		checks    []string
+		want      []checker.CheckResult
+		wantErr   error
+	}{
+		{
+			name:      "subset of the stored checks",
+			repoURL:   "https://github.com/owner/repo",
+			commitSHA: "sha1",
+			checks:    []string{checks.CheckLicense},
+			want: []checker.CheckResult{
+				{
+					Name:   checks.CheckLicense,
+					Reason: "license file not detected",
+					Details: []checker.CheckDetail{
+						{Type: checker.DetailWarn, Msg: checker.LogMessage{Text: "project does not have a license file"}},
+					},
+				},
+			},
+		},
+		{
+			name:      "missing check",
+			repoURL:   "https://github.com/owner/repo",
+			commitSHA: "sha1",
+			checks:    []string{checks.CheckLicense, checks.CheckMaintained},
+			wantErr:   ErrScoreNotFound,
+		},
+		{
+			name:      "missing commit",
+			repoURL:   "https://github.com/owner/repo",
+			commitSHA: "sha2",
+			checks:    []string{checks.CheckLicense},
+			wantErr:   ErrScoreNotFound,
+		},
+		{
+			name:      "missing latest results",
+			repoURL:   "https://github.com/owner/repo",
+			commitSHA: clients.HeadSHA,
+			checks:    []string{checks.CheckLicense},
+			wantErr:   ErrScoreNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			checksToRun := checker.CheckNameToFnMap{}
+			for _, c := range tt.checks {
+				checksToRun[c] = checker.Check{}
+			}
+			got, err := source.GetScore(context.Background(), tt.repoURL, tt.commitSHA, checksToRun)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetScore() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Repo.CommitSHA != tt.commitSHA {
+				t.Errorf("GetScore() commit = %s, want %s", got.Repo.CommitSHA, tt.commitSHA)
+			}
+			if diff := cmp.Diff(tt.want, got.Checks); diff != "" {
+				t.Errorf("GetScore() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCachingScoreSource(t *testing.T) {
+	t.Parallel()
+	storedDir := t.TempDir()
+	stored, err := NewStoredScoreSource(storedDir)
+	if err != nil {
+		t.Fatalf("NewStoredScoreSource: %v", err)
+	}
+	cache, err := NewScoreCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewScoreCache: %v", err)
+	}
+	// The cache already has a result of another check at the commit.
+	//nolint:forcetypeassert
+	if err := cache.(scoreStore).putScore(context.Background(), "https://github.com/owner/repo",
+		testResult("sha1", map[string]int{checks.CheckMaintained: 8})); err != nil {
+		t.Fatalf("putScore: %v", err)
+	}
+	live := &fakeScoreSource{
+		results: map[string]*pkg.ScorecardResult{
+			"https://github.com/owner/repo@" + clients.HeadSHA: testResult("sha1", map[string]int{
+				checks.CheckBinaryArtifacts: 10,
+				checks.CheckLicense:         9,
+			}),
+		},
+	}
+	source := NewCachingScoreSource(stored, cache, live)
+	checksToRun := checker.CheckNameToFnMap{
+		checks.CheckBinaryArtifacts: checker.Check{},
+		checks.CheckLicense:         checker.Check{},
+	}
+
+	// The lookups of HEAD run live, as HEAD moves, and the one of the commit HEAD was at is memoized.
+	for _, commitSHA := range []string{clients.HeadSHA, clients.HeadSHA, "sha1"} {
+		result, err := source.GetScore(context.Background(), "https://github.com/owner/repo", commitSHA, checksToRun)
+		if err != nil {
+			t.Fatalf("GetScore(%s): %v", commitSHA, err)
+		}
+		if len(result.Checks) != 2 {
+			t.Errorf("GetScore(%s) returned %d checks, want 2", commitSHA, len(result.Checks))
+		}
+	}
+	if live.calls != 2 {
+		t.Errorf("live source called %d times, want 2", live.calls)
+	}
+
+	// The live results were merged into the cache for later runs.
+	result, err := cache.GetScore(context.Background(), "https://github.com/owner/repo", "sha1",
+		checker.CheckNameToFnMap{checks.CheckLicense: checker.Check{}, checks.CheckMaintained: checker.Check{}})
+	if err != nil {
+		t.Fatalf("cache GetScore: %v", err)
+	}
+	scores := map[string]int{}
+	for i := range result.Checks {
+		scores[result.Checks[i].Name] = result.Checks[i].Score
+	}
+	if diff := cmp.Diff(map[string]int{checks.CheckLicense: 9, checks.CheckMaintained: 8}, scores); diff != "" {
+		t.Errorf("cache GetScore() mismatch (-want +got):\n%s", diff)
+	}
+	// The stored results are only read.
+	if entries, err := os.ReadDir(storedDir); err != nil || len(entries) != 0 {
+		t.Errorf("stored results were written to: %v, %v", entries, err)
+	}
+
+	// Neither source has results.
+	_, err = source.GetScore(context.Background(), "https://github.com/owner/other", clients.HeadSHA, checksToRun)
+	if !errors.Is(err, ErrScoreNotFound) {
+		t.Errorf("GetScore() error = %v, want %v", err, ErrScoreNotFound)
+	}
+}
@@ -16,6 +16,7 @@ package e2e
 
 import (
 	"context"
+	"math"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -66,21 +67,26 @@ var _ = Describe("E2E TEST:"+checks.CheckVulnerabilities, func() {
 			Expect(err).Should(BeNil())
 
 			dl := scut.TestDetailLogger{}
+			raw := checker.RawResults{}
 			checkRequest := checker.CheckRequest{
 				Ctx:                   context.Background(),
 				RepoClient:            repoClient,
 				VulnerabilitiesClient: clients.DefaultVulnerabilitiesClient(),
 				Repo:                  repo,
 				Dlogger:               &dl,
+				RawResults:            &raw,
 			}
+			result := checks.Vulnerabilities(&checkRequest)
+			// OSV knows of 3 vulnerabilities of the repo, whose severities set the score.
+			vulns := raw.VulnerabilitiesResults.Vulnerabilities
+			Expect(vulns).Should(HaveLen(3))
 			expected := scut.TestReturn{
 				Error:         nil,
-				Score:         checker.MaxResultScore - 3, // 3 vulnerabilities remove 3 points.
-				NumberOfWarn:  1,
+				Score:         expectedVulnerabilitiesScore(vulns),
+				NumberOfWarn:  len(vulns),
 				NumberOfInfo:  0,
 				NumberOfDebug: 0,
 			}
-			result := checks.Vulnerabilities(&checkRequest)
 			// New version.
 			Expect(scut.ValidateTestReturn(nil, "osv vulnerabilities", &expected, &result, &dl)).Should(BeTrue())
 			Expect(repoClient.Close()).Should(BeNil())
@@ -93,24 +99,50 @@ var _ = Describe("E2E TEST:"+checks.CheckVulnerabilities, func() {
 			Expect(err).Should(BeNil())
 
 			dl := scut.TestDetailLogger{}
+			raw := checker.RawResults{}
 			checkRequest := checker.CheckRequest{
 				Ctx:                   context.Background(),
 				RepoClient:            repoClient,
 				VulnerabilitiesClient: clients.DefaultVulnerabilitiesClient(),
 				Repo:                  repo,
 				Dlogger:               &dl,
+				RawResults:            &raw,
 			}
+			result := checks.Vulnerabilities(&checkRequest)
+			// OSV knows of 3 vulnerabilities of the repo, whose severities set the score.
+			vulns := raw.VulnerabilitiesResults.Vulnerabilities
+			Expect(vulns).Should(HaveLen(3))
 			expected := scut.TestReturn{
 				Error:         nil,
-				Score:         checker.MaxResultScore - 3, // 3 vulnerabilities remove 3 points.
-				NumberOfWarn:  1,
+				Score:         expectedVulnerabilitiesScore(vulns),
+				NumberOfWarn:  len(vulns),
 				NumberOfInfo:  0,
 				NumberOfDebug: 0,
 			}
-			result := checks.Vulnerabilities(&checkRequest)
 			// New version.
 			Expect(scut.ValidateTestReturn(nil, "osv vulnerabilities", &expected, &result, &dl)).Should(BeTrue())
 			Expect(repoClient.Close()).Should(BeNil())
 		})
 	})
 })
+
+// expectedVulnerabilitiesScore returns the score of the vulns, each costing
+// 5, 3, 1 or 0.5 points as it is critical, high, medium or low, and 1 if unknown.
+func expectedVulnerabilitiesScore(vulns []clients.Vulnerability) int {
+	penalties := map[string]float64{
+		clients.SeverityCritical: 5,
+		clients.SeverityHigh:     3,
+		clients.SeverityMedium:   1,
+		clients.SeverityLow:      0.5,
+		"":                       1,
+	}
+	var penalty float64
+	for i := range vulns {
+		penalty += penalties[vulns[i].Severity]
+	}
+	score := checker.MaxResultScore - int(math.Ceil(penalty))
+	if score < checker.MinResultScore {
+		score = checker.MinResultScore
+	}
+	return score
+}
@@ -56,6 +56,14 @@ func GetName(err error) string {
 		return "ErrRepoUnreachable"
 	case errors.Is(err, ErrorShellParsing):
 		return "ErrorShellParsing"
+	case errors.Is(err, ErrorUnsupportedHost):
+		return "ErrorUnsupportedHost"
+	case errors.Is(err, ErrorInvalidURL):
+		return "ErrorInvalidURL"
+	case errors.Is(err, ErrorUnsupportedCheck):
+		return "ErrorUnsupportedCheck"
+	case errors.Is(err, ErrorCheckRuntime):
+		return "ErrorCheckRuntime"
 	default:
 		return "ErrUnknown"
 	}
@@ -0,0 +1,176 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openvex defines OpenVEX documents, see https://github.com/openvex/spec.
+package openvex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	sce "github.com/ossf/scorecard/v4/errors"
+)
+
+// Context is the JSON-LD context of the OpenVEX documents written by Scorecard.
+const Context = "https://openvex.dev/ns/v0.2.0"
+
+// Status is the status of a product regarding a vulnerability.
+type Status string
+
+const (
+	// StatusNotAffected suggests no remediation is required.
+	StatusNotAffected Status = "not_affected"
+	// StatusAffected suggests actions are recommended to remediate the vulnerability.
+	StatusAffected Status = "affected"
+	// StatusFixed suggests the product contains a fix for the vulnerability.
+	StatusFixed Status = "fixed"
+	// StatusUnderInvestigation suggests it's not yet known whether the product is affected.
+	StatusUnderInvestigation Status = "under_investigation"
+)
+
+// Document is an OpenVEX document.
+type Document struct {
+	Context    string      `json:"@context"`
+	ID         string      `json:"@id"`
+	Author     string      `json:"author"`
+	Timestamp  string      `json:"timestamp"`
+	Tooling    string      `json:"tooling,omitempty"`
+	Statements []Statement `json:"statements"`
+	Version    int         `json:"version"`
+}
+
+// Statement is the status of products regarding a vulnerability.
+type Statement struct {
+	Vulnerability Vulnerability `json:"vulnerability"`
+	Status        Status        `json:"status"`
+	// Justification is one of the OpenVEX justifications for StatusNotAffected,
+	// e.g. vulnerable_code_not_in_execute_path.
+	Justification string `json:"justification,omitempty"`
+	// ImpactStatement explains in free form why the products are StatusNotAffected.
+	ImpactStatement string `json:"impact_statement,omitempty"`
+	// ActionStatement describes the remediation of StatusAffected products.
+	ActionStatement string    `json:"action_statement,omitempty"`
+	StatusNotes     string    `json:"status_notes,omitempty"`
+	Products        []Product `json:"products,omitempty"`
+}
+
+// Vulnerability identifies a vulnerability, e.g. by its CVE ID.
+type Vulnerability struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// UnmarshalJSON also accepts vulnerabilities given as a string, as in OpenVEX v0.0.1.
+func (v *Vulnerability) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*v = Vulnerability{Name: name}
+		return nil
+	}
+	type vulnerability Vulnerability
+	var vuln vulnerability
+	if err := json.Unmarshal(data, &vuln); err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	*v = Vulnerability(vuln)
+	return nil
+}
+
+// Product identifies a product, e.g. by its package URL, and its subcomponents
+// the statement applies to.
+type Product struct {
+	ID            string         `json:"@id"`
+	Subcomponents []Subcomponent `json:"subcomponents,omitempty"`
+}
+
+// UnmarshalJSON also accepts products given as a string, as in OpenVEX v0.0.1.
+func (p *Product) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*p = Product{ID: id}
+		return nil
+	}
+	type product Product
+	var prod product
+	if err := json.Unmarshal(data, &prod); err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	*p = Product(prod)
+	return nil
+}
+
+// Subcomponent identifies a component of a product, e.g. a dependency.
+type Subcomponent struct {
+	ID string `json:"@id"`
+}
+
+// Parse parses an OpenVEX document.
+func Parse(content []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("json.Unmarshal: %v", err))
+	}
+	if !strings.HasPrefix(doc.Context, "https://openvex.dev/ns") {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("unsupported context: %s", doc.Context))
+	}
+	return &doc, nil
+}
+
+// IsVEXFile returns whether a file of a repo is an OpenVEX document.
+func IsVEXFile(filepath string) bool {
+	base := path.Base(filepath)
+	return base == ".vex" || strings.HasSuffix(base, ".vex.json") || strings.HasSuffix(base, ".openvex.json")
+}
+
+// RepoPackageURL returns the package URL of a repo, e.g. github.com/owner/repo,
+// at a version, e.g. a commit SHA, or without version if it's empty.
+func RepoPackageURL(repoName, version string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(repoName, "https://"), "http://")
+	if version != "" {
+		version = "@" + url.PathEscape(version)
+	}
+	host, path, _ := strings.Cut(name, "/")
+	switch host {
+	case "github.com":
+		return fmt.Sprintf("pkg:github/%s%s", strings.ToLower(path), version)
+	case "gitlab.com":
+		return fmt.Sprintf("pkg:gitlab/%s%s", strings.ToLower(path), version)
+	}
+	vcsURL := repoName
+	if !strings.Contains(vcsURL, "://") {
+		vcsURL = "https://" + vcsURL
+	}
+	base := name[strings.LastIndex(name, "/")+1:]
+	return fmt.Sprintf("pkg:generic/%s%s?vcs_url=%s", url.PathEscape(base), version, url.QueryEscape("git+"+vcsURL))
+}
+
+// SamePackage returns whether two package URLs identify the same package, whatever their versions.
+func SamePackage(purl, other string) bool {
+	return strings.EqualFold(unversioned(purl), unversioned(other))
+}
+
+// unversioned returns a package URL without its version, keeping its qualifiers.
+func unversioned(purl string) string {
+	name, qualifiers, hasQualifiers := strings.Cut(purl, "?")
+	name, _, _ = strings.Cut(name, "@")
+	if hasQualifiers {
+		return name + "?" + qualifiers
+	}
+	return name
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvex
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		want    *Document
+		wantErr bool
+	}{
+		{
+			name: "v0.2.0",
+			content: `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex-1",
+  "author": "maintainers",
+  "timestamp": "2023-01-01T00:00:00Z",
+  "version": 2,
+  "statements": [{
+    "vulnerability": {"@id": "https://nvd.nist.gov/vuln/detail/CVE-2022-1", "name": "CVE-2022-1", "aliases": ["GHSA-1"]},
+    "products": [{"@id": "pkg:github/owner/repo", "subcomponents": [{"@id": "pkg:golang/example.com/mod"}]}],
+    "status": "not_affected",
+    "justification": "vulnerable_code_not_present"
+  }]
+}`,
+			want: &Document{
+				Context:   "https://openvex.dev/ns/v0.2.0",
+				ID:        "https://example.com/vex-1",
+				Author:    "maintainers",
+				Timestamp: "2023-01-01T00:00:00Z",
+				Version:   2,
+				Statements: []Statement{{
+					Vulnerability: Vulnerability{Name: "CVE-2022-1", Aliases: []string{"GHSA-1"}},
+					Products: []Product{{
+						ID:            "pkg:github/owner/repo",
+						Subcomponents: []Subcomponent{{ID: "pkg:golang/example.com/mod"}},
+					}},
+					Status:        StatusNotAffected,
+					Justification: "vulnerable_code_not_present",
+				}},
+			},
+		},
+		{
+			name: "v0.0.1",
+			content: `{
+  "@context": "https://openvex.dev/ns",
+  "statements": [{
+    "vulnerability": "CVE-2022-1",
+    "products": ["pkg:github/owner/repo"],
+    "status": "not_affected",
+    "impact_statement": "the vulnerable function is never called"
+  }]
+}`,
+			want: &Document{
+				Context: "https://openvex.dev/ns",
+				Statements: []Statement{{
+					Vulnerability:   Vulnerability{Name: "CVE-2022-1"},
+					Products:        []Product{{ID: "pkg:github/owner/repo"}},
+					Status:          StatusNotAffected,
+					ImpactStatement: "the vulnerable function is never called",
+				}},
+			},
+		},
+		{
+			name:    "not OpenVEX",
+			content: `{"@context": "https://cyclonedx.org", "statements": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			content: `{"statements": [{"vulnerability": 1}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsVEXFile(t *testing.T) {
+	t.Parallel()
+	tests := map[string]bool{
+		".vex":                     true,
+		"security/.vex":            true,
+		"repo.vex.json":            true,
+		"vex/release.openvex.json": true,
+		"vex.go":                   false,
+		"convex.json":              false,
+	}
+	for path, want := range tests {
+		if got := IsVEXFile(path); got != want {
+			t.Errorf("IsVEXFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestSamePackage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		purl, other string
+		want        bool
+	}{
+		{purl: "pkg:github/owner/repo", other: "pkg:github/owner/repo", want: true},
+		{purl: "pkg:github/Owner/Repo@abc", other: "pkg:github/owner/repo", want: true},
+		{purl: "pkg:github/owner/other", other: "pkg:github/owner/repo", want: false},
+		{
+			purl:  "pkg:generic/tool@abc?vcs_url=git%2Bhttps%3A%2F%2Fgit.example.com%2Fteam%2Ftool",
+			other: RepoPackageURL("git.example.com/team/tool", ""),
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		if got := SamePackage(tt.purl, tt.other); got != tt.want {
+			t.Errorf("SamePackage(%s, %s) = %v, want %v", tt.purl, tt.other, got, tt.want)
+		}
+	}
+}
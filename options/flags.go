@@ -59,6 +59,26 @@ const (
 
 	// FlagFormat is the flag name for specifying output format.
 	FlagFormat = "format"
+
+	// FlagContributorsMapping is the flag name for specifying a file
+	// of company and identity aliases for the Contributors check.
+	FlagContributorsMapping = "contributors-mapping"
+
+	// FlagOSVDatabase is the flag name for specifying an offline
+	// OSV database for the Vulnerabilities check.
+	FlagOSVDatabase = "osv-db"
+
+	// FlagCacheDir is the flag name for specifying a directory
+	// caching the responses of the GitHub API across runs.
+	FlagCacheDir = "cache-dir"
+
+	// FlagShowUsage is the flag name for outputting the usage
+	// of resources by each check.
+	FlagShowUsage = "show-usage"
+
+	// FlagServeMetrics is the flag name for serving the metrics
+	// for Prometheus along with the results.
+	FlagServeMetrics = "metrics"
 )
 
 // Command is an interface for handling options for command-line utilities.
@@ -132,6 +152,34 @@ func (o *Options) AddFlags(cmd *cobra.Command) {
 		"show extra details about each check",
 	)
 
+	cmd.Flags().BoolVar(
+		&o.ShowUsage,
+		FlagShowUsage,
+		o.ShowUsage,
+		"show the wall time, HTTP requests, bytes downloaded and cache hits of each check in the JSON output",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ContributorsMapping,
+		FlagContributorsMapping,
+		o.ContributorsMapping,
+		"YAML file mapping alternate company names and user identities for the Contributors check",
+	)
+
+	cmd.Flags().StringVar(
+		&o.OSVDatabase,
+		FlagOSVDatabase,
+		o.OSVDatabase,
+		"OSV database zip export, or directory of them, to query instead of the OSV API",
+	)
+
+	cmd.Flags().StringVar(
+		&o.CacheDir,
+		FlagCacheDir,
+		o.CacheDir,
+		"directory caching the responses of the GitHub API across runs, revalidated with conditional requests",
+	)
+
 	checkNames := []string{}
 	for checkName := range checks.GetAll() {
 		checkNames = append(checkNames, checkName)
@@ -147,18 +195,22 @@ func (o *Options) AddFlags(cmd *cobra.Command) {
 	allowedFormats := []string{
 		FormatDefault,
 		FormatJSON,
+		FormatOpenVEX,
+		FormatJUnit,
+		FormatGitLabCodeQuality,
 	}
 
+	policyHelp := fmt.Sprintf("policy to enforce with the %s and %s formats", FormatJUnit, FormatGitLabCodeQuality)
 	if o.isSarifEnabled() {
-		cmd.Flags().StringVar(
-			&o.PolicyFile,
-			FlagPolicyFile,
-			o.PolicyFile,
-			"policy to enforce",
-		)
-
+		policyHelp = "policy to enforce"
 		allowedFormats = append(allowedFormats, FormatSarif)
 	}
+	cmd.Flags().StringVar(
+		&o.PolicyFile,
+		FlagPolicyFile,
+		o.PolicyFile,
+		policyHelp,
+	)
 
 	cmd.Flags().StringVar(
 		&o.Format,
@@ -37,11 +37,26 @@ type Options struct {
 	PyPI       string
 	RubyGems   string
 	PolicyFile string
+	// ContributorsMapping is a file of company and
+	// identity aliases for the Contributors check.
+	ContributorsMapping string
+	// OSVDatabase is an OSV database zip export, or a directory
+	// of them, queried offline by the Vulnerabilities check.
+	OSVDatabase string
+	// CacheDir is a directory caching the responses
+	// of the GitHub API across runs.
+	CacheDir string
 	// TODO(action): Add logic for writing results to file
 	ResultsFile string
 	ChecksToRun []string
 	Metadata    []string
 	ShowDetails bool
+	// ShowUsage records the usage of resources
+	// by each check and by their shared setup, output in JSON.
+	ShowUsage bool
+	// ServeMetrics exposes the metrics for Prometheus
+	// on /metrics of `scorecard serve`.
+	ServeMetrics bool
 
 	// Feature flags.
 	EnableSarif       bool `env:"ENABLE_SARIF"`
@@ -84,6 +99,12 @@ const (
 	FormatDefault = "default"
 	// FormatRaw specifies that results should be output in raw format.
 	FormatRaw = "raw"
+	// FormatOpenVEX specifies that the Vulnerabilities check results should be output as an OpenVEX document.
+	FormatOpenVEX = "openvex"
+	// FormatJUnit specifies that results should be output as a JUnit XML report.
+	FormatJUnit = "junit"
+	// FormatGitLabCodeQuality specifies that results should be output as a GitLab Code Quality report.
+	FormatGitLabCodeQuality = "gitlab-codequality"
 
 	// Environment variables.
 
@@ -135,7 +156,7 @@ func (o *Options) Validate() error {
 				errSARIFNotSupported,
 			)
 		}
-		if o.PolicyFile != "" {
+		if o.PolicyFile != "" && !isPolicyFormat(o.Format) {
 			errs = append(
 				errs,
 				errPolicyFileNotSupported,
@@ -209,9 +230,15 @@ func (o *Options) isV6Enabled() bool {
 
 func validateFormat(format string) bool {
 	switch format {
-	case FormatJSON, FormatSarif, FormatDefault, FormatRaw:
+	case FormatJSON, FormatSarif, FormatDefault, FormatRaw, FormatOpenVEX, FormatJUnit, FormatGitLabCodeQuality:
 		return true
 	default:
 		return false
 	}
 }
+
+// isPolicyFormat returns true if the format reports the results against the policy
+// without SARIF being enabled.
+func isPolicyFormat(format string) bool {
+	return format == FormatJUnit || format == FormatGitLabCodeQuality
+}
@@ -77,6 +77,16 @@ func TestOptions_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "format junit with a policy file",
+			fields: fields{
+				Repo:       "github.com/oss/scorecard",
+				Commit:     "HEAD",
+				Format:     "junit",
+				PolicyFile: "testdata/policy.yaml",
+			},
+			wantErr: false,
+		},
 		{
 			name: "format raw is not supported when V6 is not enabled",
 			fields: fields{
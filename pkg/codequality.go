@@ -0,0 +1,123 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ossf/scorecard/v4/checker"
+	docs "github.com/ossf/scorecard/v4/docs/checks"
+	sce "github.com/ossf/scorecard/v4/errors"
+	spol "github.com/ossf/scorecard/v4/policy"
+)
+
+// GitLab Code Quality report,
+// see https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool.
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin uint `json:"begin"`
+	End   uint `json:"end,omitempty"`
+}
+
+// Severities of the issues, by risk of their check.
+var riskToCodeQualitySeverity = map[string]string{
+	"Critical": "critical",
+	"High":     "major",
+	"Medium":   "minor",
+	"Low":      "info",
+}
+
+// AsGitLabCodeQuality outputs ScorecardResult as a GitLab Code Quality report.
+// Each warning of the checks about a file of the repo is an issue, with the
+// severity of its check's risk. Checks disabled by the policy are ignored.
+func (r *ScorecardResult) AsGitLabCodeQuality(writer io.Writer, checkDocs docs.Doc,
+	policy *spol.ScorecardPolicy,
+) error {
+	issues := []codeQualityIssue{}
+	for i := range r.Checks {
+		check := &r.Checks[i]
+		if _, enabled, _ := checkThreshold(policy, check.Name); !enabled {
+			continue
+		}
+		doc, err := checkDocs.GetCheck(check.Name)
+		if err != nil {
+			return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("GetCheck: %v: %s", err, check.Name))
+		}
+		severity, ok := riskToCodeQualitySeverity[doc.GetRisk()]
+		if !ok {
+			severity = "info"
+		}
+		for j := range check.Details {
+			d := &check.Details[j]
+			if d.Type != checker.DetailWarn || d.Msg.Path == "" || !isRepoFile(d.Msg.Type) {
+				continue
+			}
+			issue := codeQualityIssue{
+				Description: fmt.Sprintf("%s: %s", check.Name, d.Msg.Text),
+				CheckName:   check.Name,
+				Fingerprint: codeQualityFingerprint(check.Name, d),
+				Severity:    severity,
+				Location: codeQualityLocation{
+					Path:  d.Msg.Path,
+					Lines: codeQualityLines{Begin: 1},
+				},
+			}
+			// GitLab requires a line: the issues without one, or about binaries
+			// whose offsets aren't lines, are reported on the first line.
+			if d.Msg.Type != checker.FileTypeBinary && d.Msg.Offset > 0 {
+				issue.Location.Lines.Begin = d.Msg.Offset
+				if d.Msg.EndOffset > d.Msg.Offset {
+					issue.Location.Lines.End = d.Msg.EndOffset
+				}
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(issues); err != nil {
+		return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("encoder.Encode: %v", err))
+	}
+	return nil
+}
+
+func isRepoFile(t checker.FileType) bool {
+	return t == checker.FileTypeSource || t == checker.FileTypeBinary || t == checker.FileTypeText
+}
+
+// codeQualityFingerprint identifies an issue across runs. Like the SARIF results,
+// it leaves out the line so that the issue survives lines added above it.
+func codeQualityFingerprint(checkName string, d *checker.CheckDetail) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s", checkName, d.Msg.Path, d.Msg.Text, d.Msg.Snippet)
+	return hex.EncodeToString(h.Sum(nil))
+}
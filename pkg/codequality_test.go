@@ -0,0 +1,90 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	spol "github.com/ossf/scorecard/v4/policy"
+)
+
+func TestAsGitLabCodeQuality(t *testing.T) {
+	t.Parallel()
+	checkDocs := &mockDoc{
+		checks: map[string]mockCheck{
+			"Binary-Artifacts":    {name: "Binary-Artifacts", risk: "High"},
+			"Code-Review":         {name: "Code-Review", risk: "High"},
+			"License":             {name: "License", risk: "Low"},
+			"Pinned-Dependencies": {name: "Pinned-Dependencies", risk: "Medium"},
+			"Signed-Releases":     {name: "Signed-Releases", risk: "High"},
+		},
+	}
+	binaryIssue := codeQualityIssue{
+		Description: "Binary-Artifacts: binary detected",
+		CheckName:   "Binary-Artifacts",
+		Severity:    "major",
+		Location:    codeQualityLocation{Path: "bin/tool.exe", Lines: codeQualityLines{Begin: 1}},
+	}
+	pinningIssue := codeQualityIssue{
+		Description: "Pinned-Dependencies: containerImage not pinned by hash",
+		CheckName:   "Pinned-Dependencies",
+		Severity:    "minor",
+		Location:    codeQualityLocation{Path: "Dockerfile", Lines: codeQualityLines{Begin: 3, End: 4}},
+	}
+	tests := []struct {
+		name   string
+		policy *spol.ScorecardPolicy
+		want   []codeQualityIssue
+	}{
+		{
+			name: "no policy",
+			want: []codeQualityIssue{binaryIssue, pinningIssue},
+		},
+		{
+			name:   "check disabled by the policy",
+			policy: formatTestPolicy(),
+			want:   []codeQualityIssue{binaryIssue},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			if err := formatTestResult().AsGitLabCodeQuality(&buf, checkDocs, tt.policy); err != nil {
+				t.Fatalf("AsGitLabCodeQuality: %v", err)
+			}
+			var got []codeQualityIssue
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+			fingerprints := map[string]bool{}
+			for i := range got {
+				if len(got[i].Fingerprint) != 64 || fingerprints[got[i].Fingerprint] {
+					t.Errorf("invalid or duplicate fingerprint %q", got[i].Fingerprint)
+				}
+				fingerprints[got[i].Fingerprint] = true
+				got[i].Fingerprint = ""
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("AsGitLabCodeQuality() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
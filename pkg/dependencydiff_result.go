@@ -44,6 +44,23 @@ func (ct ChangeType) IsValid() bool {
 	}
 }
 
+// CommitResolution is how the commit of the source repository scored for a dependency
+// was resolved from the dependency version.
+type CommitResolution string
+
+const (
+	// ResolvedByGitHead suggests the commit is the gitHead of the npm package version.
+	ResolvedByGitHead CommitResolution = "npm-git-head"
+	// ResolvedByPseudoVersion suggests the commit is the revision of the Go module pseudo-version.
+	ResolvedByPseudoVersion CommitResolution = "go-pseudo-version"
+	// ResolvedByVCSInfo suggests the commit is the one recorded in the .cargo_vcs_info.json of the crate.
+	ResolvedByVCSInfo CommitResolution = "cargo-vcs-info"
+	// ResolvedByTag suggests the commit is the one of the source repository tag matching the version.
+	ResolvedByTag CommitResolution = "version-tag"
+	// NotResolved suggests the version couldn't be resolved, so the HEAD of the source repository is scored.
+	NotResolved CommitResolution = "head"
+)
+
 // ScorecardResultWithError is used for the dependency-diff module to record the scorecard result
 // and a error field to record potential errors when the Scorecard run fails.
 type ScorecardResultWithError struct {
@@ -74,6 +91,13 @@ type DependencyCheckResult struct {
 	// Version is the package version of the dependency.
 	Version *string
 
+	// ScoredCommit is the commit of the source repository the scorecard checks ran at.
+	// The checks which don't support commits, e.g. Maintained, ran at HEAD.
+	ScoredCommit *string
+
+	// CommitResolution is how the scored commit was resolved from the dependency version.
+	CommitResolution *CommitResolution
+
 	// ScorecardResultWithError is the scorecard checking result of the dependency.
 	ScorecardResultWithError ScorecardResultWithError
 
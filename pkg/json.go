@@ -18,7 +18,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
+	"github.com/ossf/scorecard/v4/checker"
 	docs "github.com/ossf/scorecard/v4/docs/checks"
 	sce "github.com/ossf/scorecard/v4/errors"
 	"github.com/ossf/scorecard/v4/log"
@@ -52,6 +55,39 @@ type jsonCheckResultV2 struct {
 	Reason  string                   `json:"reason"`
 	Name    string                   `json:"name"`
 	Doc     jsonCheckDocumentationV2 `json:"documentation"`
+	Usage   *jsonResourceUsageV2     `json:"usage,omitempty"`
+}
+
+// jsonResourceUsageV2 is the checker.ResourceUsage of a check, or of the setup shared by the checks.
+type jsonResourceUsageV2 struct {
+	Requests        map[string]int64 `json:"requests"`
+	WallTimeSeconds float64          `json:"wallTimeSeconds"`
+	BytesDownloaded int64            `json:"bytesDownloaded"`
+	CacheHits       int64            `json:"cacheHits"`
+}
+
+func newJSONResourceUsageV2(usage *checker.ResourceUsage) *jsonResourceUsageV2 {
+	if usage == nil {
+		return nil
+	}
+	return &jsonResourceUsageV2{
+		Requests:        usage.Requests,
+		WallTimeSeconds: usage.WallTime.Seconds(),
+		BytesDownloaded: usage.BytesDownloaded,
+		CacheHits:       usage.CacheHits,
+	}
+}
+
+func (u *jsonResourceUsageV2) resourceUsage() *checker.ResourceUsage {
+	if u == nil {
+		return nil
+	}
+	return &checker.ResourceUsage{
+		Requests:        u.Requests,
+		WallTime:        time.Duration(u.WallTimeSeconds * float64(time.Second)),
+		BytesDownloaded: u.BytesDownloaded,
+		CacheHits:       u.CacheHits,
+	}
 }
 
 type jsonRepoV2 struct {
@@ -75,12 +111,13 @@ func (s jsonFloatScore) MarshalJSON() ([]byte, error) {
 //
 //nolint:govet
 type JSONScorecardResultV2 struct {
-	Date           string              `json:"date"`
-	Repo           jsonRepoV2          `json:"repo"`
-	Scorecard      jsonScorecardV2     `json:"scorecard"`
-	AggregateScore jsonFloatScore      `json:"score"`
-	Checks         []jsonCheckResultV2 `json:"checks"`
-	Metadata       []string            `json:"metadata"`
+	Date           string               `json:"date"`
+	Repo           jsonRepoV2           `json:"repo"`
+	Scorecard      jsonScorecardV2      `json:"scorecard"`
+	AggregateScore jsonFloatScore       `json:"score"`
+	Checks         []jsonCheckResultV2  `json:"checks"`
+	Metadata       []string             `json:"metadata"`
+	SetupUsage     *jsonResourceUsageV2 `json:"setupUsage,omitempty"`
 }
 
 // AsJSON exports results as JSON for new detail format.
@@ -137,6 +174,7 @@ func (r *ScorecardResult) AsJSON2(showDetails bool,
 		Date:           r.Date.Format("2006-01-02"),
 		Metadata:       r.Metadata,
 		AggregateScore: jsonFloatScore(score),
+		SetupUsage:     newJSONResourceUsageV2(r.SetupUsage),
 	}
 
 	for _, checkResult := range r.Checks {
@@ -153,6 +191,7 @@ func (r *ScorecardResult) AsJSON2(showDetails bool,
 			},
 			Reason: checkResult.Reason,
 			Score:  checkResult.Score,
+			Usage:  newJSONResourceUsageV2(checkResult.Usage),
 		}
 		if showDetails {
 			for i := range checkResult.Details {
@@ -172,3 +211,58 @@ func (r *ScorecardResult) AsJSON2(showDetails bool,
 
 	return nil
 }
+
+// ExperimentalFromJSON2 parses results exported by AsJSON2, e.g. those published by the cron job,
+// and returns them along with their aggregate score. Details are parsed back into their type and text.
+func ExperimentalFromJSON2(r io.Reader) (result ScorecardResult, score float64, err error) {
+	var jsr JSONScorecardResultV2
+	if err := json.NewDecoder(r).Decode(&jsr); err != nil {
+		return ScorecardResult{}, 0, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("decoder.Decode: %v", err))
+	}
+
+	date, err := time.Parse("2006-01-02", jsr.Date)
+	if err != nil {
+		return ScorecardResult{}, 0, sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("time.Parse: %v", err))
+	}
+	result = ScorecardResult{
+		Repo: RepoInfo{
+			Name:      jsr.Repo.Name,
+			CommitSHA: jsr.Repo.Commit,
+		},
+		Scorecard: ScorecardInfo{
+			Version:   jsr.Scorecard.Version,
+			CommitSHA: jsr.Scorecard.Commit,
+		},
+		Date:       date,
+		Metadata:   jsr.Metadata,
+		Checks:     make([]checker.CheckResult, 0, len(jsr.Checks)),
+		SetupUsage: jsr.SetupUsage.resourceUsage(),
+	}
+	for _, check := range jsr.Checks {
+		cr := checker.CheckResult{
+			Name:   check.Name,
+			Score:  check.Score,
+			Reason: check.Reason,
+			Usage:  check.Usage.resourceUsage(),
+		}
+		for _, d := range check.Details {
+			cr.Details = append(cr.Details, detailFromString(d))
+		}
+		result.Checks = append(result.Checks, cr)
+	}
+	return result, float64(jsr.AggregateScore), nil
+}
+
+// detailFromString is the inverse of DetailToString.
+// Paths and remediations are kept as part of the text.
+func detailFromString(s string) checker.CheckDetail {
+	d := checker.CheckDetail{Type: checker.DetailInfo, Msg: checker.LogMessage{Text: s}}
+	for _, t := range []checker.DetailType{checker.DetailInfo, checker.DetailWarn, checker.DetailDebug} {
+		prefix := typeToString(t) + ": "
+		if strings.HasPrefix(s, prefix) {
+			d.Type, d.Msg.Text = t, strings.TrimPrefix(s, prefix)
+			break
+		}
+	}
+	return d
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
 )
 
@@ -43,6 +44,7 @@ type jsonFile struct {
 	Path      string  `json:"path"`
 	Offset    uint    `json:"offset,omitempty"`
 	EndOffset uint    `json:"endOffset,omitempty"`
+	SHA256    string  `json:"sha256,omitempty"`
 }
 
 type jsonTool struct {
@@ -60,6 +62,7 @@ type jsonBranchProtectionSettings struct {
 	AllowsForcePushes                   *bool    `json:"allowsForcePushes"`
 	RequiresCodeOwnerReviews            *bool    `json:"requiresCodeOwnerReview"`
 	RequiresLinearHistory               *bool    `json:"requiredLinearHistory"`
+	RequiresSignedCommits               *bool    `json:"requiresSignedCommits,omitempty"`
 	DismissesStaleReviews               *bool    `json:"dismissesStaleReviews"`
 	EnforcesAdmins                      *bool    `json:"enforcesAdmin"`
 	RequiresStatusChecks                *bool    `json:"requiresStatuChecks"`
@@ -72,6 +75,12 @@ type jsonBranchProtection struct {
 	Name       string                        `json:"name"`
 }
 
+type jsonTagProtection struct {
+	AllowsDeletions *bool  `json:"allowsDeletions"`
+	AllowsUpdates   *bool  `json:"allowsUpdates"`
+	Pattern         string `json:"pattern"`
+}
+
 type jsonReview struct {
 	State    string   `json:"state"`
 	Reviewer jsonUser `json:"reviewer"`
@@ -85,13 +94,22 @@ type jsonUser struct {
 	// Companies refer to a claim by a user in their profile.
 	Companies        []jsonCompany `json:"company,omitempty"`
 	NumContributions int           `json:"NumContributions,omitempty"`
+	IsBot            bool          `json:"isBot,omitempty"`
 }
 
 type jsonContributors struct {
 	Users []jsonUser `json:"users"`
+	// Organizations and companies of non-bot users, by number of contributions.
+	Organizations []jsonContributorOrganization `json:"organizations,omitempty"`
 	// TODO: high-level statistics, etc
 }
 
+type jsonContributorOrganization struct {
+	Name             string `json:"name"`
+	NumContributors  int    `json:"numContributors"`
+	NumContributions int    `json:"numContributions"`
+}
+
 type jsonOrganization struct {
 	Login string `json:"login"`
 	// TODO: other info.
@@ -123,8 +141,30 @@ type jsonCommit struct {
 type jsonDatabaseVulnerability struct {
 	// For OSV: OSV-2020-484
 	// For CVE: CVE-2022-23945
-	ID string `json:"id"`
-	// TODO: additional information
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases,omitempty"`
+	// One of CRITICAL, HIGH, MEDIUM or LOW, if known.
+	Severity  string                 `json:"severity,omitempty"`
+	CVSSScore float64                `json:"cvssScore,omitempty"`
+	Package   *jsonVulnerablePackage `json:"package,omitempty"`
+	// Other dependencies of the repo affected by the vulnerability.
+	OtherPackages []jsonVulnerablePackage `json:"otherPackages,omitempty"`
+	// First version of the package fixing the vulnerability.
+	FixedVersion string `json:"fixedVersion,omitempty"`
+}
+
+// jsonNotAffectedVulnerability is a vulnerability a VEX document of the repo marks as not affecting it.
+type jsonNotAffectedVulnerability struct {
+	jsonDatabaseVulnerability
+	Justification   string   `json:"justification,omitempty"`
+	ImpactStatement string   `json:"impactStatement,omitempty"`
+	File            jsonFile `json:"file"`
+}
+
+type jsonVulnerablePackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
 }
 
 type jsonArchivedStatus struct {
@@ -167,8 +207,21 @@ type jsonOssfBestPractices struct {
 }
 
 type jsonLicense struct {
-	File jsonFile `json:"file"`
-	// TODO: add fields, like type of license, etc.
+	File        jsonFile `json:"file"`
+	SpdxID      string   `json:"spdxId,omitempty"`
+	Attribution string   `json:"attribution,omitempty"`
+	Confidence  float64  `json:"confidence,omitempty"`
+}
+
+type jsonLicenseHeader struct {
+	File       jsonFile `json:"file"`
+	Expression string   `json:"expression"`
+	NumFiles   int      `json:"numFiles"`
+}
+
+type jsonManifestLicense struct {
+	File       jsonFile `json:"file"`
+	Expression string   `json:"expression"`
 }
 
 type jsonWorkflow struct {
@@ -242,12 +295,18 @@ type jsonRawResults struct {
 	Permissions jsonPermissionsData `json:"permissions"`
 	// License.
 	Licenses []jsonLicense `json:"licenses"`
+	// SPDX-License-Identifier tags found in source files.
+	LicenseHeaders []jsonLicenseHeader `json:"licenseHeaders,omitempty"`
+	// Licenses declared in package manifests.
+	ManifestLicenses []jsonManifestLicense `json:"manifestLicenses,omitempty"`
 	// List of recent issues.
 	RecentIssues []jsonIssue `json:"issues"`
 	// OSSF best practices badge.
 	OssfBestPractices jsonOssfBestPractices `json:"openssfBestPracticesBadge"`
 	// Vulnerabilities.
 	DatabaseVulnerabilities []jsonDatabaseVulnerability `json:"databaseVulnerabilities"`
+	// Vulnerabilities excluded by the VEX documents of the repo.
+	NotAffectedVulnerabilities []jsonNotAffectedVulnerability `json:"notAffectedVulnerabilities,omitempty"`
 	// List of binaries found in the repo.
 	Binaries []jsonFile `json:"binaries"`
 	// List of security policy files found in the repo.
@@ -258,6 +317,8 @@ type jsonRawResults struct {
 	DependencyUpdateTools []jsonTool `json:"dependencyUpdateTools"`
 	// Branch protection settings for development and release branches.
 	BranchProtections []jsonBranchProtection `json:"branchProtections"`
+	// Tag protection rules.
+	TagProtections []jsonTagProtection `json:"tagProtections,omitempty"`
 	// Contributors. Note: we could use the list of commits instead to store this data.
 	// However, it's harder to get statistics using commit list, so we have a dedicated
 	// structure for it.
@@ -428,6 +489,7 @@ func (r *jsonScorecardRawResult) addContributorsRawResults(cr *checker.Contribut
 		u := jsonUser{
 			Login:            user.Login,
 			NumContributions: user.NumContributions,
+			IsBot:            user.IsBot,
 		}
 
 		for _, org := range user.Organizations {
@@ -449,6 +511,16 @@ func (r *jsonScorecardRawResult) addContributorsRawResults(cr *checker.Contribut
 		r.Results.Contributors.Users = append(r.Results.Contributors.Users, u)
 	}
 
+	for _, org := range cr.Organizations {
+		r.Results.Contributors.Organizations = append(r.Results.Contributors.Organizations,
+			jsonContributorOrganization{
+				Name:             org.Name,
+				NumContributors:  org.NumContributors,
+				NumContributions: org.NumContributions,
+			},
+		)
+	}
+
 	return nil
 }
 
@@ -595,12 +667,37 @@ func (r *jsonScorecardRawResult) addCodeReviewRawResults(cr *checker.CodeReviewD
 //nolint:unparam
 func (r *jsonScorecardRawResult) addLicenseRawResults(ld *checker.LicenseData) error {
 	r.Results.Licenses = []jsonLicense{}
-	for _, file := range ld.Files {
+	for _, file := range ld.LicenseFiles {
 		r.Results.Licenses = append(r.Results.Licenses,
 			jsonLicense{
 				File: jsonFile{
-					Path: file.Path,
+					Path: file.File.Path,
 				},
+				SpdxID:      file.LicenseInformation.SpdxID,
+				Attribution: string(file.LicenseInformation.Attribution),
+				Confidence:  file.LicenseInformation.Confidence,
+			},
+		)
+	}
+	for _, h := range ld.SourceHeaders {
+		r.Results.LicenseHeaders = append(r.Results.LicenseHeaders,
+			jsonLicenseHeader{
+				File: jsonFile{
+					Path:   h.File.Path,
+					Offset: h.File.Offset,
+				},
+				Expression: h.Expression,
+				NumFiles:   h.NumFiles,
+			},
+		)
+	}
+	for _, m := range ld.ManifestLicenses {
+		r.Results.ManifestLicenses = append(r.Results.ManifestLicenses,
+			jsonManifestLicense{
+				File: jsonFile{
+					Path: m.File.Path,
+				},
+				Expression: m.Expression,
 			},
 		)
 	}
@@ -610,21 +707,55 @@ func (r *jsonScorecardRawResult) addLicenseRawResults(ld *checker.LicenseData) e
 //nolint:unparam
 func (r *jsonScorecardRawResult) addVulnerbilitiesRawResults(vd *checker.VulnerabilitiesData) error {
 	r.Results.DatabaseVulnerabilities = []jsonDatabaseVulnerability{}
-	for _, v := range vd.Vulnerabilities {
+	for i := range vd.Vulnerabilities {
 		r.Results.DatabaseVulnerabilities = append(r.Results.DatabaseVulnerabilities,
-			jsonDatabaseVulnerability{
-				ID: v.ID,
+			asJSONDatabaseVulnerability(&vd.Vulnerabilities[i]))
+	}
+	for i := range vd.NotAffected {
+		na := &vd.NotAffected[i]
+		r.Results.NotAffectedVulnerabilities = append(r.Results.NotAffectedVulnerabilities,
+			jsonNotAffectedVulnerability{
+				jsonDatabaseVulnerability: asJSONDatabaseVulnerability(&na.Vulnerability),
+				Justification:             na.Justification,
+				ImpactStatement:           na.ImpactStatement,
+				File:                      jsonFile{Path: na.File.Path},
 			})
 	}
 	return nil
 }
 
+func asJSONDatabaseVulnerability(v *clients.Vulnerability) jsonDatabaseVulnerability {
+	jv := jsonDatabaseVulnerability{
+		ID:           v.ID,
+		Aliases:      v.Aliases,
+		Severity:     v.Severity,
+		CVSSScore:    v.CVSSScore,
+		FixedVersion: v.FixedVersion,
+	}
+	if v.Package.Name != "" {
+		jv.Package = &jsonVulnerablePackage{
+			Ecosystem: v.Package.Ecosystem,
+			Name:      v.Package.Name,
+			Version:   v.Package.Version,
+		}
+	}
+	for _, p := range v.OtherPackages {
+		jv.OtherPackages = append(jv.OtherPackages, jsonVulnerablePackage{
+			Ecosystem: p.Ecosystem,
+			Name:      p.Name,
+			Version:   p.Version,
+		})
+	}
+	return jv
+}
+
 //nolint:unparam
 func (r *jsonScorecardRawResult) addBinaryArtifactRawResults(ba *checker.BinaryArtifactData) error {
 	r.Results.Binaries = []jsonFile{}
 	for _, v := range ba.Files {
 		r.Results.Binaries = append(r.Results.Binaries, jsonFile{
-			Path: v.Path,
+			Path:   v.Path,
+			SHA256: v.SHA256,
 		})
 	}
 	return nil
@@ -708,6 +839,7 @@ func (r *jsonScorecardRawResult) addBranchProtectionRawResults(bp *checker.Branc
 				AllowsForcePushes:                   v.BranchProtectionRule.AllowForcePushes,
 				RequiresCodeOwnerReviews:            v.BranchProtectionRule.RequiredPullRequestReviews.RequireCodeOwnerReviews,
 				RequiresLinearHistory:               v.BranchProtectionRule.RequireLinearHistory,
+				RequiresSignedCommits:               v.BranchProtectionRule.RequireSignedCommits,
 				DismissesStaleReviews:               v.BranchProtectionRule.RequiredPullRequestReviews.DismissStaleReviews,
 				EnforcesAdmins:                      v.BranchProtectionRule.EnforceAdmins,
 				RequiresStatusChecks:                v.BranchProtectionRule.CheckRules.RequiresStatusChecks,
@@ -721,6 +853,13 @@ func (r *jsonScorecardRawResult) addBranchProtectionRawResults(bp *checker.Branc
 			Protection: bp,
 		})
 	}
+	for _, v := range bp.TagProtections {
+		r.Results.TagProtections = append(r.Results.TagProtections, jsonTagProtection{
+			Pattern:         v.Pattern,
+			AllowsDeletions: v.AllowDeletions,
+			AllowsUpdates:   v.AllowUpdates,
+		})
+	}
 	return nil
 }
 
@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/xeipuuv/gojsonschema"
 
 	"github.com/ossf/scorecard/v4/checker"
@@ -411,6 +412,48 @@ func TestJSONOutput(t *testing.T) {
 				Metadata: []string{},
 			},
 		},
+		{
+			name:        "check-7",
+			showDetails: true,
+			expected:    "./testdata/check7.json",
+			logLevel:    log.WarnLevel,
+			result: ScorecardResult{
+				Repo: RepoInfo{
+					Name:      repoName,
+					CommitSHA: repoCommit,
+				},
+				Scorecard: ScorecardInfo{
+					Version:   scorecardVersion,
+					CommitSHA: scorecardCommit,
+				},
+				Date: date,
+				Checks: []checker.CheckResult{
+					{
+						Details: []checker.CheckDetail{
+							{
+								Type: checker.DetailWarn,
+								Msg:  checker.LogMessage{Text: "warn message"},
+							},
+						},
+						Score:  6,
+						Reason: "six score reason",
+						Name:   "Check-Name",
+						Usage: &checker.ResourceUsage{
+							Requests:        map[string]int64{"graphql": 1, "rest": 12},
+							WallTime:        1500 * time.Millisecond,
+							BytesDownloaded: 2048,
+							CacheHits:       3,
+						},
+					},
+				},
+				Metadata: []string{},
+				SetupUsage: &checker.ResourceUsage{
+					Requests:        map[string]int64{"graphql": 2, "tarball": 1},
+					WallTime:        500 * time.Millisecond,
+					BytesDownloaded: 4096,
+				},
+			},
+		},
 	}
 
 	// Load the JSON schema.
@@ -488,3 +531,49 @@ func TestJSONOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestExperimentalFromJSON2(t *testing.T) {
+	t.Parallel()
+	checkDocs := jsonMockDocRead()
+	tests := []string{
+		"./testdata/check1.json",
+		"./testdata/check3.json",
+		"./testdata/check5.json",
+		"./testdata/check6.json",
+		"./testdata/check7.json",
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt, func(t *testing.T) {
+			t.Parallel()
+			content, err := os.ReadFile(tt)
+			if err != nil {
+				t.Fatalf("cannot read file: %v", err)
+			}
+			result, score, err := ExperimentalFromJSON2(bytes.NewReader(content))
+			if err != nil {
+				t.Fatalf("ExperimentalFromJSON2: %v", err)
+			}
+
+			// Exporting the parsed results again must give back the input.
+			var js JSONScorecardResultV2
+			if err := json.Unmarshal(content, &js); err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+			if score != float64(js.AggregateScore) {
+				t.Errorf("score = %v, want %v", score, js.AggregateScore)
+			}
+			var expected bytes.Buffer
+			if err := json.NewEncoder(&expected).Encode(js); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			var got bytes.Buffer
+			if err := result.AsJSON2(true, log.DebugLevel, checkDocs, &got); err != nil {
+				t.Fatalf("AsJSON2: %v", err)
+			}
+			if diff := cmp.Diff(expected.String(), got.String()); diff != "" {
+				t.Errorf("AsJSON2() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ossf/scorecard/v4/checker"
+	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/log"
+	spol "github.com/ossf/scorecard/v4/policy"
+)
+
+// JUnit XML report, as read by Jenkins and GitLab,
+// see https://github.com/testmoapp/junitxml.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitTestCase `xml:"testcase"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// checkThreshold returns the minimum score of the check required by the policy, if any.
+// Checks missing from the policy have no threshold.
+func checkThreshold(policy *spol.ScorecardPolicy, name string) (minScore int, enabled, exists bool) {
+	cp, exists := policy.GetPolicies()[name]
+	if !exists {
+		return 0, true, false
+	}
+	if cp.GetMode() == spol.CheckPolicy_DISABLED {
+		return 0, false, true
+	}
+	return int(cp.GetScore()), true, true
+}
+
+// AsJUnit outputs ScorecardResult as a JUnit XML report with a test case per check.
+// A check fails when its score is below the threshold of the policy, errors when it
+// couldn't run and is skipped when it's inconclusive or disabled by the policy.
+// The details of the checks are reported as their output.
+func (r *ScorecardResult) AsJUnit(logLevel log.Level, writer io.Writer, policy *spol.ScorecardPolicy) error {
+	suite := junitTestSuite{
+		Name:      r.Repo.Name,
+		Timestamp: r.Date.UTC().Format(time.RFC3339),
+		Properties: []junitProperty{
+			{Name: "commit", Value: r.Repo.CommitSHA},
+			{Name: "scorecard.version", Value: r.Scorecard.Version},
+		},
+	}
+	for i := range r.Checks {
+		check := &r.Checks[i]
+		tc := junitTestCase{
+			Name:      check.Name,
+			ClassName: r.Repo.Name,
+		}
+		if details, ok := detailsToString(check.Details, logLevel); ok {
+			tc.SystemOut = details
+		}
+
+		minScore, enabled, exists := checkThreshold(policy, check.Name)
+		switch {
+		case check.Error != nil:
+			tc.Error = &junitMessage{
+				Message: check.Error.Error(),
+				Type:    "runtime",
+			}
+			suite.Errors++
+		case !enabled:
+			tc.Skipped = &junitMessage{Message: "disabled by the policy"}
+			suite.Skipped++
+		case check.Score == checker.InconclusiveResultScore:
+			tc.Skipped = &junitMessage{Message: check.Reason}
+			suite.Skipped++
+		case exists && check.Score < minScore:
+			tc.Failure = &junitMessage{
+				Message: fmt.Sprintf("score %d is below the policy threshold %d: %s", check.Score, minScore, check.Reason),
+				Type:    "policy",
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	report := junitTestSuites{
+		Name:     "scorecard",
+		Suites:   []junitTestSuite{suite},
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+	}
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("io.WriteString: %v", err))
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("encoder.Encode: %v", err))
+	}
+	if _, err := io.WriteString(writer, "\n"); err != nil {
+		return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("io.WriteString: %v", err))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/log"
+	spol "github.com/ossf/scorecard/v4/policy"
+)
+
+func formatTestResult() *ScorecardResult {
+	return &ScorecardResult{
+		Repo: RepoInfo{
+			Name:      "github.com/foo/bar",
+			CommitSHA: "68bc59a1ff4d0a4b9cfc0ee3bb1b3fae4d15b4b9",
+		},
+		Scorecard: ScorecardInfo{Version: "v4.8.0"},
+		Date:      time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC),
+		Checks: []checker.CheckResult{
+			{
+				Name:   "Binary-Artifacts",
+				Score:  5,
+				Reason: "binaries present in source code",
+				Details: []checker.CheckDetail{
+					{
+						Type: checker.DetailWarn,
+						Msg: checker.LogMessage{
+							Text: "binary detected",
+							Path: "bin/tool.exe",
+							Type: checker.FileTypeBinary,
+						},
+					},
+				},
+			},
+			{
+				Name:   "Code-Review",
+				Score:  checker.InconclusiveResultScore,
+				Reason: "no reviews found",
+			},
+			{
+				Name:   "License",
+				Score:  9,
+				Reason: "license file detected",
+				Details: []checker.CheckDetail{
+					{
+						Type: checker.DetailInfo,
+						Msg:  checker.LogMessage{Text: "license file found", Path: "LICENSE", Type: checker.FileTypeSource},
+					},
+				},
+			},
+			{
+				Name:   "Pinned-Dependencies",
+				Score:  7,
+				Reason: "dependency not pinned by hash detected",
+				Details: []checker.CheckDetail{
+					{
+						Type: checker.DetailWarn,
+						Msg: checker.LogMessage{
+							Text:      "containerImage not pinned by hash",
+							Path:      "Dockerfile",
+							Type:      checker.FileTypeSource,
+							Offset:    3,
+							EndOffset: 4,
+							Snippet:   "FROM python:3.7",
+						},
+					},
+					{
+						Type: checker.DetailWarn,
+						Msg:  checker.LogMessage{Text: "see the docs", Path: "https://example.com", Type: checker.FileTypeURL},
+					},
+				},
+			},
+			{
+				Name:  "Signed-Releases",
+				Score: checker.InconclusiveResultScore,
+				Error: errors.New("internal error: API rate limit"),
+			},
+		},
+	}
+}
+
+func formatTestPolicy() *spol.ScorecardPolicy {
+	return &spol.ScorecardPolicy{
+		Version: 1,
+		Policies: map[string]*spol.CheckPolicy{
+			"Binary-Artifacts":    {Score: 8, Mode: spol.CheckPolicy_ENFORCED},
+			"License":             {Score: 5, Mode: spol.CheckPolicy_ENFORCED},
+			"Pinned-Dependencies": {Score: 5, Mode: spol.CheckPolicy_DISABLED},
+		},
+	}
+}
+
+func TestAsJUnit(t *testing.T) {
+	t.Parallel()
+	var sb strings.Builder
+	if err := formatTestResult().AsJUnit(log.DefaultLevel, &sb, formatTestPolicy()); err != nil {
+		t.Fatalf("AsJUnit: %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="scorecard" tests="5" failures="1" errors="1" skipped="2">
+  <testsuite name="github.com/foo/bar" timestamp="2022-10-01T12:00:00Z" tests="5" failures="1" errors="1" skipped="2">
+    <properties>
+      <property name="commit" value="68bc59a1ff4d0a4b9cfc0ee3bb1b3fae4d15b4b9"></property>
+      <property name="scorecard.version" value="v4.8.0"></property>
+    </properties>
+    <testcase name="Binary-Artifacts" classname="github.com/foo/bar">
+      <failure message="score 5 is below the policy threshold 8: binaries present in source code" type="policy"></failure>
+      <system-out>Warn: binary detected: bin/tool.exe</system-out>
+    </testcase>
+    <testcase name="Code-Review" classname="github.com/foo/bar">
+      <skipped message="no reviews found"></skipped>
+    </testcase>
+    <testcase name="License" classname="github.com/foo/bar">
+      <system-out>Info: license file found: LICENSE</system-out>
+    </testcase>
+    <testcase name="Pinned-Dependencies" classname="github.com/foo/bar">
+      <skipped message="disabled by the policy"></skipped>
+      <system-out>Warn: containerImage not pinned by hash: Dockerfile:3-4&#xA;Warn: see the docs: https://example.com</system-out>
+    </testcase>
+    <testcase name="Signed-Releases" classname="github.com/foo/bar">
+      <error message="internal error: API rate limit" type="runtime"></error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("AsJUnit() mismatch (-want +got):\n%s", diff)
+	}
+}
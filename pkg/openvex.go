@@ -0,0 +1,156 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ossf/scorecard/v4/checks"
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/clients/lockfile"
+	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/openvex"
+)
+
+const openVEXAuthor = "OpenSSF Scorecard"
+
+// AsOpenVEX writes the results of the Vulnerabilities check as an OpenVEX document
+// about the scanned commit of the repo: HEAD is affected by the vulnerabilities
+// known for the commit, and the vulnerabilities of its dependencies are under
+// investigation. The vulnerabilities the VEX documents of the repo mark as
+// not affecting it are kept with their justification.
+func (r *ScorecardResult) AsOpenVEX(writer io.Writer) error {
+	if !r.hasCheck(checks.CheckVulnerabilities) {
+		return sce.WithMessage(sce.ErrScorecardInternal,
+			fmt.Sprintf("the openvex format requires the %s check", checks.CheckVulnerabilities))
+	}
+
+	product := repoPackageURL(r.Repo)
+	vd := &r.RawResults.VulnerabilitiesResults
+	statements := []openvex.Statement{}
+	for i := range vd.Vulnerabilities {
+		vuln := &vd.Vulnerabilities[i]
+		s := openvex.Statement{
+			Vulnerability: openVEXVulnerability(vuln),
+			Products:      []openvex.Product{openVEXProduct(product, vuln)},
+		}
+		if vuln.Package.Version == "" {
+			s.Status = openvex.StatusAffected
+			s.ActionStatement = actionStatement(vuln)
+		} else {
+			s.Status = openvex.StatusUnderInvestigation
+			s.StatusNotes = fmt.Sprintf("the repo depends on %s, which is vulnerable", packageVersions(vuln))
+			if len(vuln.OtherPackages) > 0 {
+				s.StatusNotes = fmt.Sprintf("the repo depends on %s, which are vulnerable", packageVersions(vuln))
+			}
+		}
+		statements = append(statements, s)
+	}
+	for i := range vd.NotAffected {
+		na := &vd.NotAffected[i]
+		statements = append(statements, openvex.Statement{
+			Vulnerability:   openVEXVulnerability(&na.Vulnerability),
+			Products:        []openvex.Product{openVEXProduct(product, &na.Vulnerability)},
+			Status:          openvex.StatusNotAffected,
+			Justification:   na.Justification,
+			ImpactStatement: na.ImpactStatement,
+			StatusNotes:     fmt.Sprintf("as stated by %s", na.File.Path),
+		})
+	}
+
+	doc := openvex.Document{
+		Context:    openvex.Context,
+		ID:         openVEXDocumentID(product, r.Date),
+		Author:     openVEXAuthor,
+		Timestamp:  r.Date.UTC().Format(time.RFC3339),
+		Tooling:    fmt.Sprintf("scorecard %s", r.Scorecard.Version),
+		Version:    1,
+		Statements: statements,
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return sce.WithMessage(sce.ErrScorecardInternal, fmt.Sprintf("encoder.Encode: %v", err))
+	}
+	return nil
+}
+
+func (r *ScorecardResult) hasCheck(name string) bool {
+	for i := range r.Checks {
+		if r.Checks[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func openVEXVulnerability(vuln *clients.Vulnerability) openvex.Vulnerability {
+	return openvex.Vulnerability{
+		Name:    vuln.ID,
+		Aliases: vuln.Aliases,
+	}
+}
+
+// openVEXProduct returns the repo as the product of the vuln,
+// with the vulnerable dependencies as its subcomponents.
+func openVEXProduct(product string, vuln *clients.Vulnerability) openvex.Product {
+	p := openvex.Product{ID: product}
+	if vuln.Package.Version == "" {
+		return p
+	}
+	for _, pkg := range vuln.Packages() {
+		if purl := lockfile.PackageURL(pkg); purl != "" {
+			p.Subcomponents = append(p.Subcomponents, openvex.Subcomponent{ID: purl})
+		}
+	}
+	return p
+}
+
+// packageVersions lists the dependencies affected by the vuln, e.g. "foo 1.0, bar 2.0".
+func packageVersions(vuln *clients.Vulnerability) string {
+	var versions []string
+	for _, p := range vuln.Packages() {
+		versions = append(versions, fmt.Sprintf("%s %s", p.Name, p.Version))
+	}
+	return strings.Join(versions, ", ")
+}
+
+func actionStatement(vuln *clients.Vulnerability) string {
+	if vuln.FixedVersion != "" {
+		return fmt.Sprintf("Update to %s or later.", vuln.FixedVersion)
+	}
+	return fmt.Sprintf("Apply the remediation of %s, or document why the repo isn't affected in a VEX file.", vuln.ID)
+}
+
+// repoPackageURL returns the package URL of the scanned commit of the repo.
+func repoPackageURL(repo RepoInfo) string {
+	version := repo.CommitSHA
+	if version == clients.HeadSHA {
+		version = ""
+	}
+	return openvex.RepoPackageURL(repo.Name, version)
+}
+
+// openVEXDocumentID returns an ID unique to the product and the time of the scan.
+func openVEXDocumentID(product string, date time.Time) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s %s", product, date.UTC().Format(time.RFC3339))))
+	return fmt.Sprintf("https://openssf.org/scorecard/openvex/%x", h[:16])
+}
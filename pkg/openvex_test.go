@@ -0,0 +1,136 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v4/checker"
+	"github.com/ossf/scorecard/v4/checks"
+	"github.com/ossf/scorecard/v4/clients"
+	"github.com/ossf/scorecard/v4/openvex"
+)
+
+func TestAsOpenVEX(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	django := clients.Package{Ecosystem: clients.EcosystemPyPI, Name: "django", Version: "3.2.1"}
+	result := ScorecardResult{
+		Repo: RepoInfo{
+			Name:      "github.com/Owner/Repo",
+			CommitSHA: "68bc59a1ff4d0a4b9cfc0ee3bb1b3fae4d15b4b9",
+		},
+		Scorecard: ScorecardInfo{Version: "v4.8.0"},
+		Date:      date,
+		Checks:    []checker.CheckResult{{Name: checks.CheckVulnerabilities, Score: 7}},
+		RawResults: checker.RawResults{
+			VulnerabilitiesResults: checker.VulnerabilitiesData{
+				Vulnerabilities: []clients.Vulnerability{
+					{ID: "OSV-2022-1", Aliases: []string{"CVE-2022-1"}},
+					{ID: "GHSA-2", Package: django, FixedVersion: "3.2.4"},
+				},
+				NotAffected: []checker.NotAffectedVulnerability{
+					{
+						Vulnerability: clients.Vulnerability{ID: "GHSA-3", Package: django},
+						Justification: "vulnerable_code_not_in_execute_path",
+						File:          checker.File{Path: ".vex"},
+					},
+				},
+			},
+		},
+	}
+	product := "pkg:github/owner/repo@68bc59a1ff4d0a4b9cfc0ee3bb1b3fae4d15b4b9"
+	productWithDjango := openvex.Product{
+		ID:            product,
+		Subcomponents: []openvex.Subcomponent{{ID: "pkg:pypi/django@3.2.1"}},
+	}
+	want := openvex.Document{
+		Context:   openvex.Context,
+		ID:        openVEXDocumentID(product, date),
+		Author:    "OpenSSF Scorecard",
+		Timestamp: "2022-10-01T12:00:00Z",
+		Tooling:   "scorecard v4.8.0",
+		Version:   1,
+		Statements: []openvex.Statement{
+			{
+				Vulnerability:   openvex.Vulnerability{Name: "OSV-2022-1", Aliases: []string{"CVE-2022-1"}},
+				Products:        []openvex.Product{{ID: product}},
+				Status:          openvex.StatusAffected,
+				ActionStatement: "Apply the remediation of OSV-2022-1, or document why the repo isn't affected in a VEX file.",
+			},
+			{
+				Vulnerability: openvex.Vulnerability{Name: "GHSA-2"},
+				Products:      []openvex.Product{productWithDjango},
+				Status:        openvex.StatusUnderInvestigation,
+				StatusNotes:   "the repo depends on django 3.2.1, which is vulnerable",
+			},
+			{
+				Vulnerability: openvex.Vulnerability{Name: "GHSA-3"},
+				Products:      []openvex.Product{productWithDjango},
+				Status:        openvex.StatusNotAffected,
+				Justification: "vulnerable_code_not_in_execute_path",
+				StatusNotes:   "as stated by .vex",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := result.AsOpenVEX(&buf); err != nil {
+		t.Fatalf("AsOpenVEX: %v", err)
+	}
+	var got openvex.Document
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AsOpenVEX() mismatch (-want +got):\n%s", diff)
+	}
+
+	result.Checks = nil
+	if err := result.AsOpenVEX(&buf); err == nil {
+		t.Errorf("AsOpenVEX() without the %s check: want error", checks.CheckVulnerabilities)
+	}
+}
+
+func TestRepoPackageURL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		repo RepoInfo
+		want string
+	}{
+		{
+			repo: RepoInfo{Name: "github.com/ossf/scorecard", CommitSHA: "abc"},
+			want: "pkg:github/ossf/scorecard@abc",
+		},
+		{
+			repo: RepoInfo{Name: "https://gitlab.com/group/project", CommitSHA: clients.HeadSHA},
+			want: "pkg:gitlab/group/project",
+		},
+		{
+			repo: RepoInfo{Name: "git.example.com/team/tool", CommitSHA: "abc"},
+			want: "pkg:generic/tool@abc?vcs_url=git%2Bhttps%3A%2F%2Fgit.example.com%2Fteam%2Ftool",
+		},
+	}
+	for _, tt := range tests {
+		if got := repoPackageURL(tt.repo); got != tt.want {
+			t.Errorf("repoPackageURL(%v) = %s, want %s", tt.repo, got, tt.want)
+		}
+	}
+}
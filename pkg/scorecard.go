@@ -22,17 +22,19 @@ import (
 	"sync"
 	"time"
 
+	opencensusstats "go.opencensus.io/stats"
 	"sigs.k8s.io/release-utils/version"
 
 	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/clients"
 	sce "github.com/ossf/scorecard/v4/errors"
+	"github.com/ossf/scorecard/v4/stats"
 )
 
 func runEnabledChecks(ctx context.Context,
 	repo clients.Repo, raw *checker.RawResults, checksToRun checker.CheckNameToFnMap,
 	repoClient clients.RepoClient, ossFuzzRepoClient clients.RepoClient, ciiClient clients.CIIBestPracticesClient,
-	vulnsClient clients.VulnerabilitiesClient,
+	vulnsClient clients.VulnerabilitiesClient, opts RunOptions,
 	resultsCh chan checker.CheckResult,
 ) {
 	request := checker.CheckRequest{
@@ -43,6 +45,8 @@ func runEnabledChecks(ctx context.Context,
 		VulnerabilitiesClient: vulnsClient,
 		Repo:                  repo,
 		RawResults:            raw,
+		PolicySettings:        opts.Settings,
+		RecordUsage:           opts.RecordUsage,
 	}
 	wg := sync.WaitGroup{}
 	for checkName, checkFn := range checksToRun {
@@ -90,6 +94,66 @@ func RunScorecards(ctx context.Context,
 	ciiClient clients.CIIBestPracticesClient,
 	vulnsClient clients.VulnerabilitiesClient,
 ) (ScorecardResult, error) {
+	return RunScorecardsWithOptions(ctx, repo, commitSHA, checksToRun,
+		repoClient, ossFuzzRepoClient, ciiClient, vulnsClient, RunOptions{})
+}
+
+// CheckSelector returns the checks to run on a commit of the repo, see RunOptions.SelectChecks.
+type CheckSelector func(commitSHA string) checker.CheckNameToFnMap
+
+// RunOptions are the options of a run of the Scorecard checks.
+type RunOptions struct {
+	// Settings are the check-specific settings, e.g. of the policy. See policy.GetSettings.
+	Settings *checker.PolicySettings
+	// SelectChecks, if set, selects the checks to run in place of checksToRun once the commit
+	// of the Repo is resolved, e.g. to skip those whose results at the HEAD commit are already known.
+	SelectChecks CheckSelector
+	// RecordUsage records the usage of resources by each check in its result,
+	// and by the setup shared by the checks in ScorecardResult.SetupUsage.
+	RecordUsage bool
+}
+
+// RunScorecardsWithOptions runs enabled Scorecard checks on a Repo with opts.
+func RunScorecardsWithOptions(ctx context.Context,
+	repo clients.Repo,
+	commitSHA string,
+	checksToRun checker.CheckNameToFnMap,
+	repoClient clients.RepoClient,
+	ossFuzzRepoClient clients.RepoClient,
+	ciiClient clients.CIIBestPracticesClient,
+	vulnsClient clients.VulnerabilitiesClient,
+	opts RunOptions,
+) (ScorecardResult, error) {
+	selectChecks := opts.SelectChecks
+	if selectChecks == nil {
+		selectChecks = func(string) checker.CheckNameToFnMap { return checksToRun }
+	}
+	return runScorecards(ctx, repo, commitSHA, selectChecks,
+		repoClient, ossFuzzRepoClient, ciiClient, vulnsClient, opts)
+}
+
+func runScorecards(ctx context.Context,
+	repo clients.Repo,
+	commitSHA string,
+	selectChecks CheckSelector,
+	repoClient clients.RepoClient,
+	ossFuzzRepoClient clients.RepoClient,
+	ciiClient clients.CIIBestPracticesClient,
+	vulnsClient clients.VulnerabilitiesClient,
+	opts RunOptions,
+) (ScorecardResult, error) {
+	// Count the requests made to scan the repo, and apart those of the setup shared by the checks.
+	usage := new(stats.Usage)
+	ctx = stats.WithUsage(ctx, usage)
+	setupUsage := new(stats.Usage)
+	ctx = stats.WithSetupUsage(ctx, setupUsage)
+	startTime := time.Now()
+
+	// Make the requests of the setup with ctx, so that its deadline applies to InitRepo too.
+	// The view is initialized before deriving the one of the checks, as it may not share its state.
+	if contextualClient, ok := repoClient.(clients.ContextualRepoClient); ok {
+		repoClient = contextualClient.WithContext(stats.ForSetup(ctx))
+	}
 	if err := repoClient.InitRepo(repo, commitSHA); err != nil {
 		// No need to call sce.WithMessage() since InitRepo will do that for us.
 		//nolint:wrapcheck
@@ -101,6 +165,10 @@ func RunScorecards(ctx context.Context,
 	if err != nil || commitSHA == "" {
 		return ScorecardResult{}, err
 	}
+	checksClient := repoClient
+	if contextualClient, ok := repoClient.(clients.ContextualRepoClient); ok {
+		checksClient = contextualClient.WithContext(ctx)
+	}
 	versionInfo := version.GetVersionInfo()
 	ret := ScorecardResult{
 		Repo: RepoInfo{
@@ -113,12 +181,18 @@ func RunScorecards(ctx context.Context,
 		},
 		Date: time.Now(),
 	}
+	setupTime := time.Since(startTime)
 	resultsCh := make(chan checker.CheckResult)
-	go runEnabledChecks(ctx, repo, &ret.RawResults, checksToRun, repoClient, ossFuzzRepoClient,
-		ciiClient, vulnsClient, resultsCh)
+	go runEnabledChecks(ctx, repo, &ret.RawResults, selectChecks(commitSHA), checksClient, ossFuzzRepoClient,
+		ciiClient, vulnsClient, opts, resultsCh)
 
 	for result := range resultsCh {
 		ret.Checks = append(ret.Checks, result)
 	}
+	if opts.RecordUsage {
+		// The wall time of the setup is the one before the checks run, the shared setups they make excluded.
+		ret.SetupUsage = checker.NewResourceUsage(setupUsage, setupTime)
+	}
+	opencensusstats.Record(ctx, stats.RepoHTTPRequests.M(usage.Requests()))
 	return ret, nil
 }
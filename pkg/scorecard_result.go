@@ -51,6 +51,9 @@ type ScorecardResult struct {
 	Checks     []checker.CheckResult
 	RawResults checker.RawResults
 	Metadata   []string
+	// SetupUsage is the usage of resources by the setup shared by the checks, if recorded,
+	// see RunOptions: InitRepo, the resolution of the commit and e.g. the download of the tarball.
+	SetupUsage *checker.ResourceUsage
 }
 
 func scoreToString(s float64) string {
@@ -119,6 +122,12 @@ func FormatResults(
 		err = results.AsJSON2(opts.ShowDetails, log.ParseLevel(opts.LogLevel), doc, os.Stdout)
 	case options.FormatRaw:
 		err = results.AsRawJSON(os.Stdout)
+	case options.FormatOpenVEX:
+		err = results.AsOpenVEX(os.Stdout)
+	case options.FormatJUnit:
+		err = results.AsJUnit(log.ParseLevel(opts.LogLevel), os.Stdout, policy)
+	case options.FormatGitLabCodeQuality:
+		err = results.AsGitLabCodeQuality(os.Stdout, doc, policy)
 	default:
 		err = sce.WithMessage(
 			sce.ErrScorecardInternal,
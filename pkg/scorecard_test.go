@@ -15,15 +15,19 @@ package pkg
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 
+	"github.com/ossf/scorecard/v4/checker"
 	"github.com/ossf/scorecard/v4/clients"
 	"github.com/ossf/scorecard/v4/clients/localdir"
 	mockrepo "github.com/ossf/scorecard/v4/clients/mockclients"
 	"github.com/ossf/scorecard/v4/log"
+	"github.com/ossf/scorecard/v4/stats"
 )
 
 func Test_getRepoCommitHash(t *testing.T) {
@@ -175,3 +179,124 @@ func TestRunScorecards(t *testing.T) {
 		})
 	}
 }
+
+// usageRepoClient counts a request made with the context of its view for each call,
+// the download of the tarball being shared by the checks. Like githubrepo.Client, its
+// views are shallow copies, which don't share the repo set by InitRepo.
+type usageRepoClient struct {
+	clients.RepoClient
+	ctx  context.Context
+	repo clients.Repo
+}
+
+var errNotInitialized = errors.New("InitRepo wasn't called")
+
+func (c *usageRepoClient) WithContext(ctx context.Context) clients.RepoClient {
+	view := *c
+	view.ctx = ctx
+	return &view
+}
+
+func (c *usageRepoClient) InitRepo(repo clients.Repo, commitSHA string) error {
+	stats.RecordRequest(c.ctx, "rest", false)
+	c.repo = repo
+	return nil
+}
+
+func (c *usageRepoClient) ListCommits() ([]clients.Commit, error) {
+	if c.repo == nil {
+		return nil, errNotInitialized
+	}
+	stats.RecordRequest(c.ctx, "graphql", false)
+	return []clients.Commit{{SHA: "sha"}}, nil
+}
+
+func (c *usageRepoClient) ListFiles(predicate func(string) (bool, error)) ([]string, error) {
+	if c.repo == nil {
+		return nil, errNotInitialized
+	}
+	stats.RecordRequest(stats.ForSetup(c.ctx), "tarball", false)
+	stats.RecordRequest(c.ctx, "rest", false)
+	return nil, nil
+}
+
+func (c *usageRepoClient) Close() error {
+	return nil
+}
+
+func TestRunScorecardsWithOptionsUsage(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRepo(ctrl)
+	repo.EXPECT().URI().Return("github.com/owner/repo").AnyTimes()
+	checks := checker.CheckNameToFnMap{
+		"Fake-Check": checker.Check{
+			Fn: func(r *checker.CheckRequest) checker.CheckResult {
+				if _, err := r.RepoClient.ListFiles(nil); err != nil {
+					return checker.CreateRuntimeErrorResult("Fake-Check", err)
+				}
+				return checker.CreateMaxScoreResult("Fake-Check", "reason")
+			},
+		},
+	}
+
+	for _, recordUsage := range []bool{false, true} {
+		got, err := RunScorecardsWithOptions(context.Background(), repo, clients.HeadSHA, checks,
+			&usageRepoClient{ctx: context.Background()}, nil, nil, nil, RunOptions{RecordUsage: recordUsage})
+		if err != nil {
+			t.Fatalf("RunScorecardsWithOptions: %v", err)
+		}
+		if len(got.Checks) != 1 {
+			t.Fatalf("RunScorecardsWithOptions got %d checks, want 1", len(got.Checks))
+		}
+		// The check runs on a view derived from the initialized client.
+		if err := got.Checks[0].Error; err != nil {
+			t.Fatalf("check error: %v", err)
+		}
+		if !recordUsage {
+			if got.SetupUsage != nil || got.Checks[0].Usage != nil {
+				t.Errorf("RunScorecardsWithOptions recorded the usage without RecordUsage")
+			}
+			continue
+		}
+		// InitRepo, the resolution of the commit and the shared tarball are charged to the setup.
+		if diff := cmp.Diff(map[string]int64{"rest": 1, "graphql": 1, "tarball": 1},
+			got.SetupUsage.Requests); diff != "" {
+			t.Errorf("setup requests (-want +got): %s", diff)
+		}
+		if diff := cmp.Diff(map[string]int64{"rest": 1}, got.Checks[0].Usage.Requests); diff != "" {
+			t.Errorf("check requests (-want +got): %s", diff)
+		}
+	}
+}
+
+func TestRunScorecardsWithOptionsSelectChecks(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockRepo(ctrl)
+	repo.EXPECT().URI().Return("github.com/owner/repo").AnyTimes()
+	check := checker.Check{
+		Fn: func(r *checker.CheckRequest) checker.CheckResult {
+			return checker.CreateMaxScoreResult("Selected-Check", "reason")
+		},
+	}
+
+	var selectedAt string
+	got, err := RunScorecardsWithOptions(context.Background(), repo, clients.HeadSHA,
+		checker.CheckNameToFnMap{"Other-Check": check}, &usageRepoClient{ctx: context.Background()}, nil, nil, nil,
+		RunOptions{
+			SelectChecks: func(commitSHA string) checker.CheckNameToFnMap {
+				selectedAt = commitSHA
+				return checker.CheckNameToFnMap{"Selected-Check": check}
+			},
+		})
+	if err != nil {
+		t.Fatalf("RunScorecardsWithOptions: %v", err)
+	}
+	if selectedAt != "sha" {
+		t.Errorf("checks selected at %q, want the resolved commit", selectedAt)
+	}
+	if len(got.Checks) != 1 || got.Checks[0].Name != "Selected-Check" {
+		t.Errorf("RunScorecardsWithOptions ran %v, want the selected check", got.Checks)
+	}
+}
@@ -34,6 +34,7 @@ var (
 	errInvalidScore   = errors.New("invalid score")
 	errInvalidMode    = errors.New("invalid mode")
 	errRepeatingCheck = errors.New("check has multiple definitions")
+	errInvalidOption  = errors.New("option not supported by check")
 )
 
 var allowedVersions = map[int]bool{1: true}
@@ -41,8 +42,11 @@ var allowedVersions = map[int]bool{1: true}
 var modes = map[string]bool{"enforced": true, "disabled": true}
 
 type checkPolicy struct {
-	Mode  string `yaml:"mode"`
-	Score int    `yaml:"score"`
+	Mode            string   `yaml:"mode"`
+	Score           int      `yaml:"score"`
+	AllowedLicenses []string `yaml:"allowed-licenses"`
+	DeniedLicenses  []string `yaml:"denied-licenses"`
+	AllowedHashes   []string `yaml:"allowed-hashes"`
 }
 
 type scorecardPolicy struct {
@@ -128,10 +132,22 @@ func parseFromYAML(b []byte) (*ScorecardPolicy, error) {
 		}
 		checksFound[n] = true
 
+		if (len(p.AllowedLicenses) != 0 || len(p.DeniedLicenses) != 0) && n != checks.CheckLicense {
+			return &retPolicy, sce.WithMessage(sce.ErrScorecardInternal,
+				fmt.Sprintf("%v: %v: %v", errInvalidOption.Error(), n, "allowed-licenses/denied-licenses"))
+		}
+		if len(p.AllowedHashes) != 0 && n != checks.CheckBinaryArtifacts {
+			return &retPolicy, sce.WithMessage(sce.ErrScorecardInternal,
+				fmt.Sprintf("%v: %v: %v", errInvalidOption.Error(), n, "allowed-hashes"))
+		}
+
 		// Add an entry to the policy.
 		retPolicy.Policies[n] = &CheckPolicy{
-			Score: int32(p.Score),
-			Mode:  modeToProto(p.Mode),
+			Score:               int32(p.Score),
+			Mode:                modeToProto(p.Mode),
+			AllowedLicenses:     p.AllowedLicenses,
+			DeniedLicenses:      p.DeniedLicenses,
+			AllowedBinaryHashes: p.AllowedHashes,
 		}
 	}
 
@@ -198,6 +214,42 @@ func GetEnabled(
 	return enabledChecks, nil
 }
 
+// ParseContributorsMappingFromFile takes a YAML file of company and identity
+// aliases and returns a `ContributorsMapping`.
+func ParseContributorsMappingFromFile(mappingFile string) (*checker.ContributorsMapping, error) {
+	if mappingFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(mappingFile)
+	if err != nil {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal,
+			fmt.Sprintf("os.ReadFile: %v", err))
+	}
+
+	var mapping checker.ContributorsMapping
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return nil, sce.WithMessage(sce.ErrScorecardInternal, err.Error())
+	}
+	return &mapping, nil
+}
+
+// GetSettings returns the check-specific settings declared in the policy.
+func GetSettings(sp *ScorecardPolicy) *checker.PolicySettings {
+	settings := checker.PolicySettings{}
+	if sp == nil {
+		return &settings
+	}
+	if p, exists := sp.GetPolicies()[checks.CheckLicense]; exists {
+		settings.AllowedLicenses = p.GetAllowedLicenses()
+		settings.DeniedLicenses = p.GetDeniedLicenses()
+	}
+	if p, exists := sp.GetPolicies()[checks.CheckBinaryArtifacts]; exists {
+		settings.AllowedBinaryHashes = p.GetAllowedBinaryHashes()
+	}
+	return &settings
+}
+
 func checksHavePolicies(sp *ScorecardPolicy, enabledChecks checker.CheckNameToFnMap) bool {
 	for checkName := range enabledChecks {
 		_, exists := sp.Policies[checkName]
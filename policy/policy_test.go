@@ -17,8 +17,10 @@ package policy
 import (
 	"errors"
 	"os"
+	"reflect"
 	"testing"
 
+	"github.com/ossf/scorecard/v4/checker"
 	sce "github.com/ossf/scorecard/v4/errors"
 )
 
@@ -101,6 +103,49 @@ func TestPolicyRead(t *testing.T) {
 			filename: "./testdata/policy-multiple-defs.yaml",
 			err:      sce.ErrScorecardInternal,
 		},
+		{
+			name:     "license lists",
+			filename: "./testdata/policy-licenses.yaml",
+			err:      nil,
+			result: ScorecardPolicy{
+				Version: 1,
+				Policies: map[string]*CheckPolicy{
+					"License": {
+						Score:           10,
+						Mode:            CheckPolicy_ENFORCED,
+						AllowedLicenses: []string{"Apache-2.0", "MIT"},
+						DeniedLicenses:  []string{"AGPL-3.0"},
+					},
+				},
+			},
+		},
+		{
+			name:     "license lists on another check",
+			filename: "./testdata/policy-invalid-option.yaml",
+			err:      sce.ErrScorecardInternal,
+		},
+		{
+			name:     "binary hashes",
+			filename: "./testdata/policy-binary-hashes.yaml",
+			err:      nil,
+			result: ScorecardPolicy{
+				Version: 1,
+				Policies: map[string]*CheckPolicy{
+					"Binary-Artifacts": {
+						Score: 10,
+						Mode:  CheckPolicy_ENFORCED,
+						AllowedBinaryHashes: []string{
+							"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "binary hashes on another check",
+			filename: "./testdata/policy-invalid-hashes.yaml",
+			err:      sce.ErrScorecardInternal,
+		},
 	}
 
 	for i := range tests {
@@ -131,3 +176,45 @@ func TestPolicyRead(t *testing.T) {
 		})
 	}
 }
+
+func TestParseContributorsMappingFromFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err      error
+		want     *checker.ContributorsMapping
+		name     string
+		filename string
+	}{
+		{
+			name: "no file",
+		},
+		{
+			name:     "valid",
+			filename: "./testdata/contributors-mapping.yaml",
+			want: &checker.ContributorsMapping{
+				Companies:  map[string][]string{"example": {"Example Holdings"}},
+				Identities: map[string][]string{"alice": {"alice@example.com"}},
+			},
+		},
+		{
+			name:     "missing file",
+			filename: "./testdata/does-not-exist.yaml",
+			err:      sce.ErrScorecardInternal,
+		},
+	}
+
+	for i := range tests {
+		tt := &tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseContributorsMappingFromFile(tt.filename)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -24,4 +24,22 @@ var (
 	CheckErrors = stats.Int64("CheckErrors", "Measures the count of errors", stats.UnitDimensionless)
 	// HTTPRequests measures the count of HTTP requests.
 	HTTPRequests = stats.Int64("HTTPRequests", "Measures the count of HTTP requests", stats.UnitDimensionless)
+	// RateLimitWaitInSec measures the time spent waiting for API rate limits to reset.
+	RateLimitWaitInSec = stats.Float64("RateLimitWaitInSec", "Measures the wait for API rate limits in seconds",
+		stats.UnitSeconds)
+	// CheckLatencyInSec measures the wall time in seconds per check.
+	CheckLatencyInSec = stats.Float64("CheckLatencyInSec", "Measures the wall time in seconds for a check",
+		stats.UnitSeconds)
+	// CheckHTTPRequests measures the count of HTTP requests made by a check.
+	CheckHTTPRequests = stats.Int64("CheckHTTPRequests", "Measures the count of HTTP requests made by a check",
+		stats.UnitDimensionless)
+	// RepoHTTPRequests measures the count of HTTP requests made to scan a repo.
+	RepoHTTPRequests = stats.Int64("RepoHTTPRequests", "Measures the count of HTTP requests made to scan a repo",
+		stats.UnitDimensionless)
+	// TarballSizeInBytes measures the size of the downloaded repo tarballs.
+	TarballSizeInBytes = stats.Int64("TarballSizeInBytes", "Measures the size of a repo tarball in bytes",
+		stats.UnitBytes)
+	// TarballDownloadTimeInSec measures the time spent downloading repo tarballs.
+	TarballDownloadTimeInSec = stats.Float64("TarballDownloadTimeInSec",
+		"Measures the download time of a repo tarball in seconds", stats.UnitSeconds)
 )
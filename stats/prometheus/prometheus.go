@@ -0,0 +1,67 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prometheus exports the metrics of package stats to Prometheus.
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+
+	ocprometheus "contrib.go.opencensus.io/exporter/prometheus"
+	"go.opencensus.io/stats/view"
+)
+
+const (
+	// MetricsPath is the path of the endpoint serving the metrics to Prometheus.
+	MetricsPath = "/metrics"
+
+	prometheusNamespace = "scorecard"
+)
+
+// Exporter serves the metrics of the registered views for Prometheus to scrape, see stats.RegisterViews.
+type Exporter struct {
+	exporter *ocprometheus.Exporter
+}
+
+// NewExporter returns an Exporter of the metrics prefixed with scorecard_.
+func NewExporter() (*Exporter, error) {
+	exporter, err := ocprometheus.NewExporter(ocprometheus.Options{
+		Namespace: prometheusNamespace,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error during prometheus.NewExporter: %w", err)
+	}
+	return &Exporter{exporter: exporter}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.exporter.ServeHTTP(w, r)
+}
+
+// ExportView is a no-op: the metrics are read when scraped.
+func (e *Exporter) ExportView(viewData *view.Data) {}
+
+// StartMetricsExporter serves the metrics on MetricsPath of http.DefaultServeMux.
+func (e *Exporter) StartMetricsExporter() error {
+	http.Handle(MetricsPath, e)
+	return nil
+}
+
+// StopMetricsExporter is a no-op.
+func (e *Exporter) StopMetricsExporter() {}
+
+// Flush is a no-op.
+func (e *Exporter) Flush() {}
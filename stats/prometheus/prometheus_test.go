@@ -0,0 +1,66 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	opencensusstats "go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	"github.com/ossf/scorecard/v4/stats"
+)
+
+func TestPrometheusExporter(t *testing.T) {
+	t.Parallel()
+	exporter, err := NewExporter()
+	if err != nil {
+		t.Fatalf("NewExporter: %v", err)
+	}
+	if err := exporter.StartMetricsExporter(); err != nil {
+		t.Fatalf("StartMetricsExporter: %v", err)
+	}
+	defer exporter.StopMetricsExporter()
+	if err := stats.RegisterViews(); err != nil {
+		t.Fatalf("stats.RegisterViews: %v", err)
+	}
+
+	ctx, err := tag.New(context.Background(), tag.Upsert(stats.CheckName, "Binary-Artifacts"))
+	if err != nil {
+		t.Fatalf("tag.New: %v", err)
+	}
+	opencensusstats.Record(ctx, stats.CheckLatencyInSec.M(0.3), stats.CheckHTTPRequests.M(4))
+	opencensusstats.Record(ctx, stats.TarballSizeInBytes.M(1<<20))
+
+	w := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: status %d", MetricsPath, w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`scorecard_CheckLatency_bucket{checkName="Binary-Artifacts",le="0.5"} 1`,
+		`scorecard_CheckHTTPRequestCount_sum{checkName="Binary-Artifacts"} 4`,
+		`scorecard_TarballSize_count 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics don't contain %q:\n%s", want, body)
+		}
+	}
+}
@@ -23,4 +23,8 @@ var (
 	ErrorName = tag.MustNewKey("errorName")
 	// RequestTag is the tag key for the request type.
 	RequestTag = tag.MustNewKey("requestTag")
+	// RateLimitResource is the tag key for the API resource of a rate limit, e.g. core or graphql.
+	RateLimitResource = tag.MustNewKey("rateLimitResource")
+	// RateLimitReason is the tag key for the kind of rate limit hit, primary or secondary.
+	RateLimitReason = tag.MustNewKey("rateLimitReason")
 )
@@ -0,0 +1,127 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	usageKey struct{}
+	setupKey struct{}
+)
+
+// Usage accounts for the HTTP requests made with the contexts returned by WithUsage.
+type Usage struct {
+	mu sync.Mutex
+	// requests are counted per endpoint class, e.g. rest or graphql.
+	requests  map[string]int64
+	bytes     int64
+	cacheHits int64
+}
+
+// WithUsage returns a copy of ctx whose HTTP requests are accounted for in u as well as in the Usages
+// of ctx, e.g. the requests of a check in its own Usage and in the one of the repo scan.
+func WithUsage(ctx context.Context, u *Usage) context.Context {
+	parents, _ := ctx.Value(usageKey{}).([]*Usage)
+	usages := make([]*Usage, len(parents), len(parents)+1)
+	copy(usages, parents)
+	return context.WithValue(ctx, usageKey{}, append(usages, u))
+}
+
+// WithSetupUsage returns a copy of ctx whose setups shared by several checks, see ForSetup, are accounted
+// for in u as well as in the Usages of ctx, e.g. the download of the tarball of the repo, which would
+// otherwise be accounted for in the Usage of the first check reading its files.
+func WithSetupUsage(ctx context.Context, u *Usage) context.Context {
+	return context.WithValue(ctx, setupKey{}, usages(WithUsage(ctx, u)))
+}
+
+// ForSetup returns a copy of ctx whose HTTP requests are accounted for in the Usages of the setup
+// of the context returned by WithSetupUsage it derives from, if any, instead of in its own Usages,
+// e.g. the one of the check making the setup.
+func ForSetup(ctx context.Context) context.Context {
+	setup, ok := ctx.Value(setupKey{}).([]*Usage)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, usageKey{}, setup)
+}
+
+func usages(ctx context.Context) []*Usage {
+	usages, _ := ctx.Value(usageKey{}).([]*Usage)
+	return usages
+}
+
+// RecordRequest counts an HTTP request to an endpoint of the given class made with ctx in its Usages.
+// Requests answered from a cache are counted as well, as cache hits.
+func RecordRequest(ctx context.Context, class string, fromCache bool) {
+	for _, u := range usages(ctx) {
+		u.mu.Lock()
+		if u.requests == nil {
+			u.requests = map[string]int64{}
+		}
+		u.requests[class]++
+		if fromCache {
+			u.cacheHits++
+		}
+		u.mu.Unlock()
+	}
+}
+
+// RecordBytes counts n bytes downloaded by an HTTP request made with ctx in its Usages.
+func RecordBytes(ctx context.Context, n int64) {
+	for _, u := range usages(ctx) {
+		u.mu.Lock()
+		u.bytes += n
+		u.mu.Unlock()
+	}
+}
+
+// Requests returns the count of HTTP requests.
+func (u *Usage) Requests() int64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var requests int64
+	for _, n := range u.requests {
+		requests += n
+	}
+	return requests
+}
+
+// RequestsPerClass returns the count of HTTP requests per endpoint class.
+func (u *Usage) RequestsPerClass() map[string]int64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	requests := make(map[string]int64, len(u.requests))
+	for class, n := range u.requests {
+		requests[class] = n
+	}
+	return requests
+}
+
+// BytesDownloaded returns the count of bytes downloaded, excluding the responses answered from a cache.
+func (u *Usage) BytesDownloaded() int64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.bytes
+}
+
+// CacheHits returns the count of HTTP requests answered from a cache.
+func (u *Usage) CacheHits() int64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.cacheHits
+}
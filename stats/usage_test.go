@@ -0,0 +1,129 @@
+// Copyright 2022 Security Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+	var repo, check, other Usage
+	ctx := WithUsage(context.Background(), &repo)
+	checkCtx := WithUsage(ctx, &check)
+	otherCtx := WithUsage(ctx, &other)
+
+	RecordRequest(context.Background(), "rest", false)
+	RecordRequest(ctx, "rest", false)
+	RecordRequest(checkCtx, "rest", true)
+	RecordRequest(checkCtx, "graphql", false)
+	RecordBytes(checkCtx, 100)
+	RecordRequest(otherCtx, "tarball", false)
+	RecordBytes(otherCtx, 1000)
+
+	for _, tt := range []struct {
+		name          string
+		usage         *Usage
+		wantRequests  map[string]int64
+		wantBytes     int64
+		wantCacheHits int64
+	}{
+		{
+			name:          "repo",
+			usage:         &repo,
+			wantRequests:  map[string]int64{"rest": 2, "graphql": 1, "tarball": 1},
+			wantBytes:     1100,
+			wantCacheHits: 1,
+		},
+		{
+			name:          "check",
+			usage:         &check,
+			wantRequests:  map[string]int64{"rest": 1, "graphql": 1},
+			wantBytes:     100,
+			wantCacheHits: 1,
+		},
+		{
+			name:         "other",
+			usage:        &other,
+			wantRequests: map[string]int64{"tarball": 1},
+			wantBytes:    1000,
+		},
+	} {
+		if diff := cmp.Diff(tt.wantRequests, tt.usage.RequestsPerClass()); diff != "" {
+			t.Errorf("%s requests mismatch (-want +got):\n%s", tt.name, diff)
+		}
+		var wantTotal int64
+		for _, n := range tt.wantRequests {
+			wantTotal += n
+		}
+		if got := tt.usage.Requests(); got != wantTotal {
+			t.Errorf("%s requests = %d, want %d", tt.name, got, wantTotal)
+		}
+		if got := tt.usage.BytesDownloaded(); got != tt.wantBytes {
+			t.Errorf("%s bytes = %d, want %d", tt.name, got, tt.wantBytes)
+		}
+		if got := tt.usage.CacheHits(); got != tt.wantCacheHits {
+			t.Errorf("%s cache hits = %d, want %d", tt.name, got, tt.wantCacheHits)
+		}
+	}
+}
+
+func TestSetupUsage(t *testing.T) {
+	t.Parallel()
+	var repo, setup, check Usage
+	ctx := WithSetupUsage(WithUsage(context.Background(), &repo), &setup)
+	checkCtx := WithUsage(ctx, &check)
+
+	RecordRequest(ForSetup(ctx), "graphql", false)
+	RecordRequest(checkCtx, "rest", false)
+	RecordRequest(ForSetup(checkCtx), "tarball", false)
+	RecordBytes(ForSetup(checkCtx), 1000)
+	RecordRequest(ForSetup(context.Background()), "rest", false)
+
+	for _, tt := range []struct {
+		name         string
+		usage        *Usage
+		wantRequests map[string]int64
+		wantBytes    int64
+	}{
+		{
+			name:         "repo",
+			usage:        &repo,
+			wantRequests: map[string]int64{"graphql": 1, "rest": 1, "tarball": 1},
+			wantBytes:    1000,
+		},
+		{
+			name:         "setup",
+			usage:        &setup,
+			wantRequests: map[string]int64{"graphql": 1, "tarball": 1},
+			wantBytes:    1000,
+		},
+		{
+			name:         "check",
+			usage:        &check,
+			wantRequests: map[string]int64{"rest": 1},
+		},
+	} {
+		if diff := cmp.Diff(tt.wantRequests, tt.usage.RequestsPerClass()); diff != "" {
+			t.Errorf("%s requests mismatch (-want +got):\n%s", tt.name, diff)
+		}
+		if got := tt.usage.BytesDownloaded(); got != tt.wantBytes {
+			t.Errorf("%s bytes = %d, want %d", tt.name, got, tt.wantBytes)
+		}
+	}
+}
@@ -16,8 +16,12 @@
 package stats
 
 import (
+	"fmt"
+
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+
+	githubstats "github.com/ossf/scorecard/v4/clients/githubrepo/stats"
 )
 
 var (
@@ -63,4 +67,95 @@ var (
 		TagKeys:     []tag.Key{CheckName, RequestTag},
 		Aggregation: view.Count(),
 	}
+
+	// RateLimitWaits tracks the count of waits for API rate limits.
+	RateLimitWaits = view.View{
+		Name:        "RateLimitWaits",
+		Description: "Waits for API rate limits per resource and reason",
+		Measure:     RateLimitWaitInSec,
+		TagKeys:     []tag.Key{RateLimitResource, RateLimitReason},
+		Aggregation: view.Count(),
+	}
+
+	// RateLimitWaitTime tracks the time spent waiting for API rate limits.
+	RateLimitWaitTime = view.View{
+		Name:        "RateLimitWaitTime",
+		Description: "Time spent waiting for API rate limits per resource and reason",
+		Measure:     RateLimitWaitInSec,
+		TagKeys:     []tag.Key{RateLimitResource, RateLimitReason},
+		//nolint:gomnd
+		Aggregation: view.Distribution(0, 1, 10, 60, 300, 900, 1800, 3600),
+	}
+
+	// CheckLatency tracks the wall time of checks.
+	CheckLatency = view.View{
+		Name:        "CheckLatency",
+		Description: "Wall time stats per check",
+		Measure:     CheckLatencyInSec,
+		TagKeys:     []tag.Key{CheckName},
+		//nolint:gomnd
+		Aggregation: view.Distribution(0, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800),
+	}
+
+	// CheckHTTPRequestCount tracks the count of HTTP requests made by checks.
+	CheckHTTPRequestCount = view.View{
+		Name:        "CheckHTTPRequestCount",
+		Description: "HTTPRequests made by a run of a check",
+		Measure:     CheckHTTPRequests,
+		TagKeys:     []tag.Key{CheckName},
+		//nolint:gomnd
+		Aggregation: view.Distribution(0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000),
+	}
+
+	// RepoHTTPRequestCount tracks the count of HTTP requests made to scan repos.
+	RepoHTTPRequestCount = view.View{
+		Name:        "RepoHTTPRequestCount",
+		Description: "HTTPRequests made to scan a repo",
+		Measure:     RepoHTTPRequests,
+		//nolint:gomnd
+		Aggregation: view.Distribution(0, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000),
+	}
+
+	// TarballSize tracks the size of the downloaded repo tarballs.
+	TarballSize = view.View{
+		Name:        "TarballSize",
+		Description: "Size of the downloaded repo tarballs",
+		Measure:     TarballSizeInBytes,
+		//nolint:gomnd
+		Aggregation: view.Distribution(0, 1<<10, 1<<14, 1<<18, 1<<20, 1<<22, 1<<24, 1<<26, 1<<28, 1<<30),
+	}
+
+	// TarballDownloadTime tracks the time spent downloading repo tarballs.
+	TarballDownloadTime = view.View{
+		Name:        "TarballDownloadTime",
+		Description: "Download time of the repo tarballs",
+		Measure:     TarballDownloadTimeInSec,
+		//nolint:gomnd
+		Aggregation: view.Distribution(0, 0.1, 0.5, 1, 2, 5, 10, 30, 60, 120, 300),
+	}
 )
+
+// Views returns the views of the metrics recorded by Scorecard, and the GitHub tokens it uses.
+func Views() []*view.View {
+	return []*view.View{
+		&CheckRuntime,
+		&CheckLatency,
+		&CheckErrorCount,
+		&OutgoingHTTPRequests,
+		&CheckHTTPRequestCount,
+		&RepoHTTPRequestCount,
+		&RateLimitWaits,
+		&RateLimitWaitTime,
+		&TarballSize,
+		&TarballDownloadTime,
+		&githubstats.GithubTokens,
+	}
+}
+
+// RegisterViews registers the Views, for their metrics to be exported.
+func RegisterViews() error {
+	if err := view.Register(Views()...); err != nil {
+		return fmt.Errorf("error during view.Register: %w", err)
+	}
+	return nil
+}